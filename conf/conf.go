@@ -18,12 +18,20 @@ type Server struct {
 // System 系统设置
 // System 系统配置
 type System struct {
-	Env      string   `mapstructure:"env" json:"env" yaml:"env"`                // 环境
-	Addr     string   `mapstructure:"addr" json:"addr" yaml:"addr"`             // 系统服务监听端口
-	Name     string   `mapstructure:"name" json:"name" yaml:"name"`             // 系统服务名称
-	Version  string   `mapstructure:"version" json:"version" yaml:"version"`    // 系统版本
-	Http     Http     `mapstructure:"http" json:"http" yaml:"http"`             // HTTP 配置
-	Security Security `mapstructure:"security" json:"security" yaml:"security"` // 安全配置
+	Env        string     `mapstructure:"env" json:"env" yaml:"env"`                      // 环境
+	Addr       string     `mapstructure:"addr" json:"addr" yaml:"addr"`                   // 系统服务监听端口
+	Name       string     `mapstructure:"name" json:"name" yaml:"name"`                   // 系统服务名称
+	Version    string     `mapstructure:"version" json:"version" yaml:"version"`          // 系统版本
+	Http       Http       `mapstructure:"http" json:"http" yaml:"http"`                   // HTTP 配置
+	Security   Security   `mapstructure:"security" json:"security" yaml:"security"`       // 安全配置
+	Task       Task       `mapstructure:"task" json:"task" yaml:"task"`                   // 后台定时任务配置
+	Policy     Policy     `mapstructure:"policy" json:"policy" yaml:"policy"`             // 权限决策接口配置
+	Permission Permission `mapstructure:"permission" json:"permission" yaml:"permission"` // 用户权限缓存配置
+	Event      Event      `mapstructure:"event" json:"event" yaml:"event"`                // 权限变更事件配置
+	Outbox     Outbox     `mapstructure:"outbox" json:"outbox" yaml:"outbox"`             // 事件发件箱投递配置
+	Webhook    Webhook    `mapstructure:"webhook" json:"webhook" yaml:"webhook"`          // Webhook 回调配置
+	RecycleBin RecycleBin `mapstructure:"recycleBin" json:"recycleBin" yaml:"recycleBin"` // 回收站配置
+	Import     Import     `mapstructure:"import" json:"import" yaml:"import"`             // 批量导入配置
 }
 
 // Http HTTP配置
@@ -35,7 +43,12 @@ type Http struct {
 
 // Security 安全配置
 type Security struct {
-	Cors Cors `mapstructure:"cors" json:"cors" yaml:"cors"` // CORS 跨域配置
+	Cors            Cors              `mapstructure:"cors" json:"cors" yaml:"cors"`                                  // CORS 跨域配置
+	Lockout         Lockout           `mapstructure:"lockout" json:"lockout" yaml:"lockout"`                         // 登录失败锁定配置
+	PasswordPolicy  PasswordPolicy    `mapstructure:"passwordPolicy" json:"passwordPolicy" yaml:"passwordPolicy"`    // 密码策略配置
+	ServiceTokens   map[string]string `mapstructure:"serviceTokens" json:"serviceTokens" yaml:"serviceTokens"`       // 下游业务服务的访问令牌，键为服务名称（小写），值为令牌；未配置时拒绝全部服务调用
+	ScimTokens      map[string]string `mapstructure:"scimTokens" json:"scimTokens" yaml:"scimTokens"`                // SCIM 客户端（身份提供商）的 Bearer 令牌，键为客户端名称（小写），值为令牌；未配置时拒绝全部 SCIM 调用
+	ErasureTokenTTL time.Duration     `mapstructure:"erasureTokenTTL" json:"erasureTokenTTL" yaml:"erasureTokenTTL"` // 擦除用户个人数据的确认令牌有效期
 }
 
 // Cors 跨域配置
@@ -45,6 +58,100 @@ type Cors struct {
 	AllowMethods string `mapstructure:"allowMethods" json:"allowMethods" yaml:"allowMethods"` // 允许的HTTP方法
 }
 
+// Lockout 登录失败锁定配置
+type Lockout struct {
+	Enabled               bool            `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                                           // 是否启用登录失败锁定
+	MaxIdentifierFailures int64           `mapstructure:"maxIdentifierFailures" json:"maxIdentifierFailures" yaml:"maxIdentifierFailures"` // 同一账号标识在统计窗口内允许的最大失败次数
+	MaxIPFailures         int64           `mapstructure:"maxIPFailures" json:"maxIPFailures" yaml:"maxIPFailures"`                         // 同一客户端IP在统计窗口内允许的最大失败次数
+	FailureWindow         time.Duration   `mapstructure:"failureWindow" json:"failureWindow" yaml:"failureWindow"`                         // 失败次数统计窗口
+	LockDurations         []time.Duration `mapstructure:"lockDurations" json:"lockDurations" yaml:"lockDurations"`                         // 逐级递增的锁定时长，第N次锁定使用第N个值，超出后沿用最后一个
+	LevelTTL              time.Duration   `mapstructure:"levelTTL" json:"levelTTL" yaml:"levelTTL"`                                        // 锁定级别的保留时长，超过该时长未再被锁定则级别清零
+}
+
+// PasswordPolicy 密码策略配置，新增账号、重置密码、修改密码时校验
+type PasswordPolicy struct {
+	MinLength       int           `mapstructure:"minLength" json:"minLength" yaml:"minLength"`                   // 最小长度
+	MaxLength       int           `mapstructure:"maxLength" json:"maxLength" yaml:"maxLength"`                   // 最大长度（字节），bcrypt 最多只处理前72字节
+	RequireUpper    bool          `mapstructure:"requireUpper" json:"requireUpper" yaml:"requireUpper"`          // 是否必须包含大写字母
+	RequireLower    bool          `mapstructure:"requireLower" json:"requireLower" yaml:"requireLower"`          // 是否必须包含小写字母
+	RequireDigit    bool          `mapstructure:"requireDigit" json:"requireDigit" yaml:"requireDigit"`          // 是否必须包含数字
+	RequireSymbol   bool          `mapstructure:"requireSymbol" json:"requireSymbol" yaml:"requireSymbol"`       // 是否必须包含特殊字符
+	MinCharClasses  int           `mapstructure:"minCharClasses" json:"minCharClasses" yaml:"minCharClasses"`    // 至少包含的字符类别数（大写、小写、数字、特殊字符）
+	CheckDictionary bool          `mapstructure:"checkDictionary" json:"checkDictionary" yaml:"checkDictionary"` // 是否拒绝常见弱密码
+	Dictionary      []string      `mapstructure:"dictionary" json:"dictionary" yaml:"dictionary"`                // 额外的弱密码词条，与内置词典合并
+	DictionaryFile  string        `mapstructure:"dictionaryFile" json:"dictionaryFile" yaml:"dictionaryFile"`    // 弱密码词典文件路径，每行一个词条，选填
+	CheckSimilarity bool          `mapstructure:"checkSimilarity" json:"checkSimilarity" yaml:"checkSimilarity"` // 是否拒绝与用户名、邮箱、手机号相似的密码
+	HistoryDepth    int           `mapstructure:"historyDepth" json:"historyDepth" yaml:"historyDepth"`          // 不允许与当前密码及最近N次使用过的旧密码相同，0 表示不限制
+	MaxAge          time.Duration `mapstructure:"maxAge" json:"maxAge" yaml:"maxAge"`                            // 密码有效期，过期后登录只能修改密码，0 表示永不过期
+}
+
+// Task 后台定时任务配置
+type Task struct {
+	RoleAssignmentInterval time.Duration `mapstructure:"roleAssignmentInterval" json:"roleAssignmentInterval" yaml:"roleAssignmentInterval"` // 按有效期激活、失效用户角色的检查间隔，0 表示不启用
+}
+
+// Policy 权限决策接口配置
+type Policy struct {
+	CacheTTL time.Duration `mapstructure:"cacheTTL" json:"cacheTTL" yaml:"cacheTTL"` // 决策结果在 Redis 中的缓存时长，授权变更时主动失效
+}
+
+// Permission 用户权限缓存配置
+type Permission struct {
+	CacheTTL    time.Duration `mapstructure:"cacheTTL" json:"cacheTTL" yaml:"cacheTTL"`          // 用户有效接口集合在 Redis 中的缓存时长，权限重新计算时主动失效
+	WarmOnStart bool          `mapstructure:"warmOnStart" json:"warmOnStart" yaml:"warmOnStart"` // 服务启动时是否预热全部用户的权限缓存
+}
+
+// Event 权限变更事件配置
+type Event struct {
+	Enabled        bool     `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                      // 是否发布权限变更事件
+	Stream         string   `mapstructure:"stream" json:"stream" yaml:"stream"`                         // 事件 Stream 名称，消费者通过消费组回放
+	Channel        string   `mapstructure:"channel" json:"channel" yaml:"channel"`                      // 事件广播的 Pub/Sub 频道名称，为空表示不广播
+	MaxLen         int64    `mapstructure:"maxLen" json:"maxLen" yaml:"maxLen"`                         // Stream 的近似最大长度，超出后裁剪最早的事件，0 表示不裁剪
+	ConsumerGroups []string `mapstructure:"consumerGroups" json:"consumerGroups" yaml:"consumerGroups"` // 服务启动时预先创建的消费组，保证消费者首次连接前的事件也能被回放
+}
+
+// Outbox 事件发件箱投递配置
+type Outbox struct {
+	RelayInterval time.Duration `mapstructure:"relayInterval" json:"relayInterval" yaml:"relayInterval"` // 投递待发送事件的检查间隔，0 表示不启用
+	BatchSize     int           `mapstructure:"batchSize" json:"batchSize" yaml:"batchSize"`             // 每批领取的事件数量
+	Lease         time.Duration `mapstructure:"lease" json:"lease" yaml:"lease"`                         // 领取后的租约时长，投递进程异常退出时租约到期后重新投递
+	MaxAttempts   int           `mapstructure:"maxAttempts" json:"maxAttempts" yaml:"maxAttempts"`       // 最大尝试投递次数，超过后进入死信
+	BackoffBase   time.Duration `mapstructure:"backoffBase" json:"backoffBase" yaml:"backoffBase"`       // 首次重试的等待时长，之后每次翻倍
+	BackoffMax    time.Duration `mapstructure:"backoffMax" json:"backoffMax" yaml:"backoffMax"`          // 重试等待时长的上限
+	Sinks         []string      `mapstructure:"sinks" json:"sinks" yaml:"sinks"`                         // 启用的投递目标（redis、http、webhook）
+	HTTPEndpoints []string      `mapstructure:"httpEndpoints" json:"httpEndpoints" yaml:"httpEndpoints"` // http 投递目标的地址，事件以 JSON 请求体 POST 到每个地址
+	HTTPTimeout   time.Duration `mapstructure:"httpTimeout" json:"httpTimeout" yaml:"httpTimeout"`       // http 投递的请求超时时间
+	PurgeInterval time.Duration `mapstructure:"purgeInterval" json:"purgeInterval" yaml:"purgeInterval"` // 清除过期已投递、死信事件的检查间隔，0 表示不启用
+	Retention     time.Duration `mapstructure:"retention" json:"retention" yaml:"retention"`             // 已投递事件的保留时长，超过后永久删除，0 表示永久保留
+	DeadRetention time.Duration `mapstructure:"deadRetention" json:"deadRetention" yaml:"deadRetention"` // 死信事件自最后一次投递失败起的保留时长，超过后永久删除，0 表示永久保留
+}
+
+// Webhook Webhook 回调配置
+type Webhook struct {
+	DeliveryInterval time.Duration `mapstructure:"deliveryInterval" json:"deliveryInterval" yaml:"deliveryInterval"` // 回调待投递记录的检查间隔，0 表示不启用
+	BatchSize        int           `mapstructure:"batchSize" json:"batchSize" yaml:"batchSize"`                      // 每批领取的投递记录数量
+	Lease            time.Duration `mapstructure:"lease" json:"lease" yaml:"lease"`                                  // 领取后的租约时长，投递进程异常退出时租约到期后重新投递
+	MaxAttempts      int           `mapstructure:"maxAttempts" json:"maxAttempts" yaml:"maxAttempts"`                // 最大尝试回调次数，超过后标记为投递失败
+	BackoffBase      time.Duration `mapstructure:"backoffBase" json:"backoffBase" yaml:"backoffBase"`                // 首次重试的等待时长，之后每次翻倍
+	BackoffMax       time.Duration `mapstructure:"backoffMax" json:"backoffMax" yaml:"backoffMax"`                   // 重试等待时长的上限
+	Timeout          time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout"`                            // 回调请求的超时时间
+	AllowPrivate     bool          `mapstructure:"allowPrivate" json:"allowPrivate" yaml:"allowPrivate"`             // 是否允许回调内网、回环等非公网地址，仅在回调地址均为可信内网服务时开启
+}
+
+// RecycleBin 回收站配置
+type RecycleBin struct {
+	PurgeInterval time.Duration `mapstructure:"purgeInterval" json:"purgeInterval" yaml:"purgeInterval"` // 清除过期软删除记录的检查间隔，0 表示不启用
+	Retention     time.Duration `mapstructure:"retention" json:"retention" yaml:"retention"`             // 软删除记录在回收站中的保留时长，超过后永久删除
+	BatchSize     int           `mapstructure:"batchSize" json:"batchSize" yaml:"batchSize"`             // 每次检查每类记录最多永久删除的数量
+}
+
+// Import 批量导入配置
+type Import struct {
+	MaxFileSize       int64 `mapstructure:"maxFileSize" json:"maxFileSize" yaml:"maxFileSize"`                   // 上传文件的最大字节数
+	UnzipSizeLimit    int64 `mapstructure:"unzipSizeLimit" json:"unzipSizeLimit" yaml:"unzipSizeLimit"`          // XLSX 解压后的最大总字节数，防止压缩炸弹
+	UnzipXMLSizeLimit int64 `mapstructure:"unzipXMLSizeLimit" json:"unzipXMLSizeLimit" yaml:"unzipXMLSizeLimit"` // XLSX 工作表解压到内存的最大字节数，超出后暂存到临时文件，不得大于 unzipSizeLimit
+}
+
 // Logger 用于配置日志
 type Logger struct {
 	LogLevel      string `mapstructure:"logLevel" json:"logLevel" yaml:"logLevel"`                // 日志级别（debug、info、warn、error、fatal、panic）
@@ -67,6 +174,9 @@ type Mysql struct {
 	Enabled       bool   `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                   // 是否启用日志输出
 	Level         string `mapstructure:"level" json:"level" yaml:"level"`                         // 日志级别
 	SlowThreshold int    `mapstructure:"slowThreshold" json:"slowThreshold" yaml:"slowThreshold"` // 慢查询阈值
+
+	AutoMigrate        bool          `mapstructure:"autoMigrate" json:"autoMigrate" yaml:"autoMigrate"`                      // 服务启动时是否自动执行待执行的数据库迁移
+	MigrateLockTimeout time.Duration `mapstructure:"migrateLockTimeout" json:"migrateLockTimeout" yaml:"migrateLockTimeout"` // 等待数据库迁移锁的最长时间
 }
 
 // Redis 缓存配置
@@ -87,8 +197,10 @@ type Redis struct {
 
 // Jwt 鉴权
 type Jwt struct {
-	AccessSecret string `mapstructure:"accessSecret" json:"accessSecret" yaml:"accessSecret"`
-	AccessExpire int64  `mapstructure:"accessExpire" json:"accessExpire" yaml:"accessExpire"`
+	AccessSecret  string `mapstructure:"accessSecret" json:"accessSecret" yaml:"accessSecret"`    // 签名密钥
+	AccessExpire  int64  `mapstructure:"accessExpire" json:"accessExpire" yaml:"accessExpire"`    // 访问令牌有效期（秒），建议设置较短
+	RefreshExpire int64  `mapstructure:"refreshExpire" json:"refreshExpire" yaml:"refreshExpire"` // 刷新令牌有效期（秒）
+	MfaExpire     int64  `mapstructure:"mfaExpire" json:"mfaExpire" yaml:"mfaExpire"`             // 二次验证挑战令牌有效期（秒）
 }
 
 var GlobalConf *Server
@@ -109,6 +221,60 @@ func LoadConf(env string) {
 
 	vi.SetConfigFile(confPath)
 
+	// 默认值
+	vi.SetDefault("mysql.autoMigrate", true)
+	vi.SetDefault("mysql.migrateLockTimeout", "1m")
+	vi.SetDefault("jwt.accessExpire", 900)
+	vi.SetDefault("jwt.refreshExpire", 604800)
+	vi.SetDefault("jwt.mfaExpire", 300)
+	vi.SetDefault("system.security.lockout.enabled", true)
+	vi.SetDefault("system.security.lockout.maxIdentifierFailures", 5)
+	vi.SetDefault("system.security.lockout.maxIPFailures", 20)
+	vi.SetDefault("system.security.lockout.failureWindow", "15m")
+	vi.SetDefault("system.security.lockout.lockDurations", []string{"5m", "15m", "1h", "24h"})
+	vi.SetDefault("system.security.lockout.levelTTL", "24h")
+	vi.SetDefault("system.security.passwordPolicy.minLength", 8)
+	vi.SetDefault("system.security.passwordPolicy.maxLength", 72)
+	vi.SetDefault("system.security.passwordPolicy.minCharClasses", 3)
+	vi.SetDefault("system.security.passwordPolicy.checkDictionary", true)
+	vi.SetDefault("system.security.passwordPolicy.checkSimilarity", true)
+	vi.SetDefault("system.security.passwordPolicy.historyDepth", 5)
+	vi.SetDefault("system.security.passwordPolicy.maxAge", "2160h")
+	vi.SetDefault("system.security.erasureTokenTTL", "10m")
+	vi.SetDefault("system.task.roleAssignmentInterval", "1m")
+	vi.SetDefault("system.policy.cacheTTL", "10m")
+	vi.SetDefault("system.permission.cacheTTL", "1h")
+	vi.SetDefault("system.permission.warmOnStart", true)
+	vi.SetDefault("system.event.enabled", true)
+	vi.SetDefault("system.event.stream", "wam:events")
+	vi.SetDefault("system.event.channel", "wam:events")
+	vi.SetDefault("system.event.maxLen", 100000)
+	vi.SetDefault("system.outbox.relayInterval", "5s")
+	vi.SetDefault("system.outbox.batchSize", 100)
+	vi.SetDefault("system.outbox.lease", "1m")
+	vi.SetDefault("system.outbox.maxAttempts", 10)
+	vi.SetDefault("system.outbox.backoffBase", "5s")
+	vi.SetDefault("system.outbox.backoffMax", "1h")
+	vi.SetDefault("system.outbox.sinks", []string{"redis", "webhook"})
+	vi.SetDefault("system.outbox.httpTimeout", "5s")
+	vi.SetDefault("system.outbox.purgeInterval", "1h")
+	vi.SetDefault("system.outbox.retention", "168h")
+	vi.SetDefault("system.outbox.deadRetention", "720h")
+	vi.SetDefault("system.webhook.deliveryInterval", "5s")
+	vi.SetDefault("system.webhook.batchSize", 100)
+	vi.SetDefault("system.webhook.lease", "1m")
+	vi.SetDefault("system.webhook.maxAttempts", 8)
+	vi.SetDefault("system.webhook.backoffBase", "10s")
+	vi.SetDefault("system.webhook.backoffMax", "1h")
+	vi.SetDefault("system.webhook.timeout", "10s")
+	vi.SetDefault("system.webhook.allowPrivate", false)
+	vi.SetDefault("system.recycleBin.purgeInterval", "1h")
+	vi.SetDefault("system.recycleBin.retention", "720h")
+	vi.SetDefault("system.recycleBin.batchSize", 100)
+	vi.SetDefault("system.import.maxFileSize", 10<<20)
+	vi.SetDefault("system.import.unzipSizeLimit", 100<<20)
+	vi.SetDefault("system.import.unzipXMLSizeLimit", 16<<20)
+
 	err := vi.ReadInConfig()
 	if err != nil {
 		panic(fmt.Errorf("fatal error config file: #{err}"))
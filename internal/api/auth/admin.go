@@ -4,6 +4,8 @@ import (
 	"ByteScience-WAM-Admin/internal/model/dto"
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/utils"
+	"fmt"
 	"github.com/gin-gonic/gin"
 )
 
@@ -34,6 +36,22 @@ func (api *AdminApi) List(ctx *gin.Context, req *auth.ListAdminRequest) (res *au
 	return
 }
 
+// Info 获取管理员详细信息
+// @Summary 获取管理员详细信息
+// @Description 根据管理员ID获取管理员的详细信息，包含最近的登录记录
+// @Tags 管理员管理
+// @Accept json
+// @Produce json
+// @Param req body auth.InfoAdminRequest true "请求参数，包含用于定位管理员的ID"
+// @Success 200 {object} auth.InfoAdminResponse "成功返回管理员的详细信息"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如ID格式不正确或管理员不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/admin/info [get]
+func (api *AdminApi) Info(ctx *gin.Context, req *auth.InfoAdminRequest) (res *auth.InfoAdminResponse, err error) {
+	res, err = api.service.Info(ctx, req)
+	return
+}
+
 // Add 添加管理员
 // @Summary 添加管理员
 // @Description 添加一个新的管理员账户
@@ -81,3 +99,22 @@ func (api *AdminApi) Del(ctx *gin.Context, req *auth.DelAdminRequest) (res *dto.
 	err = api.service.Delete(ctx, req)
 	return
 }
+
+// Export 导出管理员
+// @Summary 导出管理员
+// @Description 按与列表查询相同的筛选条件将管理员导出为 CSV 或 XLSX 文件，按创建时间倒序，不受分页限制。roles 列为角色名称，以分号分隔。
+// @Description 可选的列：id、userName、nickname、email、phone、remark、roles、totpEnabled、lastLoginAt、createdAt、updatedAt
+// @Tags 管理员管理
+// @Accept json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param req body auth.ExportAdminRequest true "请求参数，包含筛选条件、文件格式和导出的列"
+// @Success 200 {file} file "CSV 或 XLSX 文件"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如导出的列不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/admin/export [get]
+func (api *AdminApi) Export(ctx *gin.Context, req *auth.ExportAdminRequest) error {
+	ctx.Header("Content-Type", utils.SpreadsheetContentType(req.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", utils.SpreadsheetFileName("admins", req.Format)))
+	return api.service.Export(ctx, req, ctx.Writer)
+}
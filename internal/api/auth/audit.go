@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditApi 结构体，保存服务实例
+type AuditApi struct {
+	service *service.AuditService
+}
+
+// NewAuditApi 创建 AuditApi 实例并初始化依赖项
+func NewAuditApi() *AuditApi {
+	auditService := service.NewAuditService()
+	return &AuditApi{service: auditService}
+}
+
+// List 获取审计日志列表
+// @Summary 获取审计日志列表
+// @Description 分页查询管理员、用户、角色的新增、编辑、删除等操作记录，可按操作人、操作类型、操作对象、请求ID及时间范围筛选
+// @Tags 审计日志
+// @Accept json
+// @Produce json
+// @Param req body auth.ListAuditLogRequest true "请求参数，包含分页信息和筛选条件"
+// @Success 200 {object} auth.ListAuditLogResponse "成功返回审计日志列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如时间格式不正确"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/audit [get]
+func (api *AuditApi) List(ctx *gin.Context, req *auth.ListAuditLogRequest) (res *auth.ListAuditLogResponse, err error) {
+	res, err = api.service.List(ctx, req)
+	return
+}
@@ -19,7 +19,7 @@ func NewAuthApi() *Api {
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 提供用户名和密码进行登录操作，验证用户身份并获取相应权限
+// @Description 提供用户名和密码进行登录操作，验证用户身份并获取相应权限；已启用 TOTP 的管理员返回 mfaRequired 及挑战令牌，需继续调用 /login/totp
 // @Tags 用户认证
 // @Accept json
 // @Produce json
@@ -33,6 +33,22 @@ func (api *Api) Login(ctx *gin.Context, req *auth.LoginRequest) (res *auth.Login
 	return
 }
 
+// LoginTotp 二次验证登录
+// @Summary 二次验证登录
+// @Description 已启用 TOTP 的管理员在密码校验通过后，使用返回的挑战令牌及验证码（或恢复码）换取正式令牌。挑战令牌只能使用一次，验证码错误时需重新登录
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param req body auth.TotpLoginRequest true "请求参数，包含挑战令牌与验证码"
+// @Success 200 {object} auth.LoginResponse "成功登录，返回token凭证表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或挑战令牌无效、验证码无效等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询或 Redis 访问异常等情况"
+// @Router /login/totp [post]
+func (api *Api) LoginTotp(ctx *gin.Context, req *auth.TotpLoginRequest) (res *auth.LoginResponse, err error) {
+	res, err = api.service.LoginTotp(ctx, req)
+	return
+}
+
 // ChangPassword 修改密码
 // @Summary 修改用户密码
 // @Description 根据提供的原密码及新密码等信息修改用户当前账户的密码
@@ -49,3 +65,99 @@ func (api *Api) ChangPassword(ctx *gin.Context, req *auth.ChangePasswordRequest)
 	err = api.service.ChangePassword(ctx, req)
 	return
 }
+
+// ChangeOwnPassword 修改当前账号密码
+// @Summary 修改当前账号密码
+// @Description 已登录的管理员或业务用户修改自己的密码；密码被重置或已过期时登录返回的受限令牌只能调用此接口。修改成功后全部登录会话失效，需要重新登录
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param req body auth.ChangeOwnPasswordRequest true "请求参数，包含旧密码、新密码及确认密码"
+// @Success 200 {object} dto.Empty "成功修改密码，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，如旧密码错误、新密码强度不足或与最近使用过的密码相同等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/changPassword [put]
+func (api *Api) ChangeOwnPassword(ctx *gin.Context, req *auth.ChangeOwnPasswordRequest) (res *dto.Empty, err error) {
+	err = api.service.ChangeOwnPassword(ctx, ctx.GetString("subjectType"), ctx.GetString("userId"), req)
+	return
+}
+
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌与刷新令牌，旧的刷新令牌随即失效；重复使用已轮换的刷新令牌会吊销整个登录会话
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param req body auth.RefreshTokenRequest true "请求参数，包含刷新令牌"
+// @Success 200 {object} auth.LoginResponse "成功刷新，返回新的令牌对"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或刷新令牌无效、过期、已吊销"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是 Redis 访问异常等情况"
+// @Router /refresh [post]
+func (api *Api) Refresh(ctx *gin.Context, req *auth.RefreshTokenRequest) (res *auth.LoginResponse, err error) {
+	res, err = api.service.Refresh(ctx, req)
+	return
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 吊销当前访问令牌及其所属登录会话的刷新令牌
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param _ body dto.Empty true "此参数为空对象，当前操作无需额外传入请求参数"
+// @Success 200 {object} dto.Empty "成功退出登录，返回空对象表示操作成功"
+// @Failure 401 {object} dto.ErrorResponse "令牌无效或已吊销"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是 Redis 访问异常等情况"
+// @Router /logout [post]
+func (api *Api) Logout(ctx *gin.Context, _ *dto.Empty) (res *dto.Empty, err error) {
+	err = api.service.Logout(ctx, ctx.GetString("jti"), ctx.GetString("familyId"), ctx.GetTime("tokenExpireAt"))
+	return
+}
+
+// LogoutAll 退出所有设备
+// @Summary 退出所有设备
+// @Description 吊销当前账号在所有设备上的登录会话
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param _ body dto.Empty true "此参数为空对象，当前操作无需额外传入请求参数"
+// @Success 200 {object} dto.Empty "成功退出所有设备，返回空对象表示操作成功"
+// @Failure 401 {object} dto.ErrorResponse "令牌无效或已吊销"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是 Redis 访问异常等情况"
+// @Router /logoutAll [post]
+func (api *Api) LogoutAll(ctx *gin.Context, _ *dto.Empty) (res *dto.Empty, err error) {
+	err = api.service.LogoutAll(ctx, ctx.GetString("userId"), ctx.GetString("jti"), ctx.GetTime("tokenExpireAt"))
+	return
+}
+
+// UserLogin 业务用户登录
+// @Summary 业务用户登录
+// @Description 业务用户使用用户名、邮箱或手机号加密码登录，已禁用的用户无法登录
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param req body auth.LoginRequest true "请求参数，包含用户标识、密码等登录所需信息"
+// @Success 200 {object} auth.LoginResponse "成功登录，返回token凭证表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或用户不存在、密码错误、用户已禁用等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错、验证逻辑异常等情况"
+// @Router /user/login [post]
+func (api *Api) UserLogin(ctx *gin.Context, req *auth.LoginRequest) (res *auth.LoginResponse, err error) {
+	res, err = api.service.UserLogin(ctx, req)
+	return
+}
+
+// Unlock 解除账号登录锁定
+// @Summary 解除账号登录锁定
+// @Description 管理员解除因多次登录失败而被锁定的账号，可同时解除指定客户端IP的锁定
+// @Tags 用户认证
+// @Accept json
+// @Produce json
+// @Param req body auth.UnlockAccountRequest true "请求参数，包含账号类型、账号标识及可选的客户端IP"
+// @Success 200 {object} dto.Empty "成功解除锁定，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，如账号类型不正确、IP格式错误等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询或 Redis 访问异常等情况"
+// @Router /auth/account/unlock [put]
+func (api *Api) Unlock(ctx *gin.Context, req *auth.UnlockAccountRequest) (res *dto.Empty, err error) {
+	err = api.service.Unlock(ctx, req)
+	return
+}
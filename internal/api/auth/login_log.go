@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/utils"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginLogApi 结构体，保存服务实例
+type LoginLogApi struct {
+	service *service.LoginLogService
+}
+
+// NewLoginLogApi 创建 LoginLogApi 实例并初始化依赖项
+func NewLoginLogApi() *LoginLogApi {
+	loginLogService := service.NewLoginLogService()
+	return &LoginLogApi{service: loginLogService}
+}
+
+// List 获取登录日志列表
+// @Summary 获取登录日志列表
+// @Description 分页查询管理员与业务用户的登录、二次验证及修改密码记录（包含失败的尝试），可按账号、IP、结果及时间范围筛选
+// @Tags 登录日志
+// @Accept json
+// @Produce json
+// @Param req body auth.ListLoginLogRequest true "请求参数，包含分页信息和筛选条件"
+// @Success 200 {object} auth.ListLoginLogResponse "成功返回登录日志列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如时间格式不正确"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/loginLog [get]
+func (api *LoginLogApi) List(ctx *gin.Context, req *auth.ListLoginLogRequest) (res *auth.ListLoginLogResponse, err error) {
+	res, err = api.service.List(ctx, req)
+	return
+}
+
+// Export 导出登录日志
+// @Summary 导出登录日志
+// @Description 按筛选条件将登录日志导出为 CSV 或 XLSX 文件，按发生时间倒序，单次最多导出 100000 条
+// @Description 可选的列：id、subjectType、event、identifier、accountId、clientIp、userAgent、resultCode、createdAt
+// @Tags 登录日志
+// @Accept json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param req body auth.ExportLoginLogRequest true "请求参数，包含筛选条件、文件格式和导出的列"
+// @Success 200 {file} file "CSV 或 XLSX 文件"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如时间格式不正确、导出的列不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/loginLog/export [get]
+func (api *LoginLogApi) Export(ctx *gin.Context, req *auth.ExportLoginLogRequest) error {
+	ctx.Header("Content-Type", utils.SpreadsheetContentType(req.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", utils.SpreadsheetFileName("login_logs", req.Format)))
+	return api.service.Export(ctx, req, ctx.Writer)
+}
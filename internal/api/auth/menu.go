@@ -33,3 +33,99 @@ func (api *MenuApi) MenuTree(ctx *gin.Context, _ *dto.Empty) (res *auth.MenuTree
 	res = &auth.MenuTreeResponse{Data: data}
 	return
 }
+
+// Add 新增菜单
+// @Summary 新增菜单
+// @Description 新增一个菜单，不指定父菜单时为顶级菜单，不指定排序时排在同级菜单的最后
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param req body auth.AddMenuRequest true "请求参数，包含菜单名称、父菜单、排序和状态"
+// @Success 200 {object} dto.Empty "成功新增菜单，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如父菜单不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库写入出错等情况"
+// @Router /auth/menu [post]
+func (api *MenuApi) Add(ctx *gin.Context, req *auth.AddMenuRequest) (res *dto.Empty, err error) {
+	err = api.service.Add(ctx, req)
+	return
+}
+
+// Edit 编辑菜单
+// @Summary 编辑菜单
+// @Description 修改菜单名称，调整父菜单与排序请使用移动和排序接口
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param req body auth.EditMenuRequest true "请求参数，包含菜单ID和新的菜单名称"
+// @Success 200 {object} dto.Empty "成功编辑菜单，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如菜单不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/menu [put]
+func (api *MenuApi) Edit(ctx *gin.Context, req *auth.EditMenuRequest) (res *dto.Empty, err error) {
+	err = api.service.Edit(ctx, req)
+	return
+}
+
+// Del 删除菜单
+// @Summary 删除菜单
+// @Description 删除菜单。非级联删除时菜单下存在子菜单或接口则拒绝删除；级联删除时一并删除全部子孙菜单及其接口，并收回角色对这些接口的授权
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param req body auth.DelMenuRequest true "请求参数，包含菜单ID以及是否级联删除"
+// @Success 200 {object} dto.Empty "成功删除菜单，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如菜单不存在或菜单下存在子菜单、接口"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库删除出错等情况"
+// @Router /auth/menu [delete]
+func (api *MenuApi) Del(ctx *gin.Context, req *auth.DelMenuRequest) (res *dto.Empty, err error) {
+	err = api.service.Delete(ctx, req)
+	return
+}
+
+// UpdateStatus 启用或禁用菜单
+// @Summary 启用或禁用菜单
+// @Description 修改菜单的启用状态
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param req body auth.UpdateMenuStatusRequest true "请求参数，包含菜单ID和目标状态"
+// @Success 200 {object} dto.Empty "成功修改菜单状态，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如菜单不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/menu/status [put]
+func (api *MenuApi) UpdateStatus(ctx *gin.Context, req *auth.UpdateMenuStatusRequest) (res *dto.Empty, err error) {
+	err = api.service.UpdateStatus(ctx, req)
+	return
+}
+
+// Move 移动菜单
+// @Summary 移动菜单
+// @Description 将菜单连同其子孙菜单移动到新的父菜单下，不能移动到自身或其子孙菜单下
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param req body auth.MoveMenuRequest true "请求参数，包含菜单ID、新的父菜单ID和排序"
+// @Success 200 {object} dto.Empty "成功移动菜单，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如菜单或父菜单不存在、移动会形成环"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/menu/move [put]
+func (api *MenuApi) Move(ctx *gin.Context, req *auth.MoveMenuRequest) (res *dto.Empty, err error) {
+	err = api.service.Move(ctx, req)
+	return
+}
+
+// Sort 批量调整同级菜单顺序
+// @Summary 批量调整同级菜单顺序
+// @Description 按列表顺序重新设置同一父菜单下全部子菜单的排序值
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param req body auth.SortMenuRequest true "请求参数，包含父菜单ID和按新顺序排列的菜单ID列表"
+// @Success 200 {object} dto.Empty "成功调整顺序，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如列表与同级菜单不一致"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/menu/sort [put]
+func (api *MenuApi) Sort(ctx *gin.Context, req *auth.SortMenuRequest) (res *dto.Empty, err error) {
+	err = api.service.Sort(ctx, req)
+	return
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxApi 结构体，保存服务实例
+type OutboxApi struct {
+	service *service.OutboxService
+}
+
+// NewOutboxApi 创建 OutboxApi 实例并初始化依赖项
+func NewOutboxApi() *OutboxApi {
+	outboxService := service.NewOutboxService()
+	return &OutboxApi{service: outboxService}
+}
+
+// List 获取事件发件箱列表
+// @Summary 获取事件发件箱列表
+// @Description 分页查询权限变更事件的投递记录，可按投递状态和事件类型筛选，同时返回待投递、已投递、死信的数量
+// @Tags 事件发件箱
+// @Accept json
+// @Produce json
+// @Param req body auth.ListOutboxRequest true "请求参数，包含分页信息和筛选条件"
+// @Success 200 {object} auth.ListOutboxResponse "成功返回发件箱记录列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如投递状态不正确"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/outbox [get]
+func (api *OutboxApi) List(ctx *gin.Context, req *auth.ListOutboxRequest) (res *auth.ListOutboxResponse, err error) {
+	res, err = api.service.List(ctx, req)
+	return
+}
+
+// Retry 重新投递事件
+// @Summary 重新投递事件
+// @Description 将待投递或已进入死信的事件重新放回投递队列，尝试次数清零，由投递任务在下次运行时立即投递
+// @Tags 事件发件箱
+// @Accept json
+// @Produce json
+// @Param req body auth.RetryOutboxRequest true "请求参数，包含事件ID"
+// @Success 200 {object} dto.Empty "成功放回投递队列，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或事件不存在、已投递成功"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/outbox/retry [put]
+func (api *OutboxApi) Retry(ctx *gin.Context, req *auth.RetryOutboxRequest) (res *dto.Empty, err error) {
+	err = api.service.Retry(ctx, req)
+	return
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PathApi struct {
+	service *service.PathService
+}
+
+// NewPathApi 创建 PathApi 实例并初始化依赖项
+func NewPathApi(routes func() gin.RoutesInfo) *PathApi {
+	service := service.NewPathService(routes)
+	return &PathApi{service: service}
+}
+
+// List 获取接口列表
+// @Summary 获取接口列表
+// @Description 分页查询接口，可按所属菜单、路由、请求方法和失效状态筛选
+// @Tags 接口管理
+// @Accept json
+// @Produce json
+// @Param req body auth.ListPathRequest true "请求参数，包含分页信息和筛选条件"
+// @Success 200 {object} auth.ListPathResponse "成功返回接口列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/path [get]
+func (api *PathApi) List(ctx *gin.Context, req *auth.ListPathRequest) (res *auth.ListPathResponse, err error) {
+	res, err = api.service.List(ctx, req)
+	return
+}
+
+// Add 新增接口
+// @Summary 新增接口
+// @Description 在指定菜单下登记一个接口，同一路由与请求方法只能登记一次
+// @Tags 接口管理
+// @Accept json
+// @Produce json
+// @Param req body auth.AddPathRequest true "请求参数，包含所属菜单、路由、请求方法和描述"
+// @Success 200 {object} dto.Empty "成功新增接口，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如菜单不存在或接口已存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库写入出错等情况"
+// @Router /auth/path [post]
+func (api *PathApi) Add(ctx *gin.Context, req *auth.AddPathRequest) (res *dto.Empty, err error) {
+	err = api.service.Add(ctx, req)
+	return
+}
+
+// Edit 编辑接口
+// @Summary 编辑接口
+// @Description 修改接口的所属菜单、路由、请求方法和描述，角色对该接口的授权保持不变
+// @Tags 接口管理
+// @Accept json
+// @Produce json
+// @Param req body auth.EditPathRequest true "请求参数，包含接口ID以及新的接口信息"
+// @Success 200 {object} dto.Empty "成功编辑接口，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如接口或菜单不存在、接口已存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/path [put]
+func (api *PathApi) Edit(ctx *gin.Context, req *auth.EditPathRequest) (res *dto.Empty, err error) {
+	err = api.service.Edit(ctx, req)
+	return
+}
+
+// Del 删除接口
+// @Summary 删除接口
+// @Description 删除接口，并收回角色对该接口的授权
+// @Tags 接口管理
+// @Accept json
+// @Produce json
+// @Param req body auth.DelPathRequest true "请求参数，包含接口ID"
+// @Success 200 {object} dto.Empty "成功删除接口，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如接口不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库删除出错等情况"
+// @Router /auth/path [delete]
+func (api *PathApi) Del(ctx *gin.Context, req *auth.DelPathRequest) (res *dto.Empty, err error) {
+	err = api.service.Delete(ctx, req)
+	return
+}
+
+// Sync 同步路由到接口表
+// @Summary 同步路由到接口表
+// @Description 遍历服务已注册的路由：指定前缀下未登记的路由新增到指定菜单，已不存在的路由标记为失效，重新注册的路由清除失效标记。dryRun 为 true 时只返回差异报告，不写入数据库
+// @Tags 接口管理
+// @Accept json
+// @Produce json
+// @Param req body auth.SyncPathRequest true "请求参数，包含是否仅预览、新增接口所属菜单和路由前缀"
+// @Success 200 {object} auth.SyncPathResponse "成功返回同步差异报告"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如需要新增接口但未指定菜单或菜单不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库读写出错等情况"
+// @Router /auth/path/sync [post]
+func (api *PathApi) Sync(ctx *gin.Context, req *auth.SyncPathRequest) (res *auth.SyncPathResponse, err error) {
+	res, err = api.service.Sync(ctx, req)
+	return
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyApi struct {
+	service *service.PolicyService
+}
+
+// NewPolicyApi 创建 PolicyApi 实例并初始化依赖项
+func NewPolicyApi() *PolicyApi {
+	service := service.NewPolicyService()
+	return &PolicyApi{service: service}
+}
+
+// Decide 权限决策
+// @Summary 权限决策
+// @Description 供下游业务服务查询用户是否可以访问指定接口，返回允许或拒绝以及授予权限的角色；需在请求头 X-Service-Token 中携带服务令牌
+// @Tags 权限决策
+// @Accept json
+// @Produce json
+// @Param X-Service-Token header string true "服务令牌"
+// @Param req body auth.DecidePolicyRequest true "请求参数，包含用户ID、请求方法和路由"
+// @Success 200 {object} auth.PolicyDecision "成功返回决策结果"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误"
+// @Failure 401 {object} dto.ErrorResponse "服务令牌缺失或无效"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /policy/decision [post]
+func (api *PolicyApi) Decide(ctx *gin.Context, req *auth.DecidePolicyRequest) (res *auth.PolicyDecision, err error) {
+	res, err = api.service.Decide(ctx, req)
+	return
+}
+
+// BatchDecide 批量权限决策
+// @Summary 批量权限决策
+// @Description 一次查询多个用户与接口的决策结果，结果顺序与请求一致，单次最多100条；需在请求头 X-Service-Token 中携带服务令牌
+// @Tags 权限决策
+// @Accept json
+// @Produce json
+// @Param X-Service-Token header string true "服务令牌"
+// @Param req body auth.BatchDecidePolicyRequest true "请求参数，包含决策列表"
+// @Success 200 {object} auth.BatchDecidePolicyResponse "成功返回决策结果列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误"
+// @Failure 401 {object} dto.ErrorResponse "服务令牌缺失或无效"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /policy/decision/batch [post]
+func (api *PolicyApi) BatchDecide(ctx *gin.Context, req *auth.BatchDecidePolicyRequest) (res *auth.BatchDecidePolicyResponse, err error) {
+	res, err = api.service.BatchDecide(ctx, req)
+	return
+}
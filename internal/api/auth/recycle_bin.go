@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecycleBinApi 结构体，保存服务实例
+type RecycleBinApi struct {
+	service *service.RecycleBinService
+}
+
+// NewRecycleBinApi 创建 RecycleBinApi 实例并初始化依赖项
+func NewRecycleBinApi() *RecycleBinApi {
+	recycleBinService := service.NewRecycleBinService()
+	return &RecycleBinApi{service: recycleBinService}
+}
+
+// List 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 分页查询已删除的用户、管理员、角色、菜单或接口，按删除时间倒序，启用自动清除时返回到期时间
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param req body auth.ListRecycleBinRequest true "请求参数，包含分页信息和记录类型"
+// @Success 200 {object} auth.ListRecycleBinResponse "成功返回回收站记录列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如记录类型不正确"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/recycleBin [get]
+func (api *RecycleBinApi) List(ctx *gin.Context, req *auth.ListRecycleBinRequest) (res *auth.ListRecycleBinResponse, err error) {
+	res, err = api.service.List(ctx, req)
+	return
+}
+
+// Restore 恢复回收站记录
+// @Summary 恢复回收站记录
+// @Description 恢复已删除的记录。用户、管理员、角色的用户名、邮箱、手机号或名称已被占用时拒绝恢复，
+// @Description 并按删除时的状态重建角色分配、角色路径和父角色（已删除的角色和接口除外），重新计算受影响用户的权限；
+// @Description 菜单和接口要求所属的父菜单或菜单未被删除
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param req body auth.RestoreRecycleBinRequest true "请求参数，包含记录类型和ID"
+// @Success 200 {object} dto.Empty "成功恢复记录，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或记录不在回收站中、唯一字段已被占用、父菜单已被删除"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/recycleBin/restore [put]
+func (api *RecycleBinApi) Restore(ctx *gin.Context, req *auth.RestoreRecycleBinRequest) (res *dto.Empty, err error) {
+	err = api.service.Restore(ctx, req)
+	return
+}
+
+// Purge 永久删除回收站记录
+// @Summary 永久删除回收站记录
+// @Description 永久删除已删除的记录及其关联数据，不可恢复；菜单下仍有子菜单或接口（包括已删除的）时拒绝删除，须先永久删除它们
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param req body auth.PurgeRecycleBinRequest true "请求参数，包含记录类型和ID"
+// @Success 200 {object} dto.Empty "成功永久删除记录，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或记录不在回收站中"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库删除出错等情况"
+// @Router /auth/recycleBin [delete]
+func (api *RecycleBinApi) Purge(ctx *gin.Context, req *auth.PurgeRecycleBinRequest) (res *dto.Empty, err error) {
+	err = api.service.Purge(ctx, req)
+	return
+}
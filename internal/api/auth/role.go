@@ -4,6 +4,8 @@ import (
 	"ByteScience-WAM-Admin/internal/model/dto"
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/utils"
+	"fmt"
 	"github.com/gin-gonic/gin"
 )
 
@@ -97,3 +99,22 @@ func (api *RoleApi) Del(ctx *gin.Context, req *auth.DelRoleRequest) (res *dto.Em
 	err = api.service.Delete(ctx, req)
 	return
 }
+
+// Export 导出角色
+// @Summary 导出角色
+// @Description 按与列表查询相同的筛选条件将角色导出为 CSV 或 XLSX 文件，按创建时间倒序，不受分页限制。paths 列为角色直接拥有的接口（请求方法和路由路径），以分号分隔。
+// @Description 可选的列：id、name、description、status、isBuiltin、paths、createdAt、updatedAt
+// @Tags 角色管理
+// @Accept json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param req body auth.ExportRoleRequest true "请求参数，包含筛选条件、文件格式和导出的列"
+// @Success 200 {file} file "CSV 或 XLSX 文件"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如导出的列不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/role/export [get]
+func (api *RoleApi) Export(ctx *gin.Context, req *auth.ExportRoleRequest) error {
+	ctx.Header("Content-Type", utils.SpreadsheetContentType(req.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", utils.SpreadsheetFileName("roles", req.Format)))
+	return api.service.Export(ctx, req, ctx.Writer)
+}
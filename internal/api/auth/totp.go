@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TotpApi struct {
+	service *service.TotpService
+}
+
+// NewTotpApi 创建 TotpApi 实例并初始化依赖项
+func NewTotpApi() *TotpApi {
+	service := service.NewTotpService()
+	return &TotpApi{service: service}
+}
+
+// Enroll 绑定TOTP
+// @Summary 绑定TOTP
+// @Description 为当前管理员生成新的 TOTP 密钥及 otpauth 绑定地址，需调用启用接口校验验证码后才会生效
+// @Tags 二次验证
+// @Accept json
+// @Produce json
+// @Param _ body dto.Empty true "此参数为空对象，当前操作无需额外传入请求参数"
+// @Success 200 {object} auth.TotpEnrollResponse "成功生成密钥，返回密钥及绑定地址"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或 TOTP 已启用、当前账号不是管理员等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/admin/totp/enroll [post]
+func (api *TotpApi) Enroll(ctx *gin.Context, _ *dto.Empty) (res *auth.TotpEnrollResponse, err error) {
+	res, err = api.service.Enroll(ctx, ctx.GetString("subjectType"), ctx.GetString("userId"))
+	return
+}
+
+// Activate 启用TOTP
+// @Summary 启用TOTP
+// @Description 校验身份验证器 App 生成的验证码，通过后启用 TOTP 并返回一次性恢复码（仅返回一次）
+// @Tags 二次验证
+// @Accept json
+// @Produce json
+// @Param req body auth.TotpCodeRequest true "请求参数，包含6位验证码"
+// @Success 200 {object} auth.TotpRecoveryCodesResponse "成功启用，返回恢复码"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或未绑定 TOTP、验证码无效等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/admin/totp/activate [post]
+func (api *TotpApi) Activate(ctx *gin.Context, req *auth.TotpCodeRequest) (res *auth.TotpRecoveryCodesResponse, err error) {
+	res, err = api.service.Activate(ctx, ctx.GetString("subjectType"), ctx.GetString("userId"), req)
+	return
+}
+
+// Disable 停用TOTP
+// @Summary 停用TOTP
+// @Description 校验验证码或恢复码后停用 TOTP，同时清除密钥与全部恢复码
+// @Tags 二次验证
+// @Accept json
+// @Produce json
+// @Param req body auth.TotpCodeRequest true "请求参数，包含6位验证码或恢复码"
+// @Success 200 {object} dto.Empty "成功停用，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或 TOTP 未启用、验证码无效等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/admin/totp [delete]
+func (api *TotpApi) Disable(ctx *gin.Context, req *auth.TotpCodeRequest) (res *dto.Empty, err error) {
+	err = api.service.Disable(ctx, ctx.GetString("subjectType"), ctx.GetString("userId"), req)
+	return
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 校验验证码或恢复码后重新生成恢复码，旧的恢复码全部失效
+// @Tags 二次验证
+// @Accept json
+// @Produce json
+// @Param req body auth.TotpCodeRequest true "请求参数，包含6位验证码或恢复码"
+// @Success 200 {object} auth.TotpRecoveryCodesResponse "成功生成，返回新的恢复码"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或 TOTP 未启用、验证码无效等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/admin/totp/recoveryCodes [post]
+func (api *TotpApi) RegenerateRecoveryCodes(ctx *gin.Context, req *auth.TotpCodeRequest) (res *auth.TotpRecoveryCodesResponse, err error) {
+	res, err = api.service.RegenerateRecoveryCodes(ctx, ctx.GetString("subjectType"), ctx.GetString("userId"), req)
+	return
+}
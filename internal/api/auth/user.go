@@ -4,6 +4,8 @@ import (
 	"ByteScience-WAM-Admin/internal/model/dto"
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/utils"
+	"fmt"
 	"github.com/gin-gonic/gin"
 )
 
@@ -112,3 +114,89 @@ func (api *UserApi) ResetPassword(ctx *gin.Context, req *auth.ResetPasswordReque
 	err = api.service.ResetPassword(ctx, req)
 	return
 }
+
+// Import 批量导入用户
+// @Summary 批量导入用户
+// @Description 上传 CSV 或 XLSX 文件批量创建用户，逐行校验并返回每一行的处理结果；dryRun 为 true 时只校验不写入
+// @Tags 用户管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "导入文件，第一行为表头：userName、password、nickname、email、phone、status、remark、roles（多个角色名称以分号分隔）"
+// @Param dryRun formData bool false "是否仅校验"
+// @Success 200 {object} auth.ImportUserResponse "导入结果"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，如文件格式不支持、文件或行数超出限制等"
+// @Failure 413 {object} dto.ErrorResponse "请求体超出大小限制"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误"
+// @Router /auth/user/import [post]
+func (api *UserApi) Import(ctx *gin.Context, req *auth.ImportUserRequest) (res *auth.ImportUserResponse, err error) {
+	res, err = api.service.Import(ctx, req)
+	return
+}
+
+// Export 导出用户
+// @Summary 导出用户
+// @Description 按与列表查询相同的筛选条件将用户导出为 CSV 或 XLSX 文件，按创建时间倒序，不受分页限制。roles 列为当前有效的角色名称，以分号分隔。
+// @Description 可选的列：id、userName、nickname、email、phone、status、remark、roles、lastLoginAt、createdAt、updatedAt
+// @Tags 用户管理
+// @Accept json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param req body auth.ExportUserRequest true "请求参数，包含筛选条件、文件格式和导出的列"
+// @Success 200 {file} file "CSV 或 XLSX 文件"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，例如导出的列不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/user/export [get]
+func (api *UserApi) Export(ctx *gin.Context, req *auth.ExportUserRequest) error {
+	ctx.Header("Content-Type", utils.SpreadsheetContentType(req.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", utils.SpreadsheetFileName("users", req.Format)))
+	return api.service.Export(ctx, req, ctx.Writer)
+}
+
+// PersonalData 导出用户个人数据
+// @Summary 导出用户个人数据
+// @Description 以 JSON 导出系统保存的指定用户的全部数据：用户资料（不含密码）、角色、权限、登录记录，以及以该用户为操作对象或由该用户操作的审计日志。已删除的用户也可以导出
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param req body auth.PersonalDataRequest true "请求参数，包含用户ID"
+// @Success 200 {object} auth.PersonalDataResponse "成功返回用户的全部个人数据"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或用户不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/user/personalData [get]
+func (api *UserApi) PersonalData(ctx *gin.Context, req *auth.PersonalDataRequest) (res *auth.PersonalDataResponse, err error) {
+	res, err = api.service.PersonalData(ctx, req)
+	return
+}
+
+// ErasureToken 申请擦除确认令牌
+// @Summary 申请擦除确认令牌
+// @Description 申请擦除指定用户个人数据所需的确认令牌。令牌只返回一次，只能由申请人在有效期内使用一次，重新申请会使之前的令牌失效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param req body auth.ErasureTokenRequest true "请求参数，包含要擦除的用户ID"
+// @Success 200 {object} auth.ErasureTokenResponse "成功返回确认令牌及其过期时间"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或用户不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是令牌保存失败等情况"
+// @Router /auth/user/erasureToken [post]
+func (api *UserApi) ErasureToken(ctx *gin.Context, req *auth.ErasureTokenRequest) (res *auth.ErasureTokenResponse, err error) {
+	res, err = api.service.ErasureToken(ctx, req)
+	return
+}
+
+// Erase 擦除用户个人数据
+// @Summary 擦除用户个人数据
+// @Description 使用确认令牌擦除指定用户的个人信息：用户名替换为 erased-<用户ID>，昵称、邮箱、手机号、备注置空，禁用用户并使其登录会话失效；
+// @Description 登录日志、审计日志、事件发件箱和 Webhook 投递记录中的个人信息同时被匿名化，历史密码被清除。用户ID、角色分配和审计记录等关联数据保持不变，擦除操作本身记录审计日志
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param req body auth.EraseUserRequest true "请求参数，包含用户ID和确认令牌"
+// @Success 200 {object} dto.Empty "成功擦除用户个人数据，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或用户不存在、确认令牌无效或已过期"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/user/erase [put]
+func (api *UserApi) Erase(ctx *gin.Context, req *auth.EraseUserRequest) (res *dto.Empty, err error) {
+	err = api.service.Erase(ctx, req)
+	return
+}
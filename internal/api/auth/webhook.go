@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookApi 结构体，保存服务实例
+type WebhookApi struct {
+	service *service.WebhookService
+}
+
+// NewWebhookApi 创建 WebhookApi 实例并初始化依赖项
+func NewWebhookApi() *WebhookApi {
+	webhookService := service.NewWebhookService()
+	return &WebhookApi{service: webhookService}
+}
+
+// List 获取 Webhook 订阅列表
+// @Summary 获取 Webhook 订阅列表
+// @Description 分页查询 Webhook 订阅，可按状态筛选，不返回签名密钥
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.ListWebhookRequest true "请求参数，包含分页信息和筛选条件"
+// @Success 200 {object} auth.ListWebhookResponse "成功返回订阅列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/webhook [get]
+func (api *WebhookApi) List(ctx *gin.Context, req *auth.ListWebhookRequest) (res *auth.ListWebhookResponse, err error) {
+	res, err = api.service.List(ctx, req)
+	return
+}
+
+// Add 新增 Webhook 订阅
+// @Summary 新增 Webhook 订阅
+// @Description 订阅用户和角色的变更事件，事件以 JSON 请求体 POST 到回调地址，请求头 X-Webhook-Signature 为使用签名密钥计算的 HMAC-SHA256 签名；未指定签名密钥时自动生成，密钥只在新增和更换签名密钥时返回
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.AddWebhookRequest true "请求参数，包含名称、回调地址、订阅的事件类型及签名密钥"
+// @Success 200 {object} auth.AddWebhookResponse "成功新增订阅，返回订阅ID和签名密钥"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，如回调地址不是 http 或 https 地址、事件类型不正确等"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库插入出错等情况"
+// @Router /auth/webhook [post]
+func (api *WebhookApi) Add(ctx *gin.Context, req *auth.AddWebhookRequest) (res *auth.AddWebhookResponse, err error) {
+	res, err = api.service.Add(ctx, req)
+	return
+}
+
+// Edit 编辑 Webhook 订阅
+// @Summary 编辑 Webhook 订阅
+// @Description 修改订阅的名称、回调地址、事件类型及状态，签名密钥通过更换签名密钥接口修改；禁用后不再生成新的投递记录，尚未投递的记录标记为投递失败
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.EditWebhookRequest true "请求参数，包含订阅ID及修改后的订阅信息"
+// @Success 200 {object} dto.Empty "成功编辑订阅，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或订阅不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/webhook [put]
+func (api *WebhookApi) Edit(ctx *gin.Context, req *auth.EditWebhookRequest) (res *dto.Empty, err error) {
+	err = api.service.Edit(ctx, req)
+	return
+}
+
+// RotateSecret 更换 Webhook 签名密钥
+// @Summary 更换 Webhook 签名密钥
+// @Description 更换订阅的签名密钥，未指定新密钥时自动生成，新密钥只在此时返回；尚未投递成功的记录同样使用新密钥签名
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.RotateWebhookSecretRequest true "请求参数，包含订阅ID及可选的新签名密钥"
+// @Success 200 {object} auth.RotateWebhookSecretResponse "成功更换签名密钥，返回新的签名密钥"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或订阅不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/webhook/rotateSecret [put]
+func (api *WebhookApi) RotateSecret(ctx *gin.Context, req *auth.RotateWebhookSecretRequest) (res *auth.RotateWebhookSecretResponse, err error) {
+	res, err = api.service.RotateSecret(ctx, req)
+	return
+}
+
+// Del 删除 Webhook 订阅
+// @Summary 删除 Webhook 订阅
+// @Description 删除订阅，尚未投递的记录标记为投递失败，投递记录保留以供查询
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.DelWebhookRequest true "请求参数，包含订阅ID"
+// @Success 200 {object} dto.Empty "成功删除订阅，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或订阅不存在"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/webhook [delete]
+func (api *WebhookApi) Del(ctx *gin.Context, req *auth.DelWebhookRequest) (res *dto.Empty, err error) {
+	err = api.service.Delete(ctx, req)
+	return
+}
+
+// ListDeliveries 获取 Webhook 投递记录
+// @Summary 获取 Webhook 投递记录
+// @Description 分页查询 Webhook 的投递记录，包括尝试次数、最近一次回调的响应状态码、耗时及失败原因，不保存响应内容
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.ListWebhookDeliveryRequest true "请求参数，包含分页信息和筛选条件"
+// @Success 200 {object} auth.ListWebhookDeliveryResponse "成功返回投递记录列表"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库查询出错等情况"
+// @Router /auth/webhook/delivery [get]
+func (api *WebhookApi) ListDeliveries(ctx *gin.Context, req *auth.ListWebhookDeliveryRequest) (res *auth.ListWebhookDeliveryResponse, err error) {
+	res, err = api.service.ListDeliveries(ctx, req)
+	return
+}
+
+// Redeliver 重新投递 Webhook
+// @Summary 重新投递 Webhook
+// @Description 将投递记录重新放回投递队列，尝试次数清零，由回调任务在下次运行时立即使用订阅当前的回调地址和签名密钥投递
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param req body auth.RedeliverWebhookRequest true "请求参数，包含投递ID"
+// @Success 200 {object} dto.Empty "成功放回投递队列，返回空对象表示操作成功"
+// @Failure 400 {object} dto.ErrorResponse "请求参数错误，或投递记录不存在、订阅已删除或已禁用"
+// @Failure 500 {object} dto.ErrorResponse "服务器内部错误，可能是数据库更新出错等情况"
+// @Router /auth/webhook/redeliver [put]
+func (api *WebhookApi) Redeliver(ctx *gin.Context, req *auth.RedeliverWebhookRequest) (res *dto.Empty, err error) {
+	err = api.service.Redeliver(ctx, req)
+	return
+}
@@ -0,0 +1,87 @@
+package scim
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"ByteScience-WAM-Admin/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListGroups 查询组列表，支持 filter、startIndex、count 参数，excludedAttributes=members 时不返回成员
+func (api *ScimApi) ListGroups(ctx *gin.Context) {
+	query, ok := bindQuery(ctx)
+	if !ok {
+		return
+	}
+	res, err := api.service.ListGroups(ctx, query, excludesMembers(query.ExcludedAttributes))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	utils.SendScimResponse(ctx, http.StatusOK, res)
+}
+
+// GetGroup 获取组，excludedAttributes=members 时不返回成员
+func (api *ScimApi) GetGroup(ctx *gin.Context) {
+	res, err := api.service.GetGroup(ctx, ctx.Param("id"))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	if excludesMembers(ctx.Query("excludedAttributes")) {
+		res.Members = nil
+	}
+	sendResource(ctx, http.StatusOK, res, res.Meta)
+}
+
+// CreateGroup 新增组
+func (api *ScimApi) CreateGroup(ctx *gin.Context) {
+	var req scim.Group
+	if !bindBody(ctx, &req) {
+		return
+	}
+	res, err := api.service.CreateGroup(ctx, &req)
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusCreated, res, res.Meta)
+}
+
+// ReplaceGroup 替换组，请求头 If-Match 与资源版本不一致时返回 412
+func (api *ScimApi) ReplaceGroup(ctx *gin.Context) {
+	var req scim.Group
+	if !bindBody(ctx, &req) {
+		return
+	}
+	res, err := api.service.ReplaceGroup(ctx, ctx.Param("id"), &req, ctx.GetHeader("If-Match"))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusOK, res, res.Meta)
+}
+
+// PatchGroup 按 PATCH 操作修改组，请求头 If-Match 与资源版本不一致时返回 412
+func (api *ScimApi) PatchGroup(ctx *gin.Context) {
+	var req scim.PatchRequest
+	if !bindBody(ctx, &req) {
+		return
+	}
+	res, err := api.service.PatchGroup(ctx, ctx.Param("id"), &req, ctx.GetHeader("If-Match"))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusOK, res, res.Meta)
+}
+
+// DeleteGroup 删除组，请求头 If-Match 与资源版本不一致时返回 412
+func (api *ScimApi) DeleteGroup(ctx *gin.Context) {
+	if err := api.service.DeleteGroup(ctx, ctx.Param("id"), ctx.GetHeader("If-Match")); err != nil {
+		sendError(ctx, err)
+		return
+	}
+	utils.SendScimResponse(ctx, http.StatusNoContent, nil)
+}
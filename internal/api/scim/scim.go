@@ -0,0 +1,164 @@
+package scim
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScimApi SCIM 2.0 接口，请求和响应均使用 SCIM 格式（application/scim+json），不使用统一响应结构
+type ScimApi struct {
+	service *service.ScimService
+}
+
+// NewScimApi 创建 ScimApi 实例并初始化依赖项
+func NewScimApi() *ScimApi {
+	service := service.NewScimService()
+	return &ScimApi{service: service}
+}
+
+// ServiceProviderConfig 返回服务端支持的 SCIM 特性
+func (api *ScimApi) ServiceProviderConfig(ctx *gin.Context) {
+	baseURL := utils.ScimBaseURL(ctx)
+	utils.SendScimResponse(ctx, http.StatusOK, gin.H{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": service.ScimMaxResults},
+		"changePassword": gin.H{"supported": true},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": true},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "Authentication with a bearer token issued to the SCIM client",
+			"primary":     true,
+		}},
+		"meta": gin.H{"resourceType": "ServiceProviderConfig", "location": baseURL + "/ServiceProviderConfig"},
+	})
+}
+
+// ResourceTypes 返回支持的资源类型
+func (api *ScimApi) ResourceTypes(ctx *gin.Context) {
+	baseURL := utils.ScimBaseURL(ctx)
+	resources := []interface{}{
+		gin.H{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeUser,
+			"name":     scim.ResourceTypeUser,
+			"endpoint": "/Users",
+			"schema":   scim.SchemaUser,
+			"meta":     gin.H{"resourceType": "ResourceType", "location": baseURL + "/ResourceTypes/" + scim.ResourceTypeUser},
+		},
+		gin.H{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeGroup,
+			"name":     scim.ResourceTypeGroup,
+			"endpoint": "/Groups",
+			"schema":   scim.SchemaGroup,
+			"meta":     gin.H{"resourceType": "ResourceType", "location": baseURL + "/ResourceTypes/" + scim.ResourceTypeGroup},
+		},
+	}
+	utils.SendScimResponse(ctx, http.StatusOK, &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: int64(len(resources)),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// sendResource 返回单个资源，并设置 ETag 响应头；新增资源时额外设置 Location 响应头
+// GET 请求的 If-None-Match 请求头与资源版本一致时返回 304。
+func sendResource(ctx *gin.Context, statusCode int, resource interface{}, meta *scim.Meta) {
+	if meta.Version != "" {
+		ctx.Header("ETag", meta.Version)
+		if ctx.Request.Method == http.MethodGet && versionMatches(ctx.GetHeader("If-None-Match"), meta.Version) {
+			utils.SendScimResponse(ctx, http.StatusNotModified, nil)
+			return
+		}
+	}
+	if statusCode == http.StatusCreated {
+		ctx.Header("Location", meta.Location)
+	}
+	utils.SendScimResponse(ctx, statusCode, resource)
+}
+
+// versionMatches 判断 If-None-Match 请求头是否包含资源版本
+func versionMatches(header, version string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(version, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// bindQuery 绑定列表查询参数
+func bindQuery(ctx *gin.Context) (*scim.ListQuery, bool) {
+	var query scim.ListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, err.Error()))
+		return nil, false
+	}
+	return &query, true
+}
+
+// bindBody 解析请求体
+func bindBody(ctx *gin.Context, target interface{}) bool {
+	if err := json.NewDecoder(ctx.Request.Body).Decode(target); err != nil {
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidSyntax, "Invalid request body: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+// excludesMembers 判断 excludedAttributes 是否包含 members
+func excludesMembers(excludedAttributes string) bool {
+	for _, attr := range strings.Split(excludedAttributes, ",") {
+		if strings.EqualFold(utils.NormalizeScimAttr(strings.TrimSpace(attr)), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+// sendError 将服务层错误转换为 SCIM 错误响应
+func sendError(ctx *gin.Context, err error) {
+	var scimErr *utils.ScimError
+	if errors.As(err, &scimErr) {
+		utils.SendScimError(ctx, scimErr)
+		return
+	}
+
+	var bizErr *utils.BusinessError
+	if !errors.As(err, &bizErr) {
+		logger.Logger.Errorf("[Scim] Unexpected error: %v", err)
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusInternalServerError, "", utils.ErrorMessages[utils.InternalError]))
+		return
+	}
+
+	switch bizErr.Code {
+	case utils.UsernameAlreadyExistsCode, utils.EmailAlreadyExistsCode, utils.PhoneAlreadyExistsCode,
+		utils.RoleNameAlreadyExistsCode:
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusConflict, scim.ErrorUniqueness, bizErr.Message))
+	case utils.UserNotFoundCode, utils.RoleNotFoundCode:
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusNotFound, "", bizErr.Message))
+	case utils.RoleBuiltinProtectedCode:
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusBadRequest, scim.ErrorMutability, bizErr.Message))
+	case utils.PasswordTooWeakCode, utils.PasswordReusedCode, utils.RoleAssignmentInvalidCode:
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, bizErr.Message))
+	case utils.RoleBuiltinLastAdminCode:
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusConflict, "", bizErr.Message))
+	default:
+		utils.SendScimError(ctx, utils.NewScimError(http.StatusInternalServerError, "", bizErr.Message))
+	}
+}
@@ -0,0 +1,84 @@
+package scim
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"ByteScience-WAM-Admin/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListUsers 查询用户列表，支持 filter、startIndex、count 参数
+func (api *ScimApi) ListUsers(ctx *gin.Context) {
+	query, ok := bindQuery(ctx)
+	if !ok {
+		return
+	}
+	res, err := api.service.ListUsers(ctx, query)
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	utils.SendScimResponse(ctx, http.StatusOK, res)
+}
+
+// GetUser 获取用户
+func (api *ScimApi) GetUser(ctx *gin.Context) {
+	res, err := api.service.GetUser(ctx, ctx.Param("id"))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusOK, res, res.Meta)
+}
+
+// CreateUser 新增用户
+func (api *ScimApi) CreateUser(ctx *gin.Context) {
+	var req scim.User
+	if !bindBody(ctx, &req) {
+		return
+	}
+	res, err := api.service.CreateUser(ctx, &req)
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusCreated, res, res.Meta)
+}
+
+// ReplaceUser 替换用户，请求头 If-Match 与资源版本不一致时返回 412
+func (api *ScimApi) ReplaceUser(ctx *gin.Context) {
+	var req scim.User
+	if !bindBody(ctx, &req) {
+		return
+	}
+	res, err := api.service.ReplaceUser(ctx, ctx.Param("id"), &req, ctx.GetHeader("If-Match"))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusOK, res, res.Meta)
+}
+
+// PatchUser 按 PATCH 操作修改用户，请求头 If-Match 与资源版本不一致时返回 412
+func (api *ScimApi) PatchUser(ctx *gin.Context) {
+	var req scim.PatchRequest
+	if !bindBody(ctx, &req) {
+		return
+	}
+	res, err := api.service.PatchUser(ctx, ctx.Param("id"), &req, ctx.GetHeader("If-Match"))
+	if err != nil {
+		sendError(ctx, err)
+		return
+	}
+	sendResource(ctx, http.StatusOK, res, res.Meta)
+}
+
+// DeleteUser 删除用户，请求头 If-Match 与资源版本不一致时返回 412
+func (api *ScimApi) DeleteUser(ctx *gin.Context) {
+	if err := api.service.DeleteUser(ctx, ctx.Param("id"), ctx.GetHeader("If-Match")); err != nil {
+		sendError(ctx, err)
+		return
+	}
+	utils.SendScimResponse(ctx, http.StatusNoContent, nil)
+}
@@ -10,6 +10,7 @@ import (
 
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AdminDao 数据访问对象，封装角色相关操作
@@ -25,6 +26,11 @@ func (ad *AdminDao) Insert(ctx context.Context, admin *entity.Admins) error {
 	return db.Client.WithContext(ctx).Create(admin).Error
 }
 
+// InsertTx 在事务中插入管理员记录
+func (ad *AdminDao) InsertTx(ctx context.Context, tx *gorm.DB, admin *entity.Admins) error {
+	return tx.WithContext(ctx).Create(admin).Error
+}
+
 // GetByID 根据 ID 获取管理员
 func (ad *AdminDao) GetByID(ctx context.Context, id string) (*entity.Admins, error) {
 	var admin entity.Admins
@@ -40,9 +46,20 @@ func (ad *AdminDao) GetByID(ctx context.Context, id string) (*entity.Admins, err
 
 // GetByFields 根据 username, email, phone 获取管理员
 func (ad *AdminDao) GetByFields(ctx context.Context, username, email, phone string) (*entity.Admins, error) {
+	return ad.GetByFieldsTx(ctx, db.Client, username, email, phone)
+}
+
+// GetByFieldsForUpdateTx 在事务中根据 username, email, phone 获取管理员并加锁，直到事务结束
+// 锁定的索引范围同时阻塞并发写入相同用户名、邮箱、手机号的管理员，用于写入前的冲突检查
+func (ad *AdminDao) GetByFieldsForUpdateTx(ctx context.Context, tx *gorm.DB, username, email, phone string) (*entity.Admins, error) {
+	return ad.GetByFieldsTx(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}), username, email, phone)
+}
+
+// GetByFieldsTx 在事务中根据 username, email, phone 获取管理员
+func (ad *AdminDao) GetByFieldsTx(ctx context.Context, tx *gorm.DB, username, email, phone string) (*entity.Admins, error) {
 	// 构建查询条件
 	// 基础查询，确保 deleted_at 为 NULL
-	query := db.Client.WithContext(ctx).Model(&entity.Admins{}).
+	query := tx.WithContext(ctx).Model(&entity.Admins{}).
 		Where(entity.AdminsColumns.DeletedAt + " IS NULL")
 
 	// 创建一个切片来动态构建 OR 条件
@@ -89,6 +106,15 @@ func (ad *AdminDao) Update(ctx context.Context, id string, updates map[string]in
 		Error
 }
 
+// UpdateTx 在事务中更新管理员信息
+func (ad *AdminDao) UpdateTx(ctx context.Context, tx *gorm.DB, id string, updates map[string]interface{}) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Admins{}).
+		Where(entity.AdminsColumns.ID+" = ?", id).
+		Updates(updates).
+		Error
+}
+
 // SoftDeleteByID 软删除管理员记录
 func (ad *AdminDao) SoftDeleteByID(ctx context.Context, id string) error {
 	return db.Client.WithContext(ctx).
@@ -98,6 +124,15 @@ func (ad *AdminDao) SoftDeleteByID(ctx context.Context, id string) error {
 		Error
 }
 
+// SoftDeleteByIDTx 在事务中软删除管理员记录
+func (ad *AdminDao) SoftDeleteByIDTx(ctx context.Context, tx *gorm.DB, id string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Admins{}).
+		Where(entity.AdminsColumns.ID+" = ?", id).
+		Update(entity.AdminsColumns.DeletedAt, time.Now()).
+		Error
+}
+
 // Query 分页查询管理员
 func (ad *AdminDao) Query(ctx context.Context, page int, pageSize int,
 	filters map[string]interface{}) ([]*entity.Admins, int64, error) {
@@ -106,25 +141,7 @@ func (ad *AdminDao) Query(ctx context.Context, page int, pageSize int,
 		total  int64
 	)
 
-	// 定义需要使用 LIKE 查询的字段
-	likeFields := []string{
-		entity.AdminsColumns.Username,
-		entity.AdminsColumns.Phone,
-		entity.AdminsColumns.Email,
-	}
-
-	query := db.Client.WithContext(ctx).Model(&entity.Admins{}).Where(entity.AdminsColumns.DeletedAt + " IS NULL")
-
-	// 应用过滤条件
-	for key, value := range filters {
-		if value != nil && value != "" {
-			if utils.Contains(likeFields, key) {
-				query = query.Where(key+" LIKE ?", value.(string)+"%")
-			} else {
-				query = query.Where(key+" = ?", value)
-			}
-		}
-	}
+	query := db.Client.WithContext(ctx).Model(&entity.Admins{}).Scopes(adminFilterScope(filters))
 
 	// 统计总数
 	if err := query.Count(&total).Error; err != nil {
@@ -141,6 +158,44 @@ func (ad *AdminDao) Query(ctx context.Context, page int, pageSize int,
 	return admins, total, nil
 }
 
+// FindInBatches 按创建时间倒序分批读取符合条件的未删除管理员，用于导出
+// 参数:
+//   - fn: 每批数据的处理函数，返回错误时停止读取
+func (ad *AdminDao) FindInBatches(ctx context.Context, filters map[string]interface{}, batchSize int,
+	fn func(admins []*entity.Admins) error) error {
+	return findInBatches(func() *gorm.DB {
+		return db.Client.WithContext(ctx).Model(&entity.Admins{}).Scopes(adminFilterScope(filters))
+	}, batchSize, 0, func(record *entity.Admins) (time.Time, string) {
+		return record.CreatedAt, record.ID
+	}, fn)
+}
+
+// adminFilterScope 未删除管理员的筛选条件，用户名、手机号和邮箱按前缀匹配
+func adminFilterScope(filters map[string]interface{}) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		// 定义需要使用 LIKE 查询的字段
+		likeFields := []string{
+			entity.AdminsColumns.Username,
+			entity.AdminsColumns.Phone,
+			entity.AdminsColumns.Email,
+		}
+
+		query = query.Where(entity.AdminsColumns.DeletedAt + " IS NULL")
+
+		// 应用过滤条件
+		for key, value := range filters {
+			if value != nil && value != "" {
+				if utils.Contains(likeFields, key) {
+					query = query.Where(key+" LIKE ?", value.(string)+"%")
+				} else {
+					query = query.Where(key+" = ?", value)
+				}
+			}
+		}
+		return query
+	}
+}
+
 // UpdateLastLoginTime 更新管理员的最后登录时间
 func (ad *AdminDao) UpdateLastLoginTime(ctx context.Context, id string) error {
 	return db.Client.WithContext(ctx).
@@ -149,3 +204,38 @@ func (ad *AdminDao) UpdateLastLoginTime(ctx context.Context, id string) error {
 		Update(entity.AdminsColumns.LastLoginAt, time.Now()).
 		Error
 }
+
+// GetAllIDsTx 在事务中获取全部未删除管理员的 ID
+func (ad *AdminDao) GetAllIDsTx(ctx context.Context, tx *gorm.DB) ([]string, error) {
+	var ids []string
+	err := tx.WithContext(ctx).
+		Model(&entity.Admins{}).
+		Where(entity.AdminsColumns.DeletedAt+" IS NULL").
+		Pluck(entity.AdminsColumns.ID, &ids).Error
+	return ids, err
+}
+
+// QueryDeleted 按删除时间倒序分页查询已软删除的管理员，用于回收站
+func (ad *AdminDao) QueryDeleted(ctx context.Context, page int, pageSize int) ([]*entity.Admins, int64, error) {
+	return queryDeleted[entity.Admins](ctx, page, pageSize)
+}
+
+// GetDeletedByID 根据 ID 获取已软删除的管理员，不存在或未被删除时返回 nil
+func (ad *AdminDao) GetDeletedByID(ctx context.Context, id string) (*entity.Admins, error) {
+	return getDeletedByID[entity.Admins](ctx, id)
+}
+
+// GetDeletedIDsBefore 获取删除时间早于 before 的管理员ID，最多 limit 条
+func (ad *AdminDao) GetDeletedIDsBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	return getDeletedIDsBefore[entity.Admins](ctx, before, limit)
+}
+
+// RestoreTx 在事务中恢复已软删除的管理员，返回是否恢复成功
+func (ad *AdminDao) RestoreTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return restoreTx[entity.Admins](ctx, tx, id)
+}
+
+// PurgeTx 在事务中永久删除已软删除的管理员，返回是否删除成功
+func (ad *AdminDao) PurgeTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return purgeTx[entity.Admins](ctx, tx, id)
+}
@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AdminRecoveryCodeDao 管理员恢复码数据访问对象
+type AdminRecoveryCodeDao struct{}
+
+// NewAdminRecoveryCodeDao 创建 AdminRecoveryCodeDao 实例
+func NewAdminRecoveryCodeDao() *AdminRecoveryCodeDao {
+	return &AdminRecoveryCodeDao{}
+}
+
+// InsertBatchTx 在事务中批量插入恢复码
+func (rcd *AdminRecoveryCodeDao) InsertBatchTx(ctx context.Context, tx *gorm.DB, codes []*entity.AdminRecoveryCodes) error {
+	return tx.WithContext(ctx).CreateInBatches(&codes, 100).Error
+}
+
+// RemoveByAdminIDTx 在事务中移除管理员的全部恢复码
+func (rcd *AdminRecoveryCodeDao) RemoveByAdminIDTx(ctx context.Context, tx *gorm.DB, adminID string) error {
+	return tx.WithContext(ctx).
+		Delete(&entity.AdminRecoveryCodes{}, entity.AdminRecoveryCodesColumns.AdminID+" = ?", adminID).
+		Error
+}
+
+// GetUnusedByAdminID 获取管理员尚未使用的恢复码
+func (rcd *AdminRecoveryCodeDao) GetUnusedByAdminID(ctx context.Context, adminID string) ([]*entity.AdminRecoveryCodes, error) {
+	var codes []*entity.AdminRecoveryCodes
+	err := db.Client.WithContext(ctx).
+		Where(entity.AdminRecoveryCodesColumns.AdminID+" = ?", adminID).
+		Where(entity.AdminRecoveryCodesColumns.UsedAt + " IS NULL").
+		Find(&codes).Error
+	return codes, err
+}
+
+// MarkUsed 将恢复码标记为已使用
+// 返回:
+//   - bool: 是否标记成功，恢复码已被并发请求使用时返回 false
+func (rcd *AdminRecoveryCodeDao) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := db.Client.WithContext(ctx).
+		Model(&entity.AdminRecoveryCodes{}).
+		Where(entity.AdminRecoveryCodesColumns.ID+" = ?", id).
+		Where(entity.AdminRecoveryCodesColumns.UsedAt+" IS NULL").
+		Update(entity.AdminRecoveryCodesColumns.UsedAt, time.Now())
+	return result.RowsAffected > 0, result.Error
+}
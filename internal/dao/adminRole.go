@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AdminRoleDao 管理员角色关联表数据访问对象
+type AdminRoleDao struct{}
+
+// NewAdminRoleDao 创建 AdminRoleDao 实例
+func NewAdminRoleDao() *AdminRoleDao {
+	return &AdminRoleDao{}
+}
+
+// InsertBatchTx 在事务中批量插入管理员角色关联
+func (ard *AdminRoleDao) InsertBatchTx(ctx context.Context, tx *gorm.DB, adminRoles []*entity.AdminRoles) error {
+	if len(adminRoles) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).CreateInBatches(&adminRoles, 300).Error
+}
+
+// GetRolesByAdminID 根据管理员ID获取角色列表
+func (ard *AdminRoleDao) GetRolesByAdminID(ctx context.Context, adminID string) ([]*entity.Roles, error) {
+	var roles []*entity.Roles
+	err := db.Client.WithContext(ctx).
+		Select("roles.*").
+		Joins("JOIN admin_roles ON admin_roles.role_id = roles.id").
+		Where("admin_roles.admin_id = ?", adminID).
+		Where("roles.deleted_at" + " IS NULL").
+		Find(&roles).Error
+	return roles, err
+}
+
+// AdminRoleName 管理员与其角色的名称
+type AdminRoleName struct {
+	AdminID  string // 管理员ID
+	RoleName string // 角色名称
+}
+
+// GetRoleNamesByAdminIDs 批量获取管理员的未删除角色名称，按角色名称排序
+func (ard *AdminRoleDao) GetRoleNamesByAdminIDs(ctx context.Context, adminIDs []string) ([]*AdminRoleName, error) {
+	var roleNames []*AdminRoleName
+	if len(adminIDs) == 0 {
+		return roleNames, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Table("admin_roles").
+		Select("admin_roles.admin_id, roles.name AS role_name").
+		Joins("JOIN roles ON roles.id = admin_roles.role_id").
+		Where("admin_roles.admin_id IN ?", adminIDs).
+		Where("roles.deleted_at IS NULL").
+		Order("roles.name").
+		Scan(&roleNames).Error
+	return roleNames, err
+}
+
+// RemoveByAdminIDTx 在事务中根据管理员ID移除所有关联角色
+func (ard *AdminRoleDao) RemoveByAdminIDTx(ctx context.Context, tx *gorm.DB, adminID string) error {
+	return tx.WithContext(ctx).
+		Delete(&entity.AdminRoles{}, "admin_id = ?", adminID).
+		Error
+}
+
+// RemoveByRoleIDTx 在事务中根据角色ID移除所有关联管理员
+func (ard *AdminRoleDao) RemoveByRoleIDTx(ctx context.Context, tx *gorm.DB, roleID string) error {
+	return tx.WithContext(ctx).
+		Delete(&entity.AdminRoles{}, "role_id = ?", roleID).
+		Error
+}
+
+// CountAdminsByRoleIDTx 在事务中统计拥有指定角色的未删除管理员数量，可排除指定管理员
+// 注意: 查询会锁定命中的关联记录，避免并发移除角色时内置角色被清空。
+func (ard *AdminRoleDao) CountAdminsByRoleIDTx(ctx context.Context, tx *gorm.DB, roleID, excludeAdminID string) (int64, error) {
+	var adminIDs []string
+	query := tx.WithContext(ctx).
+		Table("admin_roles").
+		Joins("JOIN admins ON admins.id = admin_roles.admin_id").
+		Where("admin_roles.role_id = ?", roleID).
+		Where("admins.deleted_at" + " IS NULL")
+	if excludeAdminID != "" {
+		query = query.Where("admin_roles.admin_id <> ?", excludeAdminID)
+	}
+	err := query.Clauses(clause.Locking{Strength: "UPDATE"}).Pluck("admin_roles.admin_id", &adminIDs).Error
+	return int64(len(adminIDs)), err
+}
+
+// HasPermission 判断管理员是否拥有指定接口（请求方法 + 路由模板）的访问权限
+// 管理员拥有所属角色及其祖先角色的全部路径；只统计已启用且未删除的角色，拥有内置角色的管理员拥有全部接口权限。
+func (ard *AdminRoleDao) HasPermission(ctx context.Context, adminID, method, path string) (bool, error) {
+	roleIDs, err := ard.getExpandedRoleIDs(ctx, adminID)
+	if err != nil || len(roleIDs) == 0 {
+		return false, err
+	}
+
+	var count int64
+	err = db.Client.WithContext(ctx).
+		Model(&entity.Roles{}).
+		Joins("LEFT JOIN role_paths ON role_paths.role_id = roles.id").
+		Joins("LEFT JOIN paths ON paths.id = role_paths.path_id AND paths."+entity.PathsColumns.Method+
+			" = ? AND paths."+entity.PathsColumns.Path+" = ? AND paths."+entity.PathsColumns.DeletedAt+" IS NULL",
+			method, path).
+		Where("roles."+entity.RolesColumns.ID+" IN ?", roleIDs).
+		Where("roles."+entity.RolesColumns.Status+" = ?", 1).
+		Where("roles."+entity.RolesColumns.DeletedAt+" IS NULL").
+		Where("(roles."+entity.RolesColumns.IsBuiltin+" = ? OR paths.id IS NOT NULL)", 1).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetPermissionPaths 获取管理员通过所属角色及其祖先角色拥有的全部未删除接口，统计范围与 HasPermission 相同
+// 返回:
+//   - bool: 是否拥有内置角色，拥有时即拥有全部接口权限，不再返回接口列表
+//   - []*entity.Paths: 接口列表（仅包含请求方法和路由模板）
+func (ard *AdminRoleDao) GetPermissionPaths(ctx context.Context, adminID string) (bool, []*entity.Paths, error) {
+	roleIDs, err := ard.getExpandedRoleIDs(ctx, adminID)
+	if err != nil || len(roleIDs) == 0 {
+		return false, nil, err
+	}
+
+	var builtin int64
+	if err = db.Client.WithContext(ctx).
+		Model(&entity.Roles{}).
+		Where(entity.RolesColumns.ID+" IN ?", roleIDs).
+		Where(entity.RolesColumns.Status+" = ?", 1).
+		Where(entity.RolesColumns.DeletedAt+" IS NULL").
+		Where(entity.RolesColumns.IsBuiltin+" = ?", 1).
+		Count(&builtin).Error; err != nil {
+		return false, nil, err
+	}
+	if builtin > 0 {
+		return true, nil, nil
+	}
+
+	var paths []*entity.Paths
+	err = db.Client.WithContext(ctx).
+		Model(&entity.Paths{}).
+		Distinct("paths."+entity.PathsColumns.Method, "paths."+entity.PathsColumns.Path).
+		Joins("JOIN role_paths ON role_paths.path_id = paths.id").
+		Joins("JOIN roles ON roles.id = role_paths.role_id").
+		Where("roles."+entity.RolesColumns.ID+" IN ?", roleIDs).
+		Where("roles."+entity.RolesColumns.Status+" = ?", 1).
+		Where("roles." + entity.RolesColumns.DeletedAt + " IS NULL").
+		Where("paths." + entity.PathsColumns.DeletedAt + " IS NULL").
+		Find(&paths).Error
+	return false, paths, err
+}
+
+// getExpandedRoleIDs 获取管理员已启用且未删除的角色及其全部祖先角色的ID，祖先角色的状态由调用方过滤
+func (ard *AdminRoleDao) getExpandedRoleIDs(ctx context.Context, adminID string) ([]string, error) {
+	var roleIDs []string
+	if err := db.Client.WithContext(ctx).
+		Model(&entity.AdminRoles{}).
+		Joins("JOIN roles ON roles.id = admin_roles.role_id").
+		Where("admin_roles."+entity.AdminRolesColumns.AdminID+" = ?", adminID).
+		Where("roles."+entity.RolesColumns.Status+" = ?", 1).
+		Where("roles."+entity.RolesColumns.DeletedAt+" IS NULL").
+		Pluck("admin_roles."+entity.AdminRolesColumns.RoleID, &roleIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+
+	hierarchy, err := NewRoleParentDao().GetHierarchy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return hierarchy.Expand(roleIDs), nil
+}
@@ -0,0 +1,117 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogDao 审计日志数据访问对象
+type AuditLogDao struct{}
+
+// NewAuditLogDao 创建 AuditLogDao 实例
+func NewAuditLogDao() *AuditLogDao {
+	return &AuditLogDao{}
+}
+
+// InsertTx 在事务中插入审计日志，与所记录的变更一同提交或回滚
+func (ald *AuditLogDao) InsertTx(ctx context.Context, tx *gorm.DB, auditLog *entity.AuditLogs) error {
+	return tx.WithContext(ctx).Create(auditLog).Error
+}
+
+// GetLatest 获取对象最近一条指定操作的审计日志，不存在时返回 nil
+func (ald *AuditLogDao) GetLatest(ctx context.Context, targetType, targetID, action string) (*entity.AuditLogs, error) {
+	var auditLog entity.AuditLogs
+	err := db.Client.WithContext(ctx).
+		Where(entity.AuditLogsColumns.TargetType+" = ?", targetType).
+		Where(entity.AuditLogsColumns.TargetID+" = ?", targetID).
+		Where(entity.AuditLogsColumns.Action+" = ?", action).
+		Order(entity.AuditLogsColumns.CreatedAt + " DESC").
+		First(&auditLog).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &auditLog, err
+}
+
+// subjectAuditLogScope 匹配与账号相关的审计日志：以账号为操作对象，或由账号本人操作
+func subjectAuditLogScope(subjectType, subjectID string) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Where("(("+entity.AuditLogsColumns.TargetType+" = ? AND "+entity.AuditLogsColumns.TargetID+" = ?) OR ("+
+			entity.AuditLogsColumns.ActorType+" = ? AND "+entity.AuditLogsColumns.ActorID+" = ?))",
+			subjectType, subjectID, subjectType, subjectID)
+	}
+}
+
+// GetBySubject 获取与账号相关的全部审计日志，按创建时间倒序
+func (ald *AuditLogDao) GetBySubject(ctx context.Context, subjectType, subjectID string) ([]*entity.AuditLogs, error) {
+	var auditLogs []*entity.AuditLogs
+	err := db.Client.WithContext(ctx).
+		Scopes(subjectAuditLogScope(subjectType, subjectID)).
+		Order(entity.AuditLogsColumns.CreatedAt + " DESC").
+		Find(&auditLogs).Error
+	return auditLogs, err
+}
+
+// GetBySubjectTx 在事务中获取与账号相关的全部审计日志
+func (ald *AuditLogDao) GetBySubjectTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID string) ([]*entity.AuditLogs, error) {
+	var auditLogs []*entity.AuditLogs
+	err := tx.WithContext(ctx).
+		Scopes(subjectAuditLogScope(subjectType, subjectID)).
+		Find(&auditLogs).Error
+	return auditLogs, err
+}
+
+// UpdateTx 在事务中更新审计日志，仅用于擦除个人数据
+func (ald *AuditLogDao) UpdateTx(ctx context.Context, tx *gorm.DB, id string, updates map[string]interface{}) error {
+	return tx.WithContext(ctx).
+		Model(&entity.AuditLogs{}).
+		Where(entity.AuditLogsColumns.ID+" = ?", id).
+		Updates(updates).
+		Error
+}
+
+// Query 分页查询审计日志
+// 参数:
+//   - filters: 等值过滤条件，键为列名
+//   - startTime、endTime: 创建时间范围，零值表示不限制
+func (ald *AuditLogDao) Query(ctx context.Context, page int, pageSize int, filters map[string]interface{},
+	startTime, endTime time.Time) ([]*entity.AuditLogs, int64, error) {
+	var (
+		auditLogs []*entity.AuditLogs
+		total     int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.AuditLogs{})
+
+	// 应用过滤条件
+	for key, value := range filters {
+		if value != nil && value != "" {
+			query = query.Where(key+" = ?", value)
+		}
+	}
+	if !startTime.IsZero() {
+		query = query.Where(entity.AuditLogsColumns.CreatedAt+" >= ?", startTime)
+	}
+	if !endTime.IsZero() {
+		query = query.Where(entity.AuditLogsColumns.CreatedAt+" <= ?", endTime)
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	if err := query.Scopes(db.PageScope(page, pageSize)).
+		Order(entity.AuditLogsColumns.CreatedAt + " DESC").
+		Find(&auditLogs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return auditLogs, total, nil
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// findInBatches 按 (created_at, id) 倒序游标分批读取查询结果，避免大偏移量的性能问题，用于导出
+// 参数:
+//   - query: 构建查询条件的函数，每批调用一次，查询的表须有 created_at 和 id 列
+//   - limit: 最多读取的条数，0 表示不限制
+//   - cursor: 返回记录的创建时间和ID，作为读取下一批的游标
+//   - fn: 每批数据的处理函数，返回错误时停止读取
+func findInBatches[T any](query func() *gorm.DB, batchSize, limit int, cursor func(record *T) (time.Time, string),
+	fn func(records []*T) error) error {
+	var (
+		lastCreatedAt time.Time
+		lastID        string
+		read          int
+	)
+	for limit == 0 || read < limit {
+		size := batchSize
+		if limit > 0 && limit-read < size {
+			size = limit - read
+		}
+
+		q := query()
+		if lastID != "" {
+			q = q.Where("(created_at < ? OR (created_at = ? AND id < ?))", lastCreatedAt, lastCreatedAt, lastID)
+		}
+
+		var records []*T
+		if err := q.Order("created_at DESC").
+			Order("id DESC").
+			Limit(size).
+			Find(&records).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+		if len(records) < size {
+			return nil
+		}
+		read += len(records)
+		lastCreatedAt, lastID = cursor(records[len(records)-1])
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginLogDao 登录日志数据访问对象
+type LoginLogDao struct{}
+
+// NewLoginLogDao 创建 LoginLogDao 实例
+func NewLoginLogDao() *LoginLogDao {
+	return &LoginLogDao{}
+}
+
+// LoginLogFilter 登录日志查询条件
+type LoginLogFilter struct {
+	Fields    map[string]interface{} // 等值过滤条件，键为列名
+	Success   *bool                  // 是否成功，nil 表示不限制
+	StartTime time.Time              // 开始时间，零值表示不限制
+	EndTime   time.Time              // 结束时间，零值表示不限制
+}
+
+// Insert 插入登录日志
+func (lld *LoginLogDao) Insert(ctx context.Context, loginLog *entity.LoginLogs) error {
+	return db.Client.WithContext(ctx).Create(loginLog).Error
+}
+
+// Query 分页查询登录日志，按发生时间倒序
+func (lld *LoginLogDao) Query(ctx context.Context, page int, pageSize int,
+	filter LoginLogFilter) ([]*entity.LoginLogs, int64, error) {
+	var (
+		loginLogs []*entity.LoginLogs
+		total     int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.LoginLogs{}).Scopes(loginLogFilterScope(filter))
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	if err := query.Scopes(db.PageScope(page, pageSize)).
+		Order(entity.LoginLogsColumns.CreatedAt + " DESC").
+		Find(&loginLogs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return loginLogs, total, nil
+}
+
+// FindInBatches 按发生时间倒序分批读取符合条件的登录日志，用于导出
+// 参数:
+//   - limit: 最多读取的条数
+//   - fn: 每批数据的处理函数，返回错误时停止读取
+func (lld *LoginLogDao) FindInBatches(ctx context.Context, filter LoginLogFilter, batchSize, limit int,
+	fn func(loginLogs []*entity.LoginLogs) error) error {
+	return findInBatches(func() *gorm.DB {
+		return db.Client.WithContext(ctx).Model(&entity.LoginLogs{}).Scopes(loginLogFilterScope(filter))
+	}, batchSize, limit, func(record *entity.LoginLogs) (time.Time, string) {
+		return record.CreatedAt, record.ID
+	}, fn)
+}
+
+// GetRecent 获取账号最近的登录记录
+func (lld *LoginLogDao) GetRecent(ctx context.Context, subjectType, accountID string, limit int) ([]*entity.LoginLogs, error) {
+	var loginLogs []*entity.LoginLogs
+	err := db.Client.WithContext(ctx).
+		Where(entity.LoginLogsColumns.SubjectType+" = ?", subjectType).
+		Where(entity.LoginLogsColumns.AccountID+" = ?", accountID).
+		Order(entity.LoginLogsColumns.CreatedAt + " DESC").
+		Limit(limit).
+		Find(&loginLogs).Error
+	return loginLogs, err
+}
+
+// accountLoginLogScope 匹配账号的登录记录：账号ID一致，或未解析出账号的记录中提交的标识与账号的用户名、邮箱、手机号之一一致
+func accountLoginLogScope(subjectType, accountID string, identifiers []string) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		query = query.Where(entity.LoginLogsColumns.SubjectType+" = ?", subjectType)
+		if len(identifiers) == 0 {
+			return query.Where(entity.LoginLogsColumns.AccountID+" = ?", accountID)
+		}
+		return query.Where("("+entity.LoginLogsColumns.AccountID+" = ? OR ("+entity.LoginLogsColumns.AccountID+" = '' AND "+
+			entity.LoginLogsColumns.Identifier+" IN ?))", accountID, identifiers)
+	}
+}
+
+// GetByAccount 获取账号的全部登录记录，按发生时间倒序
+// 参数:
+//   - identifiers: 账号的用户名、邮箱、手机号，用于匹配账号ID为空的登录失败记录
+func (lld *LoginLogDao) GetByAccount(ctx context.Context, subjectType, accountID string,
+	identifiers []string) ([]*entity.LoginLogs, error) {
+	var loginLogs []*entity.LoginLogs
+	err := db.Client.WithContext(ctx).
+		Scopes(accountLoginLogScope(subjectType, accountID, identifiers)).
+		Order(entity.LoginLogsColumns.CreatedAt + " DESC").
+		Find(&loginLogs).Error
+	return loginLogs, err
+}
+
+// AnonymizeByAccountTx 在事务中匿名化账号的全部登录记录：标识替换为 identifier，清空客户端IP和 User-Agent
+func (lld *LoginLogDao) AnonymizeByAccountTx(ctx context.Context, tx *gorm.DB, subjectType, accountID string,
+	identifiers []string, identifier string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.LoginLogs{}).
+		Scopes(accountLoginLogScope(subjectType, accountID, identifiers)).
+		Updates(map[string]interface{}{
+			entity.LoginLogsColumns.Identifier: identifier,
+			entity.LoginLogsColumns.ClientIP:   "",
+			entity.LoginLogsColumns.UserAgent:  "",
+		}).
+		Error
+}
+
+// loginLogFilterScope 应用登录日志查询条件
+func loginLogFilterScope(filter LoginLogFilter) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		for key, value := range filter.Fields {
+			if value != nil && value != "" {
+				query = query.Where(key+" = ?", value)
+			}
+		}
+		if filter.Success != nil {
+			if *filter.Success {
+				query = query.Where(entity.LoginLogsColumns.ResultCode+" = ?", 0)
+			} else {
+				query = query.Where(entity.LoginLogsColumns.ResultCode+" <> ?", 0)
+			}
+		}
+		if !filter.StartTime.IsZero() {
+			query = query.Where(entity.LoginLogsColumns.CreatedAt+" >= ?", filter.StartTime)
+		}
+		if !filter.EndTime.IsZero() {
+			query = query.Where(entity.LoginLogsColumns.CreatedAt+" <= ?", filter.EndTime)
+		}
+		return query
+	}
+}
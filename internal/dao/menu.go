@@ -18,15 +18,29 @@ func NewMenuDao() *MenuDao {
 	return &MenuDao{}
 }
 
-// Insert 插入菜单记录
+// Insert 插入菜单记录，顶级菜单的父菜单ID写入 NULL
 func (md *MenuDao) Insert(ctx context.Context, menu *entity.Menus) error {
-	return db.Client.WithContext(ctx).Create(menu).Error
+	return md.InsertTx(ctx, db.Client, menu)
+}
+
+// InsertTx 在事务中插入菜单记录，顶级菜单的父菜单ID写入 NULL
+func (md *MenuDao) InsertTx(ctx context.Context, tx *gorm.DB, menu *entity.Menus) error {
+	query := tx.WithContext(ctx)
+	if menu.ParentID == "" {
+		query = query.Omit(entity.MenusColumns.ParentID)
+	}
+	return query.Create(menu).Error
 }
 
 // GetByID 根据 ID 获取菜单
 func (md *MenuDao) GetByID(ctx context.Context, id string) (*entity.Menus, error) {
+	return md.GetByIDTx(ctx, db.Client, id)
+}
+
+// GetByIDTx 在事务中根据 ID 获取菜单
+func (md *MenuDao) GetByIDTx(ctx context.Context, tx *gorm.DB, id string) (*entity.Menus, error) {
 	var menu entity.Menus
-	err := db.Client.WithContext(ctx).
+	err := tx.WithContext(ctx).
 		Where(entity.MenusColumns.ID+" = ?", id).
 		Where(entity.MenusColumns.DeletedAt + " IS NULL").
 		First(&menu).Error
@@ -56,6 +70,24 @@ func (md *MenuDao) Update(ctx context.Context, id string, updates map[string]int
 		Error
 }
 
+// UpdateTx 在事务中更新菜单信息
+func (md *MenuDao) UpdateTx(ctx context.Context, tx *gorm.DB, id string, updates map[string]interface{}) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Menus{}).
+		Where(entity.MenusColumns.ID+" = ?", id).
+		Updates(updates).
+		Error
+}
+
+// SoftDeleteByIDsTx 在事务中批量软删除菜单记录
+func (md *MenuDao) SoftDeleteByIDsTx(ctx context.Context, tx *gorm.DB, ids []string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Menus{}).
+		Where(entity.MenusColumns.ID+" IN ?", ids).
+		Update(entity.MenusColumns.DeletedAt, time.Now()).
+		Error
+}
+
 // SoftDeleteByID 软删除菜单记录
 func (md *MenuDao) SoftDeleteByID(ctx context.Context, id string) error {
 	return db.Client.WithContext(ctx).
@@ -108,10 +140,50 @@ func (md *MenuDao) UpdateStatus(ctx context.Context, id string, status int) erro
 
 // GetAll 获取所有菜单
 func (md *MenuDao) GetAll(ctx context.Context) ([]*entity.Menus, error) {
+	return md.GetAllTx(ctx, db.Client)
+}
+
+// GetAllTx 在事务中获取所有菜单
+func (md *MenuDao) GetAllTx(ctx context.Context, tx *gorm.DB) ([]*entity.Menus, error) {
 	var menus []*entity.Menus
-	err := db.Client.WithContext(ctx).
+	err := tx.WithContext(ctx).
 		Where(entity.MenusColumns.DeletedAt + " IS NULL").
 		Order(entity.MenusColumns.Sort + " ASC").
 		Find(&menus).Error
 	return menus, err
 }
+
+// QueryDeleted 按删除时间倒序分页查询已软删除的菜单，用于回收站
+func (md *MenuDao) QueryDeleted(ctx context.Context, page int, pageSize int) ([]*entity.Menus, int64, error) {
+	return queryDeleted[entity.Menus](ctx, page, pageSize)
+}
+
+// GetDeletedByID 根据 ID 获取已软删除的菜单，不存在或未被删除时返回 nil
+func (md *MenuDao) GetDeletedByID(ctx context.Context, id string) (*entity.Menus, error) {
+	return getDeletedByID[entity.Menus](ctx, id)
+}
+
+// GetDeletedIDsBefore 获取删除时间早于 before 的菜单ID，最多 limit 条
+func (md *MenuDao) GetDeletedIDsBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	return getDeletedIDsBefore[entity.Menus](ctx, before, limit)
+}
+
+// RestoreTx 在事务中恢复已软删除的菜单，返回是否恢复成功
+func (md *MenuDao) RestoreTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return restoreTx[entity.Menus](ctx, tx, id)
+}
+
+// CountChildrenTx 在事务中统计菜单的子菜单数量，包括已软删除的子菜单
+func (md *MenuDao) CountChildrenTx(ctx context.Context, tx *gorm.DB, id string) (int64, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.Menus{}).
+		Where(entity.MenusColumns.ParentID+" = ?", id).
+		Count(&count).Error
+	return count, err
+}
+
+// PurgeTx 在事务中永久删除已软删除的菜单，返回是否删除成功
+func (md *MenuDao) PurgeTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return purgeTx[entity.Menus](ctx, tx, id)
+}
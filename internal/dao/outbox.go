@@ -0,0 +1,224 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 发件箱投递状态
+const (
+	OutboxStatusPending   int8 = 0 // 待投递
+	OutboxStatusDelivered int8 = 1 // 已投递
+	OutboxStatusDead      int8 = 2 // 已进入死信，不再自动重试
+)
+
+// OutboxDao 事件发件箱数据访问对象
+type OutboxDao struct{}
+
+// NewOutboxDao 创建 OutboxDao 实例
+func NewOutboxDao() *OutboxDao {
+	return &OutboxDao{}
+}
+
+// InsertBatchTx 在事务中批量写入发件箱，与所记录的变更一同提交或回滚
+func (od *OutboxDao) InsertBatchTx(ctx context.Context, tx *gorm.DB, rows []*entity.Outbox) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).CreateInBatches(&rows, 100).Error
+}
+
+// GetByID 根据ID获取发件箱记录
+func (od *OutboxDao) GetByID(ctx context.Context, id string) (*entity.Outbox, error) {
+	var row entity.Outbox
+	err := db.Client.WithContext(ctx).
+		Where(entity.OutboxColumns.ID+" = ?", id).
+		First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &row, err
+}
+
+// ClaimDue 领取到期待投递的记录，按创建时间先后排序
+// 领取的记录在 lease 时长内不会被再次领取，投递结束后由 MarkDelivered 或 MarkFailed 更新状态；
+// 进程在投递过程中退出时，租约到期后记录会被重新领取，因此投递语义为至少一次。
+func (od *OutboxDao) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*entity.Outbox, error) {
+	var rows []*entity.Outbox
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where(entity.OutboxColumns.Status+" = ?", OutboxStatusPending).
+			Where(entity.OutboxColumns.NextAttemptAt+" <= ?", now).
+			Order(entity.OutboxColumns.CreatedAt).
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		return tx.Model(&entity.Outbox{}).
+			Where(entity.OutboxColumns.ID+" IN ?", ids).
+			Update(entity.OutboxColumns.NextAttemptAt, now.Add(lease)).Error
+	})
+	return rows, err
+}
+
+// MarkDelivered 标记记录已投递
+func (od *OutboxDao) MarkDelivered(ctx context.Context, id string, attempts int, now time.Time) error {
+	return db.Client.WithContext(ctx).
+		Model(&entity.Outbox{}).
+		Where(entity.OutboxColumns.ID+" = ?", id).
+		Updates(map[string]interface{}{
+			entity.OutboxColumns.Status:      OutboxStatusDelivered,
+			entity.OutboxColumns.Attempts:    attempts,
+			entity.OutboxColumns.LastError:   "",
+			entity.OutboxColumns.DeliveredAt: now,
+			entity.OutboxColumns.UpdatedAt:   now,
+		}).Error
+}
+
+// MarkFailed 记录一次投递失败
+// 参数:
+//   - status: 失败后的状态，仍可重试时为 OutboxStatusPending，超过最大尝试次数时为 OutboxStatusDead
+//   - nextAttemptAt: 下次投递时间
+func (od *OutboxDao) MarkFailed(ctx context.Context, id string, status int8, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return db.Client.WithContext(ctx).
+		Model(&entity.Outbox{}).
+		Where(entity.OutboxColumns.ID+" = ?", id).
+		Updates(map[string]interface{}{
+			entity.OutboxColumns.Status:        status,
+			entity.OutboxColumns.Attempts:      attempts,
+			entity.OutboxColumns.NextAttemptAt: nextAttemptAt,
+			entity.OutboxColumns.LastError:     lastError,
+			entity.OutboxColumns.UpdatedAt:     time.Now(),
+		}).Error
+}
+
+// Requeue 将未投递成功的记录重新放回待投递队列并立即投递，尝试次数清零
+// 返回:
+//   - bool: 记录是否存在且未投递成功
+func (od *OutboxDao) Requeue(ctx context.Context, id string) (bool, error) {
+	now := time.Now()
+	result := db.Client.WithContext(ctx).
+		Model(&entity.Outbox{}).
+		Where(entity.OutboxColumns.ID+" = ?", id).
+		Where(entity.OutboxColumns.Status+" <> ?", OutboxStatusDelivered).
+		Updates(map[string]interface{}{
+			entity.OutboxColumns.Status:        OutboxStatusPending,
+			entity.OutboxColumns.Attempts:      0,
+			entity.OutboxColumns.NextAttemptAt: now,
+			entity.OutboxColumns.UpdatedAt:     now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeDeliveredBefore 永久删除投递成功时间早于 before 的已投递记录，每次最多删除 limit 条
+func (od *OutboxDao) PurgeDeliveredBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return od.purgeBefore(ctx, OutboxStatusDelivered, entity.OutboxColumns.DeliveredAt, before, limit)
+}
+
+// PurgeDeadBefore 永久删除最后一次投递失败时间早于 before 的死信记录，每次最多删除 limit 条
+func (od *OutboxDao) PurgeDeadBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return od.purgeBefore(ctx, OutboxStatusDead, entity.OutboxColumns.UpdatedAt, before, limit)
+}
+
+// purgeBefore 永久删除指定状态下 column 早于 before 的记录
+func (od *OutboxDao) purgeBefore(ctx context.Context, status int8, column string, before time.Time, limit int) (int64, error) {
+	result := db.Client.WithContext(ctx).
+		Where(entity.OutboxColumns.Status+" = ?", status).
+		Where(column+" < ?", before).
+		Limit(limit).
+		Delete(&entity.Outbox{})
+	return result.RowsAffected, result.Error
+}
+
+// AnonymizeSubjectFieldTx 在事务中替换主体为 subjectType/subjectID 的事件内容中 data 下的 field 字段
+func (od *OutboxDao) AnonymizeSubjectFieldTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID, field string, value interface{}) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Outbox{}).
+		Scopes(eventPayloadFieldScope(entity.OutboxColumns.Payload, subjectType, subjectID, field)).
+		Update(entity.OutboxColumns.Payload, eventPayloadSet(entity.OutboxColumns.Payload, field, value)).
+		Error
+}
+
+// eventPayloadFieldScope 筛选事件内容（JSON）中主体匹配且 data 下存在 field 字段的记录
+func eventPayloadFieldScope(column, subjectType, subjectID, field string) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("JSON_UNQUOTE(JSON_EXTRACT("+column+", '$.subjectType')) = ?", subjectType).
+			Where("JSON_UNQUOTE(JSON_EXTRACT("+column+", '$.subjectId')) = ?", subjectID).
+			Where("JSON_CONTAINS_PATH("+column+", 'one', ?)", "$.data."+field)
+	}
+}
+
+// eventPayloadSet 返回将事件内容中 data 下的 field 字段替换为 value 的表达式
+func eventPayloadSet(column, field string, value interface{}) clause.Expr {
+	return gorm.Expr("JSON_SET("+column+", ?, ?)", "$.data."+field, value)
+}
+
+// Query 分页查询发件箱记录
+// 参数:
+//   - filters: 等值过滤条件，键为列名
+func (od *OutboxDao) Query(ctx context.Context, page int, pageSize int, filters map[string]interface{}) ([]*entity.Outbox, int64, error) {
+	var (
+		rows  []*entity.Outbox
+		total int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.Outbox{})
+
+	// 应用过滤条件
+	for key, value := range filters {
+		if value != nil && value != "" {
+			query = query.Where(key+" = ?", value)
+		}
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	if err := query.Scopes(db.PageScope(page, pageSize)).
+		Order(entity.OutboxColumns.CreatedAt + " DESC").
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
+
+// CountByStatus 统计各投递状态的记录数量
+func (od *OutboxDao) CountByStatus(ctx context.Context) (map[int8]int64, error) {
+	var rows []struct {
+		Status int8
+		Count  int64
+	}
+	if err := db.Client.WithContext(ctx).
+		Model(&entity.Outbox{}).
+		Select(entity.OutboxColumns.Status + ", COUNT(*) AS count").
+		Group(entity.OutboxColumns.Status).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int8]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
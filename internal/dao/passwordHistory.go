@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryDao 密码历史数据访问对象
+type PasswordHistoryDao struct{}
+
+// NewPasswordHistoryDao 创建 PasswordHistoryDao 实例
+func NewPasswordHistoryDao() *PasswordHistoryDao {
+	return &PasswordHistoryDao{}
+}
+
+// InsertTx 在事务中插入密码历史记录
+func (phd *PasswordHistoryDao) InsertTx(ctx context.Context, tx *gorm.DB, history *entity.PasswordHistories) error {
+	return tx.WithContext(ctx).Create(history).Error
+}
+
+// GetRecent 获取账号最近使用过的加密密码，按时间倒序
+func (phd *PasswordHistoryDao) GetRecent(ctx context.Context, subjectType, subjectID string, limit int) ([]string, error) {
+	var passwords []string
+	err := db.Client.WithContext(ctx).
+		Model(&entity.PasswordHistories{}).
+		Where(entity.PasswordHistoriesColumns.SubjectType+" = ?", subjectType).
+		Where(entity.PasswordHistoriesColumns.SubjectID+" = ?", subjectID).
+		Order(entity.PasswordHistoriesColumns.CreatedAt+" DESC").
+		Limit(limit).
+		Pluck(entity.PasswordHistoriesColumns.Password, &passwords).Error
+	return passwords, err
+}
+
+// PruneTx 在事务中清理超出保留数量的历史记录，只保留最近 keep 条
+func (phd *PasswordHistoryDao) PruneTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID string, keep int) error {
+	var staleIDs []string
+	if err := tx.WithContext(ctx).
+		Model(&entity.PasswordHistories{}).
+		Where(entity.PasswordHistoriesColumns.SubjectType+" = ?", subjectType).
+		Where(entity.PasswordHistoriesColumns.SubjectID+" = ?", subjectID).
+		Order(entity.PasswordHistoriesColumns.CreatedAt+" DESC").
+		Offset(keep).
+		Limit(1000).
+		Pluck(entity.PasswordHistoriesColumns.ID, &staleIDs).Error; err != nil {
+		return err
+	}
+	if len(staleIDs) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).
+		Delete(&entity.PasswordHistories{}, entity.PasswordHistoriesColumns.ID+" IN ?", staleIDs).
+		Error
+}
+
+// RemoveBySubjectTx 在事务中删除账号的全部密码历史记录，用于永久删除账号
+func (phd *PasswordHistoryDao) RemoveBySubjectTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID string) error {
+	return tx.WithContext(ctx).
+		Where(entity.PasswordHistoriesColumns.SubjectType+" = ?", subjectType).
+		Where(entity.PasswordHistoriesColumns.SubjectID+" = ?", subjectID).
+		Delete(&entity.PasswordHistories{}).
+		Error
+}
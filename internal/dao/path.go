@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PathDao 路径数据访问对象
@@ -20,7 +21,42 @@ func NewPathDao() *PathDao {
 
 // Insert 插入路径记录
 func (pd *PathDao) Insert(ctx context.Context, path *entity.Paths) error {
-	return db.Client.WithContext(ctx).Create(path).Error
+	return pd.InsertTx(ctx, db.Client, path)
+}
+
+// InsertTx 在事务中插入路径记录
+func (pd *PathDao) InsertTx(ctx context.Context, tx *gorm.DB, path *entity.Paths) error {
+	return tx.WithContext(ctx).Create(path).Error
+}
+
+// InsertBatchTx 在事务中批量插入路径记录
+func (pd *PathDao) InsertBatchTx(ctx context.Context, tx *gorm.DB, paths []*entity.Paths) error {
+	return tx.WithContext(ctx).CreateInBatches(&paths, 300).Error
+}
+
+// GetByPathMethod 根据路由路径与请求方法获取路径
+func (pd *PathDao) GetByPathMethod(ctx context.Context, path, method string) (*entity.Paths, error) {
+	return pd.GetByPathMethodTx(ctx, db.Client, path, method)
+}
+
+// GetByPathMethodForUpdateTx 在事务中根据路由路径与请求方法获取路径并加锁，直到事务结束
+// 锁定的索引范围同时阻塞并发写入相同的路径，用于写入前的冲突检查
+func (pd *PathDao) GetByPathMethodForUpdateTx(ctx context.Context, tx *gorm.DB, path, method string) (*entity.Paths, error) {
+	return pd.GetByPathMethodTx(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}), path, method)
+}
+
+// GetByPathMethodTx 在事务中根据路由路径与请求方法获取路径
+func (pd *PathDao) GetByPathMethodTx(ctx context.Context, tx *gorm.DB, path, method string) (*entity.Paths, error) {
+	var p entity.Paths
+	err := tx.WithContext(ctx).
+		Where(entity.PathsColumns.Path+" = ?", path).
+		Where(entity.PathsColumns.Method+" = ?", method).
+		Where(entity.PathsColumns.DeletedAt + " IS NULL").
+		First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &p, err
 }
 
 // GetByID 根据 ID 获取路径
@@ -46,9 +82,52 @@ func (pd *PathDao) GetByMenuID(ctx context.Context, menuID string) ([]*entity.Pa
 	return paths, err
 }
 
+// GetByMenuIDs 根据菜单ID列表获取路径列表
+func (pd *PathDao) GetByMenuIDs(ctx context.Context, menuIDs []string) ([]*entity.Paths, error) {
+	return pd.GetByMenuIDsTx(ctx, db.Client, menuIDs)
+}
+
+// GetByMenuIDsTx 在事务中根据菜单ID列表获取路径列表
+func (pd *PathDao) GetByMenuIDsTx(ctx context.Context, tx *gorm.DB, menuIDs []string) ([]*entity.Paths, error) {
+	var paths []*entity.Paths
+	err := tx.WithContext(ctx).
+		Where(entity.PathsColumns.MenuID+" IN ?", menuIDs).
+		Where(entity.PathsColumns.DeletedAt + " IS NULL").
+		Find(&paths).Error
+	return paths, err
+}
+
+// GetByIDs 根据 ID 列表获取未删除的路径
+func (pd *PathDao) GetByIDs(ctx context.Context, ids []string) ([]*entity.Paths, error) {
+	var paths []*entity.Paths
+	if len(ids) == 0 {
+		return paths, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Where(entity.PathsColumns.ID+" IN ?", ids).
+		Where(entity.PathsColumns.DeletedAt + " IS NULL").
+		Find(&paths).Error
+	return paths, err
+}
+
+// SoftDeleteByMenuIDsTx 在事务中软删除指定菜单下的全部路径
+func (pd *PathDao) SoftDeleteByMenuIDsTx(ctx context.Context, tx *gorm.DB, menuIDs []string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Paths{}).
+		Where(entity.PathsColumns.MenuID+" IN ?", menuIDs).
+		Where(entity.PathsColumns.DeletedAt+" IS NULL").
+		Update(entity.PathsColumns.DeletedAt, time.Now()).
+		Error
+}
+
 // Update 更新路径信息
 func (pd *PathDao) Update(ctx context.Context, id string, updates map[string]interface{}) error {
-	return db.Client.WithContext(ctx).
+	return pd.UpdateTx(ctx, db.Client, id, updates)
+}
+
+// UpdateTx 在事务中更新路径信息
+func (pd *PathDao) UpdateTx(ctx context.Context, tx *gorm.DB, id string, updates map[string]interface{}) error {
+	return tx.WithContext(ctx).
 		Model(&entity.Paths{}).
 		Where(entity.PathsColumns.ID+" = ?", id).
 		Updates(updates).
@@ -64,6 +143,30 @@ func (pd *PathDao) SoftDelete(ctx context.Context, id string) error {
 		Error
 }
 
+// SoftDeleteTx 在事务中软删除路径记录
+func (pd *PathDao) SoftDeleteTx(ctx context.Context, tx *gorm.DB, id string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Paths{}).
+		Where(entity.PathsColumns.ID+" = ?", id).
+		Update(entity.PathsColumns.DeletedAt, time.Now()).
+		Error
+}
+
+// UpdateStaleTx 在事务中批量设置路径的失效标记
+func (pd *PathDao) UpdateStaleTx(ctx context.Context, tx *gorm.DB, ids []string, stale int8) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).
+		Model(&entity.Paths{}).
+		Where(entity.PathsColumns.ID+" IN ?", ids).
+		Updates(map[string]interface{}{
+			entity.PathsColumns.Stale:     stale,
+			entity.PathsColumns.UpdatedAt: time.Now(),
+		}).
+		Error
+}
+
 // Query 分页查询路径
 func (pd *PathDao) Query(ctx context.Context, page int, pageSize int, filters map[string]interface{}) ([]*entity.Paths, int64, error) {
 	var (
@@ -101,3 +204,38 @@ func (pd *PathDao) GetAll(ctx context.Context) ([]*entity.Paths, error) {
 		Find(&paths).Error
 	return paths, err
 }
+
+// QueryDeleted 按删除时间倒序分页查询已软删除的路径，用于回收站
+func (pd *PathDao) QueryDeleted(ctx context.Context, page int, pageSize int) ([]*entity.Paths, int64, error) {
+	return queryDeleted[entity.Paths](ctx, page, pageSize)
+}
+
+// GetDeletedByID 根据 ID 获取已软删除的路径，不存在或未被删除时返回 nil
+func (pd *PathDao) GetDeletedByID(ctx context.Context, id string) (*entity.Paths, error) {
+	return getDeletedByID[entity.Paths](ctx, id)
+}
+
+// GetDeletedIDsBefore 获取删除时间早于 before 的路径ID，最多 limit 条
+func (pd *PathDao) GetDeletedIDsBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	return getDeletedIDsBefore[entity.Paths](ctx, before, limit)
+}
+
+// RestoreTx 在事务中恢复已软删除的路径，返回是否恢复成功
+func (pd *PathDao) RestoreTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return restoreTx[entity.Paths](ctx, tx, id)
+}
+
+// CountByMenuIDTx 在事务中统计菜单下的路径数量，包括已软删除的路径
+func (pd *PathDao) CountByMenuIDTx(ctx context.Context, tx *gorm.DB, menuID string) (int64, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.Paths{}).
+		Where(entity.PathsColumns.MenuID+" = ?", menuID).
+		Count(&count).Error
+	return count, err
+}
+
+// PurgeTx 在事务中永久删除已软删除的路径，返回是否删除成功
+func (pd *PathDao) PurgeTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return purgeTx[entity.Paths](ctx, tx, id)
+}
@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 回收站通用查询，T 须为带有 id 和 deleted_at 列的实体，deleted_at 不为空表示记录已被软删除
+
+// queryDeleted 按删除时间倒序分页查询已软删除的记录
+func queryDeleted[T any](ctx context.Context, page int, pageSize int) ([]*T, int64, error) {
+	var (
+		records []*T
+		total   int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(new(T)).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Scopes(db.PageScope(page, pageSize)).
+		Order("deleted_at DESC").
+		Order("id DESC").
+		Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// getDeletedByID 根据 ID 获取已软删除的记录，记录不存在或未被删除时返回 nil
+func getDeletedByID[T any](ctx context.Context, id string) (*T, error) {
+	var record T
+	err := db.Client.WithContext(ctx).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &record, err
+}
+
+// getDeletedIDsBefore 获取删除时间早于 before 的记录ID，按删除时间正序，最多 limit 条
+func getDeletedIDsBefore[T any](ctx context.Context, before time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := db.Client.WithContext(ctx).
+		Model(new(T)).
+		Where("deleted_at IS NOT NULL").
+		Where("deleted_at < ?", before).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// restoreTx 在事务中恢复已软删除的记录，返回是否恢复成功；记录已被并发恢复或清除时返回 false
+func restoreTx[T any](ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	result := tx.WithContext(ctx).
+		Model(new(T)).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// purgeTx 在事务中永久删除已软删除的记录，返回是否删除成功；外键关联的数据由数据库级联删除
+func purgeTx[T any](ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	result := tx.WithContext(ctx).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Delete(new(T))
+	return result.RowsAffected > 0, result.Error
+}
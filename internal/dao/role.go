@@ -8,6 +8,7 @@ import (
 
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // RoleDao 数据访问对象，封装角色相关操作
@@ -41,10 +42,35 @@ func (rd *RoleDao) GetByID(ctx context.Context, id string) (*entity.Roles, error
 	return &role, err
 }
 
+// GetByIDForUpdateTx 在事务中根据 ID 获取角色并锁定该行，直到事务结束
+func (rd *RoleDao) GetByIDForUpdateTx(ctx context.Context, tx *gorm.DB, id string) (*entity.Roles, error) {
+	var role entity.Roles
+	err := tx.WithContext(ctx).
+		Where(entity.RolesColumns.ID+" = ?", id).
+		Where(entity.RolesColumns.DeletedAt + " IS NULL").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &role, err
+}
+
 // GetByName 根据名称获取角色
 func (rd *RoleDao) GetByName(ctx context.Context, name string) (*entity.Roles, error) {
+	return rd.GetByNameTx(ctx, db.Client, name)
+}
+
+// GetByNameForUpdateTx 在事务中根据名称获取角色并加锁，直到事务结束
+// 锁定的索引范围同时阻塞并发写入同名角色，用于写入前的冲突检查
+func (rd *RoleDao) GetByNameForUpdateTx(ctx context.Context, tx *gorm.DB, name string) (*entity.Roles, error) {
+	return rd.GetByNameTx(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}), name)
+}
+
+// GetByNameTx 在事务中根据名称获取角色
+func (rd *RoleDao) GetByNameTx(ctx context.Context, tx *gorm.DB, name string) (*entity.Roles, error) {
 	var role entity.Roles
-	err := db.Client.WithContext(ctx).
+	err := tx.WithContext(ctx).
 		Where(entity.RolesColumns.Name+" = ?", name).
 		Where(entity.RolesColumns.DeletedAt + " IS NULL").
 		First(&role).Error
@@ -98,18 +124,7 @@ func (rd *RoleDao) Query(ctx context.Context, page int, pageSize int, filters ma
 		total int64
 	)
 
-	query := db.Client.WithContext(ctx).Model(&entity.Roles{}).Where(entity.RolesColumns.DeletedAt + " IS NULL")
-
-	// 应用过滤条件
-	for key, value := range filters {
-		if value != nil && value != "" {
-			if key == entity.RolesColumns.Name {
-				query = query.Where(key+" LIKE ?", value.(string)+"%")
-			} else {
-				query = query.Where(key+" = ?", value)
-			}
-		}
-	}
+	query := db.Client.WithContext(ctx).Model(&entity.Roles{}).Scopes(roleFilterScope(filters))
 
 	// 统计总数
 	if err := query.Count(&total).Error; err != nil {
@@ -126,6 +141,37 @@ func (rd *RoleDao) Query(ctx context.Context, page int, pageSize int, filters ma
 	return roles, total, nil
 }
 
+// FindInBatches 按创建时间倒序分批读取符合条件的未删除角色，用于导出
+// 参数:
+//   - fn: 每批数据的处理函数，返回错误时停止读取
+func (rd *RoleDao) FindInBatches(ctx context.Context, filters map[string]interface{}, batchSize int,
+	fn func(roles []*entity.Roles) error) error {
+	return findInBatches(func() *gorm.DB {
+		return db.Client.WithContext(ctx).Model(&entity.Roles{}).Scopes(roleFilterScope(filters))
+	}, batchSize, 0, func(record *entity.Roles) (time.Time, string) {
+		return record.CreatedAt, record.ID
+	}, fn)
+}
+
+// roleFilterScope 未删除角色的筛选条件，角色名称按前缀匹配
+func roleFilterScope(filters map[string]interface{}) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		query = query.Where(entity.RolesColumns.DeletedAt + " IS NULL")
+
+		// 应用过滤条件
+		for key, value := range filters {
+			if value != nil && value != "" {
+				if key == entity.RolesColumns.Name {
+					query = query.Where(key+" LIKE ?", value.(string)+"%")
+				} else {
+					query = query.Where(key+" = ?", value)
+				}
+			}
+		}
+		return query
+	}
+}
+
 // UpdateStatus 更新角色的状态
 func (rd *RoleDao) UpdateStatus(ctx context.Context, id string, status int) error {
 	return db.Client.WithContext(ctx).
@@ -134,3 +180,122 @@ func (rd *RoleDao) UpdateStatus(ctx context.Context, id string, status int) erro
 		Update(entity.RolesColumns.Status, status).
 		Error
 }
+
+// GetByIDs 根据 ID 列表获取未删除的角色
+func (rd *RoleDao) GetByIDs(ctx context.Context, ids []string) ([]*entity.Roles, error) {
+	var roles []*entity.Roles
+	if len(ids) == 0 {
+		return roles, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Where(entity.RolesColumns.ID+" IN ?", ids).
+		Where(entity.RolesColumns.DeletedAt + " IS NULL").
+		Find(&roles).Error
+	return roles, err
+}
+
+// GetByIDsForUpdateTx 在事务中根据 ID 列表获取未删除的角色并锁定这些行，直到事务结束
+// 按 ID 顺序加锁，避免死锁。
+func (rd *RoleDao) GetByIDsForUpdateTx(ctx context.Context, tx *gorm.DB, ids []string) ([]*entity.Roles, error) {
+	var roles []*entity.Roles
+	if len(ids) == 0 {
+		return roles, nil
+	}
+	err := tx.WithContext(ctx).
+		Where(entity.RolesColumns.ID+" IN ?", ids).
+		Where(entity.RolesColumns.DeletedAt + " IS NULL").
+		Order(entity.RolesColumns.ID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&roles).Error
+	return roles, err
+}
+
+// GetByNames 根据名称批量获取角色
+func (rd *RoleDao) GetByNames(ctx context.Context, names []string) ([]*entity.Roles, error) {
+	var roles []*entity.Roles
+	if len(names) == 0 {
+		return roles, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Where(entity.RolesColumns.Name+" IN ?", names).
+		Where(entity.RolesColumns.DeletedAt + " IS NULL").
+		Find(&roles).Error
+	return roles, err
+}
+
+// GetBuiltin 获取内置角色
+func (rd *RoleDao) GetBuiltin(ctx context.Context) (*entity.Roles, error) {
+	return rd.GetBuiltinTx(ctx, db.Client)
+}
+
+// GetBuiltinTx 在事务中获取内置超级管理员角色，不存在时返回 nil
+func (rd *RoleDao) GetBuiltinTx(ctx context.Context, tx *gorm.DB) (*entity.Roles, error) {
+	var role entity.Roles
+	err := tx.WithContext(ctx).
+		Where(entity.RolesColumns.IsBuiltin+" = ?", 1).
+		Where(entity.RolesColumns.DeletedAt + " IS NULL").
+		First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &role, err
+}
+
+// QueryByCondition 按条件表达式查询未删除的角色，按创建时间先后排序，保证分页稳定
+// 参数:
+//   - condition: SQL 条件表达式，为空表示不过滤
+//   - offset: 跳过的记录数
+//   - limit: 返回的最大记录数，为 0 时只统计总数
+func (rd *RoleDao) QueryByCondition(ctx context.Context, condition string, args []interface{}, offset, limit int) ([]*entity.Roles, int64, error) {
+	var (
+		roles []*entity.Roles
+		total int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.Roles{}).Where(entity.RolesColumns.DeletedAt + " IS NULL")
+	if condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if limit == 0 {
+		return roles, total, nil
+	}
+
+	if err := query.Order(entity.RolesColumns.CreatedAt).
+		Order(entity.RolesColumns.ID).
+		Offset(offset).
+		Limit(limit).
+		Find(&roles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return roles, total, nil
+}
+
+// QueryDeleted 按删除时间倒序分页查询已软删除的角色，用于回收站
+func (rd *RoleDao) QueryDeleted(ctx context.Context, page int, pageSize int) ([]*entity.Roles, int64, error) {
+	return queryDeleted[entity.Roles](ctx, page, pageSize)
+}
+
+// GetDeletedByID 根据 ID 获取已软删除的角色，不存在或未被删除时返回 nil
+func (rd *RoleDao) GetDeletedByID(ctx context.Context, id string) (*entity.Roles, error) {
+	return getDeletedByID[entity.Roles](ctx, id)
+}
+
+// GetDeletedIDsBefore 获取删除时间早于 before 的角色ID，最多 limit 条
+func (rd *RoleDao) GetDeletedIDsBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	return getDeletedIDsBefore[entity.Roles](ctx, before, limit)
+}
+
+// RestoreTx 在事务中恢复已软删除的角色，返回是否恢复成功
+func (rd *RoleDao) RestoreTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return restoreTx[entity.Roles](ctx, tx, id)
+}
+
+// PurgeTx 在事务中永久删除已软删除的角色，返回是否删除成功
+func (rd *RoleDao) PurgeTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return purgeTx[entity.Roles](ctx, tx, id)
+}
@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// RoleHierarchy 角色继承关系，角色ID -> 直接父角色ID列表
+type RoleHierarchy map[string][]string
+
+// Ancestors 获取角色的全部祖先角色ID（不含自身），按广度优先顺序返回
+func (rh RoleHierarchy) Ancestors(roleID string) []string {
+	return walkRoles(roleID, func(id string) []string { return rh[id] })
+}
+
+// Descendants 获取角色的全部子孙角色ID（不含自身），按广度优先顺序返回
+func (rh RoleHierarchy) Descendants(roleID string) []string {
+	children := make(map[string][]string)
+	for child, parents := range rh {
+		for _, parent := range parents {
+			children[parent] = append(children[parent], child)
+		}
+	}
+	return walkRoles(roleID, func(id string) []string { return children[id] })
+}
+
+// Expand 获取角色及其全部祖先角色ID（去重）
+func (rh RoleHierarchy) Expand(roleIDs []string) []string {
+	seen := make(map[string]struct{})
+	expanded := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		for _, id := range append([]string{roleID}, rh.Ancestors(roleID)...) {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				expanded = append(expanded, id)
+			}
+		}
+	}
+	return expanded
+}
+
+// CreatesCycle 判断将角色的父角色设置为 parentIDs 后是否会形成环
+func (rh RoleHierarchy) CreatesCycle(roleID string, parentIDs []string) bool {
+	for _, parentID := range parentIDs {
+		if parentID == roleID {
+			return true
+		}
+		for _, ancestor := range rh.Ancestors(parentID) {
+			if ancestor == roleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// walkRoles 从指定角色出发按广度优先遍历，返回可达的角色ID（不含起点），已访问的角色不会重复遍历
+func walkRoles(start string, next func(string) []string) []string {
+	visited := map[string]struct{}{start: {}}
+	var result []string
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, id := range next(current) {
+			if _, ok := visited[id]; ok {
+				continue
+			}
+			visited[id] = struct{}{}
+			result = append(result, id)
+			queue = append(queue, id)
+		}
+	}
+	return result
+}
+
+// RoleParentDao 角色继承关系数据访问对象
+type RoleParentDao struct{}
+
+// NewRoleParentDao 创建 RoleParentDao 实例
+func NewRoleParentDao() *RoleParentDao {
+	return &RoleParentDao{}
+}
+
+// InsertBatchTx 在事务中批量插入角色继承关系
+func (rpd *RoleParentDao) InsertBatchTx(ctx context.Context, tx *gorm.DB, roleParents []*entity.RoleParents) error {
+	if len(roleParents) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).CreateInBatches(&roleParents, 300).Error
+}
+
+// GetParentIDs 获取角色的直接父角色ID
+func (rpd *RoleParentDao) GetParentIDs(ctx context.Context, roleID string) ([]string, error) {
+	return rpd.GetParentIDsTx(ctx, db.Client, roleID)
+}
+
+// GetParentIDsTx 在事务中获取角色的直接父角色ID
+func (rpd *RoleParentDao) GetParentIDsTx(ctx context.Context, tx *gorm.DB, roleID string) ([]string, error) {
+	var parentIDs []string
+	err := tx.WithContext(ctx).
+		Model(&entity.RoleParents{}).
+		Where(entity.RoleParentsColumns.RoleID+" = ?", roleID).
+		Pluck(entity.RoleParentsColumns.ParentID, &parentIDs).Error
+	return parentIDs, err
+}
+
+// GetHierarchy 获取全部角色继承关系
+func (rpd *RoleParentDao) GetHierarchy(ctx context.Context) (RoleHierarchy, error) {
+	return rpd.GetHierarchyTx(ctx, db.Client)
+}
+
+// GetHierarchyTx 在事务中获取全部角色继承关系
+func (rpd *RoleParentDao) GetHierarchyTx(ctx context.Context, tx *gorm.DB) (RoleHierarchy, error) {
+	var roleParents []*entity.RoleParents
+	if err := tx.WithContext(ctx).Find(&roleParents).Error; err != nil {
+		return nil, err
+	}
+
+	hierarchy := make(RoleHierarchy)
+	for _, roleParent := range roleParents {
+		hierarchy[roleParent.RoleID] = append(hierarchy[roleParent.RoleID], roleParent.ParentID)
+	}
+	return hierarchy, nil
+}
+
+// RemoveByRoleIDTx 在事务中移除角色的全部父角色
+func (rpd *RoleParentDao) RemoveByRoleIDTx(ctx context.Context, tx *gorm.DB, roleID string) error {
+	return tx.WithContext(ctx).
+		Delete(&entity.RoleParents{}, entity.RoleParentsColumns.RoleID+" = ?", roleID).
+		Error
+}
+
+// RemoveByParentIDTx 在事务中移除全部角色对指定父角色的继承
+func (rpd *RoleParentDao) RemoveByParentIDTx(ctx context.Context, tx *gorm.DB, parentID string) error {
+	return tx.WithContext(ctx).
+		Delete(&entity.RoleParents{}, entity.RoleParentsColumns.ParentID+" = ?", parentID).
+		Error
+}
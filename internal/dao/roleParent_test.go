@@ -0,0 +1,105 @@
+package dao
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testHierarchy 测试用的角色继承关系
+//
+//	admin ──> editor ──> viewer
+//	auditor ──> viewer
+//	lead ──> editor, auditor
+var testHierarchy = RoleHierarchy{
+	"admin":   {"editor"},
+	"editor":  {"viewer"},
+	"auditor": {"viewer"},
+	"lead":    {"editor", "auditor"},
+}
+
+func TestRoleHierarchyAncestors(t *testing.T) {
+	tests := []struct {
+		roleID string
+		want   []string
+	}{
+		{"admin", []string{"editor", "viewer"}},
+		{"lead", []string{"editor", "auditor", "viewer"}},
+		{"viewer", nil},
+		{"unknown", nil},
+	}
+	for _, tt := range tests {
+		if got := testHierarchy.Ancestors(tt.roleID); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Ancestors(%q) = %v, want %v", tt.roleID, got, tt.want)
+		}
+	}
+}
+
+func TestRoleHierarchyDescendants(t *testing.T) {
+	tests := []struct {
+		roleID string
+		want   map[string]bool
+	}{
+		{"viewer", map[string]bool{"editor": true, "auditor": true, "admin": true, "lead": true}},
+		{"editor", map[string]bool{"admin": true, "lead": true}},
+		{"admin", map[string]bool{}},
+	}
+	for _, tt := range tests {
+		got := make(map[string]bool)
+		for _, id := range testHierarchy.Descendants(tt.roleID) {
+			got[id] = true
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Descendants(%q) = %v, want %v", tt.roleID, got, tt.want)
+		}
+	}
+}
+
+func TestRoleHierarchyExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		roleIDs []string
+		want    []string
+	}{
+		{"single role", []string{"admin"}, []string{"admin", "editor", "viewer"}},
+		{"deduplicated", []string{"admin", "auditor"}, []string{"admin", "editor", "viewer", "auditor"}},
+		{"role already included as ancestor", []string{"viewer", "editor"}, []string{"viewer", "editor"}},
+		{"empty", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testHierarchy.Expand(tt.roleIDs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand(%v) = %v, want %v", tt.roleIDs, got, tt.want)
+			}
+		})
+	}
+
+	cyclic := RoleHierarchy{"a": {"b"}, "b": {"c"}, "c": {"a"}}
+	if got, want := cyclic.Expand([]string{"a"}), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand() on a cyclic hierarchy = %v, want %v", got, want)
+	}
+}
+
+func TestRoleHierarchyCreatesCycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		roleID    string
+		parentIDs []string
+		want      bool
+	}{
+		{"self parent", "editor", []string{"editor"}, true},
+		{"direct cycle", "viewer", []string{"editor"}, true},
+		{"indirect cycle", "viewer", []string{"admin"}, true},
+		{"cycle through second parent", "viewer", []string{"auditor"}, true},
+		{"new parent", "admin", []string{"auditor"}, false},
+		{"sibling", "auditor", []string{"editor"}, false},
+		{"no parents", "viewer", nil, false},
+		{"unknown role", "guest", []string{"admin"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testHierarchy.CreatesCycle(tt.roleID, tt.parentIDs); got != tt.want {
+				t.Errorf("CreatesCycle(%q, %v) = %v, want %v", tt.roleID, tt.parentIDs, got, tt.want)
+			}
+		})
+	}
+}
@@ -31,8 +31,13 @@ func (rpd *RolePathDao) Assign(ctx context.Context, roleID, pathID string) error
 
 // GetByRoleID 根据角色ID获取路径列表
 func (rpd *RolePathDao) GetByRoleID(ctx context.Context, roleID string) ([]*entity.Paths, error) {
+	return rpd.GetByRoleIDTx(ctx, db.Client, roleID)
+}
+
+// GetByRoleIDTx 在事务中根据角色ID获取路径列表
+func (rpd *RolePathDao) GetByRoleIDTx(ctx context.Context, tx *gorm.DB, roleID string) ([]*entity.Paths, error) {
 	var paths []*entity.Paths
-	err := db.Client.WithContext(ctx).
+	err := tx.WithContext(ctx).
 		Select("paths.*").
 		Joins("JOIN role_paths ON role_paths.path_id = paths.id").
 		Where("role_paths.role_id = ?", roleID).
@@ -41,6 +46,51 @@ func (rpd *RolePathDao) GetByRoleID(ctx context.Context, roleID string) ([]*enti
 	return paths, err
 }
 
+// RolePath 角色直接拥有的接口
+type RolePath struct {
+	RoleID string // 角色ID
+	Method string // HTTP 方法
+	Path   string // 路由路径
+}
+
+// GetPathsByRoleIDs 批量获取角色直接拥有的未删除接口，按路由路径和方法排序
+func (rpd *RolePathDao) GetPathsByRoleIDs(ctx context.Context, roleIDs []string) ([]*RolePath, error) {
+	var rolePaths []*RolePath
+	if len(roleIDs) == 0 {
+		return rolePaths, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Table("role_paths").
+		Select("role_paths.role_id, paths.method, paths.path").
+		Joins("JOIN paths ON paths.id = role_paths.path_id").
+		Where("role_paths.role_id IN ?", roleIDs).
+		Where("paths.deleted_at IS NULL").
+		Order("paths.path").
+		Order("paths.method").
+		Scan(&rolePaths).Error
+	return rolePaths, err
+}
+
+// GetRolesByPath 获取指定角色中授权了指定接口（请求方法 + 路由模板）的未删除角色
+func (rpd *RolePathDao) GetRolesByPath(ctx context.Context, roleIDs []string, method, path string) ([]*entity.Roles, error) {
+	var roles []*entity.Roles
+	if len(roleIDs) == 0 {
+		return roles, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Select("DISTINCT roles.*").
+		Joins("JOIN role_paths ON role_paths.role_id = roles.id").
+		Joins("JOIN paths ON paths.id = role_paths.path_id").
+		Where("roles."+entity.RolesColumns.ID+" IN ?", roleIDs).
+		Where("paths."+entity.PathsColumns.Method+" = ?", method).
+		Where("paths."+entity.PathsColumns.Path+" = ?", path).
+		Where("paths." + entity.PathsColumns.DeletedAt + " IS NULL").
+		Where("roles." + entity.RolesColumns.DeletedAt + " IS NULL").
+		Order("roles." + entity.RolesColumns.Name).
+		Find(&roles).Error
+	return roles, err
+}
+
 // Remove 移除角色的路径
 func (rpd *RolePathDao) Remove(ctx context.Context, roleID, pathID string) error {
 	return db.Client.WithContext(ctx).
@@ -62,6 +112,16 @@ func (rpd *RolePathDao) RemoveByRoleIDTx(ctx context.Context, tx *gorm.DB, roleI
 		Error
 }
 
+// RemoveByPathIDsTx 在事务中移除全部角色对指定路径的授权
+func (rpd *RolePathDao) RemoveByPathIDsTx(ctx context.Context, tx *gorm.DB, pathIDs []string) error {
+	if len(pathIDs) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).
+		Delete(&entity.RolePaths{}, entity.RolePathsColumns.PathID+" IN ?", pathIDs).
+		Error
+}
+
 // GetByPathID 根据路径ID获取拥有该路径的角色列表
 func (rpd *RolePathDao) GetByPathID(ctx context.Context, pathID string) ([]*entity.Roles, error) {
 	var roles []*entity.Roles
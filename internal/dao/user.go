@@ -10,6 +10,7 @@ import (
 
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserDao 数据访问对象，封装角色相关操作
@@ -30,6 +31,11 @@ func (ud *UserDao) InsertTx(ctx context.Context, tx *gorm.DB, user *entity.Users
 	return tx.WithContext(ctx).Create(user).Error
 }
 
+// InsertBatchTx 在事务中批量插入用户记录
+func (ud *UserDao) InsertBatchTx(ctx context.Context, tx *gorm.DB, users []*entity.Users) error {
+	return tx.WithContext(ctx).CreateInBatches(&users, 300).Error
+}
+
 // GetByID 根据 ID 获取用户
 func (ud *UserDao) GetByID(ctx context.Context, id string) (*entity.Users, error) {
 	var user entity.Users
@@ -43,10 +49,47 @@ func (ud *UserDao) GetByID(ctx context.Context, id string) (*entity.Users, error
 	return &user, err
 }
 
+// GetByIDForUpdateTx 在事务中根据 ID 获取未删除的用户并锁定该行，直到事务结束
+func (ud *UserDao) GetByIDForUpdateTx(ctx context.Context, tx *gorm.DB, id string) (*entity.Users, error) {
+	var user entity.Users
+	err := tx.WithContext(ctx).
+		Where(entity.UsersColumns.ID+" = ?", id).
+		Where(entity.UsersColumns.DeletedAt + " IS NULL").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &user, err
+}
+
+// GetByIDWithDeleted 根据 ID 获取用户，包括已软删除的用户
+func (ud *UserDao) GetByIDWithDeleted(ctx context.Context, id string) (*entity.Users, error) {
+	var user entity.Users
+	err := db.Client.WithContext(ctx).
+		Where(entity.UsersColumns.ID+" = ?", id).
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &user, err
+}
+
 // GetByFields 根据字段（用户名、邮箱、手机号）获取用户
 func (ud *UserDao) GetByFields(ctx context.Context, username, email, phone string) (*entity.Users, error) {
+	return ud.GetByFieldsTx(ctx, db.Client, username, email, phone)
+}
+
+// GetByFieldsForUpdateTx 在事务中根据字段获取用户并加锁，直到事务结束
+// 锁定的索引范围同时阻塞并发写入相同用户名、邮箱、手机号的用户，用于写入前的冲突检查
+func (ud *UserDao) GetByFieldsForUpdateTx(ctx context.Context, tx *gorm.DB, username, email, phone string) (*entity.Users, error) {
+	return ud.GetByFieldsTx(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}), username, email, phone)
+}
+
+// GetByFieldsTx 在事务中根据字段（用户名、邮箱、手机号）获取用户
+func (ud *UserDao) GetByFieldsTx(ctx context.Context, tx *gorm.DB, username, email, phone string) (*entity.Users, error) {
 	// 构建查询条件
-	query := db.Client.WithContext(ctx).Model(&entity.Users{}).
+	query := tx.WithContext(ctx).Model(&entity.Users{}).
 		Where(entity.UsersColumns.DeletedAt + " IS NULL")
 
 	conditions := []string{}
@@ -77,6 +120,34 @@ func (ud *UserDao) GetByFields(ctx context.Context, username, email, phone strin
 	return &user, err
 }
 
+// GetByIdentities 获取用户名、邮箱或手机号在给定列表中的未删除用户，空值不参与匹配
+func (ud *UserDao) GetByIdentities(ctx context.Context, usernames, emails, phones []string) ([]*entity.Users, error) {
+	var users []*entity.Users
+	conditions := []string{}
+	params := []interface{}{}
+	if len(usernames) > 0 {
+		conditions = append(conditions, entity.UsersColumns.Username+" IN ?")
+		params = append(params, usernames)
+	}
+	if len(emails) > 0 {
+		conditions = append(conditions, entity.UsersColumns.Email+" IN ?")
+		params = append(params, emails)
+	}
+	if len(phones) > 0 {
+		conditions = append(conditions, entity.UsersColumns.Phone+" IN ?")
+		params = append(params, phones)
+	}
+	if len(conditions) == 0 {
+		return users, nil
+	}
+
+	err := db.Client.WithContext(ctx).
+		Where(entity.UsersColumns.DeletedAt+" IS NULL").
+		Where("("+strings.Join(conditions, " OR ")+")", params...).
+		Find(&users).Error
+	return users, err
+}
+
 // Update 更新用户信息
 func (ud *UserDao) Update(ctx context.Context, id string, updates map[string]interface{}) error {
 	return db.Client.WithContext(ctx).
@@ -121,25 +192,7 @@ func (ud *UserDao) Query(ctx context.Context, page int, pageSize int,
 		total int64
 	)
 
-	// 定义需要使用 LIKE 查询的字段
-	likeFields := []string{
-		entity.UsersColumns.Username,
-		entity.UsersColumns.Email,
-		entity.UsersColumns.Phone,
-		entity.UsersColumns.Nickname,
-	}
-
-	query := db.Client.WithContext(ctx).Model(&entity.Users{}).Where(entity.UsersColumns.DeletedAt + " IS NULL")
-
-	for key, value := range filters {
-		if value != nil && value != "" {
-			if utils.Contains(likeFields, key) {
-				query = query.Where(key+" LIKE ?", value.(string)+"%")
-			} else {
-				query = query.Where(key+" = ?", value)
-			}
-		}
-	}
+	query := db.Client.WithContext(ctx).Model(&entity.Users{}).Scopes(userFilterScope(filters))
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -154,6 +207,44 @@ func (ud *UserDao) Query(ctx context.Context, page int, pageSize int,
 	return users, total, nil
 }
 
+// FindInBatches 按创建时间倒序分批读取符合条件的未删除用户，用于导出
+// 参数:
+//   - fn: 每批数据的处理函数，返回错误时停止读取
+func (ud *UserDao) FindInBatches(ctx context.Context, filters map[string]interface{}, batchSize int,
+	fn func(users []*entity.Users) error) error {
+	return findInBatches(func() *gorm.DB {
+		return db.Client.WithContext(ctx).Model(&entity.Users{}).Scopes(userFilterScope(filters))
+	}, batchSize, 0, func(record *entity.Users) (time.Time, string) {
+		return record.CreatedAt, record.ID
+	}, fn)
+}
+
+// userFilterScope 未删除用户的筛选条件，用户名、邮箱、手机号和昵称按前缀匹配
+func userFilterScope(filters map[string]interface{}) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		// 定义需要使用 LIKE 查询的字段
+		likeFields := []string{
+			entity.UsersColumns.Username,
+			entity.UsersColumns.Email,
+			entity.UsersColumns.Phone,
+			entity.UsersColumns.Nickname,
+		}
+
+		query = query.Where(entity.UsersColumns.DeletedAt + " IS NULL")
+
+		for key, value := range filters {
+			if value != nil && value != "" {
+				if utils.Contains(likeFields, key) {
+					query = query.Where(key+" LIKE ?", value.(string)+"%")
+				} else {
+					query = query.Where(key+" = ?", value)
+				}
+			}
+		}
+		return query
+	}
+}
+
 // UpdateStatus 更新用户状态
 func (ud *UserDao) UpdateStatus(ctx context.Context, id string, status int) error {
 	return db.Client.WithContext(ctx).
@@ -162,3 +253,89 @@ func (ud *UserDao) UpdateStatus(ctx context.Context, id string, status int) erro
 		Update(entity.UsersColumns.Status, status).
 		Error
 }
+
+// UpdateLastLoginTime 更新用户的最后登录时间
+func (ud *UserDao) UpdateLastLoginTime(ctx context.Context, id string) error {
+	return db.Client.WithContext(ctx).
+		Model(&entity.Users{}).
+		Where(entity.UsersColumns.ID+" = ?", id).
+		Update(entity.UsersColumns.LastLoginAt, time.Now()).
+		Error
+}
+
+// GetByIDs 根据 ID 列表获取未删除的用户
+func (ud *UserDao) GetByIDs(ctx context.Context, ids []string) ([]*entity.Users, error) {
+	return ud.GetByIDsTx(ctx, db.Client, ids)
+}
+
+// GetByIDsTx 在事务中根据 ID 列表获取未删除的用户
+func (ud *UserDao) GetByIDsTx(ctx context.Context, tx *gorm.DB, ids []string) ([]*entity.Users, error) {
+	var users []*entity.Users
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := tx.WithContext(ctx).
+		Where(entity.UsersColumns.ID+" IN ?", ids).
+		Where(entity.UsersColumns.DeletedAt + " IS NULL").
+		Find(&users).Error
+	return users, err
+}
+
+// QueryByCondition 按条件表达式查询未删除的用户，按创建时间先后排序，保证分页稳定
+// 参数:
+//   - condition: SQL 条件表达式，为空表示不过滤
+//   - offset: 跳过的记录数
+//   - limit: 返回的最大记录数，为 0 时只统计总数
+func (ud *UserDao) QueryByCondition(ctx context.Context, condition string, args []interface{}, offset, limit int) ([]*entity.Users, int64, error) {
+	var (
+		users []*entity.Users
+		total int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.Users{}).Where(entity.UsersColumns.DeletedAt + " IS NULL")
+	if condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if limit == 0 {
+		return users, total, nil
+	}
+
+	if err := query.Order(entity.UsersColumns.CreatedAt).
+		Order(entity.UsersColumns.ID).
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// QueryDeleted 按删除时间倒序分页查询已软删除的用户，用于回收站
+func (ud *UserDao) QueryDeleted(ctx context.Context, page int, pageSize int) ([]*entity.Users, int64, error) {
+	return queryDeleted[entity.Users](ctx, page, pageSize)
+}
+
+// GetDeletedByID 根据 ID 获取已软删除的用户，不存在或未被删除时返回 nil
+func (ud *UserDao) GetDeletedByID(ctx context.Context, id string) (*entity.Users, error) {
+	return getDeletedByID[entity.Users](ctx, id)
+}
+
+// GetDeletedIDsBefore 获取删除时间早于 before 的用户ID，最多 limit 条
+func (ud *UserDao) GetDeletedIDsBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	return getDeletedIDsBefore[entity.Users](ctx, before, limit)
+}
+
+// RestoreTx 在事务中恢复已软删除的用户，返回是否恢复成功
+func (ud *UserDao) RestoreTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return restoreTx[entity.Users](ctx, tx, id)
+}
+
+// PurgeTx 在事务中永久删除已软删除的用户，返回是否删除成功
+func (ud *UserDao) PurgeTx(ctx context.Context, tx *gorm.DB, id string) (bool, error) {
+	return purgeTx[entity.Users](ctx, tx, id)
+}
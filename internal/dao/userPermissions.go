@@ -2,9 +2,11 @@ package dao
 
 import (
 	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
 	"context"
 	"fmt"
 	"gorm.io/gorm"
+	"time"
 )
 
 // UserPermissionDao 用户权限关联表数据访问对象
@@ -31,6 +33,21 @@ func (dao *UserPermissionDao) RemoveByUserIDsTx(ctx context.Context, tx *gorm.DB
 	return nil
 }
 
+// RemoveByPathIDsTx 删除指定路径的权限记录
+func (dao *UserPermissionDao) RemoveByPathIDsTx(ctx context.Context, tx *gorm.DB, pathIDs []string) error {
+	if len(pathIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.WithContext(ctx).
+		Where(entity.UserPermissionsColumns.PathID+" IN ?", pathIDs).
+		Delete(&entity.UserPermissions{}).Error; err != nil {
+		return fmt.Errorf("failed to remove path permissions: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateUserPermissionsTx 更新用户权限表
 func (dao *UserPermissionDao) UpdateUserPermissionsTx(ctx context.Context, tx *gorm.DB, userIDs []string) error {
 	if len(userIDs) == 0 {
@@ -44,18 +61,46 @@ func (dao *UserPermissionDao) UpdateUserPermissionsTx(ctx context.Context, tx *g
 		return fmt.Errorf("failed to delete user permissions: %w", err)
 	}
 
-	// 查询 user_roles 中所有关联的角色 ID
+	// 查询 user_roles 中所有处于有效期内的角色 ID，并同步刷新关联的生效状态
+	now := time.Now()
 	var userRoles []entity.UserRoles
 	if err := tx.WithContext(ctx).
+		Scopes(validUserRoleScope(now)).
 		Where(entity.UserRolesColumns.UserID+" IN ?", userIDs).
 		Find(&userRoles).Error; err != nil {
 		return fmt.Errorf("failed to fetch user roles: %w", err)
 	}
+	if err := NewUserRoleDao().RefreshActiveTx(ctx, tx, userIDs, now); err != nil {
+		return fmt.Errorf("failed to refresh user roles: %w", err)
+	}
+
+	// 按角色继承关系展开每个用户的角色，用户同时拥有全部祖先角色的路径
+	hierarchy, err := NewRoleParentDao().GetHierarchyTx(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch role hierarchy: %w", err)
+	}
+	userRoleMap := make(map[string][]string)
+	for _, ur := range userRoles {
+		userRoleMap[ur.UserID] = append(userRoleMap[ur.UserID], ur.RoleID)
+	}
+
+	// 与管理员权限判断保持一致：只按已启用的直接角色展开，且只统计已启用的角色（含祖先角色）的路径
+	var candidateRoleIDs []string
+	for _, directRoleIDs := range userRoleMap {
+		candidateRoleIDs = append(candidateRoleIDs, hierarchy.Expand(directRoleIDs)...)
+	}
+	enabledRoleIDs, err := enabledRoleIDSetTx(ctx, tx, candidateRoleIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch enabled roles: %w", err)
+	}
 
 	// 提取所有 roleID
 	roleIDSet := make(map[string]struct{})
-	for _, ur := range userRoles {
-		roleIDSet[ur.RoleID] = struct{}{}
+	for userID, directRoleIDs := range userRoleMap {
+		userRoleMap[userID] = filterRoleIDs(hierarchy.Expand(filterRoleIDs(directRoleIDs, enabledRoleIDs)), enabledRoleIDs)
+		for _, roleID := range userRoleMap[userID] {
+			roleIDSet[roleID] = struct{}{}
+		}
 	}
 	var roleIDs []string
 	for roleID := range roleIDSet {
@@ -78,13 +123,15 @@ func (dao *UserPermissionDao) UpdateUserPermissionsTx(ctx context.Context, tx *g
 
 	// 构造用户与路径的映射
 	userPathMap := make(map[string]map[string]struct{})
-	for _, ur := range userRoles {
-		if paths, exists := rolePathMap[ur.RoleID]; exists {
-			if _, ok := userPathMap[ur.UserID]; !ok {
-				userPathMap[ur.UserID] = make(map[string]struct{})
-			}
-			for _, pathID := range paths {
-				userPathMap[ur.UserID][pathID] = struct{}{}
+	for userID, expandedRoleIDs := range userRoleMap {
+		for _, roleID := range expandedRoleIDs {
+			if paths, exists := rolePathMap[roleID]; exists {
+				if _, ok := userPathMap[userID]; !ok {
+					userPathMap[userID] = make(map[string]struct{})
+				}
+				for _, pathID := range paths {
+					userPathMap[userID][pathID] = struct{}{}
+				}
 			}
 		}
 	}
@@ -109,3 +156,116 @@ func (dao *UserPermissionDao) UpdateUserPermissionsTx(ctx context.Context, tx *g
 
 	return nil
 }
+
+// enabledRoleIDSetTx 在事务中获取指定角色中已启用且未删除的角色ID集合
+func enabledRoleIDSetTx(ctx context.Context, tx *gorm.DB, roleIDs []string) (map[string]struct{}, error) {
+	enabled := make(map[string]struct{})
+	if len(roleIDs) == 0 {
+		return enabled, nil
+	}
+	var ids []string
+	if err := tx.WithContext(ctx).
+		Model(&entity.Roles{}).
+		Where(entity.RolesColumns.ID+" IN ?", roleIDs).
+		Where(entity.RolesColumns.Status+" = ?", 1).
+		Where(entity.RolesColumns.DeletedAt+" IS NULL").
+		Pluck(entity.RolesColumns.ID, &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		enabled[id] = struct{}{}
+	}
+	return enabled, nil
+}
+
+// filterRoleIDs 保留属于 allowed 集合的角色ID
+func filterRoleIDs(roleIDs []string, allowed map[string]struct{}) []string {
+	filtered := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if _, ok := allowed[roleID]; ok {
+			filtered = append(filtered, roleID)
+		}
+	}
+	return filtered
+}
+
+// HasPermission 判断用户是否拥有指定接口（请求方法 + 路由模板）的访问权限
+func (dao *UserPermissionDao) HasPermission(ctx context.Context, userID, method, path string) (bool, error) {
+	var count int64
+	err := db.Client.WithContext(ctx).
+		Model(&entity.UserPermissions{}).
+		Joins("JOIN paths ON paths.id = user_permissions.path_id").
+		Where("user_permissions."+entity.UserPermissionsColumns.UserID+" = ?", userID).
+		Where("paths."+entity.PathsColumns.Method+" = ?", method).
+		Where("paths."+entity.PathsColumns.Path+" = ?", path).
+		Where("paths." + entity.PathsColumns.DeletedAt + " IS NULL").
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// userPermissionPath 用户权限对应的接口
+type userPermissionPath struct {
+	UserID string
+	Method string
+	Path   string
+}
+
+// GetPermissionPathsByUserIDs 获取指定用户拥有的全部未删除接口，按用户ID分组
+// 返回:
+//   - map[string][]*entity.Paths: 用户ID -> 接口列表（仅包含请求方法和路由模板），没有任何权限的用户不包含在结果中
+func (dao *UserPermissionDao) GetPermissionPathsByUserIDs(ctx context.Context, userIDs []string) (map[string][]*entity.Paths, error) {
+	result := make(map[string][]*entity.Paths)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []userPermissionPath
+	if err := db.Client.WithContext(ctx).
+		Model(&entity.UserPermissions{}).
+		Select("user_permissions."+entity.UserPermissionsColumns.UserID+", paths."+entity.PathsColumns.Method+", paths."+entity.PathsColumns.Path).
+		Joins("JOIN paths ON paths.id = user_permissions.path_id").
+		Where("user_permissions."+entity.UserPermissionsColumns.UserID+" IN ?", userIDs).
+		Where("paths." + entity.PathsColumns.DeletedAt + " IS NULL").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.UserID] = append(result[row.UserID], &entity.Paths{Method: row.Method, Path: row.Path})
+	}
+	return result, nil
+}
+
+// GetUserIDsAfter 按用户ID升序获取拥有权限的用户ID（去重），用于分批遍历
+func (dao *UserPermissionDao) GetUserIDsAfter(ctx context.Context, afterUserID string, limit int) ([]string, error) {
+	var userIDs []string
+	err := db.Client.WithContext(ctx).
+		Model(&entity.UserPermissions{}).
+		Where(entity.UserPermissionsColumns.UserID+" > ?", afterUserID).
+		Distinct(entity.UserPermissionsColumns.UserID).
+		Order(entity.UserPermissionsColumns.UserID).
+		Limit(limit).
+		Pluck(entity.UserPermissionsColumns.UserID, &userIDs).Error
+	return userIDs, err
+}
+
+// GetUserIDsByPathIDs 获取拥有指定路径权限的用户ID（去重）
+func (dao *UserPermissionDao) GetUserIDsByPathIDs(ctx context.Context, pathIDs []string) ([]string, error) {
+	return dao.GetUserIDsByPathIDsTx(ctx, db.Client, pathIDs)
+}
+
+// GetUserIDsByPathIDsTx 在事务中获取拥有指定路径权限的用户ID（去重）
+func (dao *UserPermissionDao) GetUserIDsByPathIDsTx(ctx context.Context, tx *gorm.DB, pathIDs []string) ([]string, error) {
+	var userIDs []string
+	if len(pathIDs) == 0 {
+		return userIDs, nil
+	}
+	err := tx.WithContext(ctx).
+		Model(&entity.UserPermissions{}).
+		Where(entity.UserPermissionsColumns.PathID+" IN ?", pathIDs).
+		Distinct(entity.UserPermissionsColumns.UserID).
+		Pluck(entity.UserPermissionsColumns.UserID, &userIDs).Error
+	return userIDs, err
+}
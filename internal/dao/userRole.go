@@ -6,8 +6,28 @@ import (
 	"context"
 	"fmt"
 	"gorm.io/gorm"
+	"time"
 )
 
+// validUserRoleCondition 用户角色关联处于有效期内的条件，两个参数均为当前时间
+const validUserRoleCondition = "(user_roles.valid_from IS NULL OR user_roles.valid_from <= ?) AND " +
+	"(user_roles.valid_until IS NULL OR user_roles.valid_until > ?)"
+
+// UserRoleAssignment 用户的角色及其有效期
+type UserRoleAssignment struct {
+	entity.Roles
+	ValidFrom  *time.Time // 生效时间，为空表示立即生效
+	ValidUntil *time.Time // 失效时间，为空表示永久有效
+	Active     int8       // 当前是否已计入用户权限
+}
+
+// validUserRoleScope 只保留在指定时间处于有效期内的用户角色关联
+func validUserRoleScope(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(validUserRoleCondition, now, now)
+	}
+}
+
 // UserRoleDao 用户角色关联表数据访问对象
 type UserRoleDao struct{}
 
@@ -113,8 +133,148 @@ func (urd *UserRoleDao) GetUserIDsByRoleIDTx(ctx context.Context, tx *gorm.DB, r
 		Pluck("user_id", &userIDs).Error
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user IDs for role %d: %w", roleID, err)
+		return nil, fmt.Errorf("failed to fetch user IDs for role %s: %w", roleID, err)
 	}
 
 	return userIDs, nil
 }
+
+// GetUserIDsByRoleIDsTx 获取与指定角色列表关联的用户 ID 列表（去重）
+func (urd *UserRoleDao) GetUserIDsByRoleIDsTx(ctx context.Context, tx *gorm.DB, roleIDs []string) ([]string, error) {
+	var userIDs []string
+	if len(roleIDs) == 0 {
+		return userIDs, nil
+	}
+
+	err := tx.WithContext(ctx).
+		Table("user_roles").
+		Where("role_id IN ?", roleIDs).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user IDs for roles: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// GetAssignmentsByUserID 根据用户ID获取角色及其有效期，包括尚未生效和已失效的关联
+func (urd *UserRoleDao) GetAssignmentsByUserID(ctx context.Context, userID string) ([]*UserRoleAssignment, error) {
+	return urd.GetAssignmentsByUserIDTx(ctx, db.Client, userID)
+}
+
+// GetAssignmentsByUserIDTx 在事务中根据用户ID获取角色及其有效期，包括尚未生效和已失效的关联
+func (urd *UserRoleDao) GetAssignmentsByUserIDTx(ctx context.Context, tx *gorm.DB, userID string) ([]*UserRoleAssignment, error) {
+	var assignments []*UserRoleAssignment
+	err := tx.WithContext(ctx).
+		Table("roles").
+		Select("roles.*, user_roles.valid_from, user_roles.valid_until, user_roles.active").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Where("roles.deleted_at" + " IS NULL").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// GetValidRoleIDs 获取用户在指定时间处于有效期内的角色ID
+func (urd *UserRoleDao) GetValidRoleIDs(ctx context.Context, userID string, now time.Time) ([]string, error) {
+	var roleIDs []string
+	err := db.Client.WithContext(ctx).
+		Model(&entity.UserRoles{}).
+		Scopes(validUserRoleScope(now)).
+		Where(entity.UserRolesColumns.UserID+" = ?", userID).
+		Pluck(entity.UserRolesColumns.RoleID, &roleIDs).Error
+	return roleIDs, err
+}
+
+// GetOutdatedUserIDs 获取角色关联的生效状态与有效期不一致的用户ID（去重），即需要重新计算权限的用户
+func (urd *UserRoleDao) GetOutdatedUserIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var userIDs []string
+	err := db.Client.WithContext(ctx).
+		Table("user_roles").
+		Where("(user_roles.active = 1 AND NOT ("+validUserRoleCondition+")) OR "+
+			"(user_roles.active = 0 AND ("+validUserRoleCondition+"))", now, now, now, now).
+		Distinct().
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// RefreshActiveTx 在事务中按指定时间刷新用户角色关联的生效状态
+func (urd *UserRoleDao) RefreshActiveTx(ctx context.Context, tx *gorm.DB, userIDs []string, now time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).
+		Model(&entity.UserRoles{}).
+		Where(entity.UserRolesColumns.UserID+" IN ?", userIDs).
+		Update(entity.UserRolesColumns.Active,
+			gorm.Expr("CASE WHEN "+validUserRoleCondition+" THEN 1 ELSE 0 END", now, now)).
+		Error
+}
+
+// UserRoleMember 用户与角色的关联，附带用户名和角色名称
+type UserRoleMember struct {
+	UserID   string // 用户ID
+	RoleID   string // 角色ID
+	Username string // 用户名
+	RoleName string // 角色名称
+	Active   bool   // 当前是否处于有效期内
+}
+
+// memberQuery 查询用户角色关联及用户名、角色名称，已删除的用户和角色不计入
+func memberQuery(ctx context.Context, tx *gorm.DB) *gorm.DB {
+	return tx.WithContext(ctx).
+		Table("user_roles").
+		Select("user_roles.user_id, user_roles.role_id, users.username, roles.name AS role_name, user_roles.active").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("users.deleted_at IS NULL").
+		Where("roles.deleted_at IS NULL")
+}
+
+// GetMembersByRoleIDs 获取指定角色的全部用户，包括尚未生效和已失效的关联
+func (urd *UserRoleDao) GetMembersByRoleIDs(ctx context.Context, roleIDs []string) ([]*UserRoleMember, error) {
+	return urd.GetMembersByRoleIDsTx(ctx, db.Client, roleIDs)
+}
+
+// GetMembersByRoleIDsTx 在事务中获取指定角色的全部用户，包括尚未生效和已失效的关联
+func (urd *UserRoleDao) GetMembersByRoleIDsTx(ctx context.Context, tx *gorm.DB, roleIDs []string) ([]*UserRoleMember, error) {
+	var members []*UserRoleMember
+	if len(roleIDs) == 0 {
+		return members, nil
+	}
+	err := memberQuery(ctx, tx).
+		Where("user_roles.role_id IN ?", roleIDs).
+		Order("users.username").
+		Scan(&members).Error
+	return members, err
+}
+
+// GetMembersByUserIDs 获取指定用户的全部角色，包括尚未生效和已失效的关联
+func (urd *UserRoleDao) GetMembersByUserIDs(ctx context.Context, userIDs []string) ([]*UserRoleMember, error) {
+	return urd.GetMembersByUserIDsTx(ctx, db.Client, userIDs)
+}
+
+// GetMembersByUserIDsTx 在事务中获取指定用户的全部角色，包括尚未生效和已失效的关联
+func (urd *UserRoleDao) GetMembersByUserIDsTx(ctx context.Context, tx *gorm.DB, userIDs []string) ([]*UserRoleMember, error) {
+	var members []*UserRoleMember
+	if len(userIDs) == 0 {
+		return members, nil
+	}
+	err := memberQuery(ctx, tx).
+		Where("user_roles.user_id IN ?", userIDs).
+		Order("roles.name").
+		Scan(&members).Error
+	return members, err
+}
+
+// RemoveUsersFromRoleTx 在事务中移除指定用户与角色的关联
+func (urd *UserRoleDao) RemoveUsersFromRoleTx(ctx context.Context, tx *gorm.DB, roleID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).
+		Delete(&entity.UserRoles{}, "role_id = ? AND user_id IN ?", roleID, userIDs).
+		Error
+}
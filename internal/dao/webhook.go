@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook 订阅状态
+const (
+	WebhookStatusDisabled int8 = 0 // 禁用
+	WebhookStatusEnabled  int8 = 1 // 启用
+)
+
+// WebhookDao Webhook 订阅数据访问对象
+type WebhookDao struct{}
+
+// NewWebhookDao 创建 WebhookDao 实例
+func NewWebhookDao() *WebhookDao {
+	return &WebhookDao{}
+}
+
+// InsertTx 在事务中插入订阅记录
+func (wd *WebhookDao) InsertTx(ctx context.Context, tx *gorm.DB, webhook *entity.Webhooks) error {
+	return tx.WithContext(ctx).Create(webhook).Error
+}
+
+// GetByID 根据 ID 获取未删除的订阅
+func (wd *WebhookDao) GetByID(ctx context.Context, id string) (*entity.Webhooks, error) {
+	var webhook entity.Webhooks
+	err := db.Client.WithContext(ctx).
+		Where(entity.WebhooksColumns.ID+" = ?", id).
+		Where(entity.WebhooksColumns.DeletedAt + " IS NULL").
+		First(&webhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &webhook, err
+}
+
+// GetByIDs 根据 ID 列表获取未删除的订阅
+func (wd *WebhookDao) GetByIDs(ctx context.Context, ids []string) ([]*entity.Webhooks, error) {
+	var webhooks []*entity.Webhooks
+	if len(ids) == 0 {
+		return webhooks, nil
+	}
+	err := db.Client.WithContext(ctx).
+		Where(entity.WebhooksColumns.ID+" IN ?", ids).
+		Where(entity.WebhooksColumns.DeletedAt + " IS NULL").
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+// GetEnabled 获取全部启用的订阅
+func (wd *WebhookDao) GetEnabled(ctx context.Context) ([]*entity.Webhooks, error) {
+	var webhooks []*entity.Webhooks
+	err := db.Client.WithContext(ctx).
+		Where(entity.WebhooksColumns.Status+" = ?", WebhookStatusEnabled).
+		Where(entity.WebhooksColumns.DeletedAt + " IS NULL").
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+// UpdateTx 在事务中更新订阅信息
+func (wd *WebhookDao) UpdateTx(ctx context.Context, tx *gorm.DB, id string, updates map[string]interface{}) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Webhooks{}).
+		Where(entity.WebhooksColumns.ID+" = ?", id).
+		Updates(updates).
+		Error
+}
+
+// SoftDeleteTx 在事务中软删除订阅记录
+func (wd *WebhookDao) SoftDeleteTx(ctx context.Context, tx *gorm.DB, id string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Webhooks{}).
+		Where(entity.WebhooksColumns.ID+" = ?", id).
+		Update(entity.WebhooksColumns.DeletedAt, time.Now()).
+		Error
+}
+
+// Query 分页查询订阅
+func (wd *WebhookDao) Query(ctx context.Context, page int, pageSize int, filters map[string]interface{}) ([]*entity.Webhooks, int64, error) {
+	var (
+		webhooks []*entity.Webhooks
+		total    int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.Webhooks{}).Where(entity.WebhooksColumns.DeletedAt + " IS NULL")
+
+	for key, value := range filters {
+		if value != nil && value != "" {
+			query = query.Where(key+" = ?", value)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Scopes(db.PageScope(page, pageSize)).
+		Order(entity.WebhooksColumns.CreatedAt + " DESC").
+		Find(&webhooks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return webhooks, total, nil
+}
@@ -0,0 +1,159 @@
+package dao
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/db"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryStatusPending   int8 = 0 // 待投递（包括等待重试）
+	WebhookDeliveryStatusSucceeded int8 = 1 // 投递成功
+	WebhookDeliveryStatusFailed    int8 = 2 // 投递失败，不再自动重试
+)
+
+// WebhookDeliveryDao Webhook 投递记录数据访问对象
+type WebhookDeliveryDao struct{}
+
+// NewWebhookDeliveryDao 创建 WebhookDeliveryDao 实例
+func NewWebhookDeliveryDao() *WebhookDeliveryDao {
+	return &WebhookDeliveryDao{}
+}
+
+// InsertIgnoreDuplicates 批量写入投递记录，同一订阅的同一事件已存在时跳过
+// 发件箱可能重复投递同一事件，依靠 webhook_event 唯一索引保证每个订阅只回调一次。
+func (wdd *WebhookDeliveryDao) InsertIgnoreDuplicates(ctx context.Context, deliveries []*entity.WebhookDeliveries) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.Client.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&deliveries, 100).Error
+}
+
+// GetByID 根据ID获取投递记录
+func (wdd *WebhookDeliveryDao) GetByID(ctx context.Context, id string) (*entity.WebhookDeliveries, error) {
+	var delivery entity.WebhookDeliveries
+	err := db.Client.WithContext(ctx).
+		Where(entity.WebhookDeliveriesColumns.ID+" = ?", id).
+		First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &delivery, err
+}
+
+// ClaimDue 领取到期待投递的记录，按创建时间先后排序
+// 领取的记录在 lease 时长内不会被再次领取，回调结束后由 MarkSucceeded 或 MarkFailed 更新状态。
+func (wdd *WebhookDeliveryDao) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*entity.WebhookDeliveries, error) {
+	var deliveries []*entity.WebhookDeliveries
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where(entity.WebhookDeliveriesColumns.Status+" = ?", WebhookDeliveryStatusPending).
+			Where(entity.WebhookDeliveriesColumns.NextAttemptAt+" <= ?", now).
+			Order(entity.WebhookDeliveriesColumns.CreatedAt).
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&entity.WebhookDeliveries{}).
+			Where(entity.WebhookDeliveriesColumns.ID+" IN ?", ids).
+			Update(entity.WebhookDeliveriesColumns.NextAttemptAt, now.Add(lease)).Error
+	})
+	return deliveries, err
+}
+
+// MarkAttempt 记录一次回调的结果
+// 参数:
+//   - updates: 需要更新的列，通常包括状态、尝试次数、响应状态码、响应内容、失败原因、耗时及下次投递时间
+func (wdd *WebhookDeliveryDao) MarkAttempt(ctx context.Context, id string, updates map[string]interface{}) error {
+	updates[entity.WebhookDeliveriesColumns.UpdatedAt] = time.Now()
+	return db.Client.WithContext(ctx).
+		Model(&entity.WebhookDeliveries{}).
+		Where(entity.WebhookDeliveriesColumns.ID+" = ?", id).
+		Updates(updates).Error
+}
+
+// Requeue 将投递记录重新放回待投递队列并立即投递，尝试次数清零
+func (wdd *WebhookDeliveryDao) Requeue(ctx context.Context, id string) error {
+	now := time.Now()
+	return db.Client.WithContext(ctx).
+		Model(&entity.WebhookDeliveries{}).
+		Where(entity.WebhookDeliveriesColumns.ID+" = ?", id).
+		Updates(map[string]interface{}{
+			entity.WebhookDeliveriesColumns.Status:        WebhookDeliveryStatusPending,
+			entity.WebhookDeliveriesColumns.Attempts:      0,
+			entity.WebhookDeliveriesColumns.NextAttemptAt: now,
+			entity.WebhookDeliveriesColumns.UpdatedAt:     now,
+		}).Error
+}
+
+// FailPendingByWebhookIDTx 在事务中将订阅下全部待投递的记录标记为投递失败
+func (wdd *WebhookDeliveryDao) FailPendingByWebhookIDTx(ctx context.Context, tx *gorm.DB, webhookID, reason string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.WebhookDeliveries{}).
+		Where(entity.WebhookDeliveriesColumns.WebhookID+" = ?", webhookID).
+		Where(entity.WebhookDeliveriesColumns.Status+" = ?", WebhookDeliveryStatusPending).
+		Updates(map[string]interface{}{
+			entity.WebhookDeliveriesColumns.Status:    WebhookDeliveryStatusFailed,
+			entity.WebhookDeliveriesColumns.LastError: reason,
+			entity.WebhookDeliveriesColumns.UpdatedAt: time.Now(),
+		}).Error
+}
+
+// AnonymizeSubjectFieldTx 在事务中替换主体为 subjectType/subjectID 的回调请求体中 data 下的 field 字段
+func (wdd *WebhookDeliveryDao) AnonymizeSubjectFieldTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID, field string, value interface{}) error {
+	return tx.WithContext(ctx).
+		Model(&entity.WebhookDeliveries{}).
+		Scopes(eventPayloadFieldScope(entity.WebhookDeliveriesColumns.Payload, subjectType, subjectID, field)).
+		Update(entity.WebhookDeliveriesColumns.Payload, eventPayloadSet(entity.WebhookDeliveriesColumns.Payload, field, value)).
+		Error
+}
+
+// Query 分页查询投递记录
+// 参数:
+//   - filters: 等值过滤条件，键为列名
+func (wdd *WebhookDeliveryDao) Query(ctx context.Context, page int, pageSize int, filters map[string]interface{}) ([]*entity.WebhookDeliveries, int64, error) {
+	var (
+		deliveries []*entity.WebhookDeliveries
+		total      int64
+	)
+
+	query := db.Client.WithContext(ctx).Model(&entity.WebhookDeliveries{})
+
+	// 应用过滤条件
+	for key, value := range filters {
+		if value != nil && value != "" {
+			query = query.Where(key+" = ?", value)
+		}
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	if err := query.Scopes(db.PageScope(page, pageSize)).
+		Order(entity.WebhookDeliveriesColumns.CreatedAt + " DESC").
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
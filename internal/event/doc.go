@@ -0,0 +1,47 @@
+// Package event 发布权限变更事件，供缓存了权限视图的下游服务感知角色和用户的变化
+//
+// 事件由 RecordTx 在变更所在的事务中写入发件箱（outbox 表），与变更一同提交或回滚；
+// 后台任务 outbox_relay 定期领取待投递的事件并投递到 system.outbox.sinks 中配置的投递目标，
+// 失败时按指数退避重试，超过最大尝试次数后进入死信，可在管理端查看并手动重新投递。
+// 投递语义为至少一次，同一事件可能被重复投递。已投递和进入死信的事件分别在 system.outbox.retention、
+// system.outbox.deadRetention 之后由后台任务 outbox_purge 永久删除。
+//
+// redis 投递目标同时写入 Redis Stream 和 Pub/Sub 频道（名称见配置 system.event）：
+//   - Stream: 每个条目包含 type（事件类型）和 payload（事件 JSON）两个字段。
+//     下游服务使用消费组（XREADGROUP / XACK）消费，可从任意位置回放；需要从第一个事件开始回放的消费组
+//     可配置在 system.event.consumerGroups 中，由服务启动时预先创建。
+//   - Pub/Sub: 消息内容为事件 JSON，适用于只需实时通知、不需要回放的场景，离线期间的消息会丢失。
+//
+// http 投递目标将事件 JSON 作为请求体 POST 到 system.outbox.httpEndpoints 中的每个地址，
+// 请求头 X-Event-ID、X-Event-Type 分别为事件ID和事件类型，返回 2xx 状态码视为投递成功。
+//
+// webhook 投递目标将事件分发给管理端订阅了该事件类型的 Webhook，每个订阅生成一条投递记录（webhook_deliveries 表），
+// 由后台任务 webhook_delivery 以事件 JSON 为请求体 POST 到订阅的回调地址，请求头:
+//   - X-Webhook-ID: 投递ID，同一投递记录重试或手动重新投递时保持不变
+//   - X-Webhook-Timestamp: 签名时间（Unix 秒）
+//   - X-Webhook-Signature: sha256={HMAC-SHA256(签名密钥, "{X-Webhook-Timestamp}.{请求体}") 的十六进制摘要}
+//   - X-Event-ID、X-Event-Type: 事件ID和事件类型
+//
+// 接收方应以常量时间比较签名，并拒绝时间戳与当前时间相差过大的请求。返回 2xx 状态码视为投递成功，
+// 否则按 system.webhook 中的配置指数退避重试，超过最大尝试次数后标记为投递失败，可在管理端重新投递。
+//
+// 事件 JSON 的结构见同目录下的 schema.json（JSON Schema draft 2020-12），示例:
+//
+//	{
+//	  "id": "0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d",
+//	  "type": "user.roles_changed",
+//	  "schemaVersion": 1,
+//	  "occurredAt": "2024-11-18T10:00:00+08:00",
+//	  "actor": {"id": "clywh0xv70001rvpgzd6256ns", "type": "admin"},
+//	  "requestId": "5f0c2b1e-6a0d-4e55-8d8e-3c1f4f2f9a10",
+//	  "subjectType": "user",
+//	  "subjectId": "7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33",
+//	  "data": {
+//	    "userId": "7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33",
+//	    "roleIds": ["b1c2d3e4-0000-4000-8000-000000000001"],
+//	    "previousRoleIds": []
+//	  }
+//	}
+//
+// 消费者应按 id 去重，并忽略不认识的事件类型和字段；schemaVersion 变化表示存在不兼容变更。
+package event
@@ -0,0 +1,164 @@
+package event
+
+import (
+	"ByteScience-WAM-Admin/internal/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion 事件结构版本，事件结构发生不兼容变更时递增
+const SchemaVersion = 1
+
+// 事件类型
+const (
+	RoleCreated            = "role.created"             // 新增角色
+	RoleUpdated            = "role.updated"             // 编辑角色（名称、状态、路径或继承关系）
+	RoleDisabled           = "role.disabled"            // 角色被禁用
+	RoleEnabled            = "role.enabled"             // 角色被启用
+	RoleDeleted            = "role.deleted"             // 删除角色
+	RoleRestored           = "role.restored"            // 从回收站恢复角色
+	UserCreated            = "user.created"             // 新增用户
+	UserUpdated            = "user.updated"             // 编辑用户资料
+	UserRolesChanged       = "user.roles_changed"       // 用户的角色分配发生变化
+	UserDisabled           = "user.disabled"            // 用户被禁用
+	UserEnabled            = "user.enabled"             // 用户被启用
+	UserDeleted            = "user.deleted"             // 删除用户
+	UserRestored           = "user.restored"            // 从回收站恢复用户
+	UserPermissionsChanged = "user.permissions_changed" // 用户的有效权限被重新计算（例如角色分配到达生效或失效时间）
+)
+
+// 角色事件中发生变化的内容
+const (
+	RoleChangeName        = "name"        // 名称
+	RoleChangeDescription = "description" // 描述
+	RoleChangeStatus      = "status"      // 状态
+	RoleChangePaths       = "paths"       // 直接拥有的路径
+	RoleChangeParents     = "parents"     // 继承的父角色
+)
+
+// PermissionsReasonSchedule 用户的角色分配到达生效或失效时间，由定时任务重新计算权限
+const PermissionsReasonSchedule = "role_assignment_schedule"
+
+// 事件主体类型
+const (
+	SubjectRole = "role" // 角色
+	SubjectUser = "user" // 业务用户
+)
+
+// Event 权限变更事件，结构说明见 schema.json
+type Event struct {
+	// ID 事件唯一标识，消费者可据此去重
+	ID string `json:"id"`
+	// Type 事件类型
+	Type string `json:"type"`
+	// SchemaVersion 事件结构版本
+	SchemaVersion int `json:"schemaVersion"`
+	// OccurredAt 事件发生时间，RFC3339 格式
+	OccurredAt string `json:"occurredAt"`
+	// Actor 触发变更的操作人，由定时任务触发时为空
+	Actor Actor `json:"actor"`
+	// RequestID 触发变更的请求ID，由定时任务触发时为空
+	RequestID string `json:"requestId,omitempty"`
+	// SubjectType 事件主体类型（role、user）
+	SubjectType string `json:"subjectType"`
+	// SubjectID 事件主体ID，批量事件为空
+	SubjectID string `json:"subjectId,omitempty"`
+	// Data 事件数据，结构由事件类型决定
+	Data interface{} `json:"data"`
+}
+
+// Actor 触发变更的操作人
+type Actor struct {
+	// ID 操作人ID
+	ID string `json:"id,omitempty"`
+	// Type 操作人类型（admin、user、scim）
+	Type string `json:"type,omitempty"`
+}
+
+// RoleData 角色事件数据，用于 role.created、role.updated、role.disabled、role.enabled、role.restored
+type RoleData struct {
+	// RoleID 角色ID
+	RoleID string `json:"roleId"`
+	// Name 角色名称
+	Name string `json:"name"`
+	// Status 角色状态（1: 启用, 0: 禁用）
+	Status int8 `json:"status"`
+	// Changes 发生变化的内容（name、description、status、paths、parents），仅 role.updated 提供
+	Changes []string `json:"changes,omitempty"`
+	// PathIDs 角色直接拥有的路径ID，仅在新增、恢复角色或路径变化时提供，为空表示没有路径
+	PathIDs []string `json:"pathIds,omitempty"`
+	// ParentIDs 角色的父角色ID，仅在新增、恢复角色或继承关系变化时提供，为空表示没有父角色
+	ParentIDs []string `json:"parentIds,omitempty"`
+	// AffectedUserIDs 有效权限被重新计算的用户ID
+	AffectedUserIDs []string `json:"affectedUserIds,omitempty"`
+}
+
+// RoleDeletedData 角色删除事件数据
+type RoleDeletedData struct {
+	// RoleID 角色ID
+	RoleID string `json:"roleId"`
+	// Name 角色名称
+	Name string `json:"name"`
+	// AffectedUserIDs 有效权限被重新计算的用户ID
+	AffectedUserIDs []string `json:"affectedUserIds,omitempty"`
+}
+
+// UserData 用户事件数据，用于 user.created、user.updated、user.disabled、user.enabled、user.deleted、user.restored
+type UserData struct {
+	// UserID 用户ID
+	UserID string `json:"userId"`
+	// UserName 用户名
+	UserName string `json:"userName"`
+	// Status 用户状态（1: 启用, 0: 禁用）
+	Status int8 `json:"status"`
+	// RoleIDs 用户的角色ID，仅 user.created、user.restored 提供
+	RoleIDs []string `json:"roleIds,omitempty"`
+}
+
+// UserRolesData 用户角色变化事件数据
+type UserRolesData struct {
+	// UserID 用户ID
+	UserID string `json:"userId"`
+	// RoleIDs 变更后的角色ID，包括尚未生效或已失效的角色
+	RoleIDs []string `json:"roleIds"`
+	// PreviousRoleIDs 变更前的角色ID
+	PreviousRoleIDs []string `json:"previousRoleIds"`
+	// Assignments 带有效期的角色分配
+	Assignments []RoleAssignment `json:"assignments,omitempty"`
+}
+
+// RoleAssignment 带有效期的角色分配
+type RoleAssignment struct {
+	// RoleID 角色ID
+	RoleID string `json:"roleId"`
+	// ValidFrom 生效时间，RFC3339 格式，为空表示立即生效
+	ValidFrom string `json:"validFrom,omitempty"`
+	// ValidUntil 失效时间，RFC3339 格式，为空表示永久有效
+	ValidUntil string `json:"validUntil,omitempty"`
+}
+
+// UserPermissionsData 用户有效权限重新计算事件数据
+type UserPermissionsData struct {
+	// UserIDs 有效权限被重新计算的用户ID
+	UserIDs []string `json:"userIds"`
+	// Reason 重新计算的原因，例如 role_assignment_schedule
+	Reason string `json:"reason"`
+}
+
+// New 创建事件，操作人和请求ID从请求上下文中读取
+func New(ctx context.Context, eventType, subjectType, subjectID string, data interface{}) *Event {
+	actorID, actorType := utils.GetActor(ctx)
+	return &Event{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		SchemaVersion: SchemaVersion,
+		OccurredAt:    time.Now().Format(time.RFC3339),
+		Actor:         Actor{ID: actorID, Type: actorType},
+		RequestID:     utils.GetRequestID(ctx),
+		SubjectType:   subjectType,
+		SubjectID:     subjectID,
+		Data:          data,
+	}
+}
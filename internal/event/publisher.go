@@ -0,0 +1,54 @@
+package event
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecordTx 在事务中将权限变更事件写入发件箱，与所记录的变更一同提交或回滚
+// 事件由后台投递任务（Relay）在事务提交后投递到配置的投递目标，保证至少投递一次。
+func RecordTx(ctx context.Context, tx *gorm.DB, events ...*Event) error {
+	if !conf.GlobalConf.System.Event.Enabled || len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]*entity.Outbox, 0, len(events))
+	for _, evt := range events {
+		payload, err := json.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, &entity.Outbox{
+			ID:            evt.ID,
+			EventType:     evt.Type,
+			Payload:       string(payload),
+			Status:        dao.OutboxStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return dao.NewOutboxDao().InsertBatchTx(ctx, tx, rows)
+}
+
+// EnsureConsumerGroups 创建配置的消费组，服务启动时调用
+func EnsureConsumerGroups(ctx context.Context) error {
+	config := conf.GlobalConf.System.Event
+	if !config.Enabled {
+		return nil
+	}
+	for _, group := range config.ConsumerGroups {
+		if err := redis.EnsureConsumerGroup(ctx, config.Stream, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
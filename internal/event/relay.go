@@ -0,0 +1,149 @@
+package event
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxLastErrorLength 失败原因的最大长度，与 outbox.last_error、webhook_deliveries.last_error 列一致
+const maxLastErrorLength = 1024
+
+// Relay 发件箱投递器，将待投递事件依次投递到全部投递目标
+type Relay struct {
+	dao   *dao.OutboxDao
+	sinks []Sink
+}
+
+// NewRelay 创建 Relay 实例
+func NewRelay(sinks ...Sink) *Relay {
+	return &Relay{
+		dao:   dao.NewOutboxDao(),
+		sinks: sinks,
+	}
+}
+
+// Run 投递全部到期的待投递事件，直到没有可领取的记录
+// 每批领取的数量保证逐条投递的最长耗时不超过租约；剩余租约不足以完成下一次投递时停止，
+// 未投递的记录在租约到期后重新领取，避免租约过期后被其他实例领取而重复投递。
+// 返回:
+//   - int: 投递成功的事件数量
+func (r *Relay) Run(ctx context.Context) (int, error) {
+	config := conf.GlobalConf.System.Outbox
+	timeout := r.timeout()
+	limit := leaseBatchSize(config.BatchSize, config.Lease, timeout)
+	delivered := 0
+	for {
+		claimedAt := time.Now()
+		rows, err := r.dao.ClaimDue(ctx, claimedAt, limit, config.Lease)
+		if err != nil {
+			logger.Logger.Errorf("[OutboxRelay] Error claiming outbox rows: %v", err)
+			return delivered, err
+		}
+		if len(rows) == 0 {
+			return delivered, nil
+		}
+
+		leaseDeadline := claimedAt.Add(config.Lease)
+		for i, row := range rows {
+			if err = ctx.Err(); err != nil {
+				return delivered, err
+			}
+			if i > 0 && leaseExpiring(leaseDeadline, timeout) {
+				logger.Logger.Warnf("[OutboxRelay] Lease about to expire, %d claimed row(s) left for the next run", len(rows)-i)
+				return delivered, nil
+			}
+			if r.deliver(ctx, row) {
+				delivered++
+			}
+		}
+
+		if len(rows) < limit {
+			return delivered, nil
+		}
+	}
+}
+
+// timeout 投递一个事件到全部投递目标的最长耗时
+func (r *Relay) timeout() time.Duration {
+	var timeout time.Duration
+	for _, sink := range r.sinks {
+		timeout += sink.Timeout()
+	}
+	return timeout
+}
+
+// leaseBatchSize 计算每批领取的数量：不超过 batchSize，且逐条投递的最长耗时（数量 × timeout）不超过租约，至少为 1
+func leaseBatchSize(batchSize int, lease, timeout time.Duration) int {
+	if timeout <= 0 {
+		return max(batchSize, 1)
+	}
+	return max(min(batchSize, int(lease/timeout)), 1)
+}
+
+// leaseExpiring 剩余租约是否不足以完成一次最长耗时为 timeout 的投递
+func leaseExpiring(deadline time.Time, timeout time.Duration) bool {
+	return time.Now().Add(timeout).After(deadline)
+}
+
+// deliver 将事件投递到全部投递目标并更新发件箱记录
+// 任一投递目标失败时整条记录按退避时间重试，已成功的投递目标会再次收到该事件。
+func (r *Relay) deliver(ctx context.Context, row *entity.Outbox) bool {
+	config := conf.GlobalConf.System.Outbox
+	attempts := row.Attempts + 1
+
+	var deliverErr error
+	for _, sink := range r.sinks {
+		if err := sink.Deliver(ctx, row); err != nil {
+			deliverErr = fmt.Errorf("%s: %w", sink.Name(), err)
+			break
+		}
+	}
+
+	now := time.Now()
+	if deliverErr == nil {
+		if err := r.dao.MarkDelivered(ctx, row.ID, attempts, now); err != nil {
+			logger.Logger.Errorf("[OutboxRelay] Error marking outbox row %s delivered: %v", row.ID, err)
+		}
+		return true
+	}
+
+	status := dao.OutboxStatusPending
+	if attempts >= config.MaxAttempts {
+		status = dao.OutboxStatusDead
+		logger.Logger.Errorf("[OutboxRelay] Event %s %s moved to dead letter after %d attempts: %v",
+			row.EventType, row.ID, attempts, deliverErr)
+	}
+	nextAttemptAt := now.Add(backoff(config.BackoffBase, config.BackoffMax, attempts))
+	lastError := truncateText(deliverErr.Error(), maxLastErrorLength)
+	if err := r.dao.MarkFailed(ctx, row.ID, status, attempts, nextAttemptAt, lastError); err != nil {
+		logger.Logger.Errorf("[OutboxRelay] Error marking outbox row %s failed: %v", row.ID, err)
+	}
+	return false
+}
+
+// backoff 计算第 attempts 次失败后的重试等待时长，从 base 开始指数递增并以 max 封顶
+func backoff(base, max time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+// truncateText 将文本截断为最多 max 个字符，并替换无效的 UTF-8 字节，保证可以写入数据库
+func truncateText(s string, max int) string {
+	runes := []rune(strings.ToValidUTF8(s, "\uFFFD"))
+	if len(runes) > max {
+		runes = runes[:max]
+	}
+	return string(runes)
+}
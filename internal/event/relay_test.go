@@ -0,0 +1,80 @@
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		max      time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first failure", base: 5 * time.Second, max: time.Hour, attempts: 1, want: 5 * time.Second},
+		{name: "doubles", base: 5 * time.Second, max: time.Hour, attempts: 2, want: 10 * time.Second},
+		{name: "doubles again", base: 5 * time.Second, max: time.Hour, attempts: 4, want: 40 * time.Second},
+		{name: "capped", base: 5 * time.Second, max: time.Minute, attempts: 5, want: time.Minute},
+		{name: "many attempts do not overflow", base: 5 * time.Second, max: time.Hour, attempts: 1000, want: time.Hour},
+		{name: "base above max", base: 2 * time.Hour, max: time.Hour, attempts: 1, want: time.Hour},
+		{name: "zero attempts", base: 5 * time.Second, max: time.Hour, attempts: 0, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoff(tt.base, tt.max, tt.attempts); got != tt.want {
+				t.Errorf("backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{name: "short", s: "timeout", max: 10, want: "timeout"},
+		{name: "exact", s: "timeout", max: 7, want: "timeout"},
+		{name: "truncated", s: "timeout", max: 4, want: "time"},
+		{name: "counts characters not bytes", s: "连接超时了", max: 4, want: "连接超时"},
+		{name: "invalid utf-8 replaced", s: "bad\xffbyte", max: 10, want: "bad�byte"},
+		{name: "empty", s: "", max: 4, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateText(tt.s, tt.max); got != tt.want {
+				t.Errorf("truncateText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaseBatchSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		lease     time.Duration
+		timeout   time.Duration
+		want      int
+	}{
+		{name: "batch fits within lease", batchSize: 10, lease: time.Minute, timeout: time.Second, want: 10},
+		{name: "capped by lease", batchSize: 100, lease: time.Minute, timeout: 10 * time.Second, want: 6},
+		{name: "timeout above lease", batchSize: 100, lease: time.Minute, timeout: 2 * time.Minute, want: 1},
+		{name: "no timeout", batchSize: 100, lease: time.Minute, timeout: 0, want: 100},
+		{name: "invalid batch size", batchSize: 0, lease: time.Minute, timeout: time.Second, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaseBatchSize(tt.batchSize, tt.lease, tt.timeout); got != tt.want {
+				t.Errorf("leaseBatchSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
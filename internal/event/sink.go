@@ -0,0 +1,119 @@
+package event
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// 投递目标名称，对应配置 system.outbox.sinks
+const (
+	SinkRedis   = "redis"   // Redis Stream 与 Pub/Sub 频道
+	SinkHTTP    = "http"    // HTTP 回调地址
+	SinkWebhook = "webhook" // 通过管理端订阅的 Webhook，见 WebhookDispatcher
+)
+
+// Sink 事件投递目标
+// Deliver 返回 nil 表示投递成功；同一事件可能被重复投递，投递目标或其下游应按事件ID去重。
+// Timeout 返回投递一个事件的最长耗时，用于限制每批领取的数量，耗时可忽略时返回 0。
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, row *entity.Outbox) error
+	Timeout() time.Duration
+}
+
+// Sinks 根据配置创建启用的投递目标，忽略未知的名称
+func Sinks() []Sink {
+	var sinks []Sink
+	for _, name := range conf.GlobalConf.System.Outbox.Sinks {
+		switch name {
+		case SinkRedis:
+			sinks = append(sinks, redisSink{})
+		case SinkHTTP:
+			sinks = append(sinks, newHTTPSink(conf.GlobalConf.System.Outbox.HTTPEndpoints))
+		case SinkWebhook:
+			sinks = append(sinks, newWebhookSink())
+		default:
+			logger.Logger.Errorf("[Outbox] Unknown sink %s is ignored", name)
+		}
+	}
+	return sinks
+}
+
+// redisSink 将事件写入 Redis Stream 并广播到 Pub/Sub 频道（Stream 条目字段: type、payload）
+type redisSink struct{}
+
+// Name 投递目标名称
+func (redisSink) Name() string {
+	return SinkRedis
+}
+
+// Timeout 投递一个事件的最长耗时，写入 Redis 的耗时可忽略
+func (redisSink) Timeout() time.Duration {
+	return 0
+}
+
+// Deliver 投递事件
+func (redisSink) Deliver(ctx context.Context, row *entity.Outbox) error {
+	config := conf.GlobalConf.System.Event
+	fields := map[string]interface{}{
+		"type":    row.EventType,
+		"payload": row.Payload,
+	}
+	return redis.PublishEvent(ctx, config.Stream, config.Channel, config.MaxLen, fields, row.Payload)
+}
+
+// httpSink 将事件以 JSON 请求体 POST 到配置的每个地址，任一地址返回非 2xx 状态码即视为投递失败
+type httpSink struct {
+	endpoints []string
+	client    *http.Client
+}
+
+// newHTTPSink 创建 httpSink 实例
+func newHTTPSink(endpoints []string) httpSink {
+	return httpSink{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: conf.GlobalConf.System.Outbox.HTTPTimeout},
+	}
+}
+
+// Name 投递目标名称
+func (hs httpSink) Name() string {
+	return SinkHTTP
+}
+
+// Timeout 投递一个事件的最长耗时，事件依次 POST 到每个地址
+func (hs httpSink) Timeout() time.Duration {
+	return time.Duration(len(hs.endpoints)) * hs.client.Timeout
+}
+
+// Deliver 投递事件
+func (hs httpSink) Deliver(ctx context.Context, row *entity.Outbox) error {
+	for _, endpoint := range hs.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBufferString(row.Payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderEventID, row.ID)
+		req.Header.Set(HeaderEventType, row.EventType)
+
+		resp, err := hs.client.Do(req)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%s responded with status %d", endpoint, resp.StatusCode)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package event
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook 回调请求头
+const (
+	HeaderWebhookID        = "X-Webhook-ID"        // 投递ID，同一投递记录重试时保持不变
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp" // 签名时间，Unix 秒
+	HeaderWebhookSignature = "X-Webhook-Signature" // 签名，格式为 sha256={十六进制摘要}
+	HeaderEventID          = "X-Event-ID"          // 事件ID
+	HeaderEventType        = "X-Event-Type"        // 事件类型
+)
+
+// maxDiscardResponseLength 读取并丢弃的响应内容最大长度，不超过该长度时连接可以复用
+const maxDiscardResponseLength = 64 << 10
+
+// webhookSink 将事件分发给订阅了该事件类型的全部启用的 Webhook，为每个订阅生成一条投递记录
+// 实际回调由 WebhookDispatcher 异步完成，单个订阅的失败不会影响其他订阅，也不会阻塞发件箱。
+type webhookSink struct {
+	webhookDao  *dao.WebhookDao
+	deliveryDao *dao.WebhookDeliveryDao
+}
+
+// newWebhookSink 创建 webhookSink 实例
+func newWebhookSink() webhookSink {
+	return webhookSink{
+		webhookDao:  dao.NewWebhookDao(),
+		deliveryDao: dao.NewWebhookDeliveryDao(),
+	}
+}
+
+// Name 投递目标名称
+func (ws webhookSink) Name() string {
+	return SinkWebhook
+}
+
+// Timeout 投递一个事件的最长耗时，只写入投递记录，耗时可忽略
+func (ws webhookSink) Timeout() time.Duration {
+	return 0
+}
+
+// Deliver 为订阅了该事件的 Webhook 生成投递记录，重复投递的事件不会重复生成
+func (ws webhookSink) Deliver(ctx context.Context, row *entity.Outbox) error {
+	webhooks, err := ws.webhookDao.GetEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []*entity.WebhookDeliveries
+	for _, webhook := range webhooks {
+		if !Subscribed(webhook.Events, row.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, &entity.WebhookDeliveries{
+			ID:            uuid.New().String(),
+			WebhookID:     webhook.ID,
+			EventID:       row.ID,
+			EventType:     row.EventType,
+			Payload:       row.Payload,
+			Status:        dao.WebhookDeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return ws.deliveryDao.InsertIgnoreDuplicates(ctx, deliveries)
+}
+
+// Subscribed 判断订阅的事件类型（JSON 数组）是否包含 eventType，空数组表示订阅全部事件
+func Subscribed(events string, eventType string) bool {
+	var eventTypes []string
+	if err := json.Unmarshal([]byte(events), &eventTypes); err != nil {
+		return false
+	}
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, t := range eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDispatcher Webhook 回调器，将待投递记录签名后 POST 到订阅的回调地址
+type WebhookDispatcher struct {
+	webhookDao  *dao.WebhookDao
+	deliveryDao *dao.WebhookDeliveryDao
+	client      *http.Client
+}
+
+// NewWebhookDispatcher 创建 WebhookDispatcher 实例
+func NewWebhookDispatcher() *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookDao:  dao.NewWebhookDao(),
+		deliveryDao: dao.NewWebhookDeliveryDao(),
+		client:      newWebhookClient(conf.GlobalConf.System.Webhook),
+	}
+}
+
+// newWebhookClient 创建回调使用的 HTTP 客户端
+// 不跟随重定向，3xx 响应视为投递失败；未开启 allowPrivate 时拒绝连接非公网地址，
+// 且不经过代理，保证检查的是实际连接的地址。
+func newWebhookClient(config conf.Webhook) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !config.AllowPrivate {
+		dialer.Control = utils.WebhookDialControl
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run 回调全部到期的待投递记录，直到没有可领取的记录
+// 与 Relay.Run 相同，每批领取的数量和剩余租约保证回调在租约到期前完成，避免重复回调。
+// 返回:
+//   - int: 回调成功的记录数量
+func (wd *WebhookDispatcher) Run(ctx context.Context) (int, error) {
+	config := conf.GlobalConf.System.Webhook
+	limit := leaseBatchSize(config.BatchSize, config.Lease, config.Timeout)
+	succeeded := 0
+	for {
+		claimedAt := time.Now()
+		deliveries, err := wd.deliveryDao.ClaimDue(ctx, claimedAt, limit, config.Lease)
+		if err != nil {
+			logger.Logger.Errorf("[WebhookDispatcher] Error claiming webhook deliveries: %v", err)
+			return succeeded, err
+		}
+		if len(deliveries) == 0 {
+			return succeeded, nil
+		}
+
+		webhookIDs := make([]string, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+		}
+		webhooks, err := wd.webhookDao.GetByIDs(ctx, webhookIDs)
+		if err != nil {
+			logger.Logger.Errorf("[WebhookDispatcher] Error fetching webhooks: %v", err)
+			return succeeded, err
+		}
+		webhookMap := make(map[string]*entity.Webhooks, len(webhooks))
+		for _, webhook := range webhooks {
+			webhookMap[webhook.ID] = webhook
+		}
+
+		leaseDeadline := claimedAt.Add(config.Lease)
+		for i, delivery := range deliveries {
+			if err = ctx.Err(); err != nil {
+				return succeeded, err
+			}
+			if i > 0 && leaseExpiring(leaseDeadline, config.Timeout) {
+				logger.Logger.Warnf("[WebhookDispatcher] Lease about to expire, %d claimed delivery(s) left for the next run", len(deliveries)-i)
+				return succeeded, nil
+			}
+			if wd.deliver(ctx, webhookMap[delivery.WebhookID], delivery) {
+				succeeded++
+			}
+		}
+
+		if len(deliveries) < limit {
+			return succeeded, nil
+		}
+	}
+}
+
+// deliver 回调一条投递记录并记录结果，订阅已删除或已禁用时直接标记为投递失败
+func (wd *WebhookDispatcher) deliver(ctx context.Context, webhook *entity.Webhooks, delivery *entity.WebhookDeliveries) bool {
+	config := conf.GlobalConf.System.Webhook
+	attempts := delivery.Attempts + 1
+
+	if webhook == nil || webhook.Status != dao.WebhookStatusEnabled {
+		reason := "webhook deleted"
+		if webhook != nil {
+			reason = "webhook disabled"
+		}
+		wd.markAttempt(ctx, delivery.ID, map[string]interface{}{
+			entity.WebhookDeliveriesColumns.Status:    dao.WebhookDeliveryStatusFailed,
+			entity.WebhookDeliveriesColumns.LastError: reason,
+		})
+		return false
+	}
+
+	start := time.Now()
+	responseStatus, err := wd.post(ctx, webhook, delivery)
+	now := time.Now()
+	updates := map[string]interface{}{
+		entity.WebhookDeliveriesColumns.Attempts:       attempts,
+		entity.WebhookDeliveriesColumns.ResponseStatus: responseStatus,
+		entity.WebhookDeliveriesColumns.DurationMs:     int(now.Sub(start).Milliseconds()),
+	}
+	if err == nil && (responseStatus < 200 || responseStatus >= 300) {
+		err = fmt.Errorf("%s responded with status %d", webhook.URL, responseStatus)
+	}
+
+	if err == nil {
+		updates[entity.WebhookDeliveriesColumns.Status] = dao.WebhookDeliveryStatusSucceeded
+		updates[entity.WebhookDeliveriesColumns.LastError] = ""
+		updates[entity.WebhookDeliveriesColumns.DeliveredAt] = now
+		wd.markAttempt(ctx, delivery.ID, updates)
+		return true
+	}
+
+	updates[entity.WebhookDeliveriesColumns.LastError] = truncateText(err.Error(), maxLastErrorLength)
+	updates[entity.WebhookDeliveriesColumns.NextAttemptAt] = now.Add(backoff(config.BackoffBase, config.BackoffMax, attempts))
+	if attempts >= config.MaxAttempts {
+		updates[entity.WebhookDeliveriesColumns.Status] = dao.WebhookDeliveryStatusFailed
+		logger.Logger.Errorf("[WebhookDispatcher] Delivery %s of event %s to webhook %s failed after %d attempts: %v",
+			delivery.ID, delivery.EventID, webhook.ID, attempts, err)
+	}
+	wd.markAttempt(ctx, delivery.ID, updates)
+	return false
+}
+
+// post 发送签名后的回调请求，响应内容不保存，避免通过投递记录读取回调地址的响应
+// 返回:
+//   - int: 响应状态码，未收到响应时为 0
+func (wd *WebhookDispatcher) post(ctx context.Context, webhook *entity.Webhooks, delivery *entity.WebhookDeliveries) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookID, delivery.ID)
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, utils.SignWebhookPayload(webhook.Secret, timestamp, body))
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEventType, delivery.EventType)
+
+	resp, err := wd.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// 读取并丢弃响应内容以便复用连接，过长的响应直接关闭连接
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDiscardResponseLength))
+	return resp.StatusCode, nil
+}
+
+// markAttempt 更新投递记录，失败只记录日志，租约到期后记录会被重新领取
+func (wd *WebhookDispatcher) markAttempt(ctx context.Context, id string, updates map[string]interface{}) {
+	if err := wd.deliveryDao.MarkAttempt(ctx, id, updates); err != nil {
+		logger.Logger.Errorf("[WebhookDispatcher] Error updating webhook delivery %s: %v", id, err)
+	}
+}
@@ -0,0 +1,54 @@
+package event
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/utils"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewWebhookClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		allowPrivate bool
+		path         string
+		wantStatus   int
+		wantErr      error
+	}{
+		{name: "loopback rejected", path: "/target", wantErr: utils.ErrWebhookAddressForbidden},
+		{name: "loopback allowed", allowPrivate: true, path: "/target", wantStatus: http.StatusNoContent},
+		{name: "redirect not followed", allowPrivate: true, path: "/redirect", wantStatus: http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newWebhookClient(conf.Webhook{Timeout: 5 * time.Second, AllowPrivate: tt.allowPrivate})
+			resp, err := client.Post(server.URL+tt.path, "application/json", nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Post() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Post() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
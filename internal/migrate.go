@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/migration"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// migrateUsage 数据库迁移命令的用法
+const migrateUsage = `usage: migrate <command>
+  up           执行全部待执行的迁移
+  down [N]     回滚最近执行的 N 个迁移，默认为 1
+  status       查看迁移的执行状态`
+
+// migrateUp 执行全部待执行的数据库迁移
+func migrateUp(ctx context.Context) error {
+	migrator, err := migration.NewMigrator(db.Client, conf.GlobalConf.Mysql.MigrateLockTimeout)
+	if err != nil {
+		return err
+	}
+	count, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Logger.Infof("=== Database migrated, %d migration(s) applied ===", count)
+	return nil
+}
+
+// Migrate 数据库迁移命令，不启动 HTTP 服务
+// 用法: migrate up | migrate down [N] | migrate status
+func Migrate(mode string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	// 解析回滚步数
+	steps := 1
+	if args[0] == "down" && len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of migrations to roll back: %q", args[1])
+		}
+		steps = n
+	}
+
+	// 加载配置文件并初始化日志和 MySQL 连接
+	conf.LoadConf(mode)
+	if err := logger.NewLogger(); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	db.MysqlInit()
+	defer db.Close()
+
+	ctx := context.Background()
+	migrator, err := migration.NewMigrator(db.Client, conf.GlobalConf.Mysql.MigrateLockTimeout)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) applied\n", count)
+	case "down":
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) rolled back\n", count)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
+
+// printMigrationStatus 以表格形式输出迁移的执行状态
+func printMigrationStatus(statuses []migration.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case status.Dirty:
+			state = "dirty"
+		case status.Unknown:
+			state = "applied (unknown)"
+		case status.Applied:
+			state = "applied"
+		}
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,358 @@
+package migration
+
+import (
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 迁移文件命名为 <版本号>_<名称>.up.sql 和 <版本号>_<名称>.down.sql，版本号按数值升序执行，
+// 每个版本必须同时提供 up 和 down 两个文件。文件中的多条语句以行尾的分号分隔。
+// down 文件只包含注释时该版本不可回滚，用于引入迁移前已存在的基线表，避免回滚时删除业务数据。
+//
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// migrationFileRegexp 迁移文件名格式
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// schemaMigrationsTable 记录已执行迁移的表
+const schemaMigrationsTable = "schema_migrations"
+
+// createSchemaMigrationsSQL 建表语句，由迁移器自行维护，不属于任何迁移版本
+const createSchemaMigrationsSQL = "CREATE TABLE IF NOT EXISTS `" + schemaMigrationsTable + "` (\n" +
+	"  `version` bigint NOT NULL COMMENT '迁移版本号',\n" +
+	"  `name` varchar(128) NOT NULL COMMENT '迁移名称',\n" +
+	"  `dirty` tinyint NOT NULL DEFAULT '0' COMMENT '是否执行中断(1: 是, 0: 否)，为 1 时需人工修复后删除该记录或将其置为 0',\n" +
+	"  `applied_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '执行时间',\n" +
+	"  PRIMARY KEY (`version`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='数据库迁移记录表'"
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int64  // 版本号
+	Name    string // 名称
+	Up      string // 升级语句
+	Down    string // 回滚语句
+	// Irreversible 是否不可回滚，down 文件中没有语句时为 true
+	Irreversible bool
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Version   int64     // 版本号
+	Name      string    // 名称
+	Applied   bool      // 是否已执行
+	Dirty     bool      // 是否执行中断
+	Unknown   bool      // 已执行但当前程序中不存在，通常是由更新版本的程序执行的
+	AppliedAt time.Time // 执行时间，未执行时为零值
+}
+
+// appliedMigration schema_migrations 表中的一条记录
+type appliedMigration struct {
+	Version   int64     `gorm:"column:version"`
+	Name      string    `gorm:"column:name"`
+	Dirty     int8      `gorm:"column:dirty"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+// Migrator 数据库迁移器
+// 执行迁移前通过 MySQL 命名锁（GET_LOCK）串行化，多个实例同时启动时只有一个实例执行迁移，
+// 其余实例等待锁释放后发现已无待执行的迁移。
+type Migrator struct {
+	db          *gorm.DB
+	migrations  []*Migration
+	lockTimeout time.Duration
+}
+
+// NewMigrator 创建迁移器并加载内嵌的迁移文件
+// 参数:
+//   - lockTimeout: 等待迁移锁的最长时间
+func NewMigrator(db *gorm.DB, lockTimeout time.Duration) (*Migrator, error) {
+	migrations, err := load(sqlFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, lockTimeout: lockTimeout}, nil
+}
+
+// load 读取并校验迁移文件，按版本号升序返回
+func load(files fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(files, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if len(splitStatements(migration.Up)) == 0 || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migration %d_%s must have non-empty up and down files", migration.Version, migration.Name)
+		}
+		migration.Irreversible = len(splitStatements(migration.Down)) == 0
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up 按版本号升序执行全部待执行的迁移
+// 返回:
+//   - int: 本次执行的迁移数量
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err = m.run(conn, migration, true); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down 按版本号倒序回滚最近执行的 steps 个迁移，其中包含不可回滚的版本时不回滚任何迁移
+// 返回:
+//   - int: 本次回滚的迁移数量
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	byVersion := make(map[int64]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		// 先校验全部待回滚的版本，遇到无法回滚的版本时不执行任何回滚
+		if len(versions) > steps {
+			versions = versions[:steps]
+		}
+		rollbacks := make([]*Migration, 0, len(versions))
+		for _, version := range versions {
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s is not embedded in this binary and cannot be rolled back", version, applied[version].Name)
+			}
+			if migration.Irreversible {
+				return fmt.Errorf("migration %d_%s is irreversible and cannot be rolled back", migration.Version, migration.Name)
+			}
+			rollbacks = append(rollbacks, migration)
+		}
+
+		for _, migration := range rollbacks {
+			if err = m.run(conn, migration, false); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status 返回全部迁移的执行状态，按版本号升序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec(createSchemaMigrationsSQL).Error; err != nil {
+			return err
+		}
+		applied, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if record, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.Dirty = record.Dirty == 1
+				status.AppliedAt = record.AppliedAt
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, record := range applied {
+			statuses = append(statuses, Status{
+				Version:   record.Version,
+				Name:      record.Name,
+				Applied:   true,
+				Dirty:     record.Dirty == 1,
+				Unknown:   true,
+				AppliedAt: record.AppliedAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// withLock 持有迁移锁执行 fn，锁名为 schema_migrations
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return db.WithLock(ctx, m.db, schemaMigrationsTable, m.lockTimeout, func(conn *gorm.DB) error {
+		if err := conn.Exec(createSchemaMigrationsSQL).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// applied 返回已执行的迁移，存在执行中断的迁移时返回错误
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]*appliedMigration, error) {
+	records, err := m.records(conn)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Dirty == 1 {
+			return nil, fmt.Errorf("migration %d_%s is dirty: fix the schema manually, then delete its row from %s "+
+				"(not applied) or set dirty to 0 (applied)", record.Version, record.Name, schemaMigrationsTable)
+		}
+	}
+	return records, nil
+}
+
+// records 读取 schema_migrations 表中的全部记录
+func (m *Migrator) records(conn *gorm.DB) (map[int64]*appliedMigration, error) {
+	var records []*appliedMigration
+	if err := conn.Table(schemaMigrationsTable).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]*appliedMigration, len(records))
+	for _, record := range records {
+		result[record.Version] = record
+	}
+	return result, nil
+}
+
+// run 执行一个迁移的升级或回滚语句
+// MySQL 的 DDL 会隐式提交事务，无法整体回滚，因此执行前先将记录标记为中断，全部语句成功后再清除标记（升级）或删除记录（回滚）。
+func (m *Migrator) run(conn *gorm.DB, migration *Migration, up bool) error {
+	script, direction := migration.Down, "down"
+	if up {
+		script, direction = migration.Up, "up"
+	}
+
+	var err error
+	if up {
+		err = conn.Exec("INSERT INTO `"+schemaMigrationsTable+"` (version, name, dirty, applied_at) VALUES (?, ?, 1, ?)",
+			migration.Version, migration.Name, time.Now()).Error
+	} else {
+		err = conn.Exec("UPDATE `"+schemaMigrationsTable+"` SET dirty = 1 WHERE version = ?", migration.Version).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range splitStatements(script) {
+		if err = conn.Exec(statement).Error; err != nil {
+			return fmt.Errorf("migration %d_%s (%s) failed: %w", migration.Version, migration.Name, direction, err)
+		}
+	}
+
+	if up {
+		err = conn.Exec("UPDATE `"+schemaMigrationsTable+"` SET dirty = 0 WHERE version = ?", migration.Version).Error
+	} else {
+		err = conn.Exec("DELETE FROM `"+schemaMigrationsTable+"` WHERE version = ?", migration.Version).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Infof("[Migration] %d_%s (%s) completed", migration.Version, migration.Name, direction)
+	return nil
+}
+
+// splitStatements 将迁移文件拆分为单条语句：以分号结尾的行表示语句结束，忽略空行和 -- 开头的注释行
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		current    []string
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(current) == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";"))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		statements = append(statements, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+	return statements
+}
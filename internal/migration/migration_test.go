@@ -0,0 +1,156 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{name: "single statement", script: "DROP TABLE `a`;\n", want: []string{"DROP TABLE `a`"}},
+		{
+			name:   "multiple statements",
+			script: "DROP TABLE `a`;\n\nDROP TABLE `b`;\n",
+			want:   []string{"DROP TABLE `a`", "DROP TABLE `b`"},
+		},
+		{
+			name:   "multi-line statement",
+			script: "ALTER TABLE `a`\n  ADD COLUMN `b` int,\n  ADD COLUMN `c` int;\n",
+			want:   []string{"ALTER TABLE `a`\n  ADD COLUMN `b` int,\n  ADD COLUMN `c` int"},
+		},
+		{
+			name:   "comments skipped",
+			script: "-- drop a\nDROP TABLE `a`;\n  -- drop b\nDROP TABLE `b`;\n",
+			want:   []string{"DROP TABLE `a`", "DROP TABLE `b`"},
+		},
+		{
+			name:   "semicolon inside line kept",
+			script: "INSERT INTO `a` VALUES ('x;y');\n",
+			want:   []string{"INSERT INTO `a` VALUES ('x;y')"},
+		},
+		{name: "missing trailing semicolon", script: "DROP TABLE `a`", want: []string{"DROP TABLE `a`"}},
+		{name: "windows line endings", script: "DROP TABLE `a`;\r\nDROP TABLE `b`;\r\n", want: []string{"DROP TABLE `a`", "DROP TABLE `b`"}},
+		{name: "comments only", script: "-- irreversible\n\n", want: nil},
+		{name: "empty", script: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+	tests := []struct {
+		name             string
+		files            fstest.MapFS
+		wantVersions     []int64
+		wantIrreversible []bool
+		wantErr          bool
+	}{
+		{
+			name: "sorted by numeric version",
+			files: fstest.MapFS{
+				"sql/10_create_b.up.sql":   file("CREATE TABLE `b` (`id` int);"),
+				"sql/10_create_b.down.sql": file("DROP TABLE `b`;"),
+				"sql/9_create_a.up.sql":    file("CREATE TABLE `a` (`id` int);"),
+				"sql/9_create_a.down.sql":  file("-- baseline\n"),
+			},
+			wantVersions:     []int64{9, 10},
+			wantIrreversible: []bool{true, false},
+		},
+		{
+			name: "missing down file",
+			files: fstest.MapFS{
+				"sql/0001_create_a.up.sql": file("CREATE TABLE `a` (`id` int);"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty down file",
+			files: fstest.MapFS{
+				"sql/0001_create_a.up.sql":   file("CREATE TABLE `a` (`id` int);"),
+				"sql/0001_create_a.down.sql": file("\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "up file without statements",
+			files: fstest.MapFS{
+				"sql/0001_create_a.up.sql":   file("-- nothing\n"),
+				"sql/0001_create_a.down.sql": file("DROP TABLE `a`;"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"sql/0001_create_a.up.sql":   file("CREATE TABLE `a` (`id` int);"),
+				"sql/0001_create_a.down.sql": file("DROP TABLE `a`;"),
+				"sql/0001_create_b.up.sql":   file("CREATE TABLE `b` (`id` int);"),
+				"sql/0001_create_b.down.sql": file("DROP TABLE `b`;"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"sql/create_a.sql": file("CREATE TABLE `a` (`id` int);"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var versions []int64
+			var irreversible []bool
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+				irreversible = append(irreversible, migration.Irreversible)
+			}
+			if !reflect.DeepEqual(versions, tt.wantVersions) {
+				t.Errorf("load() versions = %v, want %v", versions, tt.wantVersions)
+			}
+			if !reflect.DeepEqual(irreversible, tt.wantIrreversible) {
+				t.Errorf("load() irreversible = %v, want %v", irreversible, tt.wantIrreversible)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	// 0001–0008 为引入迁移前已存在的基线表
+	const baselineVersion = 8
+
+	migrations, err := load(sqlFiles)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s: versions must be consecutive, want %d", migration.Version, migration.Name, i+1)
+		}
+		if baseline := migration.Version <= baselineVersion; migration.Irreversible != baseline {
+			t.Errorf("migration %d_%s: irreversible = %v, want %v", migration.Version, migration.Name, migration.Irreversible, baseline)
+		}
+	}
+}
@@ -0,0 +1 @@
+-- 基线表结构，已有数据库中的表可能在引入迁移前已手工创建，回滚会删除业务数据，因此不可回滚
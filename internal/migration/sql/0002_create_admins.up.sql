@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `admins` (
+  `id` varchar(36) NOT NULL COMMENT '唯一标识',
+  `username` varchar(128) NOT NULL COMMENT '用户名',
+  `nickname` varchar(128) DEFAULT NULL COMMENT '昵称',
+  `password` varchar(64) NOT NULL COMMENT '加密后的密码',
+  `email` varchar(256) DEFAULT NULL COMMENT '邮箱',
+  `phone` varchar(32) DEFAULT NULL COMMENT '手机号码',
+  `remark` varchar(256) DEFAULT NULL COMMENT '备注',
+  `last_login_at` datetime DEFAULT NULL COMMENT '上次登录时间',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `username` (`username`,`deleted_at`),
+  UNIQUE KEY `email_deleted_at` (`email`,`deleted_at`),
+  UNIQUE KEY `phone_deleted_at` (`phone`,`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='管理员表';
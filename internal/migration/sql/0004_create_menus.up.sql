@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `menus` (
+  `id` char(36) NOT NULL COMMENT '菜单ID',
+  `parent_id` char(36) DEFAULT NULL COMMENT '父菜单ID，指向上一级菜单',
+  `name` varchar(128) NOT NULL COMMENT '菜单名称',
+  `sort` int DEFAULT '0' COMMENT '排序字段',
+  `status` tinyint DEFAULT '1' COMMENT '状态: 1=启用, 0=禁用',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
+  PRIMARY KEY (`id`),
+  KEY `parent_id` (`parent_id`),
+  CONSTRAINT `menus_ibfk_1` FOREIGN KEY (`parent_id`) REFERENCES `menus` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='菜单表';
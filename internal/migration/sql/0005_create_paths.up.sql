@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `paths` (
+  `id` char(36) NOT NULL COMMENT '路径ID',
+  `path` varchar(256) NOT NULL COMMENT '路由路径',
+  `method` enum('GET','POST','PUT','DELETE') NOT NULL COMMENT 'HTTP 方法',
+  `description` varchar(255) DEFAULT NULL COMMENT '路径描述',
+  `menu_id` char(36) NOT NULL COMMENT '菜单ID，指向menus表的ID',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_path_method` (`path`,`method`,`deleted_at`),
+  KEY `paths_ibfk_1` (`menu_id`),
+  CONSTRAINT `paths_ibfk_1` FOREIGN KEY (`menu_id`) REFERENCES `menus` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='接口表';
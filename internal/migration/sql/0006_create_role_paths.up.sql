@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `role_paths` (
+  `role_id` char(36) NOT NULL COMMENT '角色ID',
+  `path_id` char(36) NOT NULL COMMENT '路径ID',
+  PRIMARY KEY (`role_id`,`path_id`),
+  KEY `path_id` (`path_id`),
+  CONSTRAINT `role_paths_ibfk_1` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `role_paths_ibfk_2` FOREIGN KEY (`path_id`) REFERENCES `paths` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='角色接口中间表';
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `user_permissions` (
+  `user_id` char(36) NOT NULL COMMENT '用户ID',
+  `path_id` char(36) NOT NULL COMMENT '路径ID',
+  PRIMARY KEY (`user_id`,`path_id`),
+  KEY `path_id` (`path_id`),
+  CONSTRAINT `user_permissions_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `user_permissions_ibfk_2` FOREIGN KEY (`path_id`) REFERENCES `paths` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户权限预计算表';
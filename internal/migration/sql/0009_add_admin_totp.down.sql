@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `admin_recovery_codes`;
+
+ALTER TABLE `admins`
+  DROP COLUMN `totp_enabled`,
+  DROP COLUMN `totp_secret`;
@@ -0,0 +1,13 @@
+ALTER TABLE `admins`
+  ADD COLUMN `totp_secret` varchar(64) DEFAULT NULL COMMENT 'TOTP 密钥（Base32）' AFTER `last_login_at`,
+  ADD COLUMN `totp_enabled` tinyint NOT NULL DEFAULT '0' COMMENT '是否启用TOTP二次验证(1: 启用, 0: 未启用)' AFTER `totp_secret`;
+
+CREATE TABLE `admin_recovery_codes` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `admin_id` char(36) NOT NULL COMMENT '管理员ID',
+  `code_hash` varchar(64) NOT NULL COMMENT '加密后的恢复码',
+  `used_at` datetime DEFAULT NULL COMMENT '使用时间，为空表示未使用',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='管理员二次验证恢复码表';
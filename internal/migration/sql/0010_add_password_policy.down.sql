@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS `password_histories`;
+
+ALTER TABLE `admins`
+  DROP COLUMN `must_change_password`,
+  DROP COLUMN `password_changed_at`;
+
+ALTER TABLE `users`
+  DROP COLUMN `must_change_password`,
+  DROP COLUMN `password_changed_at`;
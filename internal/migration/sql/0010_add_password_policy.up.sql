@@ -0,0 +1,17 @@
+ALTER TABLE `users`
+  ADD COLUMN `password_changed_at` datetime DEFAULT NULL COMMENT '密码最近修改时间' AFTER `last_login_at`,
+  ADD COLUMN `must_change_password` tinyint NOT NULL DEFAULT '0' COMMENT '下次登录是否必须修改密码(1: 是, 0: 否)' AFTER `password_changed_at`;
+
+ALTER TABLE `admins`
+  ADD COLUMN `password_changed_at` datetime DEFAULT NULL COMMENT '密码最近修改时间' AFTER `totp_enabled`,
+  ADD COLUMN `must_change_password` tinyint NOT NULL DEFAULT '0' COMMENT '下次登录是否必须修改密码(1: 是, 0: 否)' AFTER `password_changed_at`;
+
+CREATE TABLE `password_histories` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `subject_type` varchar(16) NOT NULL COMMENT '账号类型(admin: 管理员, user: 业务用户)',
+  `subject_id` char(36) NOT NULL COMMENT '账号ID',
+  `password` varchar(64) NOT NULL COMMENT '曾经使用过的加密密码',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `subject` (`subject_type`,`subject_id`,`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='密码历史表';
@@ -0,0 +1,19 @@
+CREATE TABLE `audit_logs` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `actor_id` varchar(36) NOT NULL DEFAULT '' COMMENT '操作人ID',
+  `actor_type` varchar(16) NOT NULL DEFAULT '' COMMENT '操作人类型(admin: 管理员, user: 业务用户, scim: SCIM 客户端)',
+  `action` varchar(32) NOT NULL COMMENT '操作类型(create、update、delete等)',
+  `target_type` varchar(32) NOT NULL COMMENT '操作对象类型(admin、user、role等)',
+  `target_id` varchar(36) NOT NULL COMMENT '操作对象ID',
+  `old_values` json DEFAULT NULL COMMENT '变更前发生变化的字段',
+  `new_values` json DEFAULT NULL COMMENT '变更后发生变化的字段',
+  `client_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+  `user_agent` varchar(512) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+  `request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '请求ID',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `actor` (`actor_id`,`created_at`),
+  KEY `target` (`target_type`,`target_id`,`created_at`),
+  KEY `request_id` (`request_id`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='审计日志表';
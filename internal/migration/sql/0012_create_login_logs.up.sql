@@ -0,0 +1,16 @@
+CREATE TABLE `login_logs` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `subject_type` varchar(16) NOT NULL COMMENT '账号类型(admin: 管理员, user: 业务用户)',
+  `event` varchar(32) NOT NULL COMMENT '事件类型(login、loginMfaChallenge、loginTotp、changePassword)',
+  `identifier` varchar(128) NOT NULL DEFAULT '' COMMENT '登录时提交的账号标识（用户名|手机号|邮箱）',
+  `account_id` varchar(36) NOT NULL DEFAULT '' COMMENT '解析出的账号ID，账号不存在时为空',
+  `client_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+  `user_agent` varchar(512) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+  `result_code` int NOT NULL DEFAULT '0' COMMENT '结果码(0: 成功, 其他: 失败时的错误码)',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '发生时间',
+  PRIMARY KEY (`id`),
+  KEY `account` (`subject_type`,`account_id`,`created_at`),
+  KEY `identifier` (`identifier`,`created_at`),
+  KEY `client_ip` (`client_ip`,`created_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='登录日志表';
@@ -0,0 +1,2 @@
+ALTER TABLE `paths`
+  DROP COLUMN `stale`;
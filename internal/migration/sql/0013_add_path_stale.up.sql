@@ -0,0 +1,2 @@
+ALTER TABLE `paths`
+  ADD COLUMN `stale` tinyint NOT NULL DEFAULT '0' COMMENT '路由是否已不存在(1: 是, 0: 否)，由路由同步标记' AFTER `menu_id`;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `admin_roles`;
+
+ALTER TABLE `roles`
+  DROP COLUMN `is_builtin`;
@@ -0,0 +1,11 @@
+ALTER TABLE `roles`
+  ADD COLUMN `is_builtin` tinyint NOT NULL DEFAULT '0' COMMENT '是否内置角色: 1=是, 0=否，内置角色不可删除或禁用' AFTER `status`;
+
+CREATE TABLE `admin_roles` (
+  `admin_id` char(36) NOT NULL COMMENT '管理员ID',
+  `role_id` char(36) NOT NULL COMMENT '角色ID',
+  PRIMARY KEY (`admin_id`,`role_id`),
+  KEY `role_id` (`role_id`),
+  CONSTRAINT `admin_roles_ibfk_1` FOREIGN KEY (`admin_id`) REFERENCES `admins` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `admin_roles_ibfk_2` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='管理员与角色关联表';
@@ -0,0 +1,8 @@
+CREATE TABLE `role_parents` (
+  `role_id` char(36) NOT NULL COMMENT '角色ID',
+  `parent_id` char(36) NOT NULL COMMENT '父角色ID',
+  PRIMARY KEY (`role_id`,`parent_id`),
+  KEY `parent_id` (`parent_id`),
+  CONSTRAINT `role_parents_ibfk_1` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `role_parents_ibfk_2` FOREIGN KEY (`parent_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='角色继承关系表';
@@ -0,0 +1,5 @@
+ALTER TABLE `user_roles`
+  DROP KEY `active`,
+  DROP COLUMN `active`,
+  DROP COLUMN `valid_until`,
+  DROP COLUMN `valid_from`;
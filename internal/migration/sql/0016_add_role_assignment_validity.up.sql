@@ -0,0 +1,5 @@
+ALTER TABLE `user_roles`
+  ADD COLUMN `valid_from` datetime DEFAULT NULL COMMENT '生效时间，为空表示立即生效' AFTER `role_id`,
+  ADD COLUMN `valid_until` datetime DEFAULT NULL COMMENT '失效时间，为空表示永久有效' AFTER `valid_from`,
+  ADD COLUMN `active` tinyint NOT NULL DEFAULT '1' COMMENT '当前是否已计入用户权限: 1=是, 0=否，由定时任务按有效期维护' AFTER `valid_until`,
+  ADD KEY `active` (`active`);
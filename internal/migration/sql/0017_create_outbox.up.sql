@@ -0,0 +1,15 @@
+CREATE TABLE `outbox` (
+  `id` char(36) NOT NULL COMMENT '唯一标识，与事件ID一致',
+  `event_type` varchar(64) NOT NULL COMMENT '事件类型',
+  `payload` json NOT NULL COMMENT '事件内容',
+  `status` tinyint NOT NULL DEFAULT '0' COMMENT '投递状态(0: 待投递, 1: 已投递, 2: 已进入死信)',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '已尝试投递次数',
+  `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次投递时间',
+  `last_error` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次投递失败的原因',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `delivered_at` datetime DEFAULT NULL COMMENT '投递成功时间',
+  PRIMARY KEY (`id`),
+  KEY `status_next_attempt` (`status`,`next_attempt_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='事件发件箱表';
@@ -0,0 +1,33 @@
+CREATE TABLE `webhooks` (
+  `id` char(36) NOT NULL COMMENT '订阅ID',
+  `name` varchar(128) NOT NULL COMMENT '订阅名称',
+  `url` varchar(512) NOT NULL COMMENT '回调地址',
+  `events` json NOT NULL COMMENT '订阅的事件类型，空数组表示全部事件',
+  `secret` varchar(128) NOT NULL COMMENT '签名密钥',
+  `status` tinyint NOT NULL DEFAULT '1' COMMENT '状态: 1=启用, 0=禁用',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='Webhook 订阅表';
+
+CREATE TABLE `webhook_deliveries` (
+  `id` char(36) NOT NULL COMMENT '投递ID',
+  `webhook_id` char(36) NOT NULL COMMENT 'Webhook 订阅ID',
+  `event_id` char(36) NOT NULL COMMENT '事件ID',
+  `event_type` varchar(64) NOT NULL COMMENT '事件类型',
+  `payload` json NOT NULL COMMENT '事件内容，即回调的请求体',
+  `status` tinyint NOT NULL DEFAULT '0' COMMENT '投递状态(0: 待投递, 1: 投递成功, 2: 投递失败)',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '已尝试投递次数',
+  `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次投递时间',
+  `response_status` int NOT NULL DEFAULT '0' COMMENT '最近一次回调的响应状态码，0 表示未收到响应',
+  `last_error` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次投递失败的原因',
+  `duration_ms` int NOT NULL DEFAULT '0' COMMENT '最近一次回调的耗时（毫秒）',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `delivered_at` datetime DEFAULT NULL COMMENT '投递成功时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `webhook_event` (`webhook_id`,`event_id`),
+  KEY `status_next_attempt` (`status`,`next_attempt_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='Webhook 投递记录表';
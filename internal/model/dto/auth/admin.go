@@ -11,8 +11,8 @@ type AddAdminRequest struct {
 	Nickname string `json:"nickname" validate:"omitempty,max=128" example:"AdminNickname"`
 
 	// Password 密码，必填，长度限制
-	// 密码，且避免特殊字符，长度需满足最小值6字符，最大值32字符
-	Password string `json:"password" validate:"required,min=6,max=32" example:"oldpassword123"`
+	// 强度规则（长度、字符类别、弱密码词典、与账号标识的相似度）由配置的密码策略决定
+	Password string `json:"password" validate:"required,max=128" example:"Wam#2024secure"`
 
 	// Email 邮箱，选填，必须符合邮箱格式
 	// 邮箱字段为可选项，但如果提供，必须符合标准的邮箱格式
@@ -25,6 +25,10 @@ type AddAdminRequest struct {
 	// Remark 备注，选填，最大长度256字符
 	// 用于提供对管理员的附加说明或备注信息，最大长度为256字符
 	Remark string `json:"remark" validate:"max=256" example:"This is a remark"`
+
+	// RoleIDList 角色ID列表，选填，用于指定该管理员具有的角色
+	// 管理员的接口权限由所属角色决定，未分配角色的管理员无法访问需授权的接口
+	RoleIDList []string `json:"roleIDList" validate:"omitempty,dive,uuid4" example:"role_id_1,role_id_2"`
 }
 
 // EditAdminRequest 用于编辑管理员信息的请求体结构
@@ -52,6 +56,10 @@ type EditAdminRequest struct {
 	// Remark 备注，选填，最大长度256字符
 	// 备注用于对管理员的附加描述或标记，最大长度为256字符
 	Remark string `json:"remark" validate:"max=256" example:"This is a remark"`
+
+	// RoleIDList 角色ID列表，选填，用于调整该管理员具有的角色
+	// 不传时保持原有角色不变，传空数组时移除全部角色
+	RoleIDList []string `json:"roleIDList" validate:"omitempty,dive,uuid4" example:"role_id_1,role_id_2"`
 }
 
 // DelAdminRequest 用于删除管理员的请求体结构
@@ -71,6 +79,21 @@ type ListAdminRequest struct {
 	// 用于限制每页返回的管理员数量，最小值为1，最大值为10000
 	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
 
+	// AdminFilter 筛选条件
+	AdminFilter
+}
+
+// ExportAdminRequest 用于导出管理员的请求体结构，导出为 CSV 或 XLSX 文件
+type ExportAdminRequest struct {
+	// AdminFilter 筛选条件，与列表查询相同
+	AdminFilter
+
+	// ExportOptions 导出的文件格式和列
+	ExportOptions
+}
+
+// AdminFilter 管理员筛选条件
+type AdminFilter struct {
 	// ID 编号，选填，UUID格式
 	// 用于过滤查询特定ID的管理员，格式必须为UUID4
 	ID string `json:"id" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
@@ -88,6 +111,21 @@ type ListAdminRequest struct {
 	Phone string `json:"phone" validate:"omitempty,e164" example:"+1234567890"`
 }
 
+// InfoAdminRequest 用于查询管理员详情的请求体结构
+type InfoAdminRequest struct {
+	// ID 编号，必填，UUID格式
+	// 用于唯一标识要查询的管理员，格式必须为UUID4
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+}
+
+type InfoAdminResponse struct {
+	AdminInfo
+	// RoleList 角色列表，包含角色的详细信息
+	RoleList []TrimRoleInfo `json:"roleList"`
+	// RecentLogins 最近的登录记录，包含成功和失败的尝试
+	RecentLogins []RecentLoginInfo `json:"recentLogins"`
+}
+
 type ListAdminResponse struct {
 	// total 总条数
 	Total int64 `json:"total" example:"100"`
@@ -110,6 +148,8 @@ type AdminInfo struct {
 	Remark string `json:"remark" example:"This is a remark"`
 	// LastLoginAt string 上次登录时间
 	LastLoginAt string `json:"lastLoginAt" example:"2024-11-18T15:04:05Z"`
+	// TotpEnabled bool 是否已启用TOTP二次验证
+	TotpEnabled bool `json:"totpEnabled" example:"false"`
 	// CreatedAt string 创建时间
 	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
 	// UpdatedAt string 更新时间
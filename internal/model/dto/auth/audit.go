@@ -0,0 +1,70 @@
+package auth
+
+import "encoding/json"
+
+// ListAuditLogRequest 用于查询审计日志的请求体结构
+type ListAuditLogRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// ActorID 操作人ID，选填
+	ActorID string `json:"actorId" validate:"omitempty,max=36" example:"2a5eed42-25a3-40be-9fcd-6132bec83517"`
+
+	// ActorType 操作人类型，选填，admin 表示管理员，user 表示业务用户，scim 表示 SCIM 客户端
+	ActorType string `json:"actorType" validate:"omitempty,oneof=admin user scim" example:"admin"`
+
+	// Action 操作类型，选填，例如 create、update、delete、resetPassword、restore、purge、erase
+	Action string `json:"action" validate:"omitempty,max=32" example:"update"`
+
+	// TargetType 操作对象类型，选填，例如 admin、user、role、menu、path
+	TargetType string `json:"targetType" validate:"omitempty,max=32" example:"user"`
+
+	// TargetID 操作对象ID，选填
+	TargetID string `json:"targetId" validate:"omitempty,max=36" example:"2a5eed42-25a3-40be-9fcd-6132bec83517"`
+
+	// RequestID 请求ID，选填，与响应头 X-Request-ID 对应
+	RequestID string `json:"requestId" validate:"omitempty,max=64" example:"7d0b2c44-0b7a-4a5e-9a57-8f4f1c0f5b11"`
+
+	// StartTime 开始时间，选填，RFC3339 格式
+	StartTime string `json:"startTime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-11-18T00:00:00Z"`
+
+	// EndTime 结束时间，选填，RFC3339 格式
+	EndTime string `json:"endTime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-11-19T00:00:00Z"`
+}
+
+type ListAuditLogResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// List 数据
+	List []AuditLogInfo `json:"list"`
+}
+
+type AuditLogInfo struct {
+	// ID string 编号
+	ID string `json:"id" example:"0c5c8a3e-7a0d-4d0b-9c4e-2f1d6b7f8e90"`
+	// ActorID string 操作人ID
+	ActorID string `json:"actorId" example:"2a5eed42-25a3-40be-9fcd-6132bec83517"`
+	// ActorType string 操作人类型
+	ActorType string `json:"actorType" example:"admin"`
+	// Action string 操作类型
+	Action string `json:"action" example:"update"`
+	// TargetType string 操作对象类型
+	TargetType string `json:"targetType" example:"user"`
+	// TargetID string 操作对象ID
+	TargetID string `json:"targetId" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+	// OldValues 变更前发生变化的字段
+	OldValues json.RawMessage `json:"oldValues" swaggertype:"object"`
+	// NewValues 变更后发生变化的字段
+	NewValues json.RawMessage `json:"newValues" swaggertype:"object"`
+	// ClientIP string 客户端IP
+	ClientIP string `json:"clientIp" example:"192.168.1.10"`
+	// UserAgent string 客户端 User-Agent
+	UserAgent string `json:"userAgent" example:"Mozilla/5.0"`
+	// RequestID string 请求ID
+	RequestID string `json:"requestId" example:"7d0b2c44-0b7a-4a5e-9a57-8f4f1c0f5b11"`
+	// CreatedAt string 创建时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+}
@@ -0,0 +1,11 @@
+package auth
+
+// ExportOptions 导出文件的格式和列
+type ExportOptions struct {
+	// Format 文件格式，选填，csv 或 xlsx，默认 csv
+	Format string `json:"format" validate:"omitempty,oneof=csv xlsx" example:"xlsx"`
+
+	// Columns 导出的列，选填，按给定的顺序输出，不传时导出全部列
+	// 可选的列见各导出接口的说明
+	Columns []string `json:"columns" validate:"omitempty,dive,required" example:"id,userName,roles"`
+}
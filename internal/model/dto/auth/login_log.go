@@ -0,0 +1,91 @@
+package auth
+
+// ListLoginLogRequest 用于查询登录日志的请求体结构
+type ListLoginLogRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// LoginLogFilter 筛选条件
+	LoginLogFilter
+}
+
+// ExportLoginLogRequest 用于导出登录日志的请求体结构，导出为 CSV 或 XLSX 文件
+type ExportLoginLogRequest struct {
+	// LoginLogFilter 筛选条件
+	LoginLogFilter
+
+	// ExportOptions 导出的文件格式和列
+	ExportOptions
+}
+
+// LoginLogFilter 登录日志筛选条件
+type LoginLogFilter struct {
+	// SubjectType 账号类型，选填，admin 表示管理员，user 表示业务用户
+	SubjectType string `json:"subjectType" validate:"omitempty,oneof=admin user" example:"admin"`
+
+	// Event 事件类型，选填
+	Event string `json:"event" validate:"omitempty,oneof=login loginMfaChallenge loginTotp changePassword" example:"login"`
+
+	// Identifier 登录时提交的账号标识，选填
+	Identifier string `json:"identifier" validate:"omitempty,max=128" example:"user1@example.com"`
+
+	// AccountID 账号ID，选填
+	AccountID string `json:"accountId" validate:"omitempty,max=36" example:"2a5eed42-25a3-40be-9fcd-6132bec83517"`
+
+	// ClientIP 客户端IP，选填
+	ClientIP string `json:"clientIp" validate:"omitempty,ip" example:"192.168.1.10"`
+
+	// Success 是否成功，选填，不传表示全部
+	Success *bool `json:"success" example:"false"`
+
+	// StartTime 开始时间，选填，RFC3339 格式
+	StartTime string `json:"startTime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-11-18T00:00:00Z"`
+
+	// EndTime 结束时间，选填，RFC3339 格式
+	EndTime string `json:"endTime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-11-25T00:00:00Z"`
+}
+
+type ListLoginLogResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// List 数据
+	List []LoginLogInfo `json:"list"`
+}
+
+type LoginLogInfo struct {
+	// ID string 编号
+	ID string `json:"id" example:"0c5c8a3e-7a0d-4d0b-9c4e-2f1d6b7f8e90"`
+	// SubjectType string 账号类型
+	SubjectType string `json:"subjectType" example:"admin"`
+	// Event string 事件类型
+	Event string `json:"event" example:"login"`
+	// Identifier string 登录时提交的账号标识
+	Identifier string `json:"identifier" example:"user1@example.com"`
+	// AccountID string 账号ID，账号不存在时为空
+	AccountID string `json:"accountId" example:"2a5eed42-25a3-40be-9fcd-6132bec83517"`
+	// ClientIP string 客户端IP
+	ClientIP string `json:"clientIp" example:"192.168.1.10"`
+	// UserAgent string 客户端 User-Agent
+	UserAgent string `json:"userAgent" example:"Mozilla/5.0"`
+	// ResultCode int 结果码，0 表示成功，其他为失败时的错误码
+	ResultCode int `json:"resultCode" example:"0"`
+	// CreatedAt string 发生时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+}
+
+// RecentLoginInfo 账号最近的登录记录
+type RecentLoginInfo struct {
+	// Event string 事件类型
+	Event string `json:"event" example:"login"`
+	// ClientIP string 客户端IP
+	ClientIP string `json:"clientIp" example:"192.168.1.10"`
+	// UserAgent string 客户端 User-Agent
+	UserAgent string `json:"userAgent" example:"Mozilla/5.0"`
+	// ResultCode int 结果码，0 表示成功，其他为失败时的错误码
+	ResultCode int `json:"resultCode" example:"0"`
+	// CreatedAt string 发生时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+}
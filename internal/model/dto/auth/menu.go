@@ -22,6 +22,12 @@ type BaseNode struct {
 type MenuNode struct {
 	BaseNode
 
+	// Sort 同级菜单中的排序，值越小越靠前
+	Sort int `json:"sort" example:"0"`
+
+	// Status 菜单状态，1表示启用，0表示禁用
+	Status int8 `json:"status" example:"1"`
+
 	// MenuData 子菜单列表
 	MenuData []*MenuNode `json:"menuData,omitempty"`
 
@@ -40,3 +46,71 @@ type PathInfo struct {
 	// Description 路径描述
 	Description string `json:"description" example:"Dashboard home page"`
 }
+
+// AddMenuRequest 用于新增菜单的请求体结构
+type AddMenuRequest struct {
+	// ParentID 父菜单ID，选填，UUID格式
+	// 不填表示新增顶级菜单
+	ParentID string `json:"parentId" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Name 菜单名称，必填，最大长度128字符
+	Name string `json:"name" validate:"required,max=128" example:"Dashboard"`
+
+	// Sort 排序，选填，值越小越靠前
+	// 不填时排在同级菜单的最后
+	Sort *int `json:"sort" validate:"omitempty,gte=0" example:"0"`
+
+	// Status 菜单状态，选填，1表示启用，0表示禁用，默认启用
+	Status *int8 `json:"status" validate:"omitempty,oneof=0 1" example:"1"`
+}
+
+// EditMenuRequest 用于编辑菜单的请求体结构，调整父菜单与排序请使用移动和排序接口
+type EditMenuRequest struct {
+	// ID 菜单ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Name 菜单名称，必填，最大长度128字符
+	Name string `json:"name" validate:"required,max=128" example:"Dashboard"`
+}
+
+// DelMenuRequest 用于删除菜单的请求体结构
+type DelMenuRequest struct {
+	// ID 菜单ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Cascade 是否级联删除，选填
+	// 为 false 时菜单下存在子菜单或接口则拒绝删除；为 true 时一并删除全部子菜单及其接口，并收回角色对这些接口的授权
+	Cascade bool `json:"cascade" example:"false"`
+}
+
+// UpdateMenuStatusRequest 用于启用或禁用菜单的请求体结构
+type UpdateMenuStatusRequest struct {
+	// ID 菜单ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Status 菜单状态，必填，1表示启用，0表示禁用
+	Status *int8 `json:"status" validate:"required,oneof=0 1" example:"0"`
+}
+
+// MoveMenuRequest 用于将菜单移动到新的父菜单下的请求体结构
+type MoveMenuRequest struct {
+	// ID 菜单ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// ParentID 新的父菜单ID，选填，UUID格式
+	// 不填表示移动为顶级菜单；不能是菜单自身或其子孙菜单
+	ParentID string `json:"parentId" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Sort 在新位置的排序，选填，不填时排在同级菜单的最后
+	Sort *int `json:"sort" validate:"omitempty,gte=0" example:"0"`
+}
+
+// SortMenuRequest 用于批量调整同级菜单顺序的请求体结构
+type SortMenuRequest struct {
+	// ParentID 父菜单ID，选填，UUID格式，不填表示调整顶级菜单
+	ParentID string `json:"parentId" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// IDList 按新顺序排列的菜单ID列表，必填
+	// 必须恰好包含该父菜单下的全部子菜单，排序值按列表顺序依次设置为 0、1、2...
+	IDList []string `json:"idList" validate:"required,min=1,dive,uuid4" example:"menu_id_1,menu_id_2"`
+}
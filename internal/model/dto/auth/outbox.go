@@ -0,0 +1,62 @@
+package auth
+
+// ListOutboxRequest 用于查询事件发件箱的请求体结构
+type ListOutboxRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// Status 投递状态，选填，0 表示待投递（包括等待重试），1 表示已投递，2 表示已进入死信，不传表示全部
+	Status *int8 `json:"status" validate:"omitempty,oneof=0 1 2" example:"2"`
+
+	// EventType 事件类型，选填，例如 role.updated
+	EventType string `json:"eventType" validate:"omitempty,max=64" example:"user.roles_changed"`
+}
+
+type ListOutboxResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// Stats 各投递状态的记录数量，不受筛选条件影响
+	Stats OutboxStats `json:"stats"`
+	// List 数据
+	List []OutboxInfo `json:"list"`
+}
+
+// OutboxStats 各投递状态的记录数量
+type OutboxStats struct {
+	// Pending int64 待投递（包括等待重试）
+	Pending int64 `json:"pending" example:"3"`
+	// Delivered int64 已投递
+	Delivered int64 `json:"delivered" example:"1024"`
+	// Dead int64 已进入死信
+	Dead int64 `json:"dead" example:"1"`
+}
+
+type OutboxInfo struct {
+	// ID string 事件ID
+	ID string `json:"id" example:"0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d"`
+	// EventType string 事件类型
+	EventType string `json:"eventType" example:"user.roles_changed"`
+	// Payload string 事件 JSON
+	Payload string `json:"payload" example:"{\"id\":\"0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d\",\"type\":\"user.roles_changed\"}"`
+	// Status int8 投递状态（0: 待投递, 1: 已投递, 2: 已进入死信）
+	Status int8 `json:"status" example:"2"`
+	// Attempts int 已尝试投递次数
+	Attempts int `json:"attempts" example:"10"`
+	// NextAttemptAt string 下次投递时间
+	NextAttemptAt string `json:"nextAttemptAt" example:"2024-11-18T10:05:00Z"`
+	// LastError string 最近一次投递失败的原因
+	LastError string `json:"lastError" example:"http: https://hooks.example.com/wam responded with status 503"`
+	// CreatedAt string 创建时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+	// DeliveredAt string 投递成功时间，未投递成功时为空
+	DeliveredAt string `json:"deliveredAt" example:""`
+}
+
+// RetryOutboxRequest 用于重新投递事件的请求体结构
+type RetryOutboxRequest struct {
+	// ID 事件ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d"`
+}
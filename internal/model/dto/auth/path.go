@@ -0,0 +1,124 @@
+package auth
+
+// ListPathRequest 用于查询接口列表的请求体结构
+type ListPathRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// MenuID 所属菜单ID，选填，UUID格式
+	MenuID string `json:"menuId" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Path 路由路径，选填
+	Path string `json:"path" validate:"omitempty,max=256" example:"/v1/auth/user"`
+
+	// Method HTTP方法，选填
+	Method string `json:"method" validate:"omitempty,oneof=GET POST PUT DELETE" example:"GET"`
+
+	// Stale 是否已失效，选填，1表示路由已不存在，0表示路由存在
+	Stale *int8 `json:"stale" validate:"omitempty,oneof=0 1" example:"0"`
+}
+
+type ListPathResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// List 数据
+	List []PathDetail `json:"list"`
+}
+
+type PathDetail struct {
+	// ID string 编号
+	ID string `json:"id" example:"clywh0xv70001rvpgzd6256ns"`
+	// MenuID string 所属菜单ID
+	MenuID string `json:"menuId" example:"clywh0xv70001rvpgzd6256ns"`
+	// Path string 路由路径
+	Path string `json:"path" example:"/v1/auth/user"`
+	// Method string HTTP方法
+	Method string `json:"method" example:"GET"`
+	// Description string 接口描述
+	Description string `json:"description" example:"用户列表"`
+	// Stale bool 路由是否已不存在
+	Stale bool `json:"stale" example:"false"`
+	// CreatedAt string 创建时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+	// UpdatedAt string 更新时间
+	UpdatedAt string `json:"updatedAt" example:"2024-11-18T11:00:00Z"`
+}
+
+// AddPathRequest 用于新增接口的请求体结构
+type AddPathRequest struct {
+	// MenuID 所属菜单ID，必填，UUID格式
+	MenuID string `json:"menuId" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Path 路由路径，必填，与 gin 的路由模板一致（包含 /v1 前缀），例如 /v1/auth/user
+	Path string `json:"path" validate:"required,startswith=/,max=256" example:"/v1/auth/user"`
+
+	// Method HTTP方法，必填
+	Method string `json:"method" validate:"required,oneof=GET POST PUT DELETE" example:"GET"`
+
+	// Description 接口描述，选填，最大长度255字符
+	Description string `json:"description" validate:"omitempty,max=255" example:"用户列表"`
+}
+
+// EditPathRequest 用于编辑接口的请求体结构
+type EditPathRequest struct {
+	// ID 接口ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// MenuID 所属菜单ID，必填，UUID格式
+	MenuID string `json:"menuId" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Path 路由路径，必填，与 gin 的路由模板一致（包含 /v1 前缀）
+	Path string `json:"path" validate:"required,startswith=/,max=256" example:"/v1/auth/user"`
+
+	// Method HTTP方法，必填
+	Method string `json:"method" validate:"required,oneof=GET POST PUT DELETE" example:"GET"`
+
+	// Description 接口描述，选填，最大长度255字符
+	Description string `json:"description" validate:"omitempty,max=255" example:"用户列表"`
+}
+
+// DelPathRequest 用于删除接口的请求体结构
+type DelPathRequest struct {
+	// ID 接口ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+}
+
+// SyncPathRequest 用于将已注册的路由同步到接口表的请求体结构
+type SyncPathRequest struct {
+	// DryRun 是否仅预览，选填，为 true 时只返回差异报告，不修改数据
+	DryRun bool `json:"dryRun" example:"true"`
+
+	// MenuID 新增接口挂载的菜单ID，选填，UUID格式
+	// 非预览模式下存在需要新增的接口时必填，新增后可再通过编辑接口调整所属菜单
+	MenuID string `json:"menuId" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Prefix 需要纳入接口管理的路由前缀，选填，默认 /v1/auth/（需要登录的接口）
+	Prefix string `json:"prefix" validate:"omitempty,startswith=/,max=256" example:"/v1/auth/"`
+}
+
+// SyncPathResponse 路由同步的差异报告
+type SyncPathResponse struct {
+	// DryRun 是否为预览结果
+	DryRun bool `json:"dryRun" example:"true"`
+	// Added 已注册但接口表中不存在的路由，非预览模式下已新增
+	Added []SyncPathItem `json:"added"`
+	// Stale 接口表中存在但已不再注册的路由，非预览模式下已标记为失效
+	Stale []SyncPathItem `json:"stale"`
+	// Restored 之前被标记为失效、现在重新注册的路由，非预览模式下已清除失效标记
+	Restored []SyncPathItem `json:"restored"`
+	// Unchanged 无需变更的接口数量
+	Unchanged int `json:"unchanged" example:"42"`
+}
+
+// SyncPathItem 路由同步差异项
+type SyncPathItem struct {
+	// ID 接口ID，新增的路由在预览模式下为空
+	ID string `json:"id,omitempty" example:"clywh0xv70001rvpgzd6256ns"`
+	// Path 路由路径
+	Path string `json:"path" example:"/v1/auth/user"`
+	// Method HTTP方法
+	Method string `json:"method" example:"GET"`
+}
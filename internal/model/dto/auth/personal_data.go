@@ -0,0 +1,94 @@
+package auth
+
+// PersonalDataRequest 用于导出用户个人数据的请求体结构
+type PersonalDataRequest struct {
+	// ID 用户唯一标识，必填，UUID格式，已删除的用户也可以导出
+	ID string `json:"id" validate:"required,uuid4" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+}
+
+// PersonalDataResponse 用户的全部个人数据
+type PersonalDataResponse struct {
+	// ExportedAt string 导出时间
+	ExportedAt string `json:"exportedAt" example:"2024-11-18T10:00:00Z"`
+	// User 用户资料，不包含密码
+	User PersonalDataUser `json:"user"`
+	// Roles 分配给用户的角色，包括尚未生效和已失效的角色
+	Roles []PersonalDataRole `json:"roles"`
+	// Permissions 用户当前拥有的接口权限
+	Permissions []PersonalDataPermission `json:"permissions"`
+	// LoginLogs 登录记录，按发生时间倒序
+	LoginLogs []LoginLogInfo `json:"loginLogs"`
+	// AuditLogs 以用户为操作对象或由用户本人操作的审计日志，按创建时间倒序
+	AuditLogs []AuditLogInfo `json:"auditLogs"`
+}
+
+type PersonalDataUser struct {
+	// ID string 用户ID
+	ID string `json:"id" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+	// UserName string 用户名
+	UserName string `json:"userName" example:"john_doe"`
+	// Nickname string 昵称
+	Nickname string `json:"nickname" example:"John"`
+	// Email string 邮箱
+	Email string `json:"email" example:"john.doe@example.com"`
+	// Phone string 手机号
+	Phone string `json:"phone" example:"+8613800138000"`
+	// Status int8 用户状态（1: 启用, 0: 禁用）
+	Status int8 `json:"status" example:"1"`
+	// Remark string 备注
+	Remark string `json:"remark" example:"VIP customer"`
+	// LastLoginAt string 最后登录时间，未登录过时为空
+	LastLoginAt string `json:"lastLoginAt" example:"2024-11-18T10:00:00Z"`
+	// PasswordChangedAt string 密码最后修改时间
+	PasswordChangedAt string `json:"passwordChangedAt" example:"2024-11-01T10:00:00Z"`
+	// MustChangePassword int8 下次登录是否必须修改密码（1: 是, 0: 否）
+	MustChangePassword int8 `json:"mustChangePassword" example:"0"`
+	// CreatedAt string 创建时间
+	CreatedAt string `json:"createdAt" example:"2024-01-01T10:00:00Z"`
+	// UpdatedAt string 更新时间
+	UpdatedAt string `json:"updatedAt" example:"2024-11-18T10:00:00Z"`
+	// DeletedAt string 删除时间，未删除时为空
+	DeletedAt string `json:"deletedAt" example:""`
+}
+
+type PersonalDataRole struct {
+	// RoleID string 角色ID
+	RoleID string `json:"roleId" example:"b1c2d3e4-0000-4000-8000-000000000001"`
+	// Name string 角色名称
+	Name string `json:"name" example:"editor"`
+	// ValidFrom string 生效时间，为空表示立即生效
+	ValidFrom string `json:"validFrom" example:""`
+	// ValidUntil string 失效时间，为空表示永久有效
+	ValidUntil string `json:"validUntil" example:"2025-01-01T00:00:00Z"`
+	// Active bool 当前是否处于有效期内
+	Active bool `json:"active" example:"true"`
+}
+
+type PersonalDataPermission struct {
+	// Method string 请求方法
+	Method string `json:"method" example:"GET"`
+	// Path string 路由路径
+	Path string `json:"path" example:"/api/v1/articles"`
+}
+
+// ErasureTokenRequest 用于申请擦除确认令牌的请求体结构
+type ErasureTokenRequest struct {
+	// ID 用户唯一标识，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+}
+
+type ErasureTokenResponse struct {
+	// Token string 确认令牌，只能由申请人使用一次
+	Token string `json:"token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// ExpiresAt string 令牌过期时间
+	ExpiresAt string `json:"expiresAt" example:"2024-11-18T10:10:00Z"`
+}
+
+// EraseUserRequest 用于擦除用户个人数据的请求体结构
+type EraseUserRequest struct {
+	// ID 用户唯一标识，必填，UUID格式，已删除的用户也可以擦除
+	ID string `json:"id" validate:"required,uuid4" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+
+	// Token 确认令牌，必填，通过申请擦除确认令牌接口获取
+	Token string `json:"token" validate:"required,len=64,hexadecimal" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
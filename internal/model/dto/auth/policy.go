@@ -0,0 +1,53 @@
+package auth
+
+// 权限决策原因
+const (
+	PolicyReasonGranted      = "granted"        // 已通过角色授权
+	PolicyReasonNoPermission = "no_permission"  // 用户的角色未授权该接口
+	PolicyReasonUserNotFound = "user_not_found" // 用户不存在或已删除
+	PolicyReasonUserDisabled = "user_disabled"  // 用户已被禁用
+)
+
+// PolicyCheck 描述一次权限决策的请求体结构
+type PolicyCheck struct {
+	// UserID 用户ID，必填，UUID格式
+	UserID string `json:"userId" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// Method HTTP方法，必填
+	Method string `json:"method" validate:"required,oneof=GET POST PUT DELETE" example:"GET"`
+
+	// Path 路由路径，必填，与接口管理中登记的路由模板一致
+	Path string `json:"path" validate:"required,max=256" example:"/v1/experiment/:id"`
+}
+
+// DecidePolicyRequest 用于查询单个权限决策的请求体结构
+type DecidePolicyRequest struct {
+	PolicyCheck
+}
+
+// BatchDecidePolicyRequest 用于批量查询权限决策的请求体结构
+type BatchDecidePolicyRequest struct {
+	// CheckList 决策列表，必填，单次最多100条
+	CheckList []PolicyCheck `json:"checkList" validate:"required,min=1,max=100,dive"`
+}
+
+// PolicyDecision 权限决策结果
+type PolicyDecision struct {
+	// UserID string 用户ID
+	UserID string `json:"userId" example:"clywh0xv70001rvpgzd6256ns"`
+	// Method string HTTP方法
+	Method string `json:"method" example:"GET"`
+	// Path string 路由路径
+	Path string `json:"path" example:"/v1/experiment/:id"`
+	// Allowed bool 是否允许访问
+	Allowed bool `json:"allowed" example:"true"`
+	// Reason string 决策原因（granted、no_permission、user_not_found、user_disabled）
+	Reason string `json:"reason" example:"granted"`
+	// RoleList 授予该接口权限的角色，包括通过继承获得权限的角色
+	RoleList []TrimRoleInfo `json:"roleList"`
+}
+
+type BatchDecidePolicyResponse struct {
+	// List 决策结果，顺序与请求中的决策列表一致
+	List []PolicyDecision `json:"list"`
+}
@@ -0,0 +1,51 @@
+package auth
+
+// ListRecycleBinRequest 用于查询回收站的请求体结构
+type ListRecycleBinRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// Type 记录类型，必填，取值：user、admin、role、menu、path
+	Type string `json:"type" validate:"required,oneof=user admin role menu path" example:"user"`
+}
+
+type ListRecycleBinResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// List 数据，按删除时间倒序
+	List []RecycleBinItem `json:"list"`
+}
+
+type RecycleBinItem struct {
+	// ID string 记录ID
+	ID string `json:"id" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+	// Type string 记录类型
+	Type string `json:"type" example:"user"`
+	// Name string 记录名称：用户和管理员为用户名，角色和菜单为名称，接口为请求方法与路由路径
+	Name string `json:"name" example:"john_doe"`
+	// DeletedAt string 删除时间
+	DeletedAt string `json:"deletedAt" example:"2024-11-18T10:00:00Z"`
+	// PurgeAt string 到期后由定时任务永久删除的时间，未启用自动清除时为空
+	PurgeAt string `json:"purgeAt" example:"2024-12-18T10:00:00Z"`
+}
+
+// RestoreRecycleBinRequest 用于从回收站恢复记录的请求体结构
+type RestoreRecycleBinRequest struct {
+	// Type 记录类型，必填，取值：user、admin、role、menu、path
+	Type string `json:"type" validate:"required,oneof=user admin role menu path" example:"user"`
+
+	// ID 记录ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+}
+
+// PurgeRecycleBinRequest 用于从回收站永久删除记录的请求体结构
+type PurgeRecycleBinRequest struct {
+	// Type 记录类型，必填，取值：user、admin、role、menu、path
+	Type string `json:"type" validate:"required,oneof=user admin role menu path" example:"user"`
+
+	// ID 记录ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"7d3e1c52-9b1a-4f4e-8f2a-2a6c9d1e5b33"`
+}
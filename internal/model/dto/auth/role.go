@@ -10,6 +10,21 @@ type ListRoleRequest struct {
 	// 用于限制每页显示角色的数量，最小值为1，最大值为10000
 	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
 
+	// RoleFilter 筛选条件
+	RoleFilter
+}
+
+// ExportRoleRequest 用于导出角色的请求体结构，导出为 CSV 或 XLSX 文件
+type ExportRoleRequest struct {
+	// RoleFilter 筛选条件，与列表查询相同
+	RoleFilter
+
+	// ExportOptions 导出的文件格式和列
+	ExportOptions
+}
+
+// RoleFilter 角色筛选条件
+type RoleFilter struct {
 	// ID 角色ID，选填，UUID格式
 	// 用于过滤查询特定角色ID的角色，格式必须为UUID4
 	ID string `json:"id" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
@@ -48,6 +63,10 @@ type AddRoleRequest struct {
 	// 角色可以访问多个路径，路径ID是与路径表中的路径关联的
 	// 使用 "dive" 校验每个元素是否符合 UUID 格式
 	PathIDList []string `json:"pathIDList" validate:"omitempty,dive,uuid4" example:"path_id_1,path_id_2"`
+
+	// ParentIDList 父角色ID列表，选填，角色继承全部父角色（及其祖先角色）的路径
+	// 使用 "dive" 校验每个元素是否符合 UUID 格式
+	ParentIDList []string `json:"parentIDList" validate:"omitempty,dive,uuid4" example:"role_id_1,role_id_2"`
 }
 
 // EditRoleRequest 用于编辑角色信息的请求体结构
@@ -72,6 +91,10 @@ type EditRoleRequest struct {
 	// 角色可以访问多个路径，路径ID是与路径表中的路径关联的
 	// 使用 "dive" 校验每个元素是否符合 UUID 格式
 	PathIDList []string `json:"pathIDList" validate:"omitempty,dive,uuid4" example:"path_id_1,path_id_2"`
+
+	// ParentIDList 父角色ID列表，选填，角色继承全部父角色（及其祖先角色）的路径
+	// 不传时保持原有父角色不变，传空数组时取消全部继承；不能继承自身或子孙角色
+	ParentIDList []string `json:"parentIDList" validate:"omitempty,dive,uuid4" example:"role_id_1,role_id_2"`
 }
 
 // DelRoleRequest 用于删除角色的请求体结构
@@ -106,6 +129,10 @@ type RoleInfo struct {
 	// 1表示启用，0表示禁用
 	Status int8 `json:"status" example:"1"`
 
+	// IsBuiltin 是否内置角色
+	// 内置超级管理员角色拥有全部接口权限，不可删除、重命名或禁用
+	IsBuiltin bool `json:"isBuiltin" example:"false"`
+
 	// CreatedAt 角色创建时间
 	// 格式为时间戳，标识角色的创建时间
 	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
@@ -132,6 +159,10 @@ type InfoRoleResponse struct {
 	// 1表示启用，0表示禁用
 	Status int8 `json:"status" example:"1"`
 
+	// IsBuiltin 是否内置角色
+	// 内置超级管理员角色拥有全部接口权限，不可删除、重命名或禁用
+	IsBuiltin bool `json:"isBuiltin" example:"false"`
+
 	// CreatedAt 角色创建时间
 	// 格式为时间戳，标识角色的创建时间
 	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
@@ -140,7 +171,10 @@ type InfoRoleResponse struct {
 	// 格式为时间戳，标识角色的最后更新时间
 	UpdatedAt string `json:"updatedAt" example:"2024-11-18T11:00:00Z"`
 
-	// MenuData 是菜单树数据
+	// ParentList 直接父角色列表
+	ParentList []TrimRoleInfo `json:"parentList"`
+
+	// MenuData 是菜单树数据，分别标识角色直接拥有和从祖先角色继承的路径
 	MenuData []*RoleMenuNode `json:"menuData"`
 }
 
@@ -148,9 +182,12 @@ type InfoRoleResponse struct {
 type RoleMenuNode struct {
 	BaseNode
 
-	// IsPermitted 当前角色是否有该菜单权限
+	// IsPermitted 当前角色是否直接拥有该菜单下的路径权限
 	IsPermitted bool `json:"isPermitted" example:"false"`
 
+	// IsInherited 当前角色是否从祖先角色继承了该菜单下的路径权限
+	IsInherited bool `json:"isInherited" example:"false"`
+
 	// MenuData 子菜单列表
 	MenuData []*RoleMenuNode `json:"menuData,omitempty"`
 
@@ -162,6 +199,9 @@ type RoleMenuNode struct {
 type RolePathInfo struct {
 	PathInfo
 
-	// IsPermitted 当前角色是否有该菜单权限
+	// IsPermitted 当前角色是否直接拥有该路径权限
 	IsPermitted bool `json:"isPermitted" example:"false"`
+
+	// IsInherited 当前角色是否从祖先角色继承了该路径权限
+	IsInherited bool `json:"isInherited" example:"false"`
 }
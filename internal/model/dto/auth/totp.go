@@ -0,0 +1,25 @@
+package auth
+
+type TotpLoginRequest struct {
+	// MfaToken 登录第一步返回的二次验证挑战令牌，必填
+	MfaToken string `json:"mfaToken" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	// Code 身份验证器 App 中的6位验证码，或一次性恢复码，必填
+	Code string `json:"code" validate:"required,min=6,max=16" example:"123456"`
+}
+
+type TotpEnrollResponse struct {
+	// Secret TOTP 密钥（Base32），无法扫码时可手动输入
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	// ProvisioningUri otpauth 绑定地址，前端据此生成二维码
+	ProvisioningUri string `json:"provisioningUri" example:"otpauth://totp/ByteScience:admin?algorithm=SHA1&digits=6&issuer=ByteScience&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+type TotpCodeRequest struct {
+	// Code 身份验证器 App 中的6位验证码，或一次性恢复码，必填
+	Code string `json:"code" validate:"required,min=6,max=16" example:"123456"`
+}
+
+type TotpRecoveryCodesResponse struct {
+	// RecoveryCodes 一次性恢复码，仅在生成时返回一次，请妥善保存
+	RecoveryCodes []string `json:"recoveryCodes" example:"k3p9x-2mfq7,8dn4c-vt6ha"`
+}
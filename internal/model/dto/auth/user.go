@@ -1,5 +1,7 @@
 package auth
 
+import "mime/multipart"
+
 // AddUserRequest 是用于新增用户的请求体结构
 type AddUserRequest struct {
 	// UserName 用户名，必填，长度限制：3-128字符
@@ -11,8 +13,8 @@ type AddUserRequest struct {
 	Nickname string `json:"nickname" validate:"omitempty,max=128" example:"Nickname"`
 
 	// Password 密码，必填，长度限制
-	// 密码，且避免特殊字符，长度需满足最小值6字符，最大值32字符
-	Password string `json:"password" validate:"required,min=6,max=32" example:"oldpassword123"`
+	// 强度规则（长度、字符类别、弱密码词典、与账号标识的相似度）由配置的密码策略决定
+	Password string `json:"password" validate:"required,max=128" example:"Wam#2024secure"`
 
 	// Email 邮箱，必填，格式验证
 	// 邮箱格式必须合法，例如 "user@example.com"
@@ -34,6 +36,10 @@ type AddUserRequest struct {
 	// 用户可以关联多个角色，每个角色的ID必须符合UUID格式
 	// 使用 "dive" 校验每个元素是否符合 UUID 格式
 	RoleIDList []string `json:"roleIDList" validate:"required,dive,uuid4" example:"role_id_1,role_id_2"`
+
+	// RoleAssignmentList 角色有效期列表，选填，用于临时授权或预约授权
+	// 列表中的角色会一并分配给用户，未在此列出的角色永久有效；到期后由定时任务自动激活或失效
+	RoleAssignmentList []RoleAssignment `json:"roleAssignmentList" validate:"omitempty,dive"`
 }
 
 // EditUserRequest 是用于编辑用户信息的请求体结构
@@ -70,6 +76,10 @@ type EditUserRequest struct {
 	// 用户可以关联多个角色，每个角色的ID必须符合UUID格式
 	// 使用 "dive" 校验每个元素是否符合 UUID 格式
 	RoleIDList []string `json:"roleIDList" validate:"required,dive,uuid4" example:"role_id_1,role_id_2"`
+
+	// RoleAssignmentList 角色有效期列表，选填，用于临时授权或预约授权
+	// 列表中的角色会一并分配给用户，未在此列出的角色永久有效；到期后由定时任务自动激活或失效
+	RoleAssignmentList []RoleAssignment `json:"roleAssignmentList" validate:"omitempty,dive"`
 }
 
 // DelUserRequest 是用于删除用户的请求体结构
@@ -96,6 +106,21 @@ type ListUserRequest struct {
 	// 用于限制每页显示的用户数量，最小值为1，最大值为10000
 	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
 
+	// UserFilter 筛选条件
+	UserFilter
+}
+
+// ExportUserRequest 用于导出用户的请求体结构，导出为 CSV 或 XLSX 文件
+type ExportUserRequest struct {
+	// UserFilter 筛选条件，与列表查询相同
+	UserFilter
+
+	// ExportOptions 导出的文件格式和列
+	ExportOptions
+}
+
+// UserFilter 用户筛选条件
+type UserFilter struct {
 	// ID 用户唯一标识，选填，UUID格式
 	// 可用于根据用户ID进行过滤查询
 	ID string `json:"id" validate:"omitempty,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
@@ -168,8 +193,36 @@ type InfoUserResponse struct {
 	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
 	// UpdatedAt string 更新时间
 	UpdatedAt string `json:"updatedAt" example:"2024-11-18T11:00:00Z"`
-	// RoleList 角色列表，包含角色的详细信息
-	RoleList []TrimRoleInfo `json:"roleList"`
+	// RoleList 角色列表，包含角色的详细信息及有效期
+	RoleList []UserRoleInfo `json:"roleList"`
+	// RecentLogins 最近的登录记录，包含成功和失败的尝试
+	RecentLogins []RecentLoginInfo `json:"recentLogins"`
+}
+
+// RoleAssignment 用于描述用户角色有效期的结构
+type RoleAssignment struct {
+	// RoleID 角色ID，必填，UUID格式
+	RoleID string `json:"roleId" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
+
+	// ValidFrom 生效时间，选填，RFC3339格式，为空表示立即生效
+	ValidFrom string `json:"validFrom" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-11-18T00:00:00Z"`
+
+	// ValidUntil 失效时间，选填，RFC3339格式，为空表示永久有效，必须晚于生效时间
+	ValidUntil string `json:"validUntil" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-12-18T00:00:00Z"`
+}
+
+// UserRoleInfo 用于描述用户角色及其有效期的结构
+type UserRoleInfo struct {
+	TrimRoleInfo
+
+	// ValidFrom 生效时间，为空表示立即生效
+	ValidFrom string `json:"validFrom,omitempty" example:"2024-11-18T00:00:00Z"`
+
+	// ValidUntil 失效时间，为空表示永久有效
+	ValidUntil string `json:"validUntil,omitempty" example:"2024-12-18T00:00:00Z"`
+
+	// Active 当前是否处于有效期内
+	Active bool `json:"active" example:"true"`
 }
 
 // TrimRoleInfo 用于描述用户角色信息的结构（修剪版）
@@ -191,6 +244,45 @@ type ResetPasswordRequest struct {
 	ID string `json:"id" validate:"required,uuid4" example:"clywh0xv70001rvpgzd6256ns"`
 
 	// NewPassword 新密码，必填，长度限制及格式要求
-	// 新密码是要设置给用户的新的登录密码，长度需满足最小值6字符，最大值32字符
-	NewPassword string `json:"newPassword" validate:"required,min=6,max=32" example:"newpassword123"`
+	// 新密码是要设置给用户的新的登录密码，强度规则由配置的密码策略决定
+	NewPassword string `json:"newPassword" validate:"required,max=128" example:"Wam#2024secure"`
+}
+
+// ImportUserRequest 是用于批量导入用户的请求体结构，以 multipart/form-data 提交
+type ImportUserRequest struct {
+	// File 导入文件，必填，支持 CSV（UTF-8 编码）和 XLSX（读取第一个工作表）
+	// 第一行为表头，列名不区分大小写：userName、password 必须提供，nickname、email、phone、status、remark、roles 选填；
+	// status 为空时默认启用，roles 为角色名称，多个角色以分号分隔
+	File *multipart.FileHeader `form:"file" validate:"required" swaggerignore:"true"`
+
+	// DryRun 是否仅校验，选填，为 true 时只返回校验结果，不写入任何数据
+	DryRun bool `form:"dryRun" example:"false"`
+}
+
+// ImportUserResponse 是批量导入用户的响应结构
+type ImportUserResponse struct {
+	// DryRun 是否仅校验
+	DryRun bool `json:"dryRun" example:"false"`
+	// Total 数据行数（不含表头和空行）
+	Total int `json:"total" example:"100"`
+	// Succeeded 导入成功的行数，仅校验时为校验通过的行数
+	Succeeded int `json:"succeeded" example:"98"`
+	// Failed 失败的行数
+	Failed int `json:"failed" example:"2"`
+	// Rows 每一行的处理结果，按行号排序
+	Rows []ImportUserRow `json:"rows"`
+}
+
+// ImportUserRow 是单行导入结果
+type ImportUserRow struct {
+	// Row 行号，与文件中的行号一致（表头为第 1 行）
+	Row int `json:"row" example:"2"`
+	// UserName 用户名
+	UserName string `json:"userName" example:"user1"`
+	// UserID 导入成功时新用户的ID
+	UserID string `json:"userId,omitempty" example:"clywh0xv70001rvpgzd6256ns"`
+	// Success 是否成功，仅校验时表示校验是否通过
+	Success bool `json:"success" example:"true"`
+	// Error 失败原因
+	Error string `json:"error,omitempty" example:"Username already exists"`
 }
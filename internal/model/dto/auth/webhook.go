@@ -0,0 +1,163 @@
+package auth
+
+// ListWebhookRequest 用于查询 Webhook 订阅列表的请求体结构
+type ListWebhookRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// Status 订阅状态，选填，1表示启用，0表示禁用，不传表示全部
+	Status *int8 `json:"status" validate:"omitempty,oneof=0 1" example:"1"`
+}
+
+type ListWebhookResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// List 数据
+	List []WebhookInfo `json:"list"`
+}
+
+type WebhookInfo struct {
+	// ID string 订阅ID
+	ID string `json:"id" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+	// Name string 订阅名称
+	Name string `json:"name" example:"crm-sync"`
+	// URL string 回调地址
+	URL string `json:"url" example:"https://crm.example.com/hooks/wam"`
+	// EventTypeList []string 订阅的事件类型，为空表示全部事件
+	EventTypeList []string `json:"eventTypeList" example:"user.created,user.disabled,user.deleted"`
+	// Status int8 订阅状态（1: 启用, 0: 禁用）
+	Status int8 `json:"status" example:"1"`
+	// CreatedAt string 创建时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+	// UpdatedAt string 更新时间
+	UpdatedAt string `json:"updatedAt" example:"2024-11-18T11:00:00Z"`
+}
+
+// AddWebhookRequest 用于新增 Webhook 订阅的请求体结构
+type AddWebhookRequest struct {
+	// Name 订阅名称，必填，最大长度128字符
+	Name string `json:"name" validate:"required,max=128" example:"crm-sync"`
+
+	// URL 回调地址，必填，仅支持 http 和 https，默认只允许公网地址
+	URL string `json:"url" validate:"required,url,max=512" example:"https://crm.example.com/hooks/wam"`
+
+	// EventTypeList 订阅的事件类型，选填，为空表示订阅全部事件
+	EventTypeList []string `json:"eventTypeList" validate:"omitempty,dive,oneof=role.created role.updated role.disabled role.enabled role.deleted role.restored user.created user.updated user.roles_changed user.disabled user.enabled user.deleted user.restored user.permissions_changed" example:"user.created,user.disabled,user.deleted"`
+
+	// Secret 签名密钥，选填，长度限制：16-128字符，不传时自动生成
+	Secret string `json:"secret" validate:"omitempty,min=16,max=128" example:"9f86d081884c7d659a2feaa0c55ad015"`
+
+	// Status 订阅状态，1表示启用，0表示禁用
+	Status int8 `json:"status" validate:"oneof=0 1" example:"1"`
+}
+
+type AddWebhookResponse struct {
+	// ID string 订阅ID
+	ID string `json:"id" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+	// Secret string 签名密钥，只在新增和更换时返回，请妥善保存
+	Secret string `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015"`
+}
+
+// EditWebhookRequest 用于编辑 Webhook 订阅的请求体结构
+type EditWebhookRequest struct {
+	// ID 订阅ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+
+	// Name 订阅名称，必填，最大长度128字符
+	Name string `json:"name" validate:"required,max=128" example:"crm-sync"`
+
+	// URL 回调地址，必填，仅支持 http 和 https，默认只允许公网地址
+	URL string `json:"url" validate:"required,url,max=512" example:"https://crm.example.com/hooks/wam"`
+
+	// EventTypeList 订阅的事件类型，选填，为空表示订阅全部事件
+	EventTypeList []string `json:"eventTypeList" validate:"omitempty,dive,oneof=role.created role.updated role.disabled role.enabled role.deleted role.restored user.created user.updated user.roles_changed user.disabled user.enabled user.deleted user.restored user.permissions_changed" example:"user.created,user.roles_changed"`
+
+	// Status 订阅状态，1表示启用，0表示禁用
+	Status int8 `json:"status" validate:"oneof=0 1" example:"1"`
+}
+
+// RotateWebhookSecretRequest 用于更换 Webhook 签名密钥的请求体结构
+type RotateWebhookSecretRequest struct {
+	// ID 订阅ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+
+	// Secret 新的签名密钥，选填，长度限制：16-128字符，不传时自动生成
+	Secret string `json:"secret" validate:"omitempty,min=16,max=128" example:"9f86d081884c7d659a2feaa0c55ad015"`
+}
+
+type RotateWebhookSecretResponse struct {
+	// Secret string 新的签名密钥，只在更换时返回，请妥善保存
+	Secret string `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015"`
+}
+
+// DelWebhookRequest 用于删除 Webhook 订阅的请求体结构
+type DelWebhookRequest struct {
+	// ID 订阅ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+}
+
+// ListWebhookDeliveryRequest 用于查询 Webhook 投递记录的请求体结构
+type ListWebhookDeliveryRequest struct {
+	// Page 页码，选填，范围限制：[1,10000]
+	Page int `json:"page" validate:"omitempty,gte=1,lte=10000" example:"1"`
+
+	// PageSize 每页大小，选填，范围限制：[1,10000]
+	PageSize int `json:"pageSize" validate:"omitempty,gte=1,lte=10000" example:"10"`
+
+	// WebhookID 订阅ID，选填，UUID格式
+	WebhookID string `json:"webhookId" validate:"omitempty,uuid4" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+
+	// EventID 事件ID，选填，UUID格式
+	EventID string `json:"eventId" validate:"omitempty,uuid4" example:"0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d"`
+
+	// EventType 事件类型，选填
+	EventType string `json:"eventType" validate:"omitempty,max=64" example:"user.created"`
+
+	// Status 投递状态，选填，0 表示待投递（包括等待重试），1 表示投递成功，2 表示投递失败，不传表示全部
+	Status *int8 `json:"status" validate:"omitempty,oneof=0 1 2" example:"2"`
+}
+
+type ListWebhookDeliveryResponse struct {
+	// total 总条数
+	Total int64 `json:"total" example:"100"`
+	// List 数据
+	List []WebhookDeliveryInfo `json:"list"`
+}
+
+type WebhookDeliveryInfo struct {
+	// ID string 投递ID，与回调请求头 X-Webhook-ID 一致
+	ID string `json:"id" example:"6a1d2c3b-4e5f-4a6b-8c7d-9e0f1a2b3c4d"`
+	// WebhookID string 订阅ID
+	WebhookID string `json:"webhookId" example:"3f6c1a2b-8d4e-4f1a-9b2c-5e7d8f9a0b1c"`
+	// EventID string 事件ID
+	EventID string `json:"eventId" example:"0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d"`
+	// EventType string 事件类型
+	EventType string `json:"eventType" example:"user.created"`
+	// Payload string 回调的请求体，即事件 JSON
+	Payload string `json:"payload" example:"{\"id\":\"0b7c4f5e-2f7a-4a56-9c1b-0f6f1f3b2c1d\",\"type\":\"user.created\"}"`
+	// Status int8 投递状态（0: 待投递, 1: 投递成功, 2: 投递失败）
+	Status int8 `json:"status" example:"1"`
+	// Attempts int 已尝试投递次数
+	Attempts int `json:"attempts" example:"1"`
+	// NextAttemptAt string 下次投递时间
+	NextAttemptAt string `json:"nextAttemptAt" example:"2024-11-18T10:00:10Z"`
+	// ResponseStatus int 最近一次回调的响应状态码，0 表示未收到响应
+	ResponseStatus int `json:"responseStatus" example:"200"`
+	// LastError string 最近一次投递失败的原因
+	LastError string `json:"lastError" example:""`
+	// DurationMs int 最近一次回调的耗时（毫秒）
+	DurationMs int `json:"durationMs" example:"85"`
+	// CreatedAt string 创建时间
+	CreatedAt string `json:"createdAt" example:"2024-11-18T10:00:00Z"`
+	// DeliveredAt string 投递成功时间，未投递成功时为空
+	DeliveredAt string `json:"deliveredAt" example:"2024-11-18T10:00:01Z"`
+}
+
+// RedeliverWebhookRequest 用于重新投递 Webhook 的请求体结构
+type RedeliverWebhookRequest struct {
+	// ID 投递ID，必填，UUID格式
+	ID string `json:"id" validate:"required,uuid4" example:"6a1d2c3b-4e5f-4a6b-8c7d-9e0f1a2b3c4d"`
+}
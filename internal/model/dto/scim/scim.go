@@ -0,0 +1,145 @@
+package scim
+
+import "encoding/json"
+
+// SCIM 2.0 资源及消息的 schema 标识（RFC 7643、RFC 7644）
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// 资源类型
+const (
+	ResourceTypeUser  = "User"  // 用户，对应业务用户
+	ResourceTypeGroup = "Group" // 组，对应角色
+)
+
+// 错误类型（scimType），见 RFC 7644 3.12
+const (
+	ErrorInvalidFilter = "invalidFilter" // 过滤表达式不合法或不支持
+	ErrorInvalidSyntax = "invalidSyntax" // 请求体无法解析
+	ErrorInvalidPath   = "invalidPath"   // PATCH 操作的路径不合法
+	ErrorInvalidValue  = "invalidValue"  // 属性值缺失或不合法
+	ErrorNoTarget      = "noTarget"      // PATCH 操作的路径没有匹配的目标
+	ErrorUniqueness    = "uniqueness"    // 属性值与已有资源冲突
+	ErrorMutability    = "mutability"    // 试图修改不可修改的属性
+)
+
+// Meta 资源元数据
+type Meta struct {
+	// ResourceType 资源类型（User、Group）
+	ResourceType string `json:"resourceType"`
+	// Created 创建时间，RFC3339 格式
+	Created string `json:"created,omitempty"`
+	// LastModified 最后修改时间，RFC3339 格式
+	LastModified string `json:"lastModified,omitempty"`
+	// Location 资源地址
+	Location string `json:"location,omitempty"`
+	// Version 资源版本，与响应头 ETag 一致
+	Version string `json:"version,omitempty"`
+}
+
+// Name 用户姓名
+type Name struct {
+	// Formatted 完整姓名
+	Formatted string `json:"formatted,omitempty"`
+	// FamilyName 姓
+	FamilyName string `json:"familyName,omitempty"`
+	// GivenName 名
+	GivenName string `json:"givenName,omitempty"`
+}
+
+// MultiValued 多值属性的元素，用于 emails、phoneNumbers
+type MultiValued struct {
+	// Value 属性值
+	Value string `json:"value"`
+	// Type 类型，例如 work、mobile
+	Type string `json:"type,omitempty"`
+	// Primary 是否为主值
+	Primary bool `json:"primary,omitempty"`
+}
+
+// Reference 对其他资源的引用，用于用户的 groups 和组的 members
+type Reference struct {
+	// Value 被引用资源的ID
+	Value string `json:"value"`
+	// Display 被引用资源的显示名称
+	Display string `json:"display,omitempty"`
+}
+
+// User 用户资源，对应业务用户
+// userName 对应用户名，displayName（或 name.formatted）对应昵称，主邮箱和主电话对应邮箱和手机号码，active 对应用户状态；
+// groups 为只读属性，列出用户所属的角色，需要通过组的 members 调整。
+type User struct {
+	Schemas      []string      `json:"schemas"`
+	ID           string        `json:"id,omitempty"`
+	UserName     string        `json:"userName"`
+	Name         *Name         `json:"name,omitempty"`
+	DisplayName  string        `json:"displayName,omitempty"`
+	Active       *bool         `json:"active,omitempty"`
+	Password     string        `json:"password,omitempty"`
+	Emails       []MultiValued `json:"emails,omitempty"`
+	PhoneNumbers []MultiValued `json:"phoneNumbers,omitempty"`
+	Groups       []Reference   `json:"groups,omitempty"`
+	Meta         *Meta         `json:"meta,omitempty"`
+}
+
+// Group 组资源，对应角色
+// displayName 对应角色名称，members 为角色的全部成员用户。
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+// ListQuery 列表查询参数
+type ListQuery struct {
+	// Filter 过滤表达式，例如 userName eq "alice"
+	Filter string `form:"filter"`
+	// StartIndex 起始位置，从 1 开始
+	StartIndex int `form:"startIndex"`
+	// Count 每页数量，不传时使用默认值，为 0 时只返回总数
+	Count *int `form:"count"`
+	// ExcludedAttributes 不返回的属性，逗号分隔，目前仅支持组的 members
+	ExcludedAttributes string `form:"excludedAttributes"`
+}
+
+// ListResponse 列表响应
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int64         `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// PatchRequest PATCH 请求体
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation PATCH 操作
+type PatchOperation struct {
+	// Op 操作类型（add、remove、replace），不区分大小写
+	Op string `json:"op"`
+	// Path 属性路径，例如 displayName、name.givenName、members[value eq "id"]
+	Path string `json:"path,omitempty"`
+	// Value 属性值，不传 path 时为包含多个属性的对象
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error 错误响应
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `admin_recovery_codes` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `admin_id` char(36) NOT NULL COMMENT '管理员ID',
+  `code_hash` varchar(64) NOT NULL COMMENT '加密后的恢复码',
+  `used_at` datetime DEFAULT NULL COMMENT '使用时间，为空表示未使用',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='管理员二次验证恢复码表'
+******sql******/
+// AdminRecoveryCodes 管理员二次验证恢复码表
+type AdminRecoveryCodes struct {
+	ID        string     `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                               // 唯一标识
+	AdminID   string     `gorm:"index:admin_id;column:admin_id;type:char(36);not null" json:"adminId"`                // 管理员ID
+	CodeHash  string     `gorm:"column:code_hash;type:varchar(64);not null" json:"codeHash"`                          // 加密后的恢复码
+	UsedAt    *time.Time `gorm:"column:used_at;type:datetime;default:null" json:"usedAt"`                             // 使用时间，为空表示未使用
+	CreatedAt time.Time  `gorm:"column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"` // 创建时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *AdminRecoveryCodes) TableName() string {
+	return "admin_recovery_codes"
+}
+
+// AdminRecoveryCodesColumns get sql column name.获取数据库列名
+var AdminRecoveryCodesColumns = struct {
+	ID        string
+	AdminID   string
+	CodeHash  string
+	UsedAt    string
+	CreatedAt string
+}{
+	ID:        "id",
+	AdminID:   "admin_id",
+	CodeHash:  "code_hash",
+	UsedAt:    "used_at",
+	CreatedAt: "created_at",
+}
@@ -0,0 +1,31 @@
+package entity
+
+/******sql******
+CREATE TABLE `admin_roles` (
+  `admin_id` char(36) NOT NULL COMMENT '管理员ID',
+  `role_id` char(36) NOT NULL COMMENT '角色ID',
+  PRIMARY KEY (`admin_id`,`role_id`),
+  KEY `role_id` (`role_id`),
+  CONSTRAINT `admin_roles_ibfk_1` FOREIGN KEY (`admin_id`) REFERENCES `admins` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `admin_roles_ibfk_2` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='管理员与角色关联表'
+******sql******/
+// AdminRoles 管理员与角色关联表
+type AdminRoles struct {
+	AdminID string `gorm:"primaryKey;column:admin_id;type:char(36);not null" json:"adminId"`             // 管理员ID
+	RoleID  string `gorm:"primaryKey;index:role_id;column:role_id;type:char(36);not null" json:"roleId"` // 角色ID
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *AdminRoles) TableName() string {
+	return "admin_roles"
+}
+
+// AdminRolesColumns get sql column name.获取数据库列名
+var AdminRolesColumns = struct {
+	AdminID string
+	RoleID  string
+}{
+	AdminID: "admin_id",
+	RoleID:  "role_id",
+}
@@ -14,6 +14,10 @@ CREATE TABLE `admins` (
   `phone` varchar(32) DEFAULT NULL COMMENT '手机号码',
   `remark` varchar(256) DEFAULT NULL COMMENT '备注',
   `last_login_at` datetime DEFAULT NULL COMMENT '上次登录时间',
+  `totp_secret` varchar(64) DEFAULT NULL COMMENT 'TOTP 密钥（Base32）',
+  `totp_enabled` tinyint NOT NULL DEFAULT '0' COMMENT '是否启用TOTP二次验证(1: 启用, 0: 未启用)',
+  `password_changed_at` datetime DEFAULT NULL COMMENT '密码最近修改时间',
+  `must_change_password` tinyint NOT NULL DEFAULT '0' COMMENT '下次登录是否必须修改密码(1: 是, 0: 否)',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
@@ -25,17 +29,21 @@ CREATE TABLE `admins` (
 ******sql******/
 // Admins 管理员表
 type Admins struct {
-	ID          string    `gorm:"primaryKey;column:id;type:varchar(36);not null" json:"id"`                                                                                      // 唯一标识
-	Username    string    `gorm:"uniqueIndex:username;column:username;type:varchar(128);not null" json:"username"`                                                               // 用户名
-	Nickname    string    `gorm:"column:nickname;type:varchar(128);default:null" json:"nickname"`                                                                                // 昵称
-	Password    string    `gorm:"column:password;type:varchar(64);not null" json:"password"`                                                                                     // 加密后的密码
-	Email       string    `gorm:"uniqueIndex:email_deleted_at;column:email;type:varchar(256);default:null" json:"email"`                                                         // 邮箱
-	Phone       string    `gorm:"uniqueIndex:phone_deleted_at;column:phone;type:varchar(32);default:null" json:"phone"`                                                          // 手机号码
-	Remark      string    `gorm:"column:remark;type:varchar(256);default:null" json:"remark"`                                                                                    // 备注
-	LastLoginAt time.Time `gorm:"column:last_login_at;type:datetime;default:null" json:"lastLoginAt"`                                                                            // 上次登录时间
-	CreatedAt   time.Time `gorm:"column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`                                                           // 创建时间
-	UpdatedAt   time.Time `gorm:"column:updated_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                                                           // 更新时间
-	DeletedAt   time.Time `gorm:"uniqueIndex:username;uniqueIndex:email_deleted_at;uniqueIndex:phone_deleted_at;column:deleted_at;type:timestamp;default:null" json:"deletedAt"` // 软删除时间
+	ID                 string    `gorm:"primaryKey;column:id;type:varchar(36);not null" json:"id"`                                                                                      // 唯一标识
+	Username           string    `gorm:"uniqueIndex:username;column:username;type:varchar(128);not null" json:"username"`                                                               // 用户名
+	Nickname           string    `gorm:"column:nickname;type:varchar(128);default:null" json:"nickname"`                                                                                // 昵称
+	Password           string    `gorm:"column:password;type:varchar(64);not null" json:"password"`                                                                                     // 加密后的密码
+	Email              string    `gorm:"uniqueIndex:email_deleted_at;column:email;type:varchar(256);default:null" json:"email"`                                                         // 邮箱
+	Phone              string    `gorm:"uniqueIndex:phone_deleted_at;column:phone;type:varchar(32);default:null" json:"phone"`                                                          // 手机号码
+	Remark             string    `gorm:"column:remark;type:varchar(256);default:null" json:"remark"`                                                                                    // 备注
+	LastLoginAt        time.Time `gorm:"column:last_login_at;type:datetime;default:null" json:"lastLoginAt"`                                                                            // 上次登录时间
+	TotpSecret         string    `gorm:"column:totp_secret;type:varchar(64);default:null" json:"totpSecret"`                                                                            // TOTP 密钥（Base32）
+	TotpEnabled        int8      `gorm:"column:totp_enabled;type:tinyint;not null;default:0" json:"totpEnabled"`                                                                        // 是否启用TOTP二次验证(1: 启用, 0: 未启用)
+	PasswordChangedAt  time.Time `gorm:"column:password_changed_at;type:datetime;default:null" json:"passwordChangedAt"`                                                                // 密码最近修改时间
+	MustChangePassword int8      `gorm:"column:must_change_password;type:tinyint;not null;default:0" json:"mustChangePassword"`                                                         // 下次登录是否必须修改密码(1: 是, 0: 否)
+	CreatedAt          time.Time `gorm:"column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`                                                           // 创建时间
+	UpdatedAt          time.Time `gorm:"column:updated_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                                                           // 更新时间
+	DeletedAt          time.Time `gorm:"uniqueIndex:username;uniqueIndex:email_deleted_at;uniqueIndex:phone_deleted_at;column:deleted_at;type:timestamp;default:null" json:"deletedAt"` // 软删除时间
 }
 
 // TableName get sql table name.获取数据库表名
@@ -45,27 +53,35 @@ func (m *Admins) TableName() string {
 
 // AdminsColumns get sql column name.获取数据库列名
 var AdminsColumns = struct {
-	ID          string
-	Username    string
-	Nickname    string
-	Password    string
-	Email       string
-	Phone       string
-	Remark      string
-	LastLoginAt string
-	CreatedAt   string
-	UpdatedAt   string
-	DeletedAt   string
+	ID                 string
+	Username           string
+	Nickname           string
+	Password           string
+	Email              string
+	Phone              string
+	Remark             string
+	LastLoginAt        string
+	PasswordChangedAt  string
+	MustChangePassword string
+	TotpSecret         string
+	TotpEnabled        string
+	CreatedAt          string
+	UpdatedAt          string
+	DeletedAt          string
 }{
-	ID:          "id",
-	Username:    "username",
-	Nickname:    "nickname",
-	Password:    "password",
-	Email:       "email",
-	Phone:       "phone",
-	Remark:      "remark",
-	LastLoginAt: "last_login_at",
-	CreatedAt:   "created_at",
-	UpdatedAt:   "updated_at",
-	DeletedAt:   "deleted_at",
+	ID:                 "id",
+	Username:           "username",
+	Nickname:           "nickname",
+	Password:           "password",
+	Email:              "email",
+	Phone:              "phone",
+	Remark:             "remark",
+	LastLoginAt:        "last_login_at",
+	TotpSecret:         "totp_secret",
+	TotpEnabled:        "totp_enabled",
+	PasswordChangedAt:  "password_changed_at",
+	MustChangePassword: "must_change_password",
+	CreatedAt:          "created_at",
+	UpdatedAt:          "updated_at",
+	DeletedAt:          "deleted_at",
 }
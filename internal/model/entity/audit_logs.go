@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `audit_logs` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `actor_id` varchar(36) NOT NULL DEFAULT '' COMMENT '操作人ID',
+  `actor_type` varchar(16) NOT NULL DEFAULT '' COMMENT '操作人类型(admin: 管理员, user: 业务用户, scim: SCIM 客户端)',
+  `action` varchar(32) NOT NULL COMMENT '操作类型(create、update、delete等)',
+  `target_type` varchar(32) NOT NULL COMMENT '操作对象类型(admin、user、role等)',
+  `target_id` varchar(36) NOT NULL COMMENT '操作对象ID',
+  `old_values` json DEFAULT NULL COMMENT '变更前发生变化的字段',
+  `new_values` json DEFAULT NULL COMMENT '变更后发生变化的字段',
+  `client_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+  `user_agent` varchar(512) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+  `request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '请求ID',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `actor` (`actor_id`,`created_at`),
+  KEY `target` (`target_type`,`target_id`,`created_at`),
+  KEY `request_id` (`request_id`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='审计日志表'
+******sql******/
+// AuditLogs 审计日志表
+type AuditLogs struct {
+	ID         string    `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                                                                         // 唯一标识
+	ActorID    string    `gorm:"index:actor;column:actor_id;type:varchar(36);not null;default:''" json:"actorId"`                                               // 操作人ID
+	ActorType  string    `gorm:"column:actor_type;type:varchar(16);not null;default:''" json:"actorType"`                                                       // 操作人类型(admin: 管理员, user: 业务用户, scim: SCIM 客户端)
+	Action     string    `gorm:"column:action;type:varchar(32);not null" json:"action"`                                                                         // 操作类型(create、update、delete等)
+	TargetType string    `gorm:"index:target;column:target_type;type:varchar(32);not null" json:"targetType"`                                                   // 操作对象类型(admin、user、role等)
+	TargetID   string    `gorm:"index:target;column:target_id;type:varchar(36);not null" json:"targetId"`                                                       // 操作对象ID
+	OldValues  string    `gorm:"column:old_values;type:json;default:null" json:"oldValues"`                                                                     // 变更前发生变化的字段
+	NewValues  string    `gorm:"column:new_values;type:json;default:null" json:"newValues"`                                                                     // 变更后发生变化的字段
+	ClientIP   string    `gorm:"column:client_ip;type:varchar(64);not null;default:''" json:"clientIp"`                                                         // 客户端IP
+	UserAgent  string    `gorm:"column:user_agent;type:varchar(512);not null;default:''" json:"userAgent"`                                                      // 客户端 User-Agent
+	RequestID  string    `gorm:"index:request_id;column:request_id;type:varchar(64);not null;default:''" json:"requestId"`                                      // 请求ID
+	CreatedAt  time.Time `gorm:"index:actor;index:target;index:created_at;column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"` // 创建时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *AuditLogs) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogsColumns get sql column name.获取数据库列名
+var AuditLogsColumns = struct {
+	ID         string
+	ActorID    string
+	ActorType  string
+	Action     string
+	TargetType string
+	TargetID   string
+	OldValues  string
+	NewValues  string
+	ClientIP   string
+	UserAgent  string
+	RequestID  string
+	CreatedAt  string
+}{
+	ID:         "id",
+	ActorID:    "actor_id",
+	ActorType:  "actor_type",
+	Action:     "action",
+	TargetType: "target_type",
+	TargetID:   "target_id",
+	OldValues:  "old_values",
+	NewValues:  "new_values",
+	ClientIP:   "client_ip",
+	UserAgent:  "user_agent",
+	RequestID:  "request_id",
+	CreatedAt:  "created_at",
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `login_logs` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `subject_type` varchar(16) NOT NULL COMMENT '账号类型(admin: 管理员, user: 业务用户)',
+  `event` varchar(32) NOT NULL COMMENT '事件类型(login、loginMfaChallenge、loginTotp、changePassword)',
+  `identifier` varchar(128) NOT NULL DEFAULT '' COMMENT '登录时提交的账号标识（用户名|手机号|邮箱）',
+  `account_id` varchar(36) NOT NULL DEFAULT '' COMMENT '解析出的账号ID，账号不存在时为空',
+  `client_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+  `user_agent` varchar(512) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+  `result_code` int NOT NULL DEFAULT '0' COMMENT '结果码(0: 成功, 其他: 失败时的错误码)',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '发生时间',
+  PRIMARY KEY (`id`),
+  KEY `account` (`subject_type`,`account_id`,`created_at`),
+  KEY `identifier` (`identifier`,`created_at`),
+  KEY `client_ip` (`client_ip`,`created_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='登录日志表'
+******sql******/
+// LoginLogs 登录日志表
+type LoginLogs struct {
+	ID          string    `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                                                                                               // 唯一标识
+	SubjectType string    `gorm:"index:account;column:subject_type;type:varchar(16);not null" json:"subjectType"`                                                                      // 账号类型(admin: 管理员, user: 业务用户)
+	Event       string    `gorm:"column:event;type:varchar(32);not null" json:"event"`                                                                                                 // 事件类型(login、loginMfaChallenge、loginTotp、changePassword)
+	Identifier  string    `gorm:"index:identifier;column:identifier;type:varchar(128);not null;default:''" json:"identifier"`                                                          // 登录时提交的账号标识（用户名|手机号|邮箱）
+	AccountID   string    `gorm:"index:account;column:account_id;type:varchar(36);not null;default:''" json:"accountId"`                                                               // 解析出的账号ID，账号不存在时为空
+	ClientIP    string    `gorm:"index:client_ip;column:client_ip;type:varchar(64);not null;default:''" json:"clientIp"`                                                               // 客户端IP
+	UserAgent   string    `gorm:"column:user_agent;type:varchar(512);not null;default:''" json:"userAgent"`                                                                            // 客户端 User-Agent
+	ResultCode  int       `gorm:"column:result_code;type:int;not null;default:0" json:"resultCode"`                                                                                    // 结果码(0: 成功, 其他: 失败时的错误码)
+	CreatedAt   time.Time `gorm:"index:account;index:identifier;index:client_ip;index:created_at;column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"` // 发生时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *LoginLogs) TableName() string {
+	return "login_logs"
+}
+
+// LoginLogsColumns get sql column name.获取数据库列名
+var LoginLogsColumns = struct {
+	ID          string
+	SubjectType string
+	Event       string
+	Identifier  string
+	AccountID   string
+	ClientIP    string
+	UserAgent   string
+	ResultCode  string
+	CreatedAt   string
+}{
+	ID:          "id",
+	SubjectType: "subject_type",
+	Event:       "event",
+	Identifier:  "identifier",
+	AccountID:   "account_id",
+	ClientIP:    "client_ip",
+	UserAgent:   "user_agent",
+	ResultCode:  "result_code",
+	CreatedAt:   "created_at",
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `outbox` (
+  `id` char(36) NOT NULL COMMENT '唯一标识，与事件ID一致',
+  `event_type` varchar(64) NOT NULL COMMENT '事件类型',
+  `payload` json NOT NULL COMMENT '事件内容',
+  `status` tinyint NOT NULL DEFAULT '0' COMMENT '投递状态(0: 待投递, 1: 已投递, 2: 已进入死信)',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '已尝试投递次数',
+  `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次投递时间',
+  `last_error` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次投递失败的原因',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `delivered_at` datetime DEFAULT NULL COMMENT '投递成功时间',
+  PRIMARY KEY (`id`),
+  KEY `status_next_attempt` (`status`,`next_attempt_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='事件发件箱表'
+******sql******/
+// Outbox 事件发件箱表
+type Outbox struct {
+	ID            string     `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                                                                  // 唯一标识，与事件ID一致
+	EventType     string     `gorm:"column:event_type;type:varchar(64);not null" json:"eventType"`                                                           // 事件类型
+	Payload       string     `gorm:"column:payload;type:json;not null" json:"payload"`                                                                       // 事件内容
+	Status        int8       `gorm:"index:status_next_attempt;column:status;type:tinyint;not null;default:0" json:"status"`                                  // 投递状态(0: 待投递, 1: 已投递, 2: 已进入死信)
+	Attempts      int        `gorm:"column:attempts;type:int;not null;default:0" json:"attempts"`                                                            // 已尝试投递次数
+	NextAttemptAt time.Time  `gorm:"index:status_next_attempt;column:next_attempt_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"nextAttemptAt"` // 下次投递时间
+	LastError     string     `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"lastError"`                                              // 最近一次投递失败的原因
+	CreatedAt     time.Time  `gorm:"index:created_at;column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`                   // 创建时间
+	UpdatedAt     time.Time  `gorm:"column:updated_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                                    // 更新时间
+	DeliveredAt   *time.Time `gorm:"column:delivered_at;type:datetime;default:null" json:"deliveredAt"`                                                      // 投递成功时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *Outbox) TableName() string {
+	return "outbox"
+}
+
+// OutboxColumns get sql column name.获取数据库列名
+var OutboxColumns = struct {
+	ID            string
+	EventType     string
+	Payload       string
+	Status        string
+	Attempts      string
+	NextAttemptAt string
+	LastError     string
+	CreatedAt     string
+	UpdatedAt     string
+	DeliveredAt   string
+}{
+	ID:            "id",
+	EventType:     "event_type",
+	Payload:       "payload",
+	Status:        "status",
+	Attempts:      "attempts",
+	NextAttemptAt: "next_attempt_at",
+	LastError:     "last_error",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
+	DeliveredAt:   "delivered_at",
+}
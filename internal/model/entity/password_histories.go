@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `password_histories` (
+  `id` char(36) NOT NULL COMMENT '唯一标识',
+  `subject_type` varchar(16) NOT NULL COMMENT '账号类型(admin: 管理员, user: 业务用户)',
+  `subject_id` char(36) NOT NULL COMMENT '账号ID',
+  `password` varchar(64) NOT NULL COMMENT '曾经使用过的加密密码',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `subject` (`subject_type`,`subject_id`,`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='密码历史表'
+******sql******/
+// PasswordHistories 密码历史表
+type PasswordHistories struct {
+	ID          string    `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                                             // 唯一标识
+	SubjectType string    `gorm:"index:subject;column:subject_type;type:varchar(16);not null" json:"subjectType"`                    // 账号类型(admin: 管理员, user: 业务用户)
+	SubjectID   string    `gorm:"index:subject;column:subject_id;type:char(36);not null" json:"subjectId"`                           // 账号ID
+	Password    string    `gorm:"column:password;type:varchar(64);not null" json:"password"`                                         // 曾经使用过的加密密码
+	CreatedAt   time.Time `gorm:"index:subject;column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"` // 创建时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *PasswordHistories) TableName() string {
+	return "password_histories"
+}
+
+// PasswordHistoriesColumns get sql column name.获取数据库列名
+var PasswordHistoriesColumns = struct {
+	ID          string
+	SubjectType string
+	SubjectID   string
+	Password    string
+	CreatedAt   string
+}{
+	ID:          "id",
+	SubjectType: "subject_type",
+	SubjectID:   "subject_id",
+	Password:    "password",
+	CreatedAt:   "created_at",
+}
@@ -11,6 +11,7 @@ CREATE TABLE `paths` (
   `method` enum('GET','POST','PUT','DELETE') NOT NULL COMMENT 'HTTP 方法',
   `description` varchar(255) DEFAULT NULL COMMENT '路径描述',
   `menu_id` char(36) NOT NULL COMMENT '菜单ID，指向menus表的ID',
+  `stale` tinyint NOT NULL DEFAULT '0' COMMENT '路由是否已不存在(1: 是, 0: 否)，由路由同步标记',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
@@ -27,6 +28,7 @@ type Paths struct {
 	Method      string    `gorm:"uniqueIndex:unique_path_method;column:method;type:enum('GET','POST','PUT','DELETE');not null" json:"method"` // HTTP 方法
 	Description string    `gorm:"column:description;type:varchar(255);default:null" json:"description"`                                       // 路径描述
 	MenuID      string    `gorm:"index:paths_ibfk_1;column:menu_id;type:char(36);not null" json:"menuId"`                                     // 菜单ID，指向menus表的ID
+	Stale       int8      `gorm:"column:stale;type:tinyint;not null;default:0" json:"stale"`                                                  // 路由是否已不存在(1: 是, 0: 否)，由路由同步标记
 	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;default:null;default:CURRENT_TIMESTAMP" json:"createdAt"`                   // 创建时间
 	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamp;default:null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                   // 更新时间
 	DeletedAt   time.Time `gorm:"uniqueIndex:unique_path_method;column:deleted_at;type:timestamp;default:null" json:"deletedAt"`              // 软删除时间
@@ -44,6 +46,7 @@ var PathsColumns = struct {
 	Method      string
 	Description string
 	MenuID      string
+	Stale       string
 	CreatedAt   string
 	UpdatedAt   string
 	DeletedAt   string
@@ -53,6 +56,7 @@ var PathsColumns = struct {
 	Method:      "method",
 	Description: "description",
 	MenuID:      "menu_id",
+	Stale:       "stale",
 	CreatedAt:   "created_at",
 	UpdatedAt:   "updated_at",
 	DeletedAt:   "deleted_at",
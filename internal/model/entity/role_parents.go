@@ -0,0 +1,31 @@
+package entity
+
+/******sql******
+CREATE TABLE `role_parents` (
+  `role_id` char(36) NOT NULL COMMENT '角色ID',
+  `parent_id` char(36) NOT NULL COMMENT '父角色ID',
+  PRIMARY KEY (`role_id`,`parent_id`),
+  KEY `parent_id` (`parent_id`),
+  CONSTRAINT `role_parents_ibfk_1` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `role_parents_ibfk_2` FOREIGN KEY (`parent_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='角色继承关系表'
+******sql******/
+// RoleParents 角色继承关系表，角色继承父角色的全部接口权限
+type RoleParents struct {
+	RoleID   string `gorm:"primaryKey;column:role_id;type:char(36);not null" json:"roleId"`                     // 角色ID
+	ParentID string `gorm:"primaryKey;index:parent_id;column:parent_id;type:char(36);not null" json:"parentId"` // 父角色ID
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *RoleParents) TableName() string {
+	return "role_parents"
+}
+
+// RoleParentsColumns get sql column name.获取数据库列名
+var RoleParentsColumns = struct {
+	RoleID   string
+	ParentID string
+}{
+	RoleID:   "role_id",
+	ParentID: "parent_id",
+}
@@ -10,6 +10,7 @@ CREATE TABLE `roles` (
   `name` varchar(128) NOT NULL COMMENT '角色名称',
   `description` varchar(255) DEFAULT NULL COMMENT '角色描述',
   `status` tinyint DEFAULT '1' COMMENT '状态: 1=启用, 0=禁用',
+  `is_builtin` tinyint NOT NULL DEFAULT '0' COMMENT '是否内置角色: 1=是, 0=否，内置角色不可删除或禁用',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
@@ -23,6 +24,7 @@ type Roles struct {
 	Name        string    `gorm:"uniqueIndex:unique_name_deleted;column:name;type:varchar(128);not null" json:"name"`             // 角色名称
 	Description string    `gorm:"column:description;type:varchar(255);default:null" json:"description"`                           // 角色描述
 	Status      int8      `gorm:"column:status;type:tinyint;default:null;default:1" json:"status"`                                // 状态: 1=启用, 0=禁用
+	IsBuiltin   int8      `gorm:"column:is_builtin;type:tinyint;not null;default:0" json:"isBuiltin"`                             // 是否内置角色: 1=是, 0=否，内置角色不可删除或禁用
 	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp;default:null;default:CURRENT_TIMESTAMP" json:"createdAt"`       // 创建时间
 	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamp;default:null;default:CURRENT_TIMESTAMP" json:"updatedAt"`       // 更新时间
 	DeletedAt   time.Time `gorm:"uniqueIndex:unique_name_deleted;column:deleted_at;type:timestamp;default:null" json:"deletedAt"` // 软删除时间
@@ -39,6 +41,7 @@ var RolesColumns = struct {
 	Name        string
 	Description string
 	Status      string
+	IsBuiltin   string
 	CreatedAt   string
 	UpdatedAt   string
 	DeletedAt   string
@@ -47,6 +50,7 @@ var RolesColumns = struct {
 	Name:        "name",
 	Description: "description",
 	Status:      "status",
+	IsBuiltin:   "is_builtin",
 	CreatedAt:   "created_at",
 	UpdatedAt:   "updated_at",
 	DeletedAt:   "deleted_at",
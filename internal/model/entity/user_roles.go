@@ -1,19 +1,30 @@
 package entity
 
+import (
+	"time"
+)
+
 /******sql******
 CREATE TABLE `user_roles` (
   `user_id` char(36) NOT NULL COMMENT '用户ID',
   `role_id` char(36) NOT NULL COMMENT '角色ID',
+  `valid_from` datetime DEFAULT NULL COMMENT '生效时间，为空表示立即生效',
+  `valid_until` datetime DEFAULT NULL COMMENT '失效时间，为空表示永久有效',
+  `active` tinyint NOT NULL DEFAULT '1' COMMENT '当前是否已计入用户权限: 1=是, 0=否，由定时任务按有效期维护',
   PRIMARY KEY (`user_id`,`role_id`),
   KEY `role_id` (`role_id`),
+  KEY `active` (`active`),
   CONSTRAINT `user_roles_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
   CONSTRAINT `user_roles_ibfk_2` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户与角色关联表'
 ******sql******/
 // UserRoles 用户与角色关联表
 type UserRoles struct {
-	UserID string `gorm:"primaryKey;column:user_id;type:char(36);not null" json:"userId"`               // 用户ID
-	RoleID string `gorm:"primaryKey;index:role_id;column:role_id;type:char(36);not null" json:"roleId"` // 角色ID
+	UserID     string     `gorm:"primaryKey;column:user_id;type:char(36);not null" json:"userId"`               // 用户ID
+	RoleID     string     `gorm:"primaryKey;index:role_id;column:role_id;type:char(36);not null" json:"roleId"` // 角色ID
+	ValidFrom  *time.Time `gorm:"column:valid_from;type:datetime;default:null" json:"validFrom"`                // 生效时间，为空表示立即生效
+	ValidUntil *time.Time `gorm:"column:valid_until;type:datetime;default:null" json:"validUntil"`              // 失效时间，为空表示永久有效
+	Active     int8       `gorm:"index:active;column:active;type:tinyint;not null;default:1" json:"active"`     // 当前是否已计入用户权限: 1=是, 0=否，由定时任务按有效期维护
 }
 
 // TableName get sql table name.获取数据库表名
@@ -23,9 +34,15 @@ func (m *UserRoles) TableName() string {
 
 // UserRolesColumns get sql column name.获取数据库列名
 var UserRolesColumns = struct {
-	UserID string
-	RoleID string
+	UserID     string
+	RoleID     string
+	ValidFrom  string
+	ValidUntil string
+	Active     string
 }{
-	UserID: "user_id",
-	RoleID: "role_id",
+	UserID:     "user_id",
+	RoleID:     "role_id",
+	ValidFrom:  "valid_from",
+	ValidUntil: "valid_until",
+	Active:     "active",
 }
@@ -15,6 +15,8 @@ CREATE TABLE `users` (
   `status` tinyint NOT NULL DEFAULT '1' COMMENT '状态(1: 启用, 0: 禁用)',
   `remark` varchar(256) DEFAULT NULL COMMENT '备注',
   `last_login_at` datetime DEFAULT NULL COMMENT '上次登录时间',
+  `password_changed_at` datetime DEFAULT NULL COMMENT '密码最近修改时间',
+  `must_change_password` tinyint NOT NULL DEFAULT '0' COMMENT '下次登录是否必须修改密码(1: 是, 0: 否)',
   `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
@@ -26,18 +28,20 @@ CREATE TABLE `users` (
 ******sql******/
 // Users 用户表
 type Users struct {
-	ID          string    `gorm:"primaryKey;column:id;type:varchar(36);not null" json:"id"`                                                                                      // 唯一标识
-	Username    string    `gorm:"uniqueIndex:username;column:username;type:varchar(128);not null" json:"username"`                                                               // 用户名
-	Nickname    string    `gorm:"column:nickname;type:varchar(128);default:null" json:"nickname"`                                                                                // 昵称
-	Password    string    `gorm:"column:password;type:varchar(64);not null" json:"password"`                                                                                     // 加密后的密码
-	Email       string    `gorm:"uniqueIndex:email_deleted_at;column:email;type:varchar(256);default:null" json:"email"`                                                         // 邮箱
-	Phone       string    `gorm:"uniqueIndex:phone_deleted_at;column:phone;type:varchar(32);default:null" json:"phone"`                                                          // 手机号码
-	Status      int8      `gorm:"column:status;type:tinyint;not null;default:1" json:"status"`                                                                                   // 状态(1: 启用, 0: 禁用)
-	Remark      string    `gorm:"column:remark;type:varchar(256);default:null" json:"remark"`                                                                                    // 备注
-	LastLoginAt time.Time `gorm:"column:last_login_at;type:datetime;default:null" json:"lastLoginAt"`                                                                            // 上次登录时间
-	CreatedAt   time.Time `gorm:"column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`                                                           // 创建时间
-	UpdatedAt   time.Time `gorm:"column:updated_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                                                           // 更新时间
-	DeletedAt   time.Time `gorm:"uniqueIndex:username;uniqueIndex:email_deleted_at;uniqueIndex:phone_deleted_at;column:deleted_at;type:timestamp;default:null" json:"deletedAt"` // 软删除时间
+	ID                 string    `gorm:"primaryKey;column:id;type:varchar(36);not null" json:"id"`                                                                                      // 唯一标识
+	Username           string    `gorm:"uniqueIndex:username;column:username;type:varchar(128);not null" json:"username"`                                                               // 用户名
+	Nickname           string    `gorm:"column:nickname;type:varchar(128);default:null" json:"nickname"`                                                                                // 昵称
+	Password           string    `gorm:"column:password;type:varchar(64);not null" json:"password"`                                                                                     // 加密后的密码
+	Email              string    `gorm:"uniqueIndex:email_deleted_at;column:email;type:varchar(256);default:null" json:"email"`                                                         // 邮箱
+	Phone              string    `gorm:"uniqueIndex:phone_deleted_at;column:phone;type:varchar(32);default:null" json:"phone"`                                                          // 手机号码
+	Status             int8      `gorm:"column:status;type:tinyint;not null;default:1" json:"status"`                                                                                   // 状态(1: 启用, 0: 禁用)
+	Remark             string    `gorm:"column:remark;type:varchar(256);default:null" json:"remark"`                                                                                    // 备注
+	LastLoginAt        time.Time `gorm:"column:last_login_at;type:datetime;default:null" json:"lastLoginAt"`                                                                            // 上次登录时间
+	PasswordChangedAt  time.Time `gorm:"column:password_changed_at;type:datetime;default:null" json:"passwordChangedAt"`                                                                // 密码最近修改时间
+	MustChangePassword int8      `gorm:"column:must_change_password;type:tinyint;not null;default:0" json:"mustChangePassword"`                                                         // 下次登录是否必须修改密码(1: 是, 0: 否)
+	CreatedAt          time.Time `gorm:"column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`                                                           // 创建时间
+	UpdatedAt          time.Time `gorm:"column:updated_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                                                           // 更新时间
+	DeletedAt          time.Time `gorm:"uniqueIndex:username;uniqueIndex:email_deleted_at;uniqueIndex:phone_deleted_at;column:deleted_at;type:timestamp;default:null" json:"deletedAt"` // 软删除时间
 }
 
 // TableName get sql table name.获取数据库表名
@@ -47,29 +51,33 @@ func (m *Users) TableName() string {
 
 // UsersColumns get sql column name.获取数据库列名
 var UsersColumns = struct {
-	ID          string
-	Username    string
-	Nickname    string
-	Password    string
-	Email       string
-	Phone       string
-	Status      string
-	Remark      string
-	LastLoginAt string
-	CreatedAt   string
-	UpdatedAt   string
-	DeletedAt   string
+	ID                 string
+	Username           string
+	Nickname           string
+	Password           string
+	Email              string
+	Phone              string
+	Status             string
+	Remark             string
+	LastLoginAt        string
+	PasswordChangedAt  string
+	MustChangePassword string
+	CreatedAt          string
+	UpdatedAt          string
+	DeletedAt          string
 }{
-	ID:          "id",
-	Username:    "username",
-	Nickname:    "nickname",
-	Password:    "password",
-	Email:       "email",
-	Phone:       "phone",
-	Status:      "status",
-	Remark:      "remark",
-	LastLoginAt: "last_login_at",
-	CreatedAt:   "created_at",
-	UpdatedAt:   "updated_at",
-	DeletedAt:   "deleted_at",
+	ID:                 "id",
+	Username:           "username",
+	Nickname:           "nickname",
+	Password:           "password",
+	Email:              "email",
+	Phone:              "phone",
+	Status:             "status",
+	Remark:             "remark",
+	LastLoginAt:        "last_login_at",
+	PasswordChangedAt:  "password_changed_at",
+	MustChangePassword: "must_change_password",
+	CreatedAt:          "created_at",
+	UpdatedAt:          "updated_at",
+	DeletedAt:          "deleted_at",
 }
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `webhook_deliveries` (
+  `id` char(36) NOT NULL COMMENT '投递ID',
+  `webhook_id` char(36) NOT NULL COMMENT 'Webhook 订阅ID',
+  `event_id` char(36) NOT NULL COMMENT '事件ID',
+  `event_type` varchar(64) NOT NULL COMMENT '事件类型',
+  `payload` json NOT NULL COMMENT '事件内容，即回调的请求体',
+  `status` tinyint NOT NULL DEFAULT '0' COMMENT '投递状态(0: 待投递, 1: 投递成功, 2: 投递失败)',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '已尝试投递次数',
+  `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次投递时间',
+  `response_status` int NOT NULL DEFAULT '0' COMMENT '最近一次回调的响应状态码，0 表示未收到响应',
+  `last_error` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次投递失败的原因',
+  `duration_ms` int NOT NULL DEFAULT '0' COMMENT '最近一次回调的耗时（毫秒）',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `delivered_at` datetime DEFAULT NULL COMMENT '投递成功时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `webhook_event` (`webhook_id`,`event_id`),
+  KEY `status_next_attempt` (`status`,`next_attempt_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='Webhook 投递记录表'
+******sql******/
+// WebhookDeliveries Webhook 投递记录表
+type WebhookDeliveries struct {
+	ID             string     `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                                                                  // 投递ID
+	WebhookID      string     `gorm:"uniqueIndex:webhook_event;column:webhook_id;type:char(36);not null" json:"webhookId"`                                    // Webhook 订阅ID
+	EventID        string     `gorm:"uniqueIndex:webhook_event;column:event_id;type:char(36);not null" json:"eventId"`                                        // 事件ID
+	EventType      string     `gorm:"column:event_type;type:varchar(64);not null" json:"eventType"`                                                           // 事件类型
+	Payload        string     `gorm:"column:payload;type:json;not null" json:"payload"`                                                                       // 事件内容，即回调的请求体
+	Status         int8       `gorm:"index:status_next_attempt;column:status;type:tinyint;not null;default:0" json:"status"`                                  // 投递状态(0: 待投递, 1: 投递成功, 2: 投递失败)
+	Attempts       int        `gorm:"column:attempts;type:int;not null;default:0" json:"attempts"`                                                            // 已尝试投递次数
+	NextAttemptAt  time.Time  `gorm:"index:status_next_attempt;column:next_attempt_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"nextAttemptAt"` // 下次投递时间
+	ResponseStatus int        `gorm:"column:response_status;type:int;not null;default:0" json:"responseStatus"`                                               // 最近一次回调的响应状态码，0 表示未收到响应
+	LastError      string     `gorm:"column:last_error;type:varchar(1024);not null;default:''" json:"lastError"`                                              // 最近一次投递失败的原因
+	DurationMs     int        `gorm:"column:duration_ms;type:int;not null;default:0" json:"durationMs"`                                                       // 最近一次回调的耗时（毫秒）
+	CreatedAt      time.Time  `gorm:"index:created_at;column:created_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`                   // 创建时间
+	UpdatedAt      time.Time  `gorm:"column:updated_at;type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`                                    // 更新时间
+	DeliveredAt    *time.Time `gorm:"column:delivered_at;type:datetime;default:null" json:"deliveredAt"`                                                      // 投递成功时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *WebhookDeliveries) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveriesColumns get sql column name.获取数据库列名
+var WebhookDeliveriesColumns = struct {
+	ID             string
+	WebhookID      string
+	EventID        string
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       string
+	NextAttemptAt  string
+	ResponseStatus string
+	LastError      string
+	DurationMs     string
+	CreatedAt      string
+	UpdatedAt      string
+	DeliveredAt    string
+}{
+	ID:             "id",
+	WebhookID:      "webhook_id",
+	EventID:        "event_id",
+	EventType:      "event_type",
+	Payload:        "payload",
+	Status:         "status",
+	Attempts:       "attempts",
+	NextAttemptAt:  "next_attempt_at",
+	ResponseStatus: "response_status",
+	LastError:      "last_error",
+	DurationMs:     "duration_ms",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
+	DeliveredAt:    "delivered_at",
+}
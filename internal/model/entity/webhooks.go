@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+)
+
+/******sql******
+CREATE TABLE `webhooks` (
+  `id` char(36) NOT NULL COMMENT '订阅ID',
+  `name` varchar(128) NOT NULL COMMENT '订阅名称',
+  `url` varchar(512) NOT NULL COMMENT '回调地址',
+  `events` json NOT NULL COMMENT '订阅的事件类型，空数组表示全部事件',
+  `secret` varchar(128) NOT NULL COMMENT '签名密钥',
+  `status` tinyint NOT NULL DEFAULT '1' COMMENT '状态: 1=启用, 0=禁用',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` timestamp NULL DEFAULT NULL COMMENT '软删除时间',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='Webhook 订阅表'
+******sql******/
+// Webhooks Webhook 订阅表
+type Webhooks struct {
+	ID        string    `gorm:"primaryKey;column:id;type:char(36);not null" json:"id"`                                    // 订阅ID
+	Name      string    `gorm:"column:name;type:varchar(128);not null" json:"name"`                                       // 订阅名称
+	URL       string    `gorm:"column:url;type:varchar(512);not null" json:"url"`                                         // 回调地址
+	Events    string    `gorm:"column:events;type:json;not null" json:"events"`                                           // 订阅的事件类型，空数组表示全部事件
+	Secret    string    `gorm:"column:secret;type:varchar(128);not null" json:"-"`                                        // 签名密钥
+	Status    int8      `gorm:"column:status;type:tinyint;not null;default:1" json:"status"`                              // 状态: 1=启用, 0=禁用
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;default:null;default:CURRENT_TIMESTAMP" json:"createdAt"` // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;default:null;default:CURRENT_TIMESTAMP" json:"updatedAt"` // 更新时间
+	DeletedAt time.Time `gorm:"column:deleted_at;type:timestamp;default:null" json:"deletedAt"`                           // 软删除时间
+}
+
+// TableName get sql table name.获取数据库表名
+func (m *Webhooks) TableName() string {
+	return "webhooks"
+}
+
+// WebhooksColumns get sql column name.获取数据库列名
+var WebhooksColumns = struct {
+	ID        string
+	Name      string
+	URL       string
+	Events    string
+	Secret    string
+	Status    string
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
+}{
+	ID:        "id",
+	Name:      "name",
+	URL:       "url",
+	Events:    "events",
+	Secret:    "secret",
+	Status:    "status",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+	DeletedAt: "deleted_at",
+}
@@ -2,6 +2,7 @@ package routers
 
 import (
 	"ByteScience-WAM-Admin/docs" // 导入 Swagger 生成的文档
+	"ByteScience-WAM-Admin/internal/routers/scim"
 	"ByteScience-WAM-Admin/internal/routers/v1"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -16,4 +17,7 @@ func Register(router *gin.Engine) {
 
 	// 加载v1的路由
 	v1.LoadRouters(router)
+
+	// 加载 SCIM 2.0 的路由
+	scim.LoadRouters(router)
 }
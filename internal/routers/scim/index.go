@@ -0,0 +1,33 @@
+package scim
+
+import (
+	"ByteScience-WAM-Admin/internal/api/scim"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadRouters 注册供身份提供商调用的 SCIM 2.0 接口，使用 SCIM 令牌鉴权
+func LoadRouters(router *gin.Engine) {
+	scimGroup := router.Group(utils.ScimBasePath, middleware.ScimAuth())
+	{
+		scimApi := scim.NewScimApi()
+		scimGroup.GET("/ServiceProviderConfig", scimApi.ServiceProviderConfig)
+		scimGroup.GET("/ResourceTypes", scimApi.ResourceTypes)
+
+		scimGroup.GET("/Users", scimApi.ListUsers)
+		scimGroup.POST("/Users", scimApi.CreateUser)
+		scimGroup.GET("/Users/:id", scimApi.GetUser)
+		scimGroup.PUT("/Users/:id", scimApi.ReplaceUser)
+		scimGroup.PATCH("/Users/:id", scimApi.PatchUser)
+		scimGroup.DELETE("/Users/:id", scimApi.DeleteUser)
+
+		scimGroup.GET("/Groups", scimApi.ListGroups)
+		scimGroup.POST("/Groups", scimApi.CreateGroup)
+		scimGroup.GET("/Groups/:id", scimApi.GetGroup)
+		scimGroup.PUT("/Groups/:id", scimApi.ReplaceGroup)
+		scimGroup.PATCH("/Groups/:id", scimApi.PatchGroup)
+		scimGroup.DELETE("/Groups/:id", scimApi.DeleteGroup)
+	}
+}
@@ -10,40 +10,107 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitAuthRouter(routerGroup *gin.RouterGroup) {
+func InitAuthRouter(routerGroup *gin.RouterGroup, routes func() gin.RoutesInfo) {
 	secret := conf.GlobalConf.Jwt.AccessSecret
 
 	authApi := auth.NewAuthApi()
 	{
 		utils.RegisterRoute(routerGroup, http.MethodPost, "/login", authApi.Login)
+		utils.RegisterRoute(routerGroup, http.MethodPost, "/login/totp", authApi.LoginTotp)
+		utils.RegisterRoute(routerGroup, http.MethodPost, "/user/login", authApi.UserLogin)
 		utils.RegisterRoute(routerGroup, http.MethodPut, "/changPassword", authApi.ChangPassword)
+		utils.RegisterRoute(routerGroup, http.MethodPost, "/refresh", authApi.Refresh)
+		// 以下接口同时接受密码被重置或已过期时签发的受限令牌
+		passwordChangeAuth := middleware.JWTAuth(secret, utils.PasswordChangeScope)
+		utils.RegisterRoute(routerGroup, http.MethodPost, "/logout", authApi.Logout, passwordChangeAuth)
+		utils.RegisterRoute(routerGroup, http.MethodPost, "/logoutAll", authApi.LogoutAll, passwordChangeAuth)
+		utils.RegisterRoute(routerGroup, http.MethodPut, "/auth/changPassword", authApi.ChangeOwnPassword, passwordChangeAuth)
 	}
 
 	authGroup := routerGroup.Group("/auth", middleware.JWTAuth(secret))
+	// 接口级权限校验，按路由挂载
+	permission := middleware.PermissionAuth()
 	{
 		adminApi := auth.NewAdminApi()
-		utils.RegisterRoute(authGroup, http.MethodGet, "/admin", adminApi.List)
-		utils.RegisterRoute(authGroup, http.MethodPost, "/admin", adminApi.Add)
-		utils.RegisterRoute(authGroup, http.MethodPut, "/admin", adminApi.Edit)
-		utils.RegisterRoute(authGroup, http.MethodDelete, "/admin", adminApi.Del)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/admin", adminApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/admin/info", adminApi.Info, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/admin", adminApi.Add, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/admin", adminApi.Edit, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/admin", adminApi.Del, permission)
+		utils.RegisterStreamRoute(authGroup, http.MethodGet, "/admin/export", adminApi.Export, permission)
+
+		// TOTP 仅作用于当前登录的管理员本人，无需接口授权
+		totpApi := auth.NewTotpApi()
+		utils.RegisterRoute(authGroup, http.MethodPost, "/admin/totp/enroll", totpApi.Enroll)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/admin/totp/activate", totpApi.Activate)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/admin/totp", totpApi.Disable)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/admin/totp/recoveryCodes", totpApi.RegenerateRecoveryCodes)
 
 		userApi := auth.NewUserApi()
-		utils.RegisterRoute(authGroup, http.MethodGet, "/user", userApi.List)
-		utils.RegisterRoute(authGroup, http.MethodGet, "/user/info", userApi.Info)
-		utils.RegisterRoute(authGroup, http.MethodPost, "/user", userApi.Add)
-		utils.RegisterRoute(authGroup, http.MethodPut, "/user", userApi.Edit)
-		utils.RegisterRoute(authGroup, http.MethodDelete, "/user", userApi.Del)
-		utils.RegisterRoute(authGroup, http.MethodPut, "/user/resetPassword", userApi.ResetPassword)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/user", userApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/user/info", userApi.Info, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/user", userApi.Add, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/user", userApi.Edit, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/user", userApi.Del, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/user/resetPassword", userApi.ResetPassword, permission)
+		// 导入文件之外为 multipart 表单的边界和其他字段预留 1MB
+		importBodyLimit := middleware.BodyLimit(conf.GlobalConf.System.Import.MaxFileSize + 1<<20)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/user/import", userApi.Import, permission, importBodyLimit)
+		utils.RegisterStreamRoute(authGroup, http.MethodGet, "/user/export", userApi.Export, permission)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/user/personalData", userApi.PersonalData, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/user/erasureToken", userApi.ErasureToken, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/user/erase", userApi.Erase, permission)
 
 		roleApi := auth.NewRoleApi()
-		utils.RegisterRoute(authGroup, http.MethodGet, "/role", roleApi.List)
-		utils.RegisterRoute(authGroup, http.MethodGet, "/role/info", roleApi.Info)
-		utils.RegisterRoute(authGroup, http.MethodPost, "/role", roleApi.Add)
-		utils.RegisterRoute(authGroup, http.MethodPut, "/role", roleApi.Edit)
-		utils.RegisterRoute(authGroup, http.MethodDelete, "/role", roleApi.Del)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/role", roleApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/role/info", roleApi.Info, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/role", roleApi.Add, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/role", roleApi.Edit, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/role", roleApi.Del, permission)
+		utils.RegisterStreamRoute(authGroup, http.MethodGet, "/role/export", roleApi.Export, permission)
+
+		utils.RegisterRoute(authGroup, http.MethodPut, "/account/unlock", authApi.Unlock, permission)
 
 		menuApi := auth.NewMenuApi()
-		utils.RegisterRoute(authGroup, http.MethodGet, "/menu/tree", menuApi.MenuTree)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/menu/tree", menuApi.MenuTree, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/menu", menuApi.Add, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/menu", menuApi.Edit, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/menu", menuApi.Del, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/menu/status", menuApi.UpdateStatus, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/menu/move", menuApi.Move, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/menu/sort", menuApi.Sort, permission)
+
+		pathApi := auth.NewPathApi(routes)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/path", pathApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/path", pathApi.Add, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/path", pathApi.Edit, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/path", pathApi.Del, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/path/sync", pathApi.Sync, permission)
+
+		auditApi := auth.NewAuditApi()
+		utils.RegisterRoute(authGroup, http.MethodGet, "/audit", auditApi.List, permission)
+
+		loginLogApi := auth.NewLoginLogApi()
+		utils.RegisterRoute(authGroup, http.MethodGet, "/loginLog", loginLogApi.List, permission)
+		utils.RegisterStreamRoute(authGroup, http.MethodGet, "/loginLog/export", loginLogApi.Export, permission)
+
+		outboxApi := auth.NewOutboxApi()
+		utils.RegisterRoute(authGroup, http.MethodGet, "/outbox", outboxApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/outbox/retry", outboxApi.Retry, permission)
+
+		webhookApi := auth.NewWebhookApi()
+		utils.RegisterRoute(authGroup, http.MethodGet, "/webhook", webhookApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodPost, "/webhook", webhookApi.Add, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/webhook", webhookApi.Edit, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/webhook", webhookApi.Del, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/webhook/rotateSecret", webhookApi.RotateSecret, permission)
+		utils.RegisterRoute(authGroup, http.MethodGet, "/webhook/delivery", webhookApi.ListDeliveries, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/webhook/redeliver", webhookApi.Redeliver, permission)
+
+		recycleBinApi := auth.NewRecycleBinApi()
+		utils.RegisterRoute(authGroup, http.MethodGet, "/recycleBin", recycleBinApi.List, permission)
+		utils.RegisterRoute(authGroup, http.MethodPut, "/recycleBin/restore", recycleBinApi.Restore, permission)
+		utils.RegisterRoute(authGroup, http.MethodDelete, "/recycleBin", recycleBinApi.Purge, permission)
 	}
 
 }
@@ -6,5 +6,6 @@ import (
 
 func LoadRouters(router *gin.Engine) {
 	v1Group := router.Group("/v1")
-	InitAuthRouter(v1Group)
+	InitAuthRouter(v1Group, router.Routes)
+	InitPolicyRouter(v1Group)
 }
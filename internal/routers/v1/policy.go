@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"ByteScience-WAM-Admin/internal/api/auth"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitPolicyRouter 注册供下游业务服务调用的权限决策接口，使用服务令牌鉴权
+func InitPolicyRouter(routerGroup *gin.RouterGroup) {
+	policyGroup := routerGroup.Group("/policy", middleware.ServiceAuth())
+	{
+		policyApi := auth.NewPolicyApi()
+		utils.RegisterRoute(policyGroup, http.MethodPost, "/decision", policyApi.Decide)
+		utils.RegisterRoute(policyGroup, http.MethodPost, "/decision/batch", policyApi.BatchDecide)
+	}
+}
@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"ByteScience-WAM-Admin/internal/event"
 	"ByteScience-WAM-Admin/internal/routers"
+	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/task"
 	"ByteScience-WAM-Admin/middleware"
 	"ByteScience-WAM-Admin/pkg/db"
 	"ByteScience-WAM-Admin/pkg/logger"
@@ -22,6 +25,7 @@ func ServerStart(eng *gin.Engine, mode string) {
 	// 加载配置文件并设置全局配置常量
 	conf.LoadConf(mode)
 	eng.Use(gin.Recovery())
+	eng.Use(middleware.RequestID())
 
 	// 配置跨域
 	if conf.GlobalConf.System.Security.Cors.Enabled {
@@ -42,6 +46,40 @@ func ServerStart(eng *gin.Engine, mode string) {
 	db.MysqlInit()        // 初始化MySQL连接（日志也是在这里初始化）
 	redis.RedisInit()     // 初始化Redis连接
 
+	// 执行待执行的数据库迁移
+	if conf.GlobalConf.Mysql.AutoMigrate {
+		if err = migrateUp(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+	}
+
+	// 确保内置超级管理员角色存在
+	if err = service.NewRoleService().EnsureSuperAdminRole(context.Background()); err != nil {
+		log.Fatalf("Failed to ensure built-in super admin role: %v", err)
+	}
+
+	// 创建权限变更事件的消费组
+	if err = event.EnsureConsumerGroups(context.Background()); err != nil {
+		log.Fatalf("Failed to create event consumer groups: %v", err)
+	}
+
+	// 预热用户权限缓存，不阻塞服务启动
+	if conf.GlobalConf.System.Permission.WarmOnStart {
+		go func() {
+			count, err := service.NewPermissionService().WarmCache(context.Background())
+			if err != nil {
+				logger.Logger.Errorf("Failed to warm permission cache: %v", err)
+				return
+			}
+			logger.Logger.Infof("Permission cache warmed for %d users", count)
+		}()
+	}
+
+	// 启动后台定时任务
+	taskCtx, stopTasks := context.WithCancel(context.Background())
+	scheduler := task.NewScheduler(task.Tasks()...)
+	scheduler.Start(taskCtx)
+
 	server := &http.Server{
 		Addr:         ":" + conf.GlobalConf.System.Addr,
 		Handler:      eng,
@@ -72,6 +110,10 @@ func ServerStart(eng *gin.Engine, mode string) {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Logger.Info("Server Shutdown:", err)
 	}
+
+	// 停止后台定时任务
+	stopTasks()
+	scheduler.Wait()
 }
 
 // ServerExit 服务退出
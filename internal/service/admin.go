@@ -5,26 +5,46 @@ import (
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
 	"ByteScience-WAM-Admin/pkg/logger"
 	"context"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AdminService struct {
-	dao *dao.AdminDao // 添加 AdminDao 作为成员
+	dao             *dao.AdminDao // 添加 AdminDao 作为成员
+	roleDao         *dao.RoleDao
+	adminRoleDao    *dao.AdminRoleDao
+	auditTrail      auditTrail
+	loginRecorder   loginRecorder
+	permissionCache permissionCache
 }
 
 // NewAdminService 创建一个新的 AdminService 实例
 func NewAdminService() *AdminService {
 	return &AdminService{
-		dao: dao.NewAdminDao(),
+		dao:             dao.NewAdminDao(),
+		roleDao:         dao.NewRoleDao(),
+		adminRoleDao:    dao.NewAdminRoleDao(),
+		auditTrail:      newAuditTrail(),
+		loginRecorder:   newLoginRecorder(),
+		permissionCache: newPermissionCache(),
 	}
 }
 
 // Add 添加管理员
 func (as *AdminService) Add(ctx context.Context, req *auth.AddAdminRequest) error {
+	// 校验密码强度
+	if err := utils.ValidatePassword(req.Password, req.UserName, req.Email, req.Phone); err != nil {
+		return err
+	}
+
 	// 密码加密
 	hashedPassword, err := utils.EncryptPassword(req.Password)
 	if err != nil {
@@ -56,6 +76,12 @@ func (as *AdminService) Add(ctx context.Context, req *auth.AddAdminRequest) erro
 		}
 	}
 
+	// 检查角色是否存在
+	roleIDs, err := as.checkRoles(ctx, req.RoleIDList)
+	if err != nil {
+		return err
+	}
+
 	// 构建管理员实体
 	admin := &entity.Admins{
 		ID:        uuid.New().String(),
@@ -67,10 +93,22 @@ func (as *AdminService) Add(ctx context.Context, req *auth.AddAdminRequest) erro
 		Password:  hashedPassword,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+
+		PasswordChangedAt: time.Now(),
 	}
 
-	// 调用 DAO 层插入数据
-	if err = as.dao.Insert(ctx, admin); err != nil {
+	// 插入数据、分配角色并记录审计日志
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := as.dao.InsertTx(ctx, tx, admin); err != nil {
+			return err
+		}
+		if err := as.adminRoleDao.InsertBatchTx(ctx, tx, buildAdminRoles(admin.ID, roleIDs)); err != nil {
+			return err
+		}
+		after := &adminAuditSnapshot{Admins: admin, RoleIDs: roleIDs}
+		return as.auditTrail.recordTx(ctx, tx, AuditActionCreate, AuditTargetAdmin, admin.ID, nil, after)
+	})
+	if err != nil {
 		// 记录插入数据库的错误
 		logger.Logger.Errorf("[AddAdmin] Error inserting admin into DB: %v", err)
 		return utils.NewBusinessError(utils.AdminInsertFailedCode) // 新增错误码 AdminInsertFailedCode
@@ -124,13 +162,56 @@ func (as *AdminService) Edit(ctx context.Context, req *auth.EditAdminRequest) er
 		entity.AdminsColumns.UpdatedAt: time.Now(),
 	}
 
-	// 调用 DAO 层更新数据
-	if err = as.dao.Update(ctx, req.ID, updates); err != nil {
+	// 变更前后的快照，用于审计；仅在调整角色时记录角色变化
+	before := &adminAuditSnapshot{Admins: admin}
+	updated := *admin
+	updated.Username = req.UserName
+	updated.Email = req.Email
+	updated.Phone = req.Phone
+	updated.Nickname = req.Nickname
+	updated.Remark = req.Remark
+	after := &adminAuditSnapshot{Admins: &updated}
+	if req.RoleIDList != nil {
+		if after.RoleIDs, err = as.checkRoles(ctx, req.RoleIDList); err != nil {
+			return err
+		}
+		if before.RoleIDs, err = as.getRoleIDs(ctx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditAdmin] Error fetching admin roles: %v", err)
+			return utils.NewBusinessError(utils.AdminUpdateFailedCode)
+		}
+	}
+
+	// 更新数据、调整角色并记录审计日志
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := as.dao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+			return err
+		}
+		if req.RoleIDList != nil {
+			if err := as.checkSuperAdminRemainsTx(ctx, tx, req.ID, before.RoleIDs, after.RoleIDs); err != nil {
+				return err
+			}
+			if err := as.adminRoleDao.RemoveByAdminIDTx(ctx, tx, req.ID); err != nil {
+				return err
+			}
+			if err := as.adminRoleDao.InsertBatchTx(ctx, tx, buildAdminRoles(req.ID, after.RoleIDs)); err != nil {
+				return err
+			}
+		}
+		return as.auditTrail.recordTx(ctx, tx, AuditActionUpdate, AuditTargetAdmin, admin.ID, before, after)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
 		// 记录更新管理员信息时的错误
 		logger.Logger.Errorf("[EditAdmin] Error updating admin info in DB: %v", err)
 		return utils.NewBusinessError(utils.AdminUpdateFailedCode)
 	}
 
+	if req.RoleIDList != nil {
+		as.permissionCache.invalidateAdmins(ctx, req.ID)
+	}
+
 	return nil
 }
 
@@ -146,25 +227,96 @@ func (as *AdminService) Delete(ctx context.Context, req *auth.DelAdminRequest) e
 		return utils.NewBusinessError(utils.AdminNotFoundCode)
 	}
 
-	// 调用 DAO 层进行软删除
-	if err = as.dao.SoftDeleteByID(ctx, req.ID); err != nil {
+	// 删除前的快照，用于审计
+	roleIDs, err := as.getRoleIDs(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[DeleteAdmin] Error fetching admin roles: %v", err)
+		return utils.NewBusinessError(utils.AdminDeleteFailedCode)
+	}
+	before := &adminAuditSnapshot{Admins: admin, RoleIDs: roleIDs}
+
+	// 软删除、移除角色并记录审计日志
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := as.checkSuperAdminRemainsTx(ctx, tx, req.ID, roleIDs, nil); err != nil {
+			return err
+		}
+		if err := as.dao.SoftDeleteByIDTx(ctx, tx, req.ID); err != nil {
+			return err
+		}
+		if err := as.adminRoleDao.RemoveByAdminIDTx(ctx, tx, req.ID); err != nil {
+			return err
+		}
+		return as.auditTrail.recordTx(ctx, tx, AuditActionDelete, AuditTargetAdmin, admin.ID, before, nil)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
 		// 记录软删除操作错误
 		logger.Logger.Errorf("[DeleteAdmin] Error soft deleting admin: %v", err)
 		return utils.NewBusinessError(utils.AdminDeleteFailedCode)
 	}
 
+	as.permissionCache.invalidateAdmins(ctx, req.ID)
+
 	return nil
 }
 
+// Info 获取管理员详细信息
+func (as *AdminService) Info(ctx context.Context, req *auth.InfoAdminRequest) (*auth.InfoAdminResponse, error) {
+	admin, err := as.dao.GetByID(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[InfoAdmin] Error fetching admin by ID: %v", err)
+		return nil, utils.NewBusinessError(utils.AdminQueryListFailedCode)
+	}
+	if admin == nil {
+		return nil, utils.NewBusinessError(utils.AdminNotFoundCode)
+	}
+
+	// 查询管理员的角色
+	roles, err := as.adminRoleDao.GetRolesByAdminID(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[InfoAdmin] Error retrieving admin roles: %v", err)
+		return nil, utils.NewBusinessError(utils.AdminQueryListFailedCode)
+	}
+	roleList := make([]auth.TrimRoleInfo, 0, len(roles))
+	for _, role := range roles {
+		roleList = append(roleList, auth.TrimRoleInfo{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+		})
+	}
+
+	// 查询最近的登录记录
+	recentLogins, err := as.loginRecorder.recent(ctx, utils.SubjectTypeAdmin, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[InfoAdmin] Error retrieving recent logins: %v", err)
+		return nil, utils.NewBusinessError(utils.AdminQueryListFailedCode)
+	}
+
+	return &auth.InfoAdminResponse{
+		AdminInfo: auth.AdminInfo{
+			ID:          admin.ID,
+			UserName:    admin.Username,
+			Nickname:    admin.Nickname,
+			Email:       admin.Email,
+			Phone:       admin.Phone,
+			Remark:      admin.Remark,
+			LastLoginAt: admin.LastLoginAt.Format(time.RFC3339),
+			TotpEnabled: admin.TotpEnabled == 1,
+			CreatedAt:   admin.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:   admin.UpdatedAt.Format(time.RFC3339),
+		},
+		RoleList:     roleList,
+		RecentLogins: recentLogins,
+	}, nil
+}
+
 // GetList 获取管理员列表（分页）
 func (as *AdminService) GetList(ctx context.Context, req *auth.ListAdminRequest) (*auth.ListAdminResponse, error) {
 	// 构建过滤条件
-	filters := map[string]interface{}{
-		entity.AdminsColumns.ID:       req.ID,
-		entity.AdminsColumns.Username: req.UserName,
-		entity.AdminsColumns.Email:    req.Email,
-		entity.AdminsColumns.Phone:    req.Phone,
-	}
+	filters := buildAdminFilters(&req.AdminFilter)
 
 	// 查询数据
 	admins, total, err := as.dao.Query(ctx, req.Page, req.PageSize, filters)
@@ -185,6 +337,7 @@ func (as *AdminService) GetList(ctx context.Context, req *auth.ListAdminRequest)
 			Phone:       admin.Phone,
 			Remark:      admin.Remark,
 			LastLoginAt: admin.LastLoginAt.Format(time.RFC3339),
+			TotpEnabled: admin.TotpEnabled == 1,
 			CreatedAt:   admin.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:   admin.UpdatedAt.Format(time.RFC3339),
 		})
@@ -196,6 +349,75 @@ func (as *AdminService) GetList(ctx context.Context, req *auth.ListAdminRequest)
 	}, nil
 }
 
+// buildAdminFilters 将请求中的筛选条件转换为 DAO 查询条件
+func buildAdminFilters(req *auth.AdminFilter) map[string]interface{} {
+	return map[string]interface{}{
+		entity.AdminsColumns.ID:       req.ID,
+		entity.AdminsColumns.Username: req.UserName,
+		entity.AdminsColumns.Email:    req.Email,
+		entity.AdminsColumns.Phone:    req.Phone,
+	}
+}
+
+// adminExportRecord 导出的管理员及其角色名称
+type adminExportRecord struct {
+	*entity.Admins
+	roleNames []string
+}
+
+// adminExportColumns 管理员导出的全部列
+var adminExportColumns = []exportColumn[*adminExportRecord]{
+	{"id", func(r *adminExportRecord) string { return r.ID }},
+	{"userName", func(r *adminExportRecord) string { return r.Username }},
+	{"nickname", func(r *adminExportRecord) string { return r.Nickname }},
+	{"email", func(r *adminExportRecord) string { return r.Email }},
+	{"phone", func(r *adminExportRecord) string { return r.Phone }},
+	{"remark", func(r *adminExportRecord) string { return r.Remark }},
+	{"roles", func(r *adminExportRecord) string { return strings.Join(r.roleNames, exportSeparator) }},
+	{"totpEnabled", func(r *adminExportRecord) string { return strconv.FormatBool(r.TotpEnabled == 1) }},
+	{"lastLoginAt", func(r *adminExportRecord) string { return formatExportTime(r.LastLoginAt) }},
+	{"createdAt", func(r *adminExportRecord) string { return formatExportTime(r.CreatedAt) }},
+	{"updatedAt", func(r *adminExportRecord) string { return formatExportTime(r.UpdatedAt) }},
+}
+
+// Export 按筛选条件导出管理员为 CSV 或 XLSX 文件，按创建时间倒序
+// 注意: 数据分批读取并直接写入 w，开始写入后发生的错误无法再以 JSON 形式返回给调用方。
+func (as *AdminService) Export(ctx context.Context, req *auth.ExportAdminRequest, w io.Writer) error {
+	columns, err := selectExportColumns(adminExportColumns, req.Columns)
+	if err != nil {
+		return err
+	}
+
+	filters := buildAdminFilters(&req.AdminFilter)
+	err = writeExport(w, req.Format, columns, func(write func(records []*adminExportRecord) error) error {
+		return as.dao.FindInBatches(ctx, filters, exportBatchSize, func(admins []*entity.Admins) error {
+			adminIDs := make([]string, 0, len(admins))
+			for _, admin := range admins {
+				adminIDs = append(adminIDs, admin.ID)
+			}
+			adminRoleNames, err := as.adminRoleDao.GetRoleNamesByAdminIDs(ctx, adminIDs)
+			if err != nil {
+				return err
+			}
+			roleNames := make(map[string][]string, len(admins))
+			for _, adminRoleName := range adminRoleNames {
+				roleNames[adminRoleName.AdminID] = append(roleNames[adminRoleName.AdminID], adminRoleName.RoleName)
+			}
+
+			records := make([]*adminExportRecord, 0, len(admins))
+			for _, admin := range admins {
+				records = append(records, &adminExportRecord{Admins: admin, roleNames: roleNames[admin.ID]})
+			}
+			return write(records)
+		})
+	})
+	if err != nil {
+		logger.Logger.Errorf("[ExportAdmin] Error exporting admins: %v", err)
+		return utils.NewBusinessError(utils.AdminExportFailedCode)
+	}
+	return nil
+}
+
 // UpdateLastLoginTime 更新管理员的最后登录时间
 func (as *AdminService) UpdateLastLoginTime(ctx context.Context, id string) error {
 	// 确保管理员存在
@@ -210,3 +432,71 @@ func (as *AdminService) UpdateLastLoginTime(ctx context.Context, id string) erro
 	// 调用 DAO 层更新最后登录时间
 	return as.dao.UpdateLastLoginTime(ctx, id)
 }
+
+// checkRoles 检查角色是否全部存在，返回去重并排序后的角色ID
+func (as *AdminService) checkRoles(ctx context.Context, roleIDs []string) ([]string, error) {
+	uniqueRoleIDs := uniqueIDs(roleIDs)
+	if len(uniqueRoleIDs) == 0 {
+		return uniqueRoleIDs, nil
+	}
+
+	roles, err := as.roleDao.GetByIDs(ctx, uniqueRoleIDs)
+	if err != nil {
+		logger.Logger.Errorf("[CheckRoles] Error fetching roles: %v", err)
+		return nil, utils.NewBusinessError(utils.RoleQueryListFailedCode)
+	}
+	if len(roles) != len(uniqueRoleIDs) {
+		return nil, utils.NewBusinessError(utils.RoleNotFoundCode)
+	}
+	return sortedIDs(uniqueRoleIDs), nil
+}
+
+// getRoleIDs 获取管理员当前的角色ID
+func (as *AdminService) getRoleIDs(ctx context.Context, adminID string) ([]string, error) {
+	roles, err := as.adminRoleDao.GetRolesByAdminID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	return sortedIDs(roleIDs), nil
+}
+
+// checkSuperAdminRemainsTx 在事务中检查管理员移出内置超级管理员角色后，该角色仍至少保留一名管理员
+// 参数:
+//   - before、after: 管理员调整前后的角色ID，删除管理员时 after 为 nil
+func (as *AdminService) checkSuperAdminRemainsTx(ctx context.Context, tx *gorm.DB, adminID string,
+	before, after []string) error {
+	builtinRole, err := as.roleDao.GetBuiltinTx(ctx, tx)
+	if err != nil || builtinRole == nil {
+		return err
+	}
+	if !utils.Contains(before, builtinRole.ID) || utils.Contains(after, builtinRole.ID) {
+		return nil
+	}
+
+	count, err := as.adminRoleDao.CountAdminsByRoleIDTx(ctx, tx, builtinRole.ID, adminID)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		logger.Logger.Infof("[CheckSuperAdminRemains] Admin %s is the last member of built-in role", adminID)
+		return utils.NewBusinessError(utils.RoleBuiltinLastAdminCode)
+	}
+	return nil
+}
+
+// buildAdminRoles 构建管理员角色关联
+func buildAdminRoles(adminID string, roleIDs []string) []*entity.AdminRoles {
+	adminRoles := make([]*entity.AdminRoles, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		adminRoles = append(adminRoles, &entity.AdminRoles{
+			AdminID: adminID,
+			RoleID:  roleID,
+		})
+	}
+	return adminRoles
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 审计操作类型
+const (
+	AuditActionCreate        = "create"        // 新增
+	AuditActionUpdate        = "update"        // 编辑
+	AuditActionDelete        = "delete"        // 删除
+	AuditActionResetPassword = "resetPassword" // 重置密码
+	AuditActionRestore       = "restore"       // 从回收站恢复
+	AuditActionPurge         = "purge"         // 从回收站永久删除
+	AuditActionErase         = "erase"         // 擦除个人数据
+	AuditActionRotateSecret  = "rotateSecret"  // 更换签名密钥
+)
+
+// 审计对象类型
+const (
+	AuditTargetAdmin   = "admin"   // 管理员
+	AuditTargetUser    = "user"    // 业务用户
+	AuditTargetRole    = "role"    // 角色
+	AuditTargetMenu    = "menu"    // 菜单
+	AuditTargetPath    = "path"    // 接口
+	AuditTargetWebhook = "webhook" // Webhook 订阅
+)
+
+// auditIgnoredFields 不写入审计日志的字段：敏感信息，以及每次变更都会变化、没有审计意义的字段
+var auditIgnoredFields = []string{"password", "totpSecret", "updatedAt", "deletedAt", "lastLoginAt"}
+
+// adminAuditSnapshot 管理员审计快照，附带管理员的角色ID
+type adminAuditSnapshot struct {
+	*entity.Admins
+	RoleIDs []string `json:"roleIds,omitempty"`
+}
+
+// userAuditSnapshot 用户审计快照，附带用户的角色ID及带有效期的角色分配
+type userAuditSnapshot struct {
+	*entity.Users
+	RoleIDs         []string                 `json:"roleIds,omitempty"`
+	RoleAssignments []roleAssignmentSnapshot `json:"roleAssignments,omitempty"`
+}
+
+// roleAssignmentSnapshot 带有效期的角色分配审计快照
+type roleAssignmentSnapshot struct {
+	RoleID     string     `json:"roleId"`
+	ValidFrom  *time.Time `json:"validFrom,omitempty"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+}
+
+// roleAuditSnapshot 角色审计快照，附带角色的路径ID和父角色ID
+type roleAuditSnapshot struct {
+	*entity.Roles
+	PathIDs   []string `json:"pathIds,omitempty"`
+	ParentIDs []string `json:"parentIds,omitempty"`
+}
+
+// auditTrail 审计日志记录，必须在业务变更所在的事务中调用
+type auditTrail struct {
+	dao *dao.AuditLogDao
+}
+
+// newAuditTrail 创建 auditTrail 实例
+func newAuditTrail() auditTrail {
+	return auditTrail{dao: dao.NewAuditLogDao()}
+}
+
+// recordTx 在事务中写入一条审计日志
+// 参数:
+//   - before、after: 变更前后的对象快照（实体或 map），新增时 before 为 nil，删除时 after 为 nil；只记录发生变化的字段
+//
+// 注意: 操作人、客户端IP、User-Agent 和请求ID 从请求上下文中读取。
+func (at auditTrail) recordTx(ctx context.Context, tx *gorm.DB, action, targetType, targetID string,
+	before, after interface{}) error {
+	oldValues, newValues, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	actorID, actorType := utils.GetActor(ctx)
+	auditLog := &entity.AuditLogs{
+		ID:         uuid.New().String(),
+		ActorID:    actorID,
+		ActorType:  actorType,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		OldValues:  oldValues,
+		NewValues:  newValues,
+		ClientIP:   utils.GetClientIP(ctx),
+		UserAgent:  truncate(utils.GetUserAgent(ctx), 512),
+		RequestID:  utils.GetRequestID(ctx),
+		CreatedAt:  time.Now(),
+	}
+	return at.dao.InsertTx(ctx, tx, auditLog)
+}
+
+// auditDiff 比较变更前后的快照，返回发生变化的字段（JSON）
+func auditDiff(before, after interface{}) (string, string, error) {
+	beforeMap, err := auditSnapshot(before)
+	if err != nil {
+		return "", "", err
+	}
+	afterMap, err := auditSnapshot(after)
+	if err != nil {
+		return "", "", err
+	}
+
+	oldValues := make(map[string]interface{})
+	newValues := make(map[string]interface{})
+	for key, value := range beforeMap {
+		if afterValue, ok := afterMap[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			oldValues[key] = value
+		}
+	}
+	for key, value := range afterMap {
+		if beforeValue, ok := beforeMap[key]; !ok || !reflect.DeepEqual(value, beforeValue) {
+			newValues[key] = value
+		}
+	}
+
+	oldJSON, err := json.Marshal(oldValues)
+	if err != nil {
+		return "", "", err
+	}
+	newJSON, err := json.Marshal(newValues)
+	if err != nil {
+		return "", "", err
+	}
+	return string(oldJSON), string(newJSON), nil
+}
+
+// auditSnapshot 将对象按 JSON 字段名转换为 map，并剔除不需要审计的字段
+func auditSnapshot(value interface{}) (map[string]interface{}, error) {
+	snapshot := make(map[string]interface{})
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return snapshot, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	for _, field := range auditIgnoredFields {
+		delete(snapshot, field)
+	}
+	return snapshot, nil
+}
+
+// sortedIDs 返回排序后的ID副本，避免仅顺序不同的关联被记录为变更
+func sortedIDs(ids []string) []string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// uniqueIDs 返回去重后的ID，保持原有顺序
+func uniqueIDs(ids []string) []string {
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !utils.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// truncate 按字符数截断字符串
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+type AuditService struct {
+	auditLogDao *dao.AuditLogDao
+}
+
+// NewAuditService 创建一个新的 AuditService 实例
+func NewAuditService() *AuditService {
+	return &AuditService{
+		auditLogDao: dao.NewAuditLogDao(),
+	}
+}
+
+// List 获取审计日志列表（分页）
+func (as *AuditService) List(ctx context.Context, req *auth.ListAuditLogRequest) (*auth.ListAuditLogResponse, error) {
+	// 构建过滤条件
+	filters := map[string]interface{}{
+		entity.AuditLogsColumns.ActorID:    req.ActorID,
+		entity.AuditLogsColumns.ActorType:  req.ActorType,
+		entity.AuditLogsColumns.Action:     req.Action,
+		entity.AuditLogsColumns.TargetType: req.TargetType,
+		entity.AuditLogsColumns.TargetID:   req.TargetID,
+		entity.AuditLogsColumns.RequestID:  req.RequestID,
+	}
+
+	// 时间格式已由校验器保证
+	var startTime, endTime time.Time
+	if req.StartTime != "" {
+		startTime, _ = time.Parse(time.RFC3339, req.StartTime)
+	}
+	if req.EndTime != "" {
+		endTime, _ = time.Parse(time.RFC3339, req.EndTime)
+	}
+
+	auditLogs, total, err := as.auditLogDao.Query(ctx, req.Page, req.PageSize, filters, startTime, endTime)
+	if err != nil {
+		logger.Logger.Errorf("[GetAuditLogList] Error fetching audit logs: %v", err)
+		return nil, utils.NewBusinessError(utils.AuditLogQueryListFailedCode)
+	}
+
+	// 转换数据格式为响应模型
+	auditLogList := make([]auth.AuditLogInfo, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		auditLogList = append(auditLogList, auditLogInfo(auditLog))
+	}
+
+	return &auth.ListAuditLogResponse{
+		Total: total,
+		List:  auditLogList,
+	}, nil
+}
+
+// auditLogInfo 将审计日志转换为响应模型
+func auditLogInfo(auditLog *entity.AuditLogs) auth.AuditLogInfo {
+	return auth.AuditLogInfo{
+		ID:         auditLog.ID,
+		ActorID:    auditLog.ActorID,
+		ActorType:  auditLog.ActorType,
+		Action:     auditLog.Action,
+		TargetType: auditLog.TargetType,
+		TargetID:   auditLog.TargetID,
+		OldValues:  rawJSON(auditLog.OldValues),
+		NewValues:  rawJSON(auditLog.NewValues),
+		ClientIP:   auditLog.ClientIP,
+		UserAgent:  auditLog.UserAgent,
+		RequestID:  auditLog.RequestID,
+		CreatedAt:  auditLog.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// rawJSON 将数据库中的 JSON 字符串原样输出，空值输出为 null
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(value)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"testing"
+	"time"
+)
+
+func TestAuditDiff(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		before  interface{}
+		after   interface{}
+		wantOld string
+		wantNew string
+	}{
+		{
+			name:    "create",
+			before:  nil,
+			after:   map[string]interface{}{"name": "editor", "status": 1},
+			wantOld: `{}`,
+			wantNew: `{"name":"editor","status":1}`,
+		},
+		{
+			name:    "delete",
+			before:  map[string]interface{}{"name": "editor"},
+			after:   nil,
+			wantOld: `{"name":"editor"}`,
+			wantNew: `{}`,
+		},
+		{
+			name:    "only changed fields",
+			before:  map[string]interface{}{"name": "editor", "status": 1},
+			after:   map[string]interface{}{"name": "editor", "status": 0},
+			wantOld: `{"status":1}`,
+			wantNew: `{"status":0}`,
+		},
+		{
+			name:    "added and removed keys",
+			before:  map[string]interface{}{"a": 1},
+			after:   map[string]interface{}{"b": 2},
+			wantOld: `{"a":1}`,
+			wantNew: `{"b":2}`,
+		},
+		{
+			name:    "slices compared by value",
+			before:  map[string]interface{}{"roleIds": []string{"r1", "r2"}},
+			after:   map[string]interface{}{"roleIds": []string{"r1", "r2"}},
+			wantOld: `{}`,
+			wantNew: `{}`,
+		},
+		{
+			name:    "typed nil pointer",
+			before:  (*entity.Users)(nil),
+			after:   (*entity.Users)(nil),
+			wantOld: `{}`,
+			wantNew: `{}`,
+		},
+		{
+			name:    "webhook secret omitted",
+			before:  &entity.Webhooks{ID: "w1", Name: "crm-sync", Secret: "old-secret-value"},
+			after:   &entity.Webhooks{ID: "w1", Name: "crm-sync", Secret: "new-secret-value"},
+			wantOld: `{}`,
+			wantNew: `{}`,
+		},
+		{
+			name:    "ignored fields",
+			before:  &entity.Users{ID: "u1", Username: "john", Password: "old", UpdatedAt: now, LastLoginAt: now},
+			after:   &entity.Users{ID: "u1", Username: "john", Password: "new", UpdatedAt: now.Add(time.Hour)},
+			wantOld: `{}`,
+			wantNew: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOld, gotNew, err := auditDiff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("auditDiff() error = %v", err)
+			}
+			if gotOld != tt.wantOld || gotNew != tt.wantNew {
+				t.Errorf("auditDiff() = %s, %s, want %s, %s", gotOld, gotNew, tt.wantOld, tt.wantNew)
+			}
+		})
+	}
+}
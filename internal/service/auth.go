@@ -6,46 +6,68 @@ import (
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
 	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
 	"context"
 	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AuthService struct {
 	adminDao *dao.AdminDao // 添加 AdminDao 作为成员
+	userDao  *dao.UserDao
+	limiter  loginLimiter
+
+	totpService     *TotpService
+	passwordHistory passwordHistory
+	loginRecorder   loginRecorder
 }
 
 // NewAuthService 创建一个新的 AuthService 实例
 func NewAuthService() *AuthService {
 	return &AuthService{
 		adminDao: dao.NewAdminDao(),
+		userDao:  dao.NewUserDao(),
+
+		totpService:     NewTotpService(),
+		passwordHistory: newPasswordHistory(),
+		loginRecorder:   newLoginRecorder(),
 	}
 }
 
 // Login 登录方法
-func (as AuthService) Login(ctx context.Context, req *auth.LoginRequest) (*auth.LoginResponse, error) {
-	// 确定 Identifier 类型（用户名、邮箱或手机号）
-	identifierType := utils.IdentifyType(req.Identifier)
-	var admin *entity.Admins
-	var err error
+func (as AuthService) Login(ctx context.Context, req *auth.LoginRequest) (res *auth.LoginResponse, err error) {
+	// 记录登录日志，包含失败的尝试
+	var adminId string
+	defer func() {
+		event := LoginEventLogin
+		if res != nil && res.MfaRequired {
+			event = LoginEventMfaChallenge
+		}
+		as.loginRecorder.record(ctx, utils.SubjectTypeAdmin, event, req.Identifier, adminId, err)
+	}()
 
-	switch identifierType {
-	case "email":
-		admin, err = as.adminDao.GetByFields(ctx, "", req.Identifier, "")
-	case "phone":
-		admin, err = as.adminDao.GetByFields(ctx, "", "", req.Identifier)
-	default:
-		admin, err = as.adminDao.GetByFields(ctx, req.Identifier, "", "")
+	// 校验账号标识与客户端IP是否已被锁定
+	clientIP := utils.GetClientIP(ctx)
+	if err := as.limiter.check(ctx, utils.SubjectTypeAdmin, req.Identifier, clientIP); err != nil {
+		return nil, err
 	}
 
-	// 检查用户是否存在
+	admin, err := as.findAdminByIdentifier(ctx, req.Identifier)
 	if err != nil {
-		logger.Logger.Errorf("[Login] Error fetching user by %s: %v", identifierType, err)
+		logger.Logger.Errorf("[Login] Error fetching admin by identifier: %v", err)
 		return nil, utils.NewBusinessError(utils.InternalError)
 	}
+
+	// 账号不存在与密码错误返回相同的错误，避免泄露账号是否存在
 	if admin == nil {
-		return nil, utils.NewBusinessError(utils.UserNotFoundCode)
+		utils.VerifyDummyPassword(req.Password)
+		return nil, as.loginFailed(ctx, utils.SubjectTypeAdmin, req.Identifier, clientIP)
 	}
+	adminId = admin.ID
 
 	// 验证密码是否正确
 	isMatch, err := utils.VerifyPassword(req.Password, admin.Password)
@@ -55,14 +77,89 @@ func (as AuthService) Login(ctx context.Context, req *auth.LoginRequest) (*auth.
 		return nil, utils.NewBusinessError(utils.InternalError)
 	}
 	if !isMatch {
-		// 如果密码不匹配，返回无效凭证错误
-		return nil, utils.NewBusinessError(utils.PasswordIncorrectCode)
+		return nil, as.loginFailed(ctx, utils.SubjectTypeAdmin, req.Identifier, clientIP)
+	}
+	as.limiter.succeed(ctx, utils.SubjectTypeAdmin, req.Identifier)
+
+	// 已启用 TOTP 的管理员需完成二次验证，此时只返回短时有效的挑战令牌
+	if admin.TotpEnabled == 1 {
+		jwtConf := conf.GlobalConf.Jwt
+		mfaToken, _, err := utils.GetToken(jwtConf.AccessSecret, jwtConf.MfaExpire, utils.TokenClaims{
+			UserId:      admin.ID,
+			SubjectType: utils.SubjectTypeAdmin,
+			Type:        utils.MfaTokenType,
+		})
+		if err != nil {
+			logger.Logger.Errorf("[Login] Error signing mfa token: %v", err)
+			return nil, utils.NewBusinessError(utils.InternalError)
+		}
+		return &auth.LoginResponse{MfaRequired: true, MfaToken: mfaToken}, nil
+	}
+
+	return as.completeAdminLogin(ctx, admin)
+}
+
+// LoginTotp 登录第二步：使用挑战令牌与 TOTP 验证码（或恢复码）换取正式令牌
+func (as AuthService) LoginTotp(ctx context.Context, req *auth.TotpLoginRequest) (res *auth.LoginResponse, err error) {
+	// 记录登录日志，挑战令牌无效时账号ID为空
+	var identifier, adminId string
+	defer func() {
+		as.loginRecorder.record(ctx, utils.SubjectTypeAdmin, LoginEventTotp, identifier, adminId, err)
+	}()
+
+	claims, err := utils.ParseToken(conf.GlobalConf.Jwt.AccessSecret, req.MfaToken)
+	if err != nil {
+		return nil, utils.NewBusinessError(utils.MfaChallengeInvalidCode)
+	}
+
+	jti := utils.ClaimString(claims, "jti")
+	adminId = utils.ClaimString(claims, "userId")
+	if utils.ClaimString(claims, "typ") != utils.MfaTokenType || jti == "" ||
+		utils.ClaimString(claims, "subjectType") != utils.SubjectTypeAdmin {
+		return nil, utils.NewBusinessError(utils.MfaChallengeInvalidCode)
+	}
+
+	admin, err := as.adminDao.GetByID(ctx, adminId)
+	if err != nil {
+		logger.Logger.Errorf("[LoginTotp] Error fetching admin by ID: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+	// 挑战令牌签发后账号被删除或已停用 TOTP，需重新登录
+	if admin == nil || admin.TotpEnabled != 1 {
+		return nil, utils.NewBusinessError(utils.MfaChallengeInvalidCode)
+	}
+	identifier = admin.Username
+
+	// 挑战令牌只能使用一次，须在校验验证码之前作废，避免重放的挑战令牌消耗一次性的验证码或恢复码；
+	// 验证码错误时需重新登录获取新的挑战令牌
+	first, err := redis.ConsumeMfaChallenge(ctx, jti, time.Until(utils.ClaimExpireAt(claims)))
+	if err != nil {
+		logger.Logger.Errorf("[LoginTotp] Error consuming mfa challenge %s: %v", jti, err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+	if !first {
+		return nil, utils.NewBusinessError(utils.MfaChallengeInvalidCode)
+	}
+
+	if err = as.totpService.CheckCode(ctx, admin, req.Code); err != nil {
+		return nil, err
+	}
+
+	return as.completeAdminLogin(ctx, admin)
+}
+
+// completeAdminLogin 管理员通过全部验证后签发令牌并记录登录时间
+func (as AuthService) completeAdminLogin(ctx context.Context, admin *entity.Admins) (*auth.LoginResponse, error) {
+	// 密码被重置或已过期时只签发受限令牌，仅允许修改密码
+	var scope string
+	if passwordChangeRequired(admin.MustChangePassword, admin.PasswordChangedAt, admin.CreatedAt) {
+		scope = utils.PasswordChangeScope
 	}
 
-	// 生成 JWT Token
-	token, err := utils.GetToken(conf.GlobalConf.Jwt.AccessSecret, conf.GlobalConf.Jwt.AccessExpire, admin.ID)
+	// 签发访问令牌与刷新令牌
+	loginResponse, err := as.issueTokens(ctx, utils.SubjectTypeAdmin, admin.ID, uuid.New().String(), scope)
 	if err != nil {
-		logger.Logger.Errorf("[Login] Error utils.GetToken: %v", err)
+		logger.Logger.Errorf("[Login] Error issuing tokens: %v", err)
 		return nil, utils.NewBusinessError(utils.InternalError)
 	}
 
@@ -71,16 +168,257 @@ func (as AuthService) Login(ctx context.Context, req *auth.LoginRequest) (*auth.
 		logger.Logger.Errorf("[Login] Error UpdateLastLoginTime: %v", err)
 	}
 
-	// 返回登录响应
-	loginResponse := &auth.LoginResponse{
-		Token: token,
+	return loginResponse, nil
+}
+
+// UserLogin 业务用户登录方法
+func (as AuthService) UserLogin(ctx context.Context, req *auth.LoginRequest) (res *auth.LoginResponse, err error) {
+	// 记录登录日志，包含失败的尝试
+	var userId string
+	defer func() {
+		as.loginRecorder.record(ctx, utils.SubjectTypeUser, LoginEventLogin, req.Identifier, userId, err)
+	}()
+
+	// 校验账号标识与客户端IP是否已被锁定
+	clientIP := utils.GetClientIP(ctx)
+	if err := as.limiter.check(ctx, utils.SubjectTypeUser, req.Identifier, clientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := as.findUserByIdentifier(ctx, req.Identifier)
+	if err != nil {
+		logger.Logger.Errorf("[UserLogin] Error fetching user by identifier: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+
+	// 账号不存在与密码错误返回相同的错误，避免泄露账号是否存在
+	if user == nil {
+		utils.VerifyDummyPassword(req.Password)
+		return nil, as.loginFailed(ctx, utils.SubjectTypeUser, req.Identifier, clientIP)
+	}
+	userId = user.ID
+
+	// 验证密码是否正确
+	isMatch, err := utils.VerifyPassword(req.Password, user.Password)
+	if err != nil {
+		logger.Logger.Errorf("[UserLogin] Error verifying password: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+	if !isMatch {
+		return nil, as.loginFailed(ctx, utils.SubjectTypeUser, req.Identifier, clientIP)
+	}
+	as.limiter.succeed(ctx, utils.SubjectTypeUser, req.Identifier)
+
+	// 已禁用的用户不允许登录（放在密码校验之后，避免泄露账号状态）
+	if user.Status == 0 {
+		logger.Logger.Infof("[UserLogin] Disabled user %s attempted to login", user.ID)
+		return nil, utils.NewBusinessError(utils.UserDisabledCode)
+	}
+
+	// 密码被重置或已过期时只签发受限令牌，仅允许修改密码
+	var scope string
+	if passwordChangeRequired(user.MustChangePassword, user.PasswordChangedAt, user.CreatedAt) {
+		scope = utils.PasswordChangeScope
+	}
+
+	// 签发访问令牌与刷新令牌
+	loginResponse, err := as.issueTokens(ctx, utils.SubjectTypeUser, user.ID, uuid.New().String(), scope)
+	if err != nil {
+		logger.Logger.Errorf("[UserLogin] Error issuing tokens: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+
+	// 记录登陆时间
+	if err = as.userDao.UpdateLastLoginTime(ctx, user.ID); err != nil {
+		logger.Logger.Errorf("[UserLogin] Error UpdateLastLoginTime: %v", err)
 	}
 
 	return loginResponse, nil
 }
 
+// Refresh 使用刷新令牌换取新的令牌对，旧的刷新令牌随即失效
+func (as AuthService) Refresh(ctx context.Context, req *auth.RefreshTokenRequest) (*auth.LoginResponse, error) {
+	claims, err := utils.ParseToken(conf.GlobalConf.Jwt.AccessSecret, req.RefreshToken)
+	if err != nil {
+		if err.Error() == "Token is expired" {
+			return nil, utils.NewBusinessError(utils.TokenExpiredCode)
+		}
+		return nil, utils.NewBusinessError(utils.InvalidTokenCode)
+	}
+
+	jti := utils.ClaimString(claims, "jti")
+	familyId := utils.ClaimString(claims, "fid")
+	userId := utils.ClaimString(claims, "userId")
+	if utils.ClaimString(claims, "typ") != utils.RefreshTokenType || jti == "" || familyId == "" {
+		return nil, utils.NewBusinessError(utils.InvalidTokenCode)
+	}
+
+	family, err := redis.GetTokenFamily(ctx, familyId)
+	if err != nil {
+		logger.Logger.Errorf("[Refresh] Error fetching token family %s: %v", familyId, err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+	if family == nil || family.UserId != userId || family.SubjectType != utils.ClaimString(claims, "subjectType") {
+		return nil, utils.NewBusinessError(utils.TokenRevokedCode)
+	}
+
+	// 旧的刷新令牌被重复使用，说明令牌可能已泄露，吊销整个令牌家族
+	if family.RefreshJti != jti {
+		logger.Logger.Warnf("[Refresh] Refresh token reuse detected, family %s of user %s revoked", familyId, userId)
+		if err = redis.RevokeTokenFamily(ctx, familyId); err != nil {
+			logger.Logger.Errorf("[Refresh] Error revoking token family %s: %v", familyId, err)
+		}
+		return nil, utils.NewBusinessError(utils.TokenRevokedCode)
+	}
+
+	// 账号被删除或禁用后不再允许续期
+	if err = as.checkSubjectActive(ctx, family.SubjectType, userId); err != nil {
+		return nil, err
+	}
+
+	// 受限令牌轮换后仍然受限，直到修改密码后重新登录
+	return as.rotateTokens(ctx, family.SubjectType, userId, familyId, jti, utils.ClaimString(claims, "scope"))
+}
+
+// checkSubjectActive 检查令牌主体是否仍然存在且可用
+func (as AuthService) checkSubjectActive(ctx context.Context, subjectType, userId string) error {
+	if subjectType == utils.SubjectTypeAdmin {
+		admin, err := as.adminDao.GetByID(ctx, userId)
+		if err != nil {
+			logger.Logger.Errorf("[Refresh] Error fetching admin by ID: %v", err)
+			return utils.NewBusinessError(utils.InternalError)
+		}
+		if admin == nil {
+			return utils.NewBusinessError(utils.TokenRevokedCode)
+		}
+		return nil
+	}
+
+	user, err := as.userDao.GetByID(ctx, userId)
+	if err != nil {
+		logger.Logger.Errorf("[Refresh] Error fetching user by ID: %v", err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	if user == nil {
+		return utils.NewBusinessError(utils.TokenRevokedCode)
+	}
+	if user.Status == 0 {
+		return utils.NewBusinessError(utils.UserDisabledCode)
+	}
+	return nil
+}
+
+// Logout 退出当前登录：吊销当前访问令牌及其所属的令牌家族
+func (as AuthService) Logout(ctx context.Context, jti, familyId string, expireAt time.Time) error {
+	if err := redis.RevokeToken(ctx, jti, time.Until(expireAt)); err != nil {
+		logger.Logger.Errorf("[Logout] Error revoking token %s: %v", jti, err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	if err := redis.RevokeTokenFamily(ctx, familyId); err != nil {
+		logger.Logger.Errorf("[Logout] Error revoking token family %s: %v", familyId, err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	return nil
+}
+
+// LogoutAll 退出所有设备：吊销用户名下的全部令牌家族
+func (as AuthService) LogoutAll(ctx context.Context, userId, jti string, expireAt time.Time) error {
+	if err := redis.RevokeToken(ctx, jti, time.Until(expireAt)); err != nil {
+		logger.Logger.Errorf("[LogoutAll] Error revoking token %s: %v", jti, err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	if err := redis.RevokeUserTokenFamilies(ctx, userId); err != nil {
+		logger.Logger.Errorf("[LogoutAll] Error revoking token families of user %s: %v", userId, err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	return nil
+}
+
+// issueTokens 创建新的令牌家族并签发访问令牌与刷新令牌
+func (as AuthService) issueTokens(ctx context.Context, subjectType, userId, familyId, scope string) (*auth.LoginResponse, error) {
+	jwtConf := conf.GlobalConf.Jwt
+
+	res, refreshJti, err := as.signTokens(subjectType, userId, familyId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	family := redis.TokenFamily{UserId: userId, SubjectType: subjectType, RefreshJti: refreshJti}
+	if err = redis.SaveTokenFamily(ctx, familyId, family, time.Duration(jwtConf.RefreshExpire)*time.Second); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// rotateTokens 在已有令牌家族内轮换刷新令牌并签发新的令牌对
+func (as AuthService) rotateTokens(ctx context.Context, subjectType, userId, familyId, oldRefreshJti, scope string) (*auth.LoginResponse, error) {
+	jwtConf := conf.GlobalConf.Jwt
+
+	res, refreshJti, err := as.signTokens(subjectType, userId, familyId, scope)
+	if err != nil {
+		logger.Logger.Errorf("[Refresh] Error signing tokens: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+
+	rotated, err := redis.RotateRefreshToken(ctx, userId, familyId, oldRefreshJti, refreshJti,
+		time.Duration(jwtConf.RefreshExpire)*time.Second)
+	if err != nil {
+		logger.Logger.Errorf("[Refresh] Error rotating refresh token: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+	if !rotated {
+		// 并发请求已抢先使用了该刷新令牌
+		return nil, utils.NewBusinessError(utils.TokenRevokedCode)
+	}
+
+	return res, nil
+}
+
+// signTokens 签发访问令牌与刷新令牌，返回响应及刷新令牌的 jti
+func (as AuthService) signTokens(subjectType, userId, familyId, scope string) (*auth.LoginResponse, string, error) {
+	jwtConf := conf.GlobalConf.Jwt
+
+	accessToken, _, err := utils.GetToken(jwtConf.AccessSecret, jwtConf.AccessExpire, utils.TokenClaims{
+		UserId:      userId,
+		SubjectType: subjectType,
+		Type:        utils.AccessTokenType,
+		FamilyId:    familyId,
+		Scope:       scope,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	refreshToken, refreshJti, err := utils.GetToken(jwtConf.AccessSecret, jwtConf.RefreshExpire, utils.TokenClaims{
+		UserId:      userId,
+		SubjectType: subjectType,
+		Type:        utils.RefreshTokenType,
+		FamilyId:    familyId,
+		Scope:       scope,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &auth.LoginResponse{
+		Token:            accessToken,
+		ExpiresIn:        jwtConf.AccessExpire,
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: jwtConf.RefreshExpire,
+
+		PasswordChangeRequired: scope == utils.PasswordChangeScope,
+	}, refreshJti, nil
+}
+
 // ChangePassword 修改用户密码
-func (as AuthService) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) error {
+func (as AuthService) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) (err error) {
+	// 记录修改密码日志，包含失败的尝试
+	var adminId string
+	defer func() {
+		as.loginRecorder.record(ctx, utils.SubjectTypeAdmin, LoginEventChangePassword, req.Identifier, adminId, err)
+	}()
+
 	// 检查确认密码是否匹配新密码（额外保险，即使已通过验证器）
 	if req.NewPassword != req.ConfirmPassword {
 		return utils.NewBusinessError(utils.PasswordMismatchCode)
@@ -91,28 +429,22 @@ func (as AuthService) ChangePassword(ctx context.Context, req *auth.ChangePasswo
 		return utils.NewBusinessError(utils.NewPasswordSameAsOldCode)
 	}
 
-	// 确定 Identifier 类型（用户名、邮箱或手机号）
-	identifierType := utils.IdentifyType(req.Identifier)
-	var admin *entity.Admins
-	var err error
-
-	switch identifierType {
-	case "email":
-		admin, err = as.adminDao.GetByFields(ctx, "", req.Identifier, "")
-	case "phone":
-		admin, err = as.adminDao.GetByFields(ctx, "", "", req.Identifier)
-	default:
-		admin, err = as.adminDao.GetByFields(ctx, req.Identifier, "", "")
+	// 校验账号标识与客户端IP是否已被锁定
+	clientIP := utils.GetClientIP(ctx)
+	if err := as.limiter.check(ctx, utils.SubjectTypeAdmin, req.Identifier, clientIP); err != nil {
+		return err
 	}
 
-	// 检查用户是否存在
+	admin, err := as.findAdminByIdentifier(ctx, req.Identifier)
 	if err != nil {
-		logger.Logger.Errorf("[ChangePassword] Error fetching user by %s: %v", identifierType, err)
+		logger.Logger.Errorf("[ChangePassword] Error fetching admin by identifier: %v", err)
 		return utils.NewBusinessError(utils.InternalError)
 	}
 	if admin == nil {
-		return utils.NewBusinessError(utils.UserNotFoundCode)
+		utils.VerifyDummyPassword(req.OldPassword)
+		return as.loginFailed(ctx, utils.SubjectTypeAdmin, req.Identifier, clientIP)
 	}
+	adminId = admin.ID
 
 	// 验证旧密码是否正确
 	isMatch, err := utils.VerifyPassword(req.OldPassword, admin.Password)
@@ -122,26 +454,205 @@ func (as AuthService) ChangePassword(ctx context.Context, req *auth.ChangePasswo
 		return utils.NewBusinessError(utils.InternalError)
 	}
 	if !isMatch {
-		// 如果密码不匹配，返回无效凭证错误
+		return as.loginFailed(ctx, utils.SubjectTypeAdmin, req.Identifier, clientIP)
+	}
+	as.limiter.succeed(ctx, utils.SubjectTypeAdmin, req.Identifier)
+
+	return as.updatePassword(ctx, utils.SubjectTypeAdmin, admin.ID, admin.Password, req.NewPassword,
+		admin.Username, admin.Email, admin.Phone)
+}
+
+// ChangeOwnPassword 已登录的管理员或业务用户修改自己的密码，密码被重置或已过期时使用受限令牌调用
+func (as AuthService) ChangeOwnPassword(ctx context.Context, subjectType, userId string, req *auth.ChangeOwnPasswordRequest) (err error) {
+	if req.NewPassword != req.ConfirmPassword {
+		return utils.NewBusinessError(utils.PasswordMismatchCode)
+	}
+	if req.NewPassword == req.OldPassword {
+		return utils.NewBusinessError(utils.NewPasswordSameAsOldCode)
+	}
+
+	var hashedPassword, username, email, phone string
+	if subjectType == utils.SubjectTypeAdmin {
+		admin, err := as.adminDao.GetByID(ctx, userId)
+		if err != nil {
+			logger.Logger.Errorf("[ChangeOwnPassword] Error fetching admin by ID: %v", err)
+			return utils.NewBusinessError(utils.InternalError)
+		}
+		if admin == nil {
+			return utils.NewBusinessError(utils.AdminNotFoundCode)
+		}
+		hashedPassword, username, email, phone = admin.Password, admin.Username, admin.Email, admin.Phone
+	} else {
+		user, err := as.userDao.GetByID(ctx, userId)
+		if err != nil {
+			logger.Logger.Errorf("[ChangeOwnPassword] Error fetching user by ID: %v", err)
+			return utils.NewBusinessError(utils.InternalError)
+		}
+		if user == nil {
+			return utils.NewBusinessError(utils.UserNotFoundCode)
+		}
+		hashedPassword, username, email, phone = user.Password, user.Username, user.Email, user.Phone
+	}
+
+	// 记录修改密码日志，包含失败的尝试
+	defer func() {
+		as.loginRecorder.record(ctx, subjectType, LoginEventChangePassword, username, userId, err)
+	}()
+
+	// 旧密码校验失败同样计入登录失败次数，防止借此暴力破解
+	clientIP := utils.GetClientIP(ctx)
+	if err := as.limiter.check(ctx, subjectType, username, clientIP); err != nil {
+		return err
+	}
+	isMatch, err := utils.VerifyPassword(req.OldPassword, hashedPassword)
+	if err != nil {
+		logger.Logger.Errorf("[ChangeOwnPassword] Error verifying password: %v", err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	if !isMatch {
+		if err = as.limiter.fail(ctx, subjectType, username, clientIP); err != nil {
+			return err
+		}
 		return utils.NewBusinessError(utils.OldPasswordIncorrectCode)
 	}
+	as.limiter.succeed(ctx, subjectType, username)
+
+	return as.updatePassword(ctx, subjectType, userId, hashedPassword, req.NewPassword, username, email, phone)
+}
+
+// updatePassword 校验新密码强度及历史记录后更新密码，并吊销账号名下的全部令牌，要求重新登录
+func (as AuthService) updatePassword(ctx context.Context, subjectType, userId, oldHash, newPassword string, identities ...string) error {
+	// 校验新密码强度
+	if err := utils.ValidatePassword(newPassword, identities...); err != nil {
+		return err
+	}
+
+	// 不允许重复使用最近的密码
+	if err := as.passwordHistory.check(ctx, subjectType, userId, oldHash, newPassword); err != nil {
+		return err
+	}
 
 	// 加密新密码
-	hashedPassword, err := utils.EncryptPassword(req.NewPassword)
+	hashedPassword, err := utils.EncryptPassword(newPassword)
 	if err != nil {
 		logger.Logger.Errorf("[ChangePassword] Error encrypting new password: %v", err)
 		return utils.NewBusinessError(utils.PasswordGenerationFailedCode)
 	}
 
-	// 更新密码
-	updates := map[string]interface{}{
-		entity.UsersColumns.Password:  hashedPassword,
-		entity.UsersColumns.UpdatedAt: time.Now(),
-	}
-	if err := as.adminDao.Update(ctx, admin.ID, updates); err != nil {
-		logger.Logger.Errorf("[ChangePassword] Error updating password for user %s: %v", admin.ID, err)
+	// 更新密码并清除强制修改标记，同时记录旧密码
+	now := time.Now()
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if subjectType == utils.SubjectTypeAdmin {
+			err = as.adminDao.UpdateTx(ctx, tx, userId, map[string]interface{}{
+				entity.AdminsColumns.Password:           hashedPassword,
+				entity.AdminsColumns.PasswordChangedAt:  now,
+				entity.AdminsColumns.MustChangePassword: 0,
+				entity.AdminsColumns.UpdatedAt:          now,
+			})
+		} else {
+			err = as.userDao.UpdateTx(ctx, tx, userId, map[string]interface{}{
+				entity.UsersColumns.Password:           hashedPassword,
+				entity.UsersColumns.PasswordChangedAt:  now,
+				entity.UsersColumns.MustChangePassword: 0,
+				entity.UsersColumns.UpdatedAt:          now,
+			})
+		}
+		if err != nil {
+			logger.Logger.Errorf("[ChangePassword] Error updating password for %s %s: %v", subjectType, userId, err)
+			return err
+		}
+
+		if err = as.passwordHistory.recordTx(ctx, tx, subjectType, userId, oldHash); err != nil {
+			logger.Logger.Errorf("[ChangePassword] Error recording password history for %s %s: %v", subjectType, userId, err)
+			return err
+		}
+		return nil
+	}); err != nil {
 		return utils.NewBusinessError(utils.PasswordChangeFailedCode)
 	}
 
+	// 修改密码后其他设备上的登录会话全部失效
+	if err = redis.RevokeUserTokenFamilies(ctx, userId); err != nil {
+		logger.Logger.Errorf("[ChangePassword] Error revoking token families of %s: %v", userId, err)
+	}
+
+	return nil
+}
+
+// Unlock 解除账号的登录锁定
+func (as AuthService) Unlock(ctx context.Context, req *auth.UnlockAccountRequest) error {
+	keys := []string{identifierLockKey(req.SubjectType, req.Identifier)}
+
+	// 账号存在时，同时解除其用户名、邮箱、手机号三个标识的锁定
+	var username, email, phone string
+	if req.SubjectType == utils.SubjectTypeAdmin {
+		admin, err := as.findAdminByIdentifier(ctx, req.Identifier)
+		if err != nil {
+			logger.Logger.Errorf("[Unlock] Error fetching admin by identifier: %v", err)
+			return utils.NewBusinessError(utils.InternalError)
+		}
+		if admin != nil {
+			username, email, phone = admin.Username, admin.Email, admin.Phone
+			// 同时解除二次验证失败导致的锁定
+			keys = append(keys, identifierLockKey(mfaLockSubject, admin.ID))
+		}
+	} else {
+		user, err := as.findUserByIdentifier(ctx, req.Identifier)
+		if err != nil {
+			logger.Logger.Errorf("[Unlock] Error fetching user by identifier: %v", err)
+			return utils.NewBusinessError(utils.InternalError)
+		}
+		if user != nil {
+			username, email, phone = user.Username, user.Email, user.Phone
+		}
+	}
+	for _, identifier := range []string{username, email, phone} {
+		if identifier != "" {
+			keys = append(keys, identifierLockKey(req.SubjectType, identifier))
+		}
+	}
+
+	if req.IP != "" {
+		keys = append(keys, ipLockKey(req.IP))
+	}
+
+	if err := redis.UnlockLogin(ctx, keys...); err != nil {
+		logger.Logger.Errorf("[Unlock] Error unlocking %v: %v", keys, err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	logger.Logger.Infof("[Unlock] Login lock cleared for %v", keys)
+
 	return nil
 }
+
+// loginFailed 记录一次认证失败并返回统一的错误
+func (as AuthService) loginFailed(ctx context.Context, subjectType, identifier, clientIP string) error {
+	if err := as.limiter.fail(ctx, subjectType, identifier, clientIP); err != nil {
+		return err
+	}
+	return utils.NewBusinessError(utils.UserInvalidCredentialsCode)
+}
+
+// findAdminByIdentifier 根据标识（用户名、邮箱或手机号）查询管理员
+func (as AuthService) findAdminByIdentifier(ctx context.Context, identifier string) (*entity.Admins, error) {
+	switch utils.IdentifyType(identifier) {
+	case "email":
+		return as.adminDao.GetByFields(ctx, "", identifier, "")
+	case "phone":
+		return as.adminDao.GetByFields(ctx, "", "", identifier)
+	default:
+		return as.adminDao.GetByFields(ctx, identifier, "", "")
+	}
+}
+
+// findUserByIdentifier 根据标识（用户名、邮箱或手机号）查询业务用户
+func (as AuthService) findUserByIdentifier(ctx context.Context, identifier string) (*entity.Users, error) {
+	switch utils.IdentifyType(identifier) {
+	case "email":
+		return as.userDao.GetByFields(ctx, "", identifier, "")
+	case "phone":
+		return as.userDao.GetByFields(ctx, "", "", identifier)
+	default:
+		return as.userDao.GetByFields(ctx, identifier, "", "")
+	}
+}
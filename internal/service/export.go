@@ -0,0 +1,108 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/utils"
+	"io"
+	"strconv"
+	"time"
+)
+
+// 导出
+const (
+	exportBatchSize = 500 // 导出时每批读取的条数
+	exportSeparator = ";" // 单元格内多个值的分隔符，与导入时的分隔符一致
+)
+
+// exportColumn 导出文件中的一列
+type exportColumn[T any] struct {
+	name  string         // 列名，即请求中 columns 的取值和表头
+	value func(T) string // 取值函数
+}
+
+// selectExportColumns 按请求中的列名依次选出要导出的列，未指定时导出全部列
+func selectExportColumns[T any](columns []exportColumn[T], names []string) ([]exportColumn[T], error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	selected := make([]exportColumn[T], 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, column := range columns {
+			if column.name == name {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, utils.NewBusinessErrorWithMessage(utils.ExportColumnInvalidCode,
+				utils.ErrorMessages[utils.ExportColumnInvalidCode]+": "+name)
+		}
+	}
+	return selected, nil
+}
+
+// writeExport 将分批读取的数据按列写出为 CSV 或 XLSX 文件
+// 参数:
+//   - find: 分批读取数据，每读取一批调用一次 write，write 返回错误时应停止读取
+//
+// 注意: CSV 在读取到第一批数据后开始写入 w，XLSX 在全部数据读取完成后才写入 w；开始写入后发生的错误无法再以 JSON 形式返回给调用方。
+func writeExport[T any](w io.Writer, format string, columns []exportColumn[T], find func(write func(records []T) error) error) error {
+	writer, err := utils.NewSpreadsheetWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.name)
+	}
+	headerWritten := false
+	writeHeader := func() error {
+		if headerWritten {
+			return nil
+		}
+		headerWritten = true
+		return writer.Write(header)
+	}
+
+	if err = find(func(records []T) error {
+		if err := writeHeader(); err != nil {
+			return err
+		}
+		for _, record := range records {
+			row := make([]string, 0, len(columns))
+			for _, column := range columns {
+				row = append(row, column.value(record))
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		return writer.Flush()
+	}); err != nil {
+		writer.Abort()
+		return err
+	}
+
+	// 没有数据时仍输出表头
+	if err = writeHeader(); err != nil {
+		writer.Abort()
+		return err
+	}
+	return writer.Close()
+}
+
+// formatExportTime 格式化导出的时间，零值输出为空
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatExportInt 格式化导出的整数
+func formatExportInt[T ~int | ~int8](v T) string {
+	return strconv.Itoa(int(v))
+}
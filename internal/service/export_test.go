@@ -0,0 +1,81 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteLoginLogExport(t *testing.T) {
+	createdAt := time.Date(2024, 11, 18, 10, 0, 0, 0, time.UTC)
+	loginLogs := []*entity.LoginLogs{
+		{ID: "l1", SubjectType: "user", Event: "login", Identifier: "=cmd()", ClientIP: "10.0.0.1", ResultCode: 1003, CreatedAt: createdAt},
+		{ID: "l2", SubjectType: "admin", Event: "login", Identifier: "admin", AccountID: "a1", CreatedAt: createdAt},
+	}
+	tests := []struct {
+		name     string
+		columns  []string
+		batches  [][]*entity.LoginLogs
+		want     string
+		wantCode int
+	}{
+		{
+			name:    "all columns",
+			batches: [][]*entity.LoginLogs{loginLogs[:1], loginLogs[1:]},
+			want: "id,subjectType,event,identifier,accountId,clientIp,userAgent,resultCode,createdAt\n" +
+				"l1,user,login,'=cmd(),,10.0.0.1,,1003,2024-11-18T10:00:00Z\n" +
+				"l2,admin,login,admin,a1,,,0,2024-11-18T10:00:00Z\n",
+		},
+		{
+			name:    "selected columns in order",
+			columns: []string{"createdAt", "id"},
+			batches: [][]*entity.LoginLogs{loginLogs},
+			want:    "createdAt,id\n2024-11-18T10:00:00Z,l1\n2024-11-18T10:00:00Z,l2\n",
+		},
+		{
+			name:    "header without data",
+			columns: []string{"id", "event"},
+			want:    "id,event\n",
+		},
+		{
+			name:     "unknown column",
+			columns:  []string{"id", "password"},
+			wantCode: utils.ExportColumnInvalidCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := selectExportColumns(loginLogExportColumns, tt.columns)
+			if tt.wantCode != utils.Success {
+				var businessErr *utils.BusinessError
+				if !errors.As(err, &businessErr) || businessErr.Code != tt.wantCode {
+					t.Fatalf("selectExportColumns() error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectExportColumns() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			err = writeExport(&buf, utils.SpreadsheetFormatCSV, columns, func(write func(records []*entity.LoginLogs) error) error {
+				for _, batch := range tt.batches {
+					if err := write(batch); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("writeExport() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeExport() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 登录日志事件类型
+const (
+	LoginEventLogin          = "login"             // 账号密码登录
+	LoginEventMfaChallenge   = "loginMfaChallenge" // 账号密码验证通过，等待二次验证
+	LoginEventTotp           = "loginTotp"         // 二次验证
+	LoginEventChangePassword = "changePassword"    // 修改密码
+)
+
+const (
+	recentLoginLimit    = 10     // 账号详情中展示的最近登录记录条数
+	loginLogExportLimit = 100000 // 单次导出的最大条数
+)
+
+// loginRecorder 登录日志记录，管理员与业务用户共用
+type loginRecorder struct {
+	dao *dao.LoginLogDao
+}
+
+// newLoginRecorder 创建 loginRecorder 实例
+func newLoginRecorder() loginRecorder {
+	return loginRecorder{dao: dao.NewLoginLogDao()}
+}
+
+// record 记录一次认证尝试
+// 参数:
+//   - accountID: 解析出的账号ID，账号不存在时为空
+//   - err: 认证结果，nil 表示成功，业务错误记录其错误码，其他错误记录为 InternalError
+//
+// 注意: 写入失败只记录日志，不影响登录流程。
+func (lr loginRecorder) record(ctx context.Context, subjectType, event, identifier, accountID string, err error) {
+	resultCode := utils.Success
+	if err != nil {
+		resultCode = utils.InternalError
+		if businessErr, ok := err.(*utils.BusinessError); ok {
+			resultCode = businessErr.Code
+		}
+	}
+
+	loginLog := &entity.LoginLogs{
+		ID:          uuid.New().String(),
+		SubjectType: subjectType,
+		Event:       event,
+		Identifier:  truncate(identifier, 128),
+		AccountID:   accountID,
+		ClientIP:    utils.GetClientIP(ctx),
+		UserAgent:   truncate(utils.GetUserAgent(ctx), 512),
+		ResultCode:  resultCode,
+		CreatedAt:   time.Now(),
+	}
+	if err = lr.dao.Insert(ctx, loginLog); err != nil {
+		logger.Logger.Errorf("[LoginLog] Error recording %s %s attempt of %s: %v", subjectType, event, identifier, err)
+	}
+}
+
+// recent 获取账号最近的登录记录
+func (lr loginRecorder) recent(ctx context.Context, subjectType, accountID string) ([]auth.RecentLoginInfo, error) {
+	loginLogs, err := lr.dao.GetRecent(ctx, subjectType, accountID, recentLoginLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	recentLogins := make([]auth.RecentLoginInfo, 0, len(loginLogs))
+	for _, loginLog := range loginLogs {
+		recentLogins = append(recentLogins, auth.RecentLoginInfo{
+			Event:      loginLog.Event,
+			ClientIP:   loginLog.ClientIP,
+			UserAgent:  loginLog.UserAgent,
+			ResultCode: loginLog.ResultCode,
+			CreatedAt:  loginLog.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return recentLogins, nil
+}
+
+type LoginLogService struct {
+	loginLogDao *dao.LoginLogDao
+}
+
+// NewLoginLogService 创建一个新的 LoginLogService 实例
+func NewLoginLogService() *LoginLogService {
+	return &LoginLogService{
+		loginLogDao: dao.NewLoginLogDao(),
+	}
+}
+
+// List 获取登录日志列表（分页）
+func (ls *LoginLogService) List(ctx context.Context, req *auth.ListLoginLogRequest) (*auth.ListLoginLogResponse, error) {
+	loginLogs, total, err := ls.loginLogDao.Query(ctx, req.Page, req.PageSize, buildLoginLogFilter(&req.LoginLogFilter))
+	if err != nil {
+		logger.Logger.Errorf("[GetLoginLogList] Error fetching login logs: %v", err)
+		return nil, utils.NewBusinessError(utils.LoginLogQueryListFailedCode)
+	}
+
+	// 转换数据格式为响应模型
+	loginLogList := make([]auth.LoginLogInfo, 0, len(loginLogs))
+	for _, loginLog := range loginLogs {
+		loginLogList = append(loginLogList, loginLogInfo(loginLog))
+	}
+
+	return &auth.ListLoginLogResponse{
+		Total: total,
+		List:  loginLogList,
+	}, nil
+}
+
+// loginLogInfo 将登录日志转换为响应模型
+func loginLogInfo(loginLog *entity.LoginLogs) auth.LoginLogInfo {
+	return auth.LoginLogInfo{
+		ID:          loginLog.ID,
+		SubjectType: loginLog.SubjectType,
+		Event:       loginLog.Event,
+		Identifier:  loginLog.Identifier,
+		AccountID:   loginLog.AccountID,
+		ClientIP:    loginLog.ClientIP,
+		UserAgent:   loginLog.UserAgent,
+		ResultCode:  loginLog.ResultCode,
+		CreatedAt:   loginLog.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// loginLogExportColumns 登录日志导出的全部列
+var loginLogExportColumns = []exportColumn[*entity.LoginLogs]{
+	{"id", func(l *entity.LoginLogs) string { return l.ID }},
+	{"subjectType", func(l *entity.LoginLogs) string { return l.SubjectType }},
+	{"event", func(l *entity.LoginLogs) string { return l.Event }},
+	{"identifier", func(l *entity.LoginLogs) string { return l.Identifier }},
+	{"accountId", func(l *entity.LoginLogs) string { return l.AccountID }},
+	{"clientIp", func(l *entity.LoginLogs) string { return l.ClientIP }},
+	{"userAgent", func(l *entity.LoginLogs) string { return l.UserAgent }},
+	{"resultCode", func(l *entity.LoginLogs) string { return formatExportInt(l.ResultCode) }},
+	{"createdAt", func(l *entity.LoginLogs) string { return formatExportTime(l.CreatedAt) }},
+}
+
+// Export 按筛选条件导出登录日志为 CSV 或 XLSX 文件，按发生时间倒序，最多导出 loginLogExportLimit 条
+// 注意: 数据分批读取并直接写入 w，开始写入后发生的错误无法再以 JSON 形式返回给调用方。
+func (ls *LoginLogService) Export(ctx context.Context, req *auth.ExportLoginLogRequest, w io.Writer) error {
+	columns, err := selectExportColumns(loginLogExportColumns, req.Columns)
+	if err != nil {
+		return err
+	}
+
+	filter := buildLoginLogFilter(&req.LoginLogFilter)
+	err = writeExport(w, req.Format, columns, func(write func(records []*entity.LoginLogs) error) error {
+		return ls.loginLogDao.FindInBatches(ctx, filter, exportBatchSize, loginLogExportLimit, write)
+	})
+	if err != nil {
+		logger.Logger.Errorf("[ExportLoginLog] Error exporting login logs: %v", err)
+		return utils.NewBusinessError(utils.LoginLogExportFailedCode)
+	}
+	return nil
+}
+
+// buildLoginLogFilter 将请求中的筛选条件转换为 DAO 查询条件，时间格式已由校验器保证
+func buildLoginLogFilter(req *auth.LoginLogFilter) dao.LoginLogFilter {
+	filter := dao.LoginLogFilter{
+		Fields: map[string]interface{}{
+			entity.LoginLogsColumns.SubjectType: req.SubjectType,
+			entity.LoginLogsColumns.Event:       req.Event,
+			entity.LoginLogsColumns.Identifier:  req.Identifier,
+			entity.LoginLogsColumns.AccountID:   req.AccountID,
+			entity.LoginLogsColumns.ClientIP:    req.ClientIP,
+		},
+		Success: req.Success,
+	}
+	if req.StartTime != "" {
+		filter.StartTime, _ = time.Parse(time.RFC3339, req.StartTime)
+	}
+	if req.EndTime != "" {
+		filter.EndTime, _ = time.Parse(time.RFC3339, req.EndTime)
+	}
+	return filter
+}
@@ -2,23 +2,320 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"ByteScience-WAM-Admin/internal/dao"
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type MenuService struct {
-	menuDao *dao.MenuDao
-	pathDao *dao.PathDao
+	menuDao           *dao.MenuDao
+	pathDao           *dao.PathDao
+	rolePathDao       *dao.RolePathDao
+	userPermissionDao *dao.UserPermissionDao
+	policyCache       policyCache
+	permissionCache   permissionCache
 }
 
 // NewMenuService 创建一个新的 MenuService 实例
 func NewMenuService() *MenuService {
 	return &MenuService{
-		menuDao: dao.NewMenuDao(),
-		pathDao: dao.NewPathDao(),
+		menuDao:           dao.NewMenuDao(),
+		pathDao:           dao.NewPathDao(),
+		rolePathDao:       dao.NewRolePathDao(),
+		userPermissionDao: dao.NewUserPermissionDao(),
+		policyCache:       newPolicyCache(),
+		permissionCache:   newPermissionCache(),
+	}
+}
+
+// Add 新增菜单
+func (ms *MenuService) Add(ctx context.Context, req *auth.AddMenuRequest) error {
+	err := menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		menus, err := ms.menuDao.GetAllTx(ctx, tx)
+		if err != nil {
+			logger.Logger.Errorf("[AddMenu] Error fetching menus: %v", err)
+			return utils.NewBusinessError(utils.MenuQueryFailedCode)
+		}
+
+		// 父菜单必须存在
+		if req.ParentID != "" && findMenu(menus, req.ParentID) == nil {
+			return utils.NewBusinessError(utils.MenuParentNotFoundCode)
+		}
+
+		menu := &entity.Menus{
+			ID:        uuid.New().String(),
+			ParentID:  req.ParentID,
+			Name:      req.Name,
+			Sort:      nextMenuSort(menus, req.ParentID, ""),
+			Status:    1,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if req.Sort != nil {
+			menu.Sort = *req.Sort
+		}
+		if req.Status != nil {
+			menu.Status = *req.Status
+		}
+
+		if err = ms.menuDao.InsertTx(ctx, tx, menu); err != nil {
+			logger.Logger.Errorf("[AddMenu] Error inserting menu: %v", err)
+			return err
+		}
+		return nil
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		return utils.NewBusinessError(utils.MenuInsertFailedCode)
 	}
+
+	return nil
+}
+
+// Edit 编辑菜单
+func (ms *MenuService) Edit(ctx context.Context, req *auth.EditMenuRequest) error {
+	if _, err := ms.checkMenuExistence(ctx, req.ID); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		entity.MenusColumns.Name:      req.Name,
+		entity.MenusColumns.UpdatedAt: time.Now(),
+	}
+	if err := ms.menuDao.Update(ctx, req.ID, updates); err != nil {
+		logger.Logger.Errorf("[EditMenu] Error updating menu: %v", err)
+		return utils.NewBusinessError(utils.MenuUpdateFailedCode)
+	}
+
+	return nil
+}
+
+// UpdateStatus 启用或禁用菜单
+func (ms *MenuService) UpdateStatus(ctx context.Context, req *auth.UpdateMenuStatusRequest) error {
+	if _, err := ms.checkMenuExistence(ctx, req.ID); err != nil {
+		return err
+	}
+
+	if err := ms.menuDao.UpdateStatus(ctx, req.ID, int(*req.Status)); err != nil {
+		logger.Logger.Errorf("[UpdateMenuStatus] Error updating menu status: %v", err)
+		return utils.NewBusinessError(utils.MenuUpdateFailedCode)
+	}
+
+	return nil
+}
+
+// Delete 删除菜单
+// 非级联删除时，菜单下存在子菜单或接口则拒绝删除；级联删除时一并删除全部子孙菜单及其接口，
+// 同时收回角色对这些接口的授权并清理用户权限预计算表
+func (ms *MenuService) Delete(ctx context.Context, req *auth.DelMenuRequest) error {
+	var (
+		pathIDs []string
+		userIDs []string
+	)
+	err := menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		menus, err := ms.menuDao.GetAllTx(ctx, tx)
+		if err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error fetching menus: %v", err)
+			return utils.NewBusinessError(utils.MenuQueryFailedCode)
+		}
+		if findMenu(menus, req.ID) == nil {
+			return utils.NewBusinessError(utils.MenuNotFoundCode)
+		}
+
+		// 收集菜单及其全部子孙菜单下的接口
+		menuIDs := collectMenuSubtree(menus, req.ID)
+		paths, err := ms.pathDao.GetByMenuIDsTx(ctx, tx, menuIDs)
+		if err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error fetching menu paths: %v", err)
+			return utils.NewBusinessError(utils.MenuQueryFailedCode)
+		}
+		if !req.Cascade && (len(menuIDs) > 1 || len(paths) > 0) {
+			return utils.NewBusinessError(utils.MenuHasChildrenCode)
+		}
+
+		pathIDs = make([]string, 0, len(paths))
+		for _, path := range paths {
+			pathIDs = append(pathIDs, path.ID)
+		}
+
+		// 删除前找出拥有这些接口权限的用户，删除后其权限缓存需要失效
+		if userIDs, err = ms.userPermissionDao.GetUserIDsByPathIDsTx(ctx, tx, pathIDs); err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error fetching users of paths: %v", err)
+			return err
+		}
+
+		// 收回角色对接口的授权
+		if err = ms.rolePathDao.RemoveByPathIDsTx(ctx, tx, pathIDs); err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error removing role paths: %v", err)
+			return err
+		}
+
+		// 清理用户权限预计算表
+		if err = ms.userPermissionDao.RemoveByPathIDsTx(ctx, tx, pathIDs); err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error removing user permissions: %v", err)
+			return err
+		}
+
+		// 软删除接口与菜单
+		if err = ms.pathDao.SoftDeleteByMenuIDsTx(ctx, tx, menuIDs); err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error soft deleting paths: %v", err)
+			return err
+		}
+		if err = ms.menuDao.SoftDeleteByIDsTx(ctx, tx, menuIDs); err != nil {
+			logger.Logger.Errorf("[DeleteMenu] Error soft deleting menus: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		return utils.NewBusinessError(utils.MenuDeleteFailedCode)
+	}
+
+	// 权限决策缓存按请求方法和路由缓存，接口删除后全部失效
+	if len(pathIDs) > 0 {
+		ms.permissionCache.invalidateUsers(ctx, userIDs...)
+		ms.policyCache.invalidateAll(ctx)
+	}
+
+	return nil
+}
+
+// Move 将菜单移动到新的父菜单下，不允许移动到自身或其子孙菜单下
+func (ms *MenuService) Move(ctx context.Context, req *auth.MoveMenuRequest) error {
+	err := menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		menus, err := ms.menuDao.GetAllTx(ctx, tx)
+		if err != nil {
+			logger.Logger.Errorf("[MoveMenu] Error fetching menus: %v", err)
+			return utils.NewBusinessError(utils.MenuQueryFailedCode)
+		}
+		if findMenu(menus, req.ID) == nil {
+			return utils.NewBusinessError(utils.MenuNotFoundCode)
+		}
+
+		if req.ParentID != "" {
+			if findMenu(menus, req.ParentID) == nil {
+				return utils.NewBusinessError(utils.MenuParentNotFoundCode)
+			}
+			// 新父菜单是菜单自身或其子孙菜单时会形成环
+			if utils.Contains(collectMenuSubtree(menus, req.ID), req.ParentID) {
+				return utils.NewBusinessError(utils.MenuMoveCycleCode)
+			}
+		}
+
+		sort := nextMenuSort(menus, req.ParentID, req.ID)
+		if req.Sort != nil {
+			sort = *req.Sort
+		}
+
+		// 顶级菜单的父菜单ID写入 NULL
+		var parentID interface{}
+		if req.ParentID != "" {
+			parentID = req.ParentID
+		}
+		updates := map[string]interface{}{
+			entity.MenusColumns.ParentID:  parentID,
+			entity.MenusColumns.Sort:      sort,
+			entity.MenusColumns.UpdatedAt: time.Now(),
+		}
+		if err = ms.menuDao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+			logger.Logger.Errorf("[MoveMenu] Error moving menu: %v", err)
+			return err
+		}
+		return nil
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		return utils.NewBusinessError(utils.MenuUpdateFailedCode)
+	}
+
+	return nil
+}
+
+// Sort 按给定顺序批量调整同级菜单的排序
+func (ms *MenuService) Sort(ctx context.Context, req *auth.SortMenuRequest) error {
+	menus, err := ms.menuDao.GetAll(ctx)
+	if err != nil {
+		logger.Logger.Errorf("[SortMenu] Error fetching menus: %v", err)
+		return utils.NewBusinessError(utils.MenuQueryFailedCode)
+	}
+	if req.ParentID != "" && findMenu(menus, req.ParentID) == nil {
+		return utils.NewBusinessError(utils.MenuParentNotFoundCode)
+	}
+
+	// 排序列表必须恰好包含全部同级菜单，避免并发调整后出现遗漏或重复
+	siblings := make(map[string]struct{})
+	for _, menu := range menus {
+		if sameMenuParent(menu.ParentID, req.ParentID) {
+			siblings[menu.ID] = struct{}{}
+		}
+	}
+	seen := make(map[string]struct{}, len(req.IDList))
+	for _, id := range req.IDList {
+		if _, ok := siblings[id]; !ok {
+			return utils.NewBusinessError(utils.MenuSortMismatchCode)
+		}
+		seen[id] = struct{}{}
+	}
+	if len(seen) != len(siblings) || len(req.IDList) != len(siblings) {
+		return utils.NewBusinessError(utils.MenuSortMismatchCode)
+	}
+
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for i, id := range req.IDList {
+			updates := map[string]interface{}{
+				entity.MenusColumns.Sort:      i,
+				entity.MenusColumns.UpdatedAt: now,
+			}
+			if err = ms.menuDao.UpdateTx(ctx, tx, id, updates); err != nil {
+				logger.Logger.Errorf("[SortMenu] Error updating menu sort: %v", err)
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return utils.NewBusinessError(utils.MenuUpdateFailedCode)
+	}
+
+	return nil
+}
+
+// menuTreeTransaction 持有菜单树锁开启事务执行 fn
+// 新增、移动、删除菜单以及向菜单下挂载接口都依据读取到的菜单树做检查，
+// 在同一个命名锁下串行执行并在事务中读取，环检查和级联删除不会遗漏并发挂载的子菜单或接口。
+func menuTreeTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return db.WithLock(ctx, db.Client, db.LockMenuTree, db.DefaultLockTimeout, func(conn *gorm.DB) error {
+		return conn.Transaction(fn)
+	})
+}
+
+// checkMenuExistence 检查菜单是否存在
+func (ms *MenuService) checkMenuExistence(ctx context.Context, id string) (*entity.Menus, error) {
+	menu, err := ms.menuDao.GetByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[CheckMenuExistence] Error fetching menu: %v", err)
+		return nil, utils.NewBusinessError(utils.MenuQueryFailedCode)
+	}
+	if menu == nil {
+		return nil, utils.NewBusinessError(utils.MenuNotFoundCode)
+	}
+	return menu, nil
 }
 
 // GetMenuPathTree 获取完整的菜单和路径树
@@ -47,6 +344,8 @@ func buildMenuPathTree(menus []*entity.Menus, paths []*entity.Paths) []*auth.Men
 				ParentID: menu.ParentID,
 				Name:     menu.Name,
 			},
+			Sort:     menu.Sort,
+			Status:   menu.Status,
 			MenuData: []*auth.MenuNode{},
 			Paths:    []*auth.PathInfo{},
 		}
@@ -75,3 +374,63 @@ func buildMenuPathTree(menus []*entity.Menus, paths []*entity.Paths) []*auth.Men
 
 	return tree
 }
+
+// isRootMenu 判断父菜单ID是否表示顶级菜单
+func isRootMenu(parentID string) bool {
+	return parentID == "" || parentID == "null"
+}
+
+// sameMenuParent 判断两个父菜单ID是否指向同一个父菜单
+func sameMenuParent(a, b string) bool {
+	if isRootMenu(a) || isRootMenu(b) {
+		return isRootMenu(a) && isRootMenu(b)
+	}
+	return a == b
+}
+
+// findMenu 在菜单列表中查找指定菜单
+func findMenu(menus []*entity.Menus, id string) *entity.Menus {
+	for _, menu := range menus {
+		if menu.ID == id {
+			return menu
+		}
+	}
+	return nil
+}
+
+// collectMenuSubtree 返回菜单自身及其全部子孙菜单的ID
+func collectMenuSubtree(menus []*entity.Menus, id string) []string {
+	children := make(map[string][]string)
+	for _, menu := range menus {
+		if !isRootMenu(menu.ParentID) {
+			children[menu.ParentID] = append(children[menu.ParentID], menu.ID)
+		}
+	}
+
+	subtree := []string{id}
+	visited := map[string]struct{}{id: {}}
+	for i := 0; i < len(subtree); i++ {
+		for _, childID := range children[subtree[i]] {
+			// 防御数据库中已存在的环
+			if _, ok := visited[childID]; ok {
+				continue
+			}
+			visited[childID] = struct{}{}
+			subtree = append(subtree, childID)
+		}
+	}
+	return subtree
+}
+
+// nextMenuSort 返回排在同级菜单最后所需的排序值
+// 参数:
+//   - excludeID: 计算时忽略的菜单，移动菜单时为菜单自身
+func nextMenuSort(menus []*entity.Menus, parentID, excludeID string) int {
+	next := 0
+	for _, menu := range menus {
+		if menu.ID != excludeID && sameMenuParent(menu.ParentID, parentID) && menu.Sort >= next {
+			next = menu.Sort + 1
+		}
+	}
+	return next
+}
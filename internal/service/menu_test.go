@@ -0,0 +1,68 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"reflect"
+	"testing"
+)
+
+func TestCollectMenuSubtree(t *testing.T) {
+	// root
+	// ├── a
+	// │   ├── a1
+	// │   └── a2
+	// │       └── a21
+	// └── b
+	// other（另一棵树）
+	menus := []*entity.Menus{
+		{ID: "root", ParentID: ""},
+		{ID: "a", ParentID: "root"},
+		{ID: "b", ParentID: "root"},
+		{ID: "a1", ParentID: "a"},
+		{ID: "a2", ParentID: "a"},
+		{ID: "a21", ParentID: "a2"},
+		{ID: "other", ParentID: "null"},
+		// 数据库中已存在的环
+		{ID: "c1", ParentID: "c2"},
+		{ID: "c2", ParentID: "c1"},
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		want []string
+	}{
+		{"whole tree in breadth-first order", "root", []string{"root", "a", "b", "a1", "a2", "a21"}},
+		{"subtree", "a", []string{"a", "a1", "a2", "a21"}},
+		{"leaf", "a21", []string{"a21"}},
+		{"root with null parent", "other", []string{"other"}},
+		{"unknown menu", "missing", []string{"missing"}},
+		{"cycle terminates", "c1", []string{"c1", "c2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collectMenuSubtree(menus, tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collectMenuSubtree(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameMenuParent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"", "", true},
+		{"", "null", true},
+		{"null", "", true},
+		{"m1", "m1", true},
+		{"m1", "m2", false},
+		{"m1", "", false},
+	}
+	for _, tt := range tests {
+		if got := sameMenuParent(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameMenuParent(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
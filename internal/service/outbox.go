@@ -0,0 +1,133 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"time"
+)
+
+type OutboxService struct {
+	outboxDao *dao.OutboxDao
+}
+
+// NewOutboxService 创建一个新的 OutboxService 实例
+func NewOutboxService() *OutboxService {
+	return &OutboxService{
+		outboxDao: dao.NewOutboxDao(),
+	}
+}
+
+// List 获取发件箱记录列表（分页）及各投递状态的记录数量
+func (os *OutboxService) List(ctx context.Context, req *auth.ListOutboxRequest) (*auth.ListOutboxResponse, error) {
+	filters := map[string]interface{}{
+		entity.OutboxColumns.EventType: req.EventType,
+	}
+	if req.Status != nil {
+		filters[entity.OutboxColumns.Status] = *req.Status
+	}
+
+	rows, total, err := os.outboxDao.Query(ctx, req.Page, req.PageSize, filters)
+	if err != nil {
+		logger.Logger.Errorf("[GetOutboxList] Error fetching outbox rows: %v", err)
+		return nil, utils.NewBusinessError(utils.OutboxQueryFailedCode)
+	}
+
+	counts, err := os.outboxDao.CountByStatus(ctx)
+	if err != nil {
+		logger.Logger.Errorf("[GetOutboxList] Error counting outbox rows: %v", err)
+		return nil, utils.NewBusinessError(utils.OutboxQueryFailedCode)
+	}
+
+	// 转换数据格式为响应模型
+	list := make([]auth.OutboxInfo, 0, len(rows))
+	for _, row := range rows {
+		info := auth.OutboxInfo{
+			ID:            row.ID,
+			EventType:     row.EventType,
+			Payload:       row.Payload,
+			Status:        row.Status,
+			Attempts:      row.Attempts,
+			NextAttemptAt: row.NextAttemptAt.Format(time.RFC3339),
+			LastError:     row.LastError,
+			CreatedAt:     row.CreatedAt.Format(time.RFC3339),
+		}
+		if row.DeliveredAt != nil {
+			info.DeliveredAt = row.DeliveredAt.Format(time.RFC3339)
+		}
+		list = append(list, info)
+	}
+
+	return &auth.ListOutboxResponse{
+		Total: total,
+		Stats: auth.OutboxStats{
+			Pending:   counts[dao.OutboxStatusPending],
+			Delivered: counts[dao.OutboxStatusDelivered],
+			Dead:      counts[dao.OutboxStatusDead],
+		},
+		List: list,
+	}, nil
+}
+
+// Retry 将待投递或已进入死信的事件重新放回待投递队列，由投递任务在下次运行时立即投递
+func (os *OutboxService) Retry(ctx context.Context, req *auth.RetryOutboxRequest) error {
+	requeued, err := os.outboxDao.Requeue(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[RetryOutbox] Error requeuing outbox row %s: %v", req.ID, err)
+		return utils.NewBusinessError(utils.OutboxUpdateFailedCode)
+	}
+	if !requeued {
+		return utils.NewBusinessError(utils.OutboxNotFoundCode)
+	}
+	return nil
+}
+
+// PurgeExpired 永久删除超过保留时长的已投递事件和死信事件，待投递事件不会被删除
+// 返回:
+//   - int: 永久删除的事件数量
+func (os *OutboxService) PurgeExpired(ctx context.Context) (int, error) {
+	config := conf.GlobalConf.System.Outbox
+	now := time.Now()
+
+	count := 0
+	if config.Retention > 0 {
+		purged, err := purgeInBatches(ctx, config.BatchSize, func(limit int) (int64, error) {
+			return os.outboxDao.PurgeDeliveredBefore(ctx, now.Add(-config.Retention), limit)
+		})
+		count += purged
+		if err != nil {
+			logger.Logger.Errorf("[PurgeOutbox] Error purging delivered events: %v", err)
+			return count, err
+		}
+	}
+	if config.DeadRetention > 0 {
+		purged, err := purgeInBatches(ctx, config.BatchSize, func(limit int) (int64, error) {
+			return os.outboxDao.PurgeDeadBefore(ctx, now.Add(-config.DeadRetention), limit)
+		})
+		count += purged
+		if err != nil {
+			logger.Logger.Errorf("[PurgeOutbox] Error purging dead events: %v", err)
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// purgeInBatches 分批执行删除，直到某一批删除的数量不足 limit
+func purgeInBatches(ctx context.Context, limit int, purge func(limit int) (int64, error)) (int, error) {
+	count := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		purged, err := purge(limit)
+		count += int(purged)
+		if err != nil || purged == 0 || purged < int64(limit) {
+			return count, err
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// passwordHistory 密码历史校验与记录，管理员与业务用户共用
+type passwordHistory struct {
+	dao *dao.PasswordHistoryDao
+}
+
+// newPasswordHistory 创建 passwordHistory 实例
+func newPasswordHistory() passwordHistory {
+	return passwordHistory{dao: dao.NewPasswordHistoryDao()}
+}
+
+// check 校验新密码是否与当前密码或最近使用过的旧密码相同
+func (ph passwordHistory) check(ctx context.Context, subjectType, subjectID, currentHash, newPassword string) error {
+	depth := conf.GlobalConf.System.Security.PasswordPolicy.HistoryDepth
+	if depth <= 0 {
+		return nil
+	}
+
+	hashes, err := ph.dao.GetRecent(ctx, subjectType, subjectID, depth)
+	if err != nil {
+		logger.Logger.Errorf("[PasswordHistory] Error fetching password history of %s: %v", subjectID, err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+
+	return checkPasswordReuse(newPassword, append([]string{currentHash}, hashes...))
+}
+
+// checkPasswordReuse 校验新密码是否与任一加密密码相同
+func checkPasswordReuse(newPassword string, hashes []string) error {
+	for _, hash := range hashes {
+		isMatch, err := utils.VerifyPassword(newPassword, hash)
+		if err != nil {
+			logger.Logger.Errorf("[PasswordHistory] Error verifying password history: %v", err)
+			return utils.NewBusinessError(utils.InternalError)
+		}
+		if isMatch {
+			return utils.NewBusinessError(utils.PasswordReusedCode)
+		}
+	}
+	return nil
+}
+
+// recordTx 在事务中记录被替换掉的旧密码，并清理超出保留数量的历史
+func (ph passwordHistory) recordTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID, oldHash string) error {
+	depth := conf.GlobalConf.System.Security.PasswordPolicy.HistoryDepth
+	if depth <= 0 {
+		return nil
+	}
+
+	history := &entity.PasswordHistories{
+		ID:          uuid.New().String(),
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Password:    oldHash,
+		CreatedAt:   time.Now(),
+	}
+	if err := ph.dao.InsertTx(ctx, tx, history); err != nil {
+		return err
+	}
+	return ph.dao.PruneTx(ctx, tx, subjectType, subjectID, depth)
+}
+
+// removeTx 在事务中清除账号的全部历史密码
+func (ph passwordHistory) removeTx(ctx context.Context, tx *gorm.DB, subjectType, subjectID string) error {
+	return ph.dao.RemoveBySubjectTx(ctx, tx, subjectType, subjectID)
+}
+
+// passwordChangeRequired 判断账号登录后是否只能修改密码：被管理员重置过密码，或密码已过期
+// 从未修改过密码的账号以创建时间作为密码设置时间
+func passwordChangeRequired(mustChange int8, changedAt, createdAt time.Time) bool {
+	if mustChange == 1 {
+		return true
+	}
+
+	maxAge := conf.GlobalConf.System.Security.PasswordPolicy.MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+	if changedAt.IsZero() {
+		changedAt = createdAt
+	}
+	return time.Since(changedAt) > maxAge
+}
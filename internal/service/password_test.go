@@ -0,0 +1,80 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/utils"
+	"testing"
+	"time"
+)
+
+func TestPasswordChangeRequired(t *testing.T) {
+	previous := conf.GlobalConf
+	t.Cleanup(func() {
+		conf.GlobalConf = previous
+	})
+
+	now := time.Now()
+	tests := []struct {
+		name       string
+		maxAge     time.Duration
+		mustChange int8
+		changedAt  time.Time
+		createdAt  time.Time
+		want       bool
+	}{
+		{"reset by admin", 0, 1, now, now, true},
+		{"no expiry", 0, 0, now.Add(-10000 * time.Hour), now.Add(-10000 * time.Hour), false},
+		{"within max age", 90 * 24 * time.Hour, 0, now.Add(-24 * time.Hour), now.Add(-1000 * time.Hour), false},
+		{"expired", 90 * 24 * time.Hour, 0, now.Add(-91 * 24 * time.Hour), now.Add(-1000 * 24 * time.Hour), true},
+		{"never changed falls back to created at", 90 * 24 * time.Hour, 0, time.Time{}, now.Add(-91 * 24 * time.Hour), true},
+		{"never changed recently created", 90 * 24 * time.Hour, 0, time.Time{}, now.Add(-time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &conf.Server{}
+			server.System.Security.PasswordPolicy.MaxAge = tt.maxAge
+			conf.GlobalConf = server
+
+			if got := passwordChangeRequired(tt.mustChange, tt.changedAt, tt.createdAt); got != tt.want {
+				t.Errorf("passwordChangeRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPasswordReuse(t *testing.T) {
+	hash := func(password string) string {
+		hashed, err := utils.EncryptPassword(password)
+		if err != nil {
+			t.Fatalf("EncryptPassword() error = %v", err)
+		}
+		return hashed
+	}
+	history := []string{hash("Current#Pass1"), hash("Older#Pass2"), hash("Oldest#Pass3")}
+
+	tests := []struct {
+		name     string
+		password string
+		hashes   []string
+		wantCode int
+	}{
+		{"new password", "Brand#New4", history, utils.Success},
+		{"same as current", "Current#Pass1", history, utils.PasswordReusedCode},
+		{"same as history", "Oldest#Pass3", history, utils.PasswordReusedCode},
+		{"no history", "Current#Pass1", nil, utils.Success},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPasswordReuse(tt.password, tt.hashes)
+			code := utils.Success
+			if businessErr, ok := err.(*utils.BusinessError); ok {
+				code = businessErr.Code
+			} else if err != nil {
+				t.Fatalf("checkPasswordReuse() error = %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("checkPasswordReuse() code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
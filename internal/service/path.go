@@ -0,0 +1,390 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultSyncPrefix 路由同步默认纳入接口管理的前缀，即需要登录的接口
+const defaultSyncPrefix = "/v1/auth/"
+
+// syncMethods 接口表支持的 HTTP 方法
+var syncMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+
+type PathService struct {
+	pathDao           *dao.PathDao
+	menuDao           *dao.MenuDao
+	rolePathDao       *dao.RolePathDao
+	userPermissionDao *dao.UserPermissionDao
+	policyCache       policyCache
+	permissionCache   permissionCache
+	routes            func() gin.RoutesInfo
+}
+
+// NewPathService 创建一个新的 PathService 实例
+// 参数:
+//   - routes: 返回 gin.Engine 上已注册路由的函数（通常为 engine.Routes），用于路由同步
+func NewPathService(routes func() gin.RoutesInfo) *PathService {
+	return &PathService{
+		pathDao:           dao.NewPathDao(),
+		menuDao:           dao.NewMenuDao(),
+		rolePathDao:       dao.NewRolePathDao(),
+		userPermissionDao: dao.NewUserPermissionDao(),
+		policyCache:       newPolicyCache(),
+		permissionCache:   newPermissionCache(),
+		routes:            routes,
+	}
+}
+
+// List 获取接口列表（分页）
+func (ps *PathService) List(ctx context.Context, req *auth.ListPathRequest) (*auth.ListPathResponse, error) {
+	filters := map[string]interface{}{
+		entity.PathsColumns.MenuID: req.MenuID,
+		entity.PathsColumns.Path:   req.Path,
+		entity.PathsColumns.Method: req.Method,
+	}
+	if req.Stale != nil {
+		filters[entity.PathsColumns.Stale] = *req.Stale
+	}
+
+	paths, total, err := ps.pathDao.Query(ctx, req.Page, req.PageSize, filters)
+	if err != nil {
+		logger.Logger.Errorf("[ListPath] Error querying paths: %v", err)
+		return nil, utils.NewBusinessError(utils.PathQueryFailedCode)
+	}
+
+	pathList := make([]auth.PathDetail, 0, len(paths))
+	for _, path := range paths {
+		pathList = append(pathList, auth.PathDetail{
+			ID:          path.ID,
+			MenuID:      path.MenuID,
+			Path:        path.Path,
+			Method:      path.Method,
+			Description: path.Description,
+			Stale:       path.Stale == 1,
+			CreatedAt:   path.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:   path.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &auth.ListPathResponse{
+		Total: total,
+		List:  pathList,
+	}, nil
+}
+
+// Add 新增接口
+func (ps *PathService) Add(ctx context.Context, req *auth.AddPathRequest) error {
+	err := menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ps.checkPathConflictTx(ctx, tx, req.MenuID, req.Path, req.Method, ""); err != nil {
+			return err
+		}
+
+		path := &entity.Paths{
+			ID:          uuid.New().String(),
+			Path:        req.Path,
+			Method:      req.Method,
+			Description: req.Description,
+			MenuID:      req.MenuID,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err := ps.pathDao.InsertTx(ctx, tx, path); err != nil {
+			logger.Logger.Errorf("[AddPath] Error inserting path: %v", err)
+			return err
+		}
+		return nil
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		return utils.NewBusinessError(utils.PathInsertFailedCode)
+	}
+
+	return nil
+}
+
+// Edit 编辑接口，角色对该接口的授权保持不变
+func (ps *PathService) Edit(ctx context.Context, req *auth.EditPathRequest) error {
+	if _, err := ps.checkPathExistence(ctx, req.ID); err != nil {
+		return err
+	}
+
+	var userIDs []string
+	err := menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		if err := ps.checkPathConflictTx(ctx, tx, req.MenuID, req.Path, req.Method, req.ID); err != nil {
+			return err
+		}
+
+		// 路由或请求方法变化后，拥有该接口权限的用户的权限缓存需要失效
+		var err error
+		if userIDs, err = ps.userPermissionDao.GetUserIDsByPathIDsTx(ctx, tx, []string{req.ID}); err != nil {
+			logger.Logger.Errorf("[EditPath] Error fetching users of path: %v", err)
+			return err
+		}
+
+		updates := map[string]interface{}{
+			entity.PathsColumns.MenuID:      req.MenuID,
+			entity.PathsColumns.Path:        req.Path,
+			entity.PathsColumns.Method:      req.Method,
+			entity.PathsColumns.Description: req.Description,
+			entity.PathsColumns.UpdatedAt:   time.Now(),
+		}
+		if err = ps.pathDao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+			logger.Logger.Errorf("[EditPath] Error updating path: %v", err)
+			return err
+		}
+		return nil
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		return utils.NewBusinessError(utils.PathUpdateFailedCode)
+	}
+
+	// 权限决策缓存按请求方法和路由缓存，接口变更后全部失效
+	ps.permissionCache.invalidateUsers(ctx, userIDs...)
+	ps.policyCache.invalidateAll(ctx)
+
+	return nil
+}
+
+// Delete 删除接口，同时收回角色对该接口的授权并清理用户权限预计算表
+func (ps *PathService) Delete(ctx context.Context, req *auth.DelPathRequest) error {
+	if _, err := ps.checkPathExistence(ctx, req.ID); err != nil {
+		return err
+	}
+
+	userIDs, err := ps.userPermissionDao.GetUserIDsByPathIDs(ctx, []string{req.ID})
+	if err != nil {
+		logger.Logger.Errorf("[DeletePath] Error fetching users of path: %v", err)
+		return utils.NewBusinessError(utils.PathDeleteFailedCode)
+	}
+
+	if err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		pathIDs := []string{req.ID}
+		if err := ps.rolePathDao.RemoveByPathIDsTx(ctx, tx, pathIDs); err != nil {
+			logger.Logger.Errorf("[DeletePath] Error removing role paths: %v", err)
+			return err
+		}
+		if err := ps.userPermissionDao.RemoveByPathIDsTx(ctx, tx, pathIDs); err != nil {
+			logger.Logger.Errorf("[DeletePath] Error removing user permissions: %v", err)
+			return err
+		}
+		if err := ps.pathDao.SoftDeleteTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[DeletePath] Error soft deleting path: %v", err)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return utils.NewBusinessError(utils.PathDeleteFailedCode)
+	}
+
+	ps.permissionCache.invalidateUsers(ctx, userIDs...)
+	ps.policyCache.invalidateAll(ctx)
+
+	return nil
+}
+
+// Sync 将 gin.Engine 上已注册的路由同步到接口表
+// 指定前缀下已注册但不存在的路由新增到 MenuID 指定的菜单下；接口表中已不再注册的路由标记为失效，
+// 重新注册的失效路由清除失效标记。DryRun 为 true 时只返回差异报告。
+func (ps *PathService) Sync(ctx context.Context, req *auth.SyncPathRequest) (*auth.SyncPathResponse, error) {
+	prefix := req.Prefix
+	if prefix == "" {
+		prefix = defaultSyncPrefix
+	}
+
+	// 收集全部已注册的路由，以及其中需要纳入接口管理的路由
+	registered := make(map[string]struct{})
+	var managed []auth.SyncPathItem
+	for _, route := range ps.routes() {
+		if !utils.Contains(syncMethods, route.Method) {
+			continue
+		}
+		key := pathKey(route.Method, route.Path)
+		if _, ok := registered[key]; ok {
+			continue
+		}
+		registered[key] = struct{}{}
+		if strings.HasPrefix(route.Path, prefix) {
+			managed = append(managed, auth.SyncPathItem{Path: route.Path, Method: route.Method})
+		}
+	}
+
+	paths, err := ps.pathDao.GetAll(ctx)
+	if err != nil {
+		logger.Logger.Errorf("[SyncPath] Error fetching paths: %v", err)
+		return nil, utils.NewBusinessError(utils.PathQueryFailedCode)
+	}
+
+	// 比较差异
+	res := &auth.SyncPathResponse{
+		DryRun:   req.DryRun,
+		Added:    []auth.SyncPathItem{},
+		Stale:    []auth.SyncPathItem{},
+		Restored: []auth.SyncPathItem{},
+	}
+	existing := make(map[string]struct{}, len(paths))
+	var staleIDs, restoredIDs []string
+	for _, path := range paths {
+		existing[pathKey(path.Method, path.Path)] = struct{}{}
+		item := auth.SyncPathItem{ID: path.ID, Path: path.Path, Method: path.Method}
+		_, alive := registered[pathKey(path.Method, path.Path)]
+		switch {
+		case !alive && path.Stale == 0:
+			res.Stale = append(res.Stale, item)
+			staleIDs = append(staleIDs, path.ID)
+		case alive && path.Stale == 1:
+			res.Restored = append(res.Restored, item)
+			restoredIDs = append(restoredIDs, path.ID)
+		default:
+			res.Unchanged++
+		}
+	}
+	var added []*entity.Paths
+	for _, item := range managed {
+		if _, ok := existing[pathKey(item.Method, item.Path)]; ok {
+			continue
+		}
+		path := &entity.Paths{
+			ID:        uuid.New().String(),
+			Path:      item.Path,
+			Method:    item.Method,
+			MenuID:    req.MenuID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if !req.DryRun {
+			item.ID = path.ID
+		}
+		added = append(added, path)
+		res.Added = append(res.Added, item)
+	}
+	sortSyncItems(res.Added)
+	sortSyncItems(res.Stale)
+	sortSyncItems(res.Restored)
+
+	if req.DryRun {
+		return res, nil
+	}
+
+	if len(added) > 0 && req.MenuID == "" {
+		return nil, utils.NewBusinessError(utils.PathSyncMenuRequiredCode)
+	}
+
+	// 标记失效或恢复的接口改变了持有这些接口的用户的有效权限
+	changedIDs := append(append([]string{}, staleIDs...), restoredIDs...)
+	userIDs, err := ps.userPermissionDao.GetUserIDsByPathIDs(ctx, changedIDs)
+	if err != nil {
+		logger.Logger.Errorf("[SyncPath] Error fetching users of paths: %v", err)
+		return nil, utils.NewBusinessError(utils.PathSyncFailedCode)
+	}
+
+	err = menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		// 新增接口需要挂载到已存在的菜单下
+		if len(added) > 0 {
+			menu, err := ps.menuDao.GetByIDTx(ctx, tx, req.MenuID)
+			if err != nil {
+				logger.Logger.Errorf("[SyncPath] Error fetching menu: %v", err)
+				return utils.NewBusinessError(utils.MenuQueryFailedCode)
+			}
+			if menu == nil {
+				return utils.NewBusinessError(utils.MenuNotFoundCode)
+			}
+			if err = ps.pathDao.InsertBatchTx(ctx, tx, added); err != nil {
+				logger.Logger.Errorf("[SyncPath] Error inserting paths: %v", err)
+				return err
+			}
+		}
+		if err := ps.pathDao.UpdateStaleTx(ctx, tx, staleIDs, 1); err != nil {
+			logger.Logger.Errorf("[SyncPath] Error marking stale paths: %v", err)
+			return err
+		}
+		if err := ps.pathDao.UpdateStaleTx(ctx, tx, restoredIDs, 0); err != nil {
+			logger.Logger.Errorf("[SyncPath] Error restoring paths: %v", err)
+			return err
+		}
+		return nil
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return nil, err
+	}
+	if err != nil {
+		return nil, utils.NewBusinessError(utils.PathSyncFailedCode)
+	}
+
+	if len(changedIDs) > 0 {
+		ps.permissionCache.invalidateUsers(ctx, userIDs...)
+		ps.policyCache.invalidateAll(ctx)
+	}
+
+	logger.Logger.Infof("[SyncPath] Paths synced: %d added, %d stale, %d restored",
+		len(res.Added), len(res.Stale), len(res.Restored))
+	return res, nil
+}
+
+// checkPathExistence 检查接口是否存在
+func (ps *PathService) checkPathExistence(ctx context.Context, id string) (*entity.Paths, error) {
+	path, err := ps.pathDao.GetByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPathExistence] Error fetching path: %v", err)
+		return nil, utils.NewBusinessError(utils.PathQueryFailedCode)
+	}
+	if path == nil {
+		return nil, utils.NewBusinessError(utils.PathNotFoundCode)
+	}
+	return path, nil
+}
+
+// checkPathConflictTx 在事务中检查所属菜单是否存在，以及相同请求方法的路由是否已被其他接口登记
+func (ps *PathService) checkPathConflictTx(ctx context.Context, tx *gorm.DB, menuID, path, method, excludeID string) error {
+	menu, err := ps.menuDao.GetByIDTx(ctx, tx, menuID)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPathConflict] Error fetching menu: %v", err)
+		return utils.NewBusinessError(utils.MenuQueryFailedCode)
+	}
+	if menu == nil {
+		return utils.NewBusinessError(utils.MenuNotFoundCode)
+	}
+
+	conflictingPath, err := ps.pathDao.GetByPathMethodTx(ctx, tx, path, method)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPathConflict] Error fetching path: %v", err)
+		return utils.NewBusinessError(utils.PathQueryFailedCode)
+	}
+	if conflictingPath != nil && conflictingPath.ID != excludeID {
+		return utils.NewBusinessError(utils.PathAlreadyExistsCode)
+	}
+	return nil
+}
+
+// pathKey 接口的唯一键：请求方法 + 路由模板
+func pathKey(method, path string) string {
+	return method + " " + path
+}
+
+// sortSyncItems 按路由路径与请求方法排序，保证差异报告稳定
+func sortSyncItems(items []auth.SyncPathItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Path != items[j].Path {
+			return items[i].Path < items[j].Path
+		}
+		return items[i].Method < items[j].Method
+	})
+}
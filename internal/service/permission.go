@@ -0,0 +1,222 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"context"
+)
+
+// permissionWarmBatchSize 预热权限缓存时每批处理的用户数量
+const permissionWarmBatchSize = 500
+
+type PermissionService struct {
+	userPermissionDao *dao.UserPermissionDao
+	adminRoleDao      *dao.AdminRoleDao
+}
+
+// NewPermissionService 创建一个新的 PermissionService 实例
+func NewPermissionService() *PermissionService {
+	return &PermissionService{
+		userPermissionDao: dao.NewUserPermissionDao(),
+		adminRoleDao:      dao.NewAdminRoleDao(),
+	}
+}
+
+// CheckPermission 校验调用方是否拥有指定接口的访问权限
+// 参数:
+//   - subjectType: JWT 中解析出的主体类型（admin、user）
+//   - subjectID: JWT 中解析出的调用方 ID
+//   - method: HTTP 请求方法
+//   - path: gin 路由模板（例如 /v1/auth/user），与 paths 表中的 path 字段对应
+//
+// 注意: 管理员按所属角色及其祖先角色的 role_paths 判断，内置超级管理员角色拥有全部权限；普通用户按 user_permissions 预计算表判断。
+// 两者的有效接口集合都缓存在 Redis 中，Redis 不可用时直接查询数据库。
+func (ps *PermissionService) CheckPermission(ctx context.Context, subjectType, subjectID, method, path string) (bool, error) {
+	if subjectType == utils.SubjectTypeAdmin {
+		return ps.checkAdminPermission(ctx, subjectID, method, path)
+	}
+
+	// 优先读取缓存；回源前记录版本号，保证回源期间权限被重新计算时不会写回旧数据
+	member := redis.PermissionMember(method, path)
+	version, err := redis.GetPermissionVersion(ctx)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error reading permission version: %v", err)
+		return ps.checkUserPermission(ctx, subjectID, method, path)
+	}
+	cached, allowed, err := redis.CheckCachedPermission(ctx, subjectID, member)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error reading permission cache: %v", err)
+		return ps.checkUserPermission(ctx, subjectID, method, path)
+	}
+	if cached {
+		return allowed, nil
+	}
+
+	userPaths, err := ps.userPermissionDao.GetPermissionPathsByUserIDs(ctx, []string{subjectID})
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error checking user permission: %v", err)
+		return false, err
+	}
+	members := permissionMembers(userPaths[subjectID])
+	if _, err = redis.SavePermissions(ctx, version, subjectID, members, conf.GlobalConf.System.Permission.CacheTTL); err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error saving permission cache: %v", err)
+	}
+
+	for _, m := range members {
+		if m == member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkAdminPermission 校验管理员是否拥有指定接口的访问权限
+// 管理员的有效接口集合按决策缓存版本号缓存，角色、接口或继承关系变更后随版本号递增失效，
+// 避免每次请求都读取全部角色继承关系。
+func (ps *PermissionService) checkAdminPermission(ctx context.Context, adminID, method, path string) (bool, error) {
+	// 回源前记录版本号和代数，保证回源期间授权变更时不会写回旧数据
+	member := redis.PermissionMember(method, path)
+	version, err := redis.GetPolicyVersion(ctx)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error reading policy version: %v", err)
+		return ps.queryAdminPermission(ctx, adminID, method, path)
+	}
+	generation, err := redis.GetAdminPermissionGeneration(ctx, adminID)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error reading admin permission generation: %v", err)
+		return ps.queryAdminPermission(ctx, adminID, method, path)
+	}
+	cached, allowed, err := redis.CheckCachedAdminPermission(ctx, version, adminID, member)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error reading admin permission cache: %v", err)
+		return ps.queryAdminPermission(ctx, adminID, method, path)
+	}
+	if cached {
+		return allowed, nil
+	}
+
+	builtin, paths, err := ps.adminRoleDao.GetPermissionPaths(ctx, adminID)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error checking admin permission: %v", err)
+		return false, err
+	}
+	members := []string{redis.AdminPermissionAll}
+	if !builtin {
+		members = permissionMembers(paths)
+	}
+	if _, err = redis.SaveAdminPermissions(ctx, version, generation, adminID, members, conf.GlobalConf.System.Permission.CacheTTL); err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error saving admin permission cache: %v", err)
+	}
+
+	if builtin {
+		return true, nil
+	}
+	for _, m := range members {
+		if m == member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// queryAdminPermission 直接查询数据库判断管理员权限
+func (ps *PermissionService) queryAdminPermission(ctx context.Context, adminID, method, path string) (bool, error) {
+	allowed, err := ps.adminRoleDao.HasPermission(ctx, adminID, method, path)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error checking admin permission: %v", err)
+		return false, err
+	}
+	return allowed, nil
+}
+
+// checkUserPermission 直接按 user_permissions 预计算表判断用户权限
+func (ps *PermissionService) checkUserPermission(ctx context.Context, userID, method, path string) (bool, error) {
+	allowed, err := ps.userPermissionDao.HasPermission(ctx, userID, method, path)
+	if err != nil {
+		logger.Logger.Errorf("[CheckPermission] Error checking user permission: %v", err)
+		return false, err
+	}
+	return allowed, nil
+}
+
+// WarmCache 预热全部拥有权限的用户的权限缓存，服务启动时调用
+// 返回:
+//   - int: 写入缓存的用户数量
+func (ps *PermissionService) WarmCache(ctx context.Context) (int, error) {
+	total := 0
+	afterUserID := ""
+	for {
+		version, err := redis.GetPermissionVersion(ctx)
+		if err != nil {
+			logger.Logger.Errorf("[WarmPermissionCache] Error reading permission version: %v", err)
+			return total, err
+		}
+
+		userIDs, err := ps.userPermissionDao.GetUserIDsAfter(ctx, afterUserID, permissionWarmBatchSize)
+		if err != nil {
+			logger.Logger.Errorf("[WarmPermissionCache] Error fetching user IDs: %v", err)
+			return total, err
+		}
+		if len(userIDs) == 0 {
+			return total, nil
+		}
+		afterUserID = userIDs[len(userIDs)-1]
+
+		userPaths, err := ps.userPermissionDao.GetPermissionPathsByUserIDs(ctx, userIDs)
+		if err != nil {
+			logger.Logger.Errorf("[WarmPermissionCache] Error fetching user permissions: %v", err)
+			return total, err
+		}
+		for _, userID := range userIDs {
+			saved, err := redis.SavePermissions(ctx, version, userID, permissionMembers(userPaths[userID]), conf.GlobalConf.System.Permission.CacheTTL)
+			if err != nil {
+				logger.Logger.Errorf("[WarmPermissionCache] Error saving permission cache: %v", err)
+				return total, err
+			}
+			if saved {
+				total++
+			}
+		}
+
+		if len(userIDs) < permissionWarmBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// permissionMembers 将接口列表转换为权限缓存集合的成员
+func permissionMembers(paths []*entity.Paths) []string {
+	members := make([]string, 0, len(paths))
+	for _, path := range paths {
+		members = append(members, redis.PermissionMember(path.Method, path.Path))
+	}
+	return members
+}
+
+// permissionCache 用户权限缓存的失效操作
+// 重新计算用户权限的事务提交后调用；失效失败只记录日志，缓存会在过期后自动更新。
+type permissionCache struct{}
+
+// newPermissionCache 创建 permissionCache 实例
+func newPermissionCache() permissionCache {
+	return permissionCache{}
+}
+
+// invalidateUsers 递增全局权限版本号并使指定用户的权限缓存失效
+func (pc permissionCache) invalidateUsers(ctx context.Context, userIDs ...string) {
+	if err := redis.InvalidatePermissions(ctx, userIDs); err != nil {
+		logger.Logger.Errorf("[PermissionCache] Error invalidating user permissions: %v", err)
+	}
+}
+
+// invalidateAdmins 使指定管理员的权限缓存失效，调整管理员角色的事务提交后调用
+// 角色、接口和继承关系的变更通过 policyCache.invalidateAll 递增决策缓存版本号，同时使全部管理员的权限缓存失效。
+func (pc permissionCache) invalidateAdmins(ctx context.Context, adminIDs ...string) {
+	if err := redis.InvalidateAdminPermissions(ctx, adminIDs, conf.GlobalConf.System.Permission.CacheTTL); err != nil {
+		logger.Logger.Errorf("[PermissionCache] Error invalidating admin permissions: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type PolicyService struct {
+	userDao           *dao.UserDao
+	userRoleDao       *dao.UserRoleDao
+	rolePathDao       *dao.RolePathDao
+	roleParentDao     *dao.RoleParentDao
+	userPermissionDao *dao.UserPermissionDao
+}
+
+// NewPolicyService 创建一个新的 PolicyService 实例
+func NewPolicyService() *PolicyService {
+	return &PolicyService{
+		userDao:           dao.NewUserDao(),
+		userRoleDao:       dao.NewUserRoleDao(),
+		rolePathDao:       dao.NewRolePathDao(),
+		roleParentDao:     dao.NewRoleParentDao(),
+		userPermissionDao: dao.NewUserPermissionDao(),
+	}
+}
+
+// Decide 判断用户是否可以访问指定接口
+func (ps *PolicyService) Decide(ctx context.Context, req *auth.DecidePolicyRequest) (*auth.PolicyDecision, error) {
+	decisions, err := ps.decide(ctx, []auth.PolicyCheck{req.PolicyCheck})
+	if err != nil {
+		return nil, err
+	}
+	return &decisions[0], nil
+}
+
+// BatchDecide 批量判断用户是否可以访问指定接口，结果顺序与请求一致
+func (ps *PolicyService) BatchDecide(ctx context.Context, req *auth.BatchDecidePolicyRequest) (*auth.BatchDecidePolicyResponse, error) {
+	decisions, err := ps.decide(ctx, req.CheckList)
+	if err != nil {
+		return nil, err
+	}
+	return &auth.BatchDecidePolicyResponse{List: decisions}, nil
+}
+
+// decide 按用户分组计算决策，优先读取 Redis 缓存，未命中的决策计算后写回缓存
+// 注意: Redis 不可用时直接查询数据库，不影响决策结果。
+func (ps *PolicyService) decide(ctx context.Context, checks []auth.PolicyCheck) ([]auth.PolicyDecision, error) {
+	version, err := redis.GetPolicyVersion(ctx)
+	if err != nil {
+		logger.Logger.Errorf("[DecidePolicy] Error reading policy cache version: %v", err)
+	}
+	useCache := err == nil
+
+	// 按用户分组，保留每个决策在请求中的位置
+	userIndexes := make(map[string][]int)
+	var userIDs []string
+	for i, check := range checks {
+		if _, ok := userIndexes[check.UserID]; !ok {
+			userIDs = append(userIDs, check.UserID)
+		}
+		userIndexes[check.UserID] = append(userIndexes[check.UserID], i)
+	}
+
+	var hierarchy dao.RoleHierarchy
+	decisions := make([]auth.PolicyDecision, len(checks))
+	for _, userID := range userIDs {
+		indexes := userIndexes[userID]
+
+		// 读取缓存
+		hits := map[string]string{}
+		if useCache {
+			fields := make([]string, 0, len(indexes))
+			for _, i := range indexes {
+				fields = append(fields, policyCacheField(checks[i]))
+			}
+			if hits, err = redis.GetPolicyDecisions(ctx, version, userID, fields); err != nil {
+				logger.Logger.Errorf("[DecidePolicy] Error reading policy cache: %v", err)
+				hits = map[string]string{}
+			}
+		}
+
+		var misses []int
+		for _, i := range indexes {
+			if value, ok := hits[policyCacheField(checks[i])]; ok && json.Unmarshal([]byte(value), &decisions[i]) == nil {
+				continue
+			}
+			misses = append(misses, i)
+		}
+		if len(misses) == 0 {
+			continue
+		}
+
+		// 计算未命中的决策
+		if hierarchy == nil {
+			if hierarchy, err = ps.roleParentDao.GetHierarchy(ctx); err != nil {
+				logger.Logger.Errorf("[DecidePolicy] Error fetching role hierarchy: %v", err)
+				return nil, utils.NewBusinessError(utils.PolicyDecisionFailedCode)
+			}
+		}
+		// 计算决策前读取用户的缓存代数，计算期间授权发生变更时不写回缓存
+		generation := ""
+		if useCache {
+			if generation, err = redis.GetPolicyGeneration(ctx, userID); err != nil {
+				logger.Logger.Errorf("[DecidePolicy] Error reading policy cache generation: %v", err)
+			}
+		}
+		values := make(map[string]string, len(misses))
+		if err = ps.decideUser(ctx, userID, hierarchy, checks, misses, decisions); err != nil {
+			return nil, err
+		}
+		for _, i := range misses {
+			if value, err := json.Marshal(decisions[i]); err == nil {
+				values[policyCacheField(checks[i])] = string(value)
+			}
+		}
+
+		// 写回缓存
+		if useCache && generation != "" {
+			if _, err = redis.SavePolicyDecisions(ctx, version, generation, userID, values, conf.GlobalConf.System.Policy.CacheTTL); err != nil {
+				logger.Logger.Errorf("[DecidePolicy] Error saving policy cache: %v", err)
+			}
+		}
+	}
+
+	return decisions, nil
+}
+
+// decideUser 计算同一用户的多个决策，结果写入 decisions 的对应位置
+// 是否允许以 user_permissions 预计算表为准，授权角色按 role_paths 查询，包括通过继承获得权限的角色。
+func (ps *PolicyService) decideUser(ctx context.Context, userID string, hierarchy dao.RoleHierarchy, checks []auth.PolicyCheck, indexes []int, decisions []auth.PolicyDecision) error {
+	reason := ""
+	user, err := ps.userDao.GetByID(ctx, userID)
+	if err != nil {
+		logger.Logger.Errorf("[DecidePolicy] Error fetching user: %v", err)
+		return utils.NewBusinessError(utils.PolicyDecisionFailedCode)
+	}
+	switch {
+	case user == nil:
+		reason = auth.PolicyReasonUserNotFound
+	case user.Status != 1:
+		reason = auth.PolicyReasonUserDisabled
+	}
+
+	var roleIDs []string
+	if reason == "" {
+		if roleIDs, err = ps.userRoleDao.GetValidRoleIDs(ctx, userID, time.Now()); err != nil {
+			logger.Logger.Errorf("[DecidePolicy] Error fetching user roles: %v", err)
+			return utils.NewBusinessError(utils.PolicyDecisionFailedCode)
+		}
+		roleIDs = hierarchy.Expand(roleIDs)
+	}
+
+	for _, i := range indexes {
+		check := checks[i]
+		decision := auth.PolicyDecision{
+			UserID:   check.UserID,
+			Method:   check.Method,
+			Path:     check.Path,
+			Reason:   reason,
+			RoleList: []auth.TrimRoleInfo{},
+		}
+		if reason == "" {
+			if decision.Allowed, err = ps.userPermissionDao.HasPermission(ctx, userID, check.Method, check.Path); err != nil {
+				logger.Logger.Errorf("[DecidePolicy] Error checking user permission: %v", err)
+				return utils.NewBusinessError(utils.PolicyDecisionFailedCode)
+			}
+			decision.Reason = auth.PolicyReasonNoPermission
+		}
+		if decision.Allowed {
+			decision.Reason = auth.PolicyReasonGranted
+			roles, err := ps.rolePathDao.GetRolesByPath(ctx, roleIDs, check.Method, check.Path)
+			if err != nil {
+				logger.Logger.Errorf("[DecidePolicy] Error fetching granting roles: %v", err)
+				return utils.NewBusinessError(utils.PolicyDecisionFailedCode)
+			}
+			for _, role := range roles {
+				decision.RoleList = append(decision.RoleList, auth.TrimRoleInfo{ID: role.ID, Name: role.Name})
+			}
+		}
+		decisions[i] = decision
+	}
+	return nil
+}
+
+// policyCacheField 决策在用户缓存 hash 中的字段名
+func policyCacheField(check auth.PolicyCheck) string {
+	return check.Method + " " + check.Path
+}
+
+// policyCache 权限决策缓存的失效操作
+// 授权变更在事务提交后调用；失效失败只记录日志，缓存会在过期后自动更新。
+type policyCache struct{}
+
+// newPolicyCache 创建 policyCache 实例
+func newPolicyCache() policyCache {
+	return policyCache{}
+}
+
+// invalidateUsers 使指定用户的决策缓存失效，失效前已开始计算的决策也不会写回缓存
+func (pc policyCache) invalidateUsers(ctx context.Context, userIDs ...string) {
+	if err := redis.InvalidatePolicyDecisions(ctx, userIDs, conf.GlobalConf.System.Policy.CacheTTL); err != nil {
+		logger.Logger.Errorf("[PolicyCache] Error invalidating user decisions: %v", err)
+	}
+}
+
+// invalidateAll 使全部用户的决策缓存失效，用于角色或接口变更等影响范围较大的场景
+func (pc policyCache) invalidateAll(ctx context.Context) {
+	if err := redis.BumpPolicyVersion(ctx); err != nil {
+		logger.Logger.Errorf("[PolicyCache] Error invalidating all decisions: %v", err)
+	}
+}
@@ -0,0 +1,750 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/event"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 回收站记录类型
+const (
+	RecycleBinTypeUser  = "user"  // 业务用户
+	RecycleBinTypeAdmin = "admin" // 管理员
+	RecycleBinTypeRole  = "role"  // 角色
+	RecycleBinTypeMenu  = "menu"  // 菜单
+	RecycleBinTypePath  = "path"  // 接口
+)
+
+// recycleBinPurgeOrder 定时清除时各类记录的处理顺序，接口先于菜单，使到期的菜单在同一次检查中即可清除
+var recycleBinPurgeOrder = []string{
+	RecycleBinTypeUser,
+	RecycleBinTypeAdmin,
+	RecycleBinTypeRole,
+	RecycleBinTypePath,
+	RecycleBinTypeMenu,
+}
+
+// deletedSnapshot 删除审计日志中记录的关联数据，恢复时据此重建用户角色、管理员角色、角色路径和继承关系
+type deletedSnapshot struct {
+	RoleIDs         []string                 `json:"roleIds"`
+	RoleAssignments []roleAssignmentSnapshot `json:"roleAssignments"`
+	PathIDs         []string                 `json:"pathIds"`
+	ParentIDs       []string                 `json:"parentIds"`
+}
+
+type RecycleBinService struct {
+	userDao            *dao.UserDao
+	adminDao           *dao.AdminDao
+	roleDao            *dao.RoleDao
+	menuDao            *dao.MenuDao
+	pathDao            *dao.PathDao
+	userRoleDao        *dao.UserRoleDao
+	adminRoleDao       *dao.AdminRoleDao
+	rolePathDao        *dao.RolePathDao
+	roleParentDao      *dao.RoleParentDao
+	userPermissionDao  *dao.UserPermissionDao
+	passwordHistoryDao *dao.PasswordHistoryDao
+	recoveryCodeDao    *dao.AdminRecoveryCodeDao
+	auditLogDao        *dao.AuditLogDao
+	auditTrail         auditTrail
+	policyCache        policyCache
+	permissionCache    permissionCache
+}
+
+// NewRecycleBinService 创建一个新的 RecycleBinService 实例
+func NewRecycleBinService() *RecycleBinService {
+	return &RecycleBinService{
+		userDao:            dao.NewUserDao(),
+		adminDao:           dao.NewAdminDao(),
+		roleDao:            dao.NewRoleDao(),
+		menuDao:            dao.NewMenuDao(),
+		pathDao:            dao.NewPathDao(),
+		userRoleDao:        dao.NewUserRoleDao(),
+		adminRoleDao:       dao.NewAdminRoleDao(),
+		rolePathDao:        dao.NewRolePathDao(),
+		roleParentDao:      dao.NewRoleParentDao(),
+		userPermissionDao:  dao.NewUserPermissionDao(),
+		passwordHistoryDao: dao.NewPasswordHistoryDao(),
+		recoveryCodeDao:    dao.NewAdminRecoveryCodeDao(),
+		auditLogDao:        dao.NewAuditLogDao(),
+		auditTrail:         newAuditTrail(),
+		policyCache:        newPolicyCache(),
+		permissionCache:    newPermissionCache(),
+	}
+}
+
+// List 分页查询回收站中指定类型的记录，按删除时间倒序
+func (rbs *RecycleBinService) List(ctx context.Context, req *auth.ListRecycleBinRequest) (*auth.ListRecycleBinResponse, error) {
+	var (
+		list  []auth.RecycleBinItem
+		total int64
+		err   error
+	)
+	appendItem := func(id, name string, deletedAt time.Time) {
+		list = append(list, recycleBinItem(req.Type, id, name, deletedAt))
+	}
+
+	switch req.Type {
+	case RecycleBinTypeUser:
+		var users []*entity.Users
+		if users, total, err = rbs.userDao.QueryDeleted(ctx, req.Page, req.PageSize); err == nil {
+			for _, user := range users {
+				appendItem(user.ID, user.Username, user.DeletedAt)
+			}
+		}
+	case RecycleBinTypeAdmin:
+		var admins []*entity.Admins
+		if admins, total, err = rbs.adminDao.QueryDeleted(ctx, req.Page, req.PageSize); err == nil {
+			for _, admin := range admins {
+				appendItem(admin.ID, admin.Username, admin.DeletedAt)
+			}
+		}
+	case RecycleBinTypeRole:
+		var roles []*entity.Roles
+		if roles, total, err = rbs.roleDao.QueryDeleted(ctx, req.Page, req.PageSize); err == nil {
+			for _, role := range roles {
+				appendItem(role.ID, role.Name, role.DeletedAt)
+			}
+		}
+	case RecycleBinTypeMenu:
+		var menus []*entity.Menus
+		if menus, total, err = rbs.menuDao.QueryDeleted(ctx, req.Page, req.PageSize); err == nil {
+			for _, menu := range menus {
+				appendItem(menu.ID, menu.Name, menu.DeletedAt)
+			}
+		}
+	case RecycleBinTypePath:
+		var paths []*entity.Paths
+		if paths, total, err = rbs.pathDao.QueryDeleted(ctx, req.Page, req.PageSize); err == nil {
+			for _, path := range paths {
+				appendItem(path.ID, path.Method+" "+path.Path, path.DeletedAt)
+			}
+		}
+	}
+	if err != nil {
+		logger.Logger.Errorf("[ListRecycleBin] Error fetching deleted %ss: %v", req.Type, err)
+		return nil, utils.NewBusinessError(utils.RecycleBinQueryFailedCode)
+	}
+
+	if list == nil {
+		list = []auth.RecycleBinItem{}
+	}
+	return &auth.ListRecycleBinResponse{
+		Total: total,
+		List:  list,
+	}, nil
+}
+
+// recycleBinItem 构建回收站记录，启用自动清除时附带到期时间
+func recycleBinItem(recordType, id, name string, deletedAt time.Time) auth.RecycleBinItem {
+	item := auth.RecycleBinItem{
+		ID:        id,
+		Type:      recordType,
+		Name:      name,
+		DeletedAt: deletedAt.Format(time.RFC3339),
+	}
+	recycleBin := conf.GlobalConf.System.RecycleBin
+	if recycleBin.PurgeInterval > 0 && recycleBin.Retention > 0 {
+		item.PurgeAt = deletedAt.Add(recycleBin.Retention).Format(time.RFC3339)
+	}
+	return item
+}
+
+// Restore 从回收站恢复记录
+// 用户、管理员、角色恢复前校验唯一字段未被占用，并按删除时的审计快照重建角色分配、角色路径和继承关系，
+// 快照中已被删除的角色和路径会被跳过；菜单和接口恢复前要求所属的父菜单或菜单未被删除。
+func (rbs *RecycleBinService) Restore(ctx context.Context, req *auth.RestoreRecycleBinRequest) error {
+	switch req.Type {
+	case RecycleBinTypeUser:
+		return rbs.restoreUser(ctx, req.ID)
+	case RecycleBinTypeAdmin:
+		return rbs.restoreAdmin(ctx, req.ID)
+	case RecycleBinTypeRole:
+		return rbs.restoreRole(ctx, req.ID)
+	case RecycleBinTypeMenu:
+		return rbs.restoreMenu(ctx, req.ID)
+	case RecycleBinTypePath:
+		return rbs.restorePath(ctx, req.ID)
+	}
+	return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+}
+
+// Purge 从回收站永久删除记录，关联数据由数据库外键级联删除
+// 用户和管理员同时删除密码历史，管理员同时删除二次验证恢复码；菜单下仍有子菜单或接口（包括已删除的）时不能永久删除，须先永久删除它们。
+func (rbs *RecycleBinService) Purge(ctx context.Context, req *auth.PurgeRecycleBinRequest) error {
+	return rbs.purge(ctx, req.Type, req.ID)
+}
+
+// PurgeExpired 永久删除在回收站中超过保留时长的记录，由定时任务调用
+// 返回:
+//   - int: 永久删除的记录数量
+func (rbs *RecycleBinService) PurgeExpired(ctx context.Context) (int, error) {
+	recycleBin := conf.GlobalConf.System.RecycleBin
+	if recycleBin.Retention <= 0 {
+		return 0, nil
+	}
+	before := time.Now().Add(-recycleBin.Retention)
+
+	count := 0
+	for _, recordType := range recycleBinPurgeOrder {
+		ids, err := rbs.getDeletedIDsBefore(ctx, recordType, before, recycleBin.BatchSize)
+		if err != nil {
+			return count, err
+		}
+		for _, id := range ids {
+			err = rbs.purge(ctx, recordType, id)
+			var businessErr *utils.BusinessError
+			if errors.As(err, &businessErr) && businessErr.Code == utils.RecycleBinNotFoundCode {
+				// 已被手动恢复或清除
+				continue
+			}
+			if errors.As(err, &businessErr) && businessErr.Code == utils.RecycleBinMenuHasChildrenCode {
+				// 子菜单或接口尚未到期，或将在之后的检查中先于该菜单清除
+				continue
+			}
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// getDeletedIDsBefore 获取指定类型中删除时间早于 before 的记录ID
+func (rbs *RecycleBinService) getDeletedIDsBefore(ctx context.Context, recordType string, before time.Time,
+	limit int) ([]string, error) {
+	switch recordType {
+	case RecycleBinTypeUser:
+		return rbs.userDao.GetDeletedIDsBefore(ctx, before, limit)
+	case RecycleBinTypeAdmin:
+		return rbs.adminDao.GetDeletedIDsBefore(ctx, before, limit)
+	case RecycleBinTypeRole:
+		return rbs.roleDao.GetDeletedIDsBefore(ctx, before, limit)
+	case RecycleBinTypeMenu:
+		return rbs.menuDao.GetDeletedIDsBefore(ctx, before, limit)
+	case RecycleBinTypePath:
+		return rbs.pathDao.GetDeletedIDsBefore(ctx, before, limit)
+	}
+	return nil, nil
+}
+
+// getDeletedSnapshot 读取记录最近一次删除时的审计快照，没有审计日志时返回空快照
+func (rbs *RecycleBinService) getDeletedSnapshot(ctx context.Context, targetType, targetID string) (*deletedSnapshot, error) {
+	snapshot := &deletedSnapshot{}
+	auditLog, err := rbs.auditLogDao.GetLatest(ctx, targetType, targetID, AuditActionDelete)
+	if err != nil {
+		return nil, err
+	}
+	if auditLog == nil || auditLog.OldValues == "" {
+		logger.Logger.Infof("[RestoreRecycleBin] No delete audit log for %s %s, restoring without associations",
+			targetType, targetID)
+		return snapshot, nil
+	}
+	if err = json.Unmarshal([]byte(auditLog.OldValues), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// restoreUser 恢复用户，并按删除时的快照重建角色分配和权限
+func (rbs *RecycleBinService) restoreUser(ctx context.Context, id string) error {
+	user, err := rbs.userDao.GetDeletedByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreUser] Error fetching deleted user: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	if user == nil {
+		return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+	}
+
+	// 按删除时的快照重建角色分配，跳过此后被删除的角色
+	snapshot, err := rbs.getDeletedSnapshot(ctx, AuditTargetUser, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreUser] Error reading delete snapshot: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	roles, err := rbs.roleDao.GetByIDs(ctx, snapshot.RoleIDs)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreUser] Error fetching roles: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	userRoles := restoredUserRoles(id, roles, snapshot.RoleAssignments)
+
+	user.DeletedAt = time.Time{}
+	after := &userAuditSnapshot{Users: user}
+	after.RoleIDs, after.RoleAssignments = userRoleSnapshot(userRoles)
+
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 删除后用户名、邮箱、手机号可能已被新用户使用，加锁检查以阻塞并发写入
+		conflictingUser, err := rbs.userDao.GetByFieldsForUpdateTx(ctx, tx, user.Username, user.Email, user.Phone)
+		if err != nil {
+			logger.Logger.Errorf("[RestoreUser] Error checking user conflicts: %v", err)
+			return utils.NewBusinessError(utils.UserConflictCheckFailedCode)
+		}
+		if conflictingUser != nil {
+			switch {
+			case conflictingUser.Username == user.Username:
+				return utils.NewBusinessError(utils.UsernameAlreadyExistsCode)
+			case user.Email != "" && conflictingUser.Email == user.Email:
+				return utils.NewBusinessError(utils.EmailAlreadyExistsCode)
+			default:
+				return utils.NewBusinessError(utils.PhoneAlreadyExistsCode)
+			}
+		}
+
+		restored, err := rbs.userDao.RestoreTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+		}
+
+		if len(userRoles) > 0 {
+			if err = rbs.userRoleDao.InsertBatchTx(ctx, tx, userRoles); err != nil {
+				return err
+			}
+			if err = rbs.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, []string{id}); err != nil {
+				return err
+			}
+		}
+
+		if err = event.RecordTx(ctx, tx, event.New(ctx, event.UserRestored, event.SubjectUser, id, &event.UserData{
+			UserID:   id,
+			UserName: user.Username,
+			Status:   user.Status,
+			RoleIDs:  after.RoleIDs,
+		})); err != nil {
+			return err
+		}
+
+		return rbs.auditTrail.recordTx(ctx, tx, AuditActionRestore, AuditTargetUser, id, nil, after)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		logger.Logger.Errorf("[RestoreUser] Error restoring user: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+
+	rbs.permissionCache.invalidateUsers(ctx, id)
+	rbs.policyCache.invalidateUsers(ctx, id)
+
+	return nil
+}
+
+// restoredUserRoles 按快照构建恢复后的用户角色关联，只保留仍然存在的角色，并沿用删除时的有效期
+func restoredUserRoles(userID string, roles []*entity.Roles, assignments []roleAssignmentSnapshot) []*entity.UserRoles {
+	validities := make(map[string]roleAssignmentSnapshot, len(assignments))
+	for _, assignment := range assignments {
+		validities[assignment.RoleID] = assignment
+	}
+
+	now := time.Now()
+	userRoles := make([]*entity.UserRoles, 0, len(roles))
+	for _, role := range roles {
+		userRole := &entity.UserRoles{UserID: userID, RoleID: role.ID}
+		if validity, ok := validities[role.ID]; ok {
+			userRole.ValidFrom, userRole.ValidUntil = validity.ValidFrom, validity.ValidUntil
+		}
+		if userRoleValidAt(userRole.ValidFrom, userRole.ValidUntil, now) {
+			userRole.Active = 1
+		}
+		userRoles = append(userRoles, userRole)
+	}
+	return userRoles
+}
+
+// restoreAdmin 恢复管理员，并按删除时的快照重建管理员角色
+func (rbs *RecycleBinService) restoreAdmin(ctx context.Context, id string) error {
+	admin, err := rbs.adminDao.GetDeletedByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreAdmin] Error fetching deleted admin: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	if admin == nil {
+		return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+	}
+
+	// 按删除时的快照重建管理员角色，跳过此后被删除的角色
+	snapshot, err := rbs.getDeletedSnapshot(ctx, AuditTargetAdmin, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreAdmin] Error reading delete snapshot: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	roles, err := rbs.roleDao.GetByIDs(ctx, snapshot.RoleIDs)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreAdmin] Error fetching roles: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	roleIDs := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	admin.DeletedAt = time.Time{}
+	after := &adminAuditSnapshot{Admins: admin, RoleIDs: sortedIDs(roleIDs)}
+
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 删除后用户名、邮箱、手机号可能已被新管理员使用，加锁检查以阻塞并发写入
+		conflictingAdmin, err := rbs.adminDao.GetByFieldsForUpdateTx(ctx, tx, admin.Username, admin.Email, admin.Phone)
+		if err != nil {
+			logger.Logger.Errorf("[RestoreAdmin] Error checking admin conflicts: %v", err)
+			return err
+		}
+		if conflictingAdmin != nil {
+			switch {
+			case conflictingAdmin.Username == admin.Username:
+				return utils.NewBusinessError(utils.AdminUsernameAlreadyExistsCode)
+			case admin.Email != "" && conflictingAdmin.Email == admin.Email:
+				return utils.NewBusinessError(utils.AdminEmailAlreadyExistsCode)
+			default:
+				return utils.NewBusinessError(utils.AdminPhoneAlreadyExistsCode)
+			}
+		}
+
+		restored, err := rbs.adminDao.RestoreTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+		}
+		if len(roleIDs) > 0 {
+			if err = rbs.adminRoleDao.InsertBatchTx(ctx, tx, buildAdminRoles(id, roleIDs)); err != nil {
+				return err
+			}
+		}
+		return rbs.auditTrail.recordTx(ctx, tx, AuditActionRestore, AuditTargetAdmin, id, nil, after)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		logger.Logger.Errorf("[RestoreAdmin] Error restoring admin: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+
+	rbs.permissionCache.invalidateAdmins(ctx, id)
+
+	return nil
+}
+
+// restoreRole 恢复角色，按删除时的快照重建路径和父角色，并重新计算仍分配了该角色的用户的权限
+// 删除角色时解除的子角色继承关系和管理员角色不会恢复。
+func (rbs *RecycleBinService) restoreRole(ctx context.Context, id string) error {
+	role, err := rbs.roleDao.GetDeletedByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreRole] Error fetching deleted role: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	if role == nil {
+		return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+	}
+
+	// 按删除时的快照重建路径和父角色，跳过此后被删除的路径和角色
+	snapshot, err := rbs.getDeletedSnapshot(ctx, AuditTargetRole, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreRole] Error reading delete snapshot: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	paths, err := rbs.pathDao.GetByIDs(ctx, snapshot.PathIDs)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreRole] Error fetching paths: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	parents, err := rbs.roleDao.GetByIDs(ctx, snapshot.ParentIDs)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreRole] Error fetching parent roles: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+
+	rolePaths := make([]*entity.RolePaths, 0, len(paths))
+	pathIDs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		rolePaths = append(rolePaths, &entity.RolePaths{RoleID: id, PathID: path.ID})
+		pathIDs = append(pathIDs, path.ID)
+	}
+	roleParents := make([]*entity.RoleParents, 0, len(parents))
+	parentIDs := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		roleParents = append(roleParents, &entity.RoleParents{RoleID: id, ParentID: parent.ID})
+		parentIDs = append(parentIDs, parent.ID)
+	}
+
+	role.DeletedAt = time.Time{}
+	after := &roleAuditSnapshot{Roles: role, PathIDs: sortedIDs(pathIDs), ParentIDs: sortedIDs(parentIDs)}
+
+	var affectedUserIDs []string
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 删除后角色名称可能已被新角色使用，加锁检查以阻塞并发写入
+		existingRole, err := rbs.roleDao.GetByNameForUpdateTx(ctx, tx, role.Name)
+		if err != nil {
+			logger.Logger.Errorf("[RestoreRole] Error checking role name: %v", err)
+			return err
+		}
+		if existingRole != nil {
+			return utils.NewBusinessError(utils.RoleNameAlreadyExistsCode)
+		}
+
+		restored, err := rbs.roleDao.RestoreTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+		}
+
+		if len(rolePaths) > 0 {
+			if err = rbs.rolePathDao.InsertBatchTx(ctx, tx, rolePaths); err != nil {
+				return err
+			}
+		}
+		if len(roleParents) > 0 {
+			if err = rbs.roleParentDao.InsertBatchTx(ctx, tx, roleParents); err != nil {
+				return err
+			}
+		}
+
+		// 删除角色时保留了用户角色关联，恢复后重新计算这些用户的权限
+		if affectedUserIDs, err = rbs.userRoleDao.GetUserIDsByRoleIDTx(ctx, tx, id); err != nil {
+			return err
+		}
+		if err = rbs.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, affectedUserIDs); err != nil {
+			return err
+		}
+
+		if err = event.RecordTx(ctx, tx, event.New(ctx, event.RoleRestored, event.SubjectRole, id, &event.RoleData{
+			RoleID:          id,
+			Name:            role.Name,
+			Status:          role.Status,
+			PathIDs:         after.PathIDs,
+			ParentIDs:       after.ParentIDs,
+			AffectedUserIDs: sortedIDs(affectedUserIDs),
+		})); err != nil {
+			return err
+		}
+
+		return rbs.auditTrail.recordTx(ctx, tx, AuditActionRestore, AuditTargetRole, id, nil, after)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		logger.Logger.Errorf("[RestoreRole] Error restoring role: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+
+	rbs.permissionCache.invalidateUsers(ctx, affectedUserIDs...)
+	rbs.policyCache.invalidateAll(ctx)
+
+	return nil
+}
+
+// restoreMenu 恢复菜单，只恢复菜单自身，子菜单和接口须分别恢复
+func (rbs *RecycleBinService) restoreMenu(ctx context.Context, id string) error {
+	menu, err := rbs.menuDao.GetDeletedByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestoreMenu] Error fetching deleted menu: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	if menu == nil {
+		return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+	}
+
+	menu.DeletedAt = time.Time{}
+	err = menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		if menu.ParentID != "" {
+			parent, err := rbs.menuDao.GetByIDTx(ctx, tx, menu.ParentID)
+			if err != nil {
+				logger.Logger.Errorf("[RestoreMenu] Error fetching parent menu: %v", err)
+				return err
+			}
+			if parent == nil {
+				return utils.NewBusinessError(utils.RecycleBinParentDeletedCode)
+			}
+		}
+
+		restored, err := rbs.menuDao.RestoreTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+		}
+		return rbs.auditTrail.recordTx(ctx, tx, AuditActionRestore, AuditTargetMenu, id, nil, menu)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		logger.Logger.Errorf("[RestoreMenu] Error restoring menu: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+
+	return nil
+}
+
+// restorePath 恢复接口，删除接口时收回的角色授权不会恢复，须重新分配
+func (rbs *RecycleBinService) restorePath(ctx context.Context, id string) error {
+	path, err := rbs.pathDao.GetDeletedByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[RestorePath] Error fetching deleted path: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+	if path == nil {
+		return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+	}
+
+	path.DeletedAt = time.Time{}
+	err = menuTreeTransaction(ctx, func(tx *gorm.DB) error {
+		menu, err := rbs.menuDao.GetByIDTx(ctx, tx, path.MenuID)
+		if err != nil {
+			logger.Logger.Errorf("[RestorePath] Error fetching menu: %v", err)
+			return err
+		}
+		if menu == nil {
+			return utils.NewBusinessError(utils.RecycleBinParentDeletedCode)
+		}
+
+		// 删除后可能已新增或同步了相同的接口，加锁检查以阻塞并发写入
+		existingPath, err := rbs.pathDao.GetByPathMethodForUpdateTx(ctx, tx, path.Path, path.Method)
+		if err != nil {
+			logger.Logger.Errorf("[RestorePath] Error checking path conflicts: %v", err)
+			return err
+		}
+		if existingPath != nil {
+			return utils.NewBusinessError(utils.PathAlreadyExistsCode)
+		}
+
+		restored, err := rbs.pathDao.RestoreTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+		}
+		return rbs.auditTrail.recordTx(ctx, tx, AuditActionRestore, AuditTargetPath, id, nil, path)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		logger.Logger.Errorf("[RestorePath] Error restoring path: %v", err)
+		return utils.NewBusinessError(utils.RecycleBinRestoreFailedCode)
+	}
+
+	rbs.policyCache.invalidateAll(ctx)
+
+	return nil
+}
+
+// purge 永久删除回收站中的记录，并记录审计日志
+func (rbs *RecycleBinService) purge(ctx context.Context, recordType, id string) error {
+	var (
+		targetType string
+		before     interface{}
+		found      bool
+		err        error
+	)
+	switch recordType {
+	case RecycleBinTypeUser:
+		var user *entity.Users
+		user, err = rbs.userDao.GetDeletedByID(ctx, id)
+		targetType, before, found = AuditTargetUser, user, user != nil
+	case RecycleBinTypeAdmin:
+		var admin *entity.Admins
+		admin, err = rbs.adminDao.GetDeletedByID(ctx, id)
+		targetType, before, found = AuditTargetAdmin, admin, admin != nil
+	case RecycleBinTypeRole:
+		var role *entity.Roles
+		role, err = rbs.roleDao.GetDeletedByID(ctx, id)
+		targetType, before, found = AuditTargetRole, role, role != nil
+	case RecycleBinTypeMenu:
+		var menu *entity.Menus
+		menu, err = rbs.menuDao.GetDeletedByID(ctx, id)
+		targetType, before, found = AuditTargetMenu, menu, menu != nil
+	case RecycleBinTypePath:
+		var path *entity.Paths
+		path, err = rbs.pathDao.GetDeletedByID(ctx, id)
+		targetType, before, found = AuditTargetPath, path, path != nil
+	}
+	if err != nil {
+		logger.Logger.Errorf("[PurgeRecycleBin] Error fetching deleted %s: %v", recordType, err)
+		return utils.NewBusinessError(utils.RecycleBinPurgeFailedCode)
+	}
+	if !found {
+		return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+	}
+
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		purged, err := rbs.purgeTx(ctx, tx, recordType, id)
+		if err != nil {
+			return err
+		}
+		if !purged {
+			return utils.NewBusinessError(utils.RecycleBinNotFoundCode)
+		}
+		return rbs.auditTrail.recordTx(ctx, tx, AuditActionPurge, targetType, id, before, nil)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		logger.Logger.Errorf("[PurgeRecycleBin] Error purging %s %s: %v", recordType, id, err)
+		return utils.NewBusinessError(utils.RecycleBinPurgeFailedCode)
+	}
+
+	return nil
+}
+
+// purgeTx 在事务中永久删除记录及没有外键约束的关联数据
+func (rbs *RecycleBinService) purgeTx(ctx context.Context, tx *gorm.DB, recordType, id string) (bool, error) {
+	switch recordType {
+	case RecycleBinTypeUser:
+		if err := rbs.passwordHistoryDao.RemoveBySubjectTx(ctx, tx, utils.SubjectTypeUser, id); err != nil {
+			return false, err
+		}
+		return rbs.userDao.PurgeTx(ctx, tx, id)
+	case RecycleBinTypeAdmin:
+		if err := rbs.passwordHistoryDao.RemoveBySubjectTx(ctx, tx, utils.SubjectTypeAdmin, id); err != nil {
+			return false, err
+		}
+		if err := rbs.recoveryCodeDao.RemoveByAdminIDTx(ctx, tx, id); err != nil {
+			return false, err
+		}
+		return rbs.adminDao.PurgeTx(ctx, tx, id)
+	case RecycleBinTypeRole:
+		return rbs.roleDao.PurgeTx(ctx, tx, id)
+	case RecycleBinTypeMenu:
+		// 不通过外键级联删除子菜单和接口，避免一并清除尚未到期或仍在使用的记录
+		children, err := rbs.menuDao.CountChildrenTx(ctx, tx, id)
+		if err != nil {
+			return false, err
+		}
+		paths, err := rbs.pathDao.CountByMenuIDTx(ctx, tx, id)
+		if err != nil {
+			return false, err
+		}
+		if children > 0 || paths > 0 {
+			return false, utils.NewBusinessError(utils.RecycleBinMenuHasChildrenCode)
+		}
+		return rbs.menuDao.PurgeTx(ctx, tx, id)
+	case RecycleBinTypePath:
+		return rbs.pathDao.PurgeTx(ctx, tx, id)
+	}
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"testing"
+	"time"
+)
+
+func TestRestoredUserRoles(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	type wantRole struct {
+		roleID     string
+		active     int8
+		validFrom  *time.Time
+		validUntil *time.Time
+	}
+	tests := []struct {
+		name        string
+		roles       []*entity.Roles
+		assignments []roleAssignmentSnapshot
+		want        []wantRole
+	}{
+		{
+			name: "no roles",
+		},
+		{
+			name:  "roles without validity",
+			roles: []*entity.Roles{{ID: "r1"}, {ID: "r2"}},
+			want:  []wantRole{{roleID: "r1", active: 1}, {roleID: "r2", active: 1}},
+		},
+		{
+			name:        "validity restored",
+			roles:       []*entity.Roles{{ID: "r1"}},
+			assignments: []roleAssignmentSnapshot{{RoleID: "r1", ValidFrom: &past, ValidUntil: &future}},
+			want:        []wantRole{{roleID: "r1", active: 1, validFrom: &past, validUntil: &future}},
+		},
+		{
+			name:        "expired while deleted",
+			roles:       []*entity.Roles{{ID: "r1"}},
+			assignments: []roleAssignmentSnapshot{{RoleID: "r1", ValidUntil: &past}},
+			want:        []wantRole{{roleID: "r1", active: 0, validUntil: &past}},
+		},
+		{
+			name:        "not yet valid",
+			roles:       []*entity.Roles{{ID: "r1"}},
+			assignments: []roleAssignmentSnapshot{{RoleID: "r1", ValidFrom: &future}},
+			want:        []wantRole{{roleID: "r1", active: 0, validFrom: &future}},
+		},
+		{
+			name:        "assignment of deleted role skipped",
+			roles:       []*entity.Roles{{ID: "r1"}},
+			assignments: []roleAssignmentSnapshot{{RoleID: "r2", ValidUntil: &future}},
+			want:        []wantRole{{roleID: "r1", active: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := restoredUserRoles("u1", tt.roles, tt.assignments)
+			if len(got) != len(tt.want) {
+				t.Fatalf("restoredUserRoles() returned %d roles, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				userRole := got[i]
+				if userRole.UserID != "u1" || userRole.RoleID != want.roleID || userRole.Active != want.active ||
+					userRole.ValidFrom != want.validFrom || userRole.ValidUntil != want.validUntil {
+					t.Errorf("restoredUserRoles()[%d] = %+v, want %+v", i, userRole, want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/event"
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"ByteScience-WAM-Admin/internal/utils"
@@ -10,9 +11,16 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// SuperAdminRoleName 内置超级管理员角色名称
+const SuperAdminRoleName = "super_admin"
+
 type RoleService struct {
 	roleDao           *dao.RoleDao
 	menuDao           *dao.MenuDao
@@ -20,6 +28,12 @@ type RoleService struct {
 	rolePathDao       *dao.RolePathDao
 	userRoleDao       *dao.UserRoleDao
 	userPermissionDao *dao.UserPermissionDao
+	adminDao          *dao.AdminDao
+	adminRoleDao      *dao.AdminRoleDao
+	roleParentDao     *dao.RoleParentDao
+	auditTrail        auditTrail
+	policyCache       policyCache
+	permissionCache   permissionCache
 }
 
 // NewRoleService 创建一个新的 RoleService 实例
@@ -31,26 +45,59 @@ func NewRoleService() *RoleService {
 		rolePathDao:       dao.NewRolePathDao(),
 		userRoleDao:       dao.NewUserRoleDao(),
 		userPermissionDao: dao.NewUserPermissionDao(),
+		adminDao:          dao.NewAdminDao(),
+		adminRoleDao:      dao.NewAdminRoleDao(),
+		roleParentDao:     dao.NewRoleParentDao(),
+		auditTrail:        newAuditTrail(),
+		policyCache:       newPolicyCache(),
+		permissionCache:   newPermissionCache(),
 	}
 }
 
 // Add 添加角色
 func (rs *RoleService) Add(ctx context.Context, req *auth.AddRoleRequest) error {
-	// 检查是否存在冲突的角色
-	conflictingRole, err := addRoleConflictCheck(ctx, req.Name, rs.roleDao)
+	_, err := rs.create(ctx, req)
+	return err
+}
+
+// create 校验冲突并创建角色及其路径、继承关系，返回创建的角色
+func (rs *RoleService) create(ctx context.Context, req *auth.AddRoleRequest) (*entity.Roles, error) {
+	var role *entity.Roles
+	err := hierarchyTransaction(ctx, len(req.ParentIDList) > 0, func(tx *gorm.DB) error {
+		var err error
+		role, err = rs.createTx(ctx, tx, req)
+		return err
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return nil, err
+	}
+	if err != nil {
+		return nil, utils.NewBusinessError(utils.RoleInsertFailedCode)
+	}
+
+	return role, nil
+}
+
+// createTx 在事务中校验冲突并创建角色及其路径、继承关系，返回创建的角色
+// 指定了父角色时，调用方须通过 hierarchyTransaction 持有角色继承关系锁开启事务。
+func (rs *RoleService) createTx(ctx context.Context, tx *gorm.DB, req *auth.AddRoleRequest) (*entity.Roles, error) {
+	// 检查是否存在冲突的角色，锁定的索引范围阻塞并发创建同名角色
+	conflictingRole, err := rs.roleDao.GetByNameForUpdateTx(ctx, tx, req.Name)
 	if err != nil {
 		logger.Logger.Errorf("[AddRole] Error checking role conflict: %v", err)
-		return err
+		return nil, err
 	}
 
 	if conflictingRole != nil {
 		logger.Logger.Infof("[AddRole] Role name %s already exists", req.Name)
-		return utils.NewBusinessError(utils.RoleNameAlreadyExistsCode)
+		return nil, utils.NewBusinessError(utils.RoleNameAlreadyExistsCode)
 	}
 
 	// 构建角色实体
+	roleID := uuid.New().String()
+
 	role := &entity.Roles{
-		ID:          uuid.New().String(),
+		ID:          roleID,
 		Name:        req.Name,
 		Description: req.Description,
 		Status:      req.Status,
@@ -58,58 +105,108 @@ func (rs *RoleService) Add(ctx context.Context, req *auth.AddRoleRequest) error
 		UpdatedAt:   time.Now(),
 	}
 
-	// 使用事务闭包
-	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error { // 插入角色数据
-		if err = rs.roleDao.InsertTx(ctx, tx, role); err != nil {
-			logger.Logger.Errorf("[AddRole] Error inserting role into DB: %v", err)
-			return err
-		}
+	// 检查父角色
+	parentIDs, err := rs.checkParentsTx(ctx, tx, roleID, req.ParentIDList)
+	if err != nil {
+		return nil, err
+	}
 
-		// 如果 PathIDList 不为空，则插入角色路径关系
-		if len(req.PathIDList) > 0 {
-			rolePaths := make([]*entity.RolePaths, 0, len(req.PathIDList))
-			for _, pathID := range req.PathIDList {
-				rolePaths = append(rolePaths, &entity.RolePaths{
-					RoleID: role.ID,
-					PathID: pathID,
-				})
-			}
+	// 插入角色数据
+	if err = rs.roleDao.InsertTx(ctx, tx, role); err != nil {
+		logger.Logger.Errorf("[AddRole] Error inserting role into DB: %v", err)
+		return nil, err
+	}
 
-			if err = rs.rolePathDao.InsertBatchTx(ctx, tx, rolePaths); err != nil {
-				logger.Logger.Errorf("[AddRole] Error inserting role paths: %v", err)
-				return err
-			}
+	// 如果 PathIDList 不为空，则插入角色路径关系
+	if len(req.PathIDList) > 0 {
+		rolePaths := make([]*entity.RolePaths, 0, len(req.PathIDList))
+		for _, pathID := range req.PathIDList {
+			rolePaths = append(rolePaths, &entity.RolePaths{
+				RoleID: role.ID,
+				PathID: pathID,
+			})
 		}
 
-		return nil
-	}); err != nil {
-		return utils.NewBusinessError(utils.RoleInsertFailedCode)
+		if err = rs.rolePathDao.InsertBatchTx(ctx, tx, rolePaths); err != nil {
+			logger.Logger.Errorf("[AddRole] Error inserting role paths: %v", err)
+			return nil, err
+		}
 	}
 
-	return nil
+	// 插入角色继承关系
+	if err = rs.roleParentDao.InsertBatchTx(ctx, tx, buildRoleParents(role.ID, parentIDs)); err != nil {
+		logger.Logger.Errorf("[AddRole] Error inserting role parents: %v", err)
+		return nil, err
+	}
+
+	// 写入事件发件箱
+	if err = event.RecordTx(ctx, tx, event.New(ctx, event.RoleCreated, event.SubjectRole, role.ID, &event.RoleData{
+		RoleID:    role.ID,
+		Name:      role.Name,
+		Status:    role.Status,
+		PathIDs:   sortedIDs(req.PathIDList),
+		ParentIDs: parentIDs,
+	})); err != nil {
+		logger.Logger.Errorf("[AddRole] Error recording events: %v", err)
+		return nil, err
+	}
+
+	// 记录审计日志
+	after := &roleAuditSnapshot{Roles: role, PathIDs: sortedIDs(req.PathIDList), ParentIDs: parentIDs}
+	if err = rs.auditTrail.recordTx(ctx, tx, AuditActionCreate, AuditTargetRole, role.ID, nil, after); err != nil {
+		logger.Logger.Errorf("[AddRole] Error recording audit log: %v", err)
+		return nil, err
+	}
+
+	return role, nil
 }
 
 // Edit 编辑角色信息
 func (rs *RoleService) Edit(ctx context.Context, req *auth.EditRoleRequest) error {
-	// 确保角色存在
-	role, err := rs.roleDao.GetByID(ctx, req.ID)
+	var affectedUserIDs []string
+	err := hierarchyTransaction(ctx, req.ParentIDList != nil, func(tx *gorm.DB) error {
+		// 确保角色存在，并锁定该行直到事务结束
+		role, err := rs.roleDao.GetByIDForUpdateTx(ctx, tx, req.ID)
+		if err != nil {
+			logger.Logger.Errorf("[EditRole] Error fetching role by ID: %v", err)
+			return utils.NewBusinessError(utils.RoleUpdateFailedCode)
+		}
+		if role == nil {
+			return utils.NewBusinessError(utils.RoleNotFoundCode)
+		}
+
+		affectedUserIDs, err = rs.editTx(ctx, tx, role, req)
+		return err
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
 	if err != nil {
-		logger.Logger.Errorf("[EditRole] Error fetching role by ID: %v", err)
 		return utils.NewBusinessError(utils.RoleUpdateFailedCode)
 	}
-	if role == nil {
-		return utils.NewBusinessError(utils.RoleNotFoundCode)
+
+	rs.invalidateEditedRole(ctx, affectedUserIDs)
+	return nil
+}
+
+// editTx 在事务中编辑已锁定的角色，返回需要重新计算权限的用户ID
+// 调整继承关系时，调用方须通过 hierarchyTransaction 持有角色继承关系锁开启事务；
+// 调用方需在事务提交后调用 invalidateEditedRole 使缓存失效。
+func (rs *RoleService) editTx(ctx context.Context, tx *gorm.DB, role *entity.Roles, req *auth.EditRoleRequest) ([]string, error) {
+	// 内置角色不可重命名或禁用
+	if role.IsBuiltin == 1 && (req.Name != role.Name || req.Status != 1) {
+		return nil, utils.NewBusinessError(utils.RoleBuiltinProtectedCode)
 	}
 
 	// 检查是否存在冲突的角色名
-	conflictingRole, err := addRoleConflictCheck(ctx, req.Name, rs.roleDao)
+	conflictingRole, err := rs.roleDao.GetByNameForUpdateTx(ctx, tx, req.Name)
 	if err != nil {
 		logger.Logger.Errorf("[EditRole] Error checking role conflict: %v", err)
-		return err
+		return nil, err
 	}
 	if conflictingRole != nil && conflictingRole.ID != req.ID {
 		logger.Logger.Infof("[EditRole] Role name %s already exists", req.Name)
-		return utils.NewBusinessError(utils.RoleNameAlreadyExistsCode)
+		return nil, utils.NewBusinessError(utils.RoleNameAlreadyExistsCode)
 	}
 
 	// 准备更新字段
@@ -120,57 +217,105 @@ func (rs *RoleService) Edit(ctx context.Context, req *auth.EditRoleRequest) erro
 		entity.RolesColumns.Status:      req.Status,
 	}
 
-	// 开启事务
-	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 调用 RoleDao 层更新数据
-		if err = rs.roleDao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
-			logger.Logger.Errorf("[EditRole] Error updating role info in DB: %v", err)
-			return err
+	// 变更前后的快照，用于审计；仅在调整路径时记录路径变化
+	before := &roleAuditSnapshot{Roles: role}
+	updated := *role
+	updated.Name = req.Name
+	updated.Description = req.Description
+	updated.Status = req.Status
+	after := &roleAuditSnapshot{Roles: &updated}
+	if req.PathIDList != nil {
+		if before.PathIDs, err = rs.getPathIDsTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditRole] Error fetching role paths: %v", err)
+			return nil, err
+		}
+		after.PathIDs = sortedIDs(req.PathIDList)
+	}
+	if req.ParentIDList != nil {
+		if before.ParentIDs, err = rs.roleParentDao.GetParentIDsTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditRole] Error fetching role parents: %v", err)
+			return nil, err
 		}
+		before.ParentIDs = sortedIDs(before.ParentIDs)
 
-		// 如果 PathIDList 不为空，则更新角色路径关系
-		if req.PathIDList != nil {
-			// 删除旧的角色路径关联
-			if err = rs.rolePathDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
-				logger.Logger.Errorf("[EditRole] Error deleting old role paths: %v", err)
-				return err
-			}
+		// 检查父角色
+		if after.ParentIDs, err = rs.checkParentsTx(ctx, tx, req.ID, req.ParentIDList); err != nil {
+			return nil, err
+		}
+	}
 
-			// 插入新的角色路径关联
-			rolePaths := make([]*entity.RolePaths, 0, len(req.PathIDList))
-			for _, pathID := range req.PathIDList {
-				rolePaths = append(rolePaths, &entity.RolePaths{
-					RoleID: req.ID,
-					PathID: pathID,
-				})
-			}
+	// 调用 RoleDao 层更新数据
+	if err = rs.roleDao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+		logger.Logger.Errorf("[EditRole] Error updating role info in DB: %v", err)
+		return nil, err
+	}
 
-			if err = rs.rolePathDao.InsertBatchTx(ctx, tx, rolePaths); err != nil {
-				logger.Logger.Errorf("[EditRole] Error inserting new role paths: %v", err)
-				return err
-			}
+	// 如果 PathIDList 不为空，则更新角色路径关系
+	if req.PathIDList != nil {
+		// 删除旧的角色路径关联
+		if err = rs.rolePathDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditRole] Error deleting old role paths: %v", err)
+			return nil, err
+		}
 
-			// **移除与该角色关联的用户权限**
-			// 找出所有关联该角色的用户
-			userIDs, err := rs.userRoleDao.GetUserIDsByRoleIDTx(ctx, tx, req.ID)
-			if err != nil {
-				logger.Logger.Errorf("[EditRole] Error fetching user IDs for role: %v", err)
-				return err
-			}
+		// 插入新的角色路径关联
+		rolePaths := make([]*entity.RolePaths, 0, len(req.PathIDList))
+		for _, pathID := range req.PathIDList {
+			rolePaths = append(rolePaths, &entity.RolePaths{
+				RoleID: req.ID,
+				PathID: pathID,
+			})
+		}
 
-			// 删除受影响用户的权限记录
-			if err = rs.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, userIDs); err != nil {
-				logger.Logger.Errorf("[EditRole] Error update user permissions: %v", err)
-				return err
-			}
+		if err = rs.rolePathDao.InsertBatchTx(ctx, tx, rolePaths); err != nil {
+			logger.Logger.Errorf("[EditRole] Error inserting new role paths: %v", err)
+			return nil, err
 		}
+	}
 
-		return nil
-	}); err != nil {
-		return utils.NewBusinessError(utils.RoleUpdateFailedCode)
+	// 如果 ParentIDList 不为空，则更新角色继承关系
+	if req.ParentIDList != nil {
+		if err = rs.roleParentDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditRole] Error deleting old role parents: %v", err)
+			return nil, err
+		}
+		if err = rs.roleParentDao.InsertBatchTx(ctx, tx, buildRoleParents(req.ID, after.ParentIDs)); err != nil {
+			logger.Logger.Errorf("[EditRole] Error inserting new role parents: %v", err)
+			return nil, err
+		}
 	}
 
-	return nil
+	// 路径、继承关系或状态变化时，重新计算该角色及其全部子孙角色下用户的权限
+	var affectedUserIDs []string
+	if req.PathIDList != nil || req.ParentIDList != nil || req.Status != role.Status {
+		if affectedUserIDs, err = rs.refreshUserPermissionsTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditRole] Error update user permissions: %v", err)
+			return nil, err
+		}
+	}
+
+	// 写入事件发件箱
+	if err = event.RecordTx(ctx, tx, roleUpdatedEvents(ctx, before, after, affectedUserIDs)...); err != nil {
+		logger.Logger.Errorf("[EditRole] Error recording events: %v", err)
+		return nil, err
+	}
+
+	// 记录审计日志
+	if err = rs.auditTrail.recordTx(ctx, tx, AuditActionUpdate, AuditTargetRole, req.ID, before, after); err != nil {
+		logger.Logger.Errorf("[EditRole] Error recording audit log: %v", err)
+		return nil, err
+	}
+
+	return affectedUserIDs, nil
+}
+
+// invalidateEditedRole 角色编辑提交后使受影响用户的权限缓存和全部权限决策缓存失效
+func (rs *RoleService) invalidateEditedRole(ctx context.Context, affectedUserIDs []string) {
+	if affectedUserIDs != nil {
+		rs.permissionCache.invalidateUsers(ctx, affectedUserIDs...)
+	}
+	// 角色名称、路径或继承关系变化会影响其全部子孙角色下的用户，直接使全部权限决策缓存失效
+	rs.policyCache.invalidateAll(ctx)
 }
 
 // Delete 软删除角色
@@ -186,41 +331,158 @@ func (rs *RoleService) Delete(ctx context.Context, req *auth.DelRoleRequest) err
 		return utils.NewBusinessError(utils.RoleNotFoundCode)
 	}
 
-	if err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	// 内置角色不可删除
+	if role.IsBuiltin == 1 {
+		return utils.NewBusinessError(utils.RoleBuiltinProtectedCode)
+	}
+
+	// 删除前的快照，用于审计
+	pathIDs, err := rs.getPathIDs(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[DeleteRole] Error fetching role paths: %v", err)
+		return utils.NewBusinessError(utils.RoleDeleteFailedCode)
+	}
+	parentIDs, err := rs.roleParentDao.GetParentIDs(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[DeleteRole] Error fetching role parents: %v", err)
+		return utils.NewBusinessError(utils.RoleDeleteFailedCode)
+	}
+	before := &roleAuditSnapshot{Roles: role, PathIDs: pathIDs, ParentIDs: sortedIDs(parentIDs)}
+
+	var affectedUserIDs []string
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 移除前先找出该角色及其全部子孙角色下的用户
+		if affectedUserIDs, err = rs.getHierarchyUserIDsTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[DeleteRole] Error fetching user IDs for role: %v", err)
+			return err
+		}
+
 		// 移除角色路径关联
-		if err := rs.rolePathDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
+		if err = rs.rolePathDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
 			logger.Logger.Errorf("[DeleteRole] Error removing role paths: %v", err)
 			return err
 		}
 
-		// **移除与该角色关联的用户权限**
-		// 找出所有关联该角色的用户
-		userIDs, err := rs.userRoleDao.GetUserIDsByRoleIDTx(ctx, tx, req.ID)
-		if err != nil {
-			logger.Logger.Errorf("[DeleteRole] Error fetching user IDs for role: %v", err)
+		// 移除角色继承关系，子角色不再继承该角色
+		if err = rs.roleParentDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[DeleteRole] Error removing role parents: %v", err)
+			return err
+		}
+		if err = rs.roleParentDao.RemoveByParentIDTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[DeleteRole] Error removing role children: %v", err)
 			return err
 		}
 
 		// 更新受影响用户的权限记录
-		if err = rs.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, userIDs); err != nil {
+		if err = rs.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, affectedUserIDs); err != nil {
 			logger.Logger.Errorf("[DeleteRole] Error update user permissions: %v", err)
 			return err
 		}
 
+		// 移除管理员角色关联
+		if err = rs.adminRoleDao.RemoveByRoleIDTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[DeleteRole] Error removing admin roles: %v", err)
+			return err
+		}
+
 		// 调用 RoleDao 层进行软删除
 		if err = rs.roleDao.SoftDeleteByIDTx(ctx, tx, req.ID); err != nil {
 			logger.Logger.Errorf("[DeleteRole] Error soft deleting role: %v", err)
 			return err
 		}
 
+		// 写入事件发件箱
+		if err = event.RecordTx(ctx, tx, event.New(ctx, event.RoleDeleted, event.SubjectRole, role.ID, &event.RoleDeletedData{
+			RoleID:          role.ID,
+			Name:            role.Name,
+			AffectedUserIDs: sortedIDs(affectedUserIDs),
+		})); err != nil {
+			logger.Logger.Errorf("[DeleteRole] Error recording events: %v", err)
+			return err
+		}
+
+		// 记录审计日志
+		if err = rs.auditTrail.recordTx(ctx, tx, AuditActionDelete, AuditTargetRole, req.ID, before, nil); err != nil {
+			logger.Logger.Errorf("[DeleteRole] Error recording audit log: %v", err)
+			return err
+		}
+
 		return nil
 	}); err != nil {
 		return utils.NewBusinessError(utils.RoleDeleteFailedCode)
 	}
 
+	rs.permissionCache.invalidateUsers(ctx, affectedUserIDs...)
+	rs.policyCache.invalidateAll(ctx)
+
+	return nil
+}
+
+// EnsureSuperAdminRole 确保内置超级管理员角色存在，服务启动时调用
+// 内置角色拥有全部接口权限，不可删除、重命名或禁用，且至少保留一名管理员。
+// 角色没有任何管理员时（例如首次升级到按角色校验管理员权限的版本），将全部现有管理员加入该角色，避免管理员失去访问权限。
+// 多个实例同时启动时通过命名锁串行化，角色名称的唯一索引包含 deleted_at，无法阻止重复创建。
+func (rs *RoleService) EnsureSuperAdminRole(ctx context.Context) error {
+	var adminIDs []string
+	err := db.WithLock(ctx, db.Client, db.LockBuiltinRole, db.DefaultLockTimeout, func(conn *gorm.DB) error {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			var err error
+			adminIDs, err = rs.ensureSuperAdminRoleTx(ctx, tx)
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	rs.permissionCache.invalidateAdmins(ctx, adminIDs...)
 	return nil
 }
 
+// ensureSuperAdminRoleTx 在事务中创建内置超级管理员角色，并在角色没有管理员时加入全部现有管理员，返回加入的管理员ID
+func (rs *RoleService) ensureSuperAdminRoleTx(ctx context.Context, tx *gorm.DB) ([]string, error) {
+	role, err := rs.roleDao.GetBuiltinTx(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		role = &entity.Roles{
+			ID:          uuid.New().String(),
+			Name:        SuperAdminRoleName,
+			Description: "Built-in role with full access",
+			Status:      1,
+			IsBuiltin:   1,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err = rs.roleDao.InsertTx(ctx, tx, role); err != nil {
+			return nil, err
+		}
+		logger.Logger.Infof("[EnsureSuperAdminRole] Built-in role %s created", role.Name)
+	}
+
+	count, err := rs.adminRoleDao.CountAdminsByRoleIDTx(ctx, tx, role.ID, "")
+	if err != nil || count > 0 {
+		return nil, err
+	}
+
+	adminIDs, err := rs.adminDao.GetAllIDsTx(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	adminRoles := make([]*entity.AdminRoles, 0, len(adminIDs))
+	for _, adminID := range adminIDs {
+		adminRoles = append(adminRoles, &entity.AdminRoles{AdminID: adminID, RoleID: role.ID})
+	}
+	if err = rs.adminRoleDao.InsertBatchTx(ctx, tx, adminRoles); err != nil {
+		return nil, err
+	}
+	logger.Logger.Infof("[EnsureSuperAdminRole] %d existing admins assigned to built-in role %s",
+		len(adminIDs), role.Name)
+	return adminIDs, nil
+}
+
 // Info 角色详情
 func (rs *RoleService) Info(ctx context.Context, req *auth.InfoRoleRequest) (*auth.InfoRoleResponse, error) {
 	// 根据角色ID获取角色信息
@@ -238,14 +500,34 @@ func (rs *RoleService) Info(ctx context.Context, req *auth.InfoRoleRequest) (*au
 		return nil, err
 	}
 
+	// 获取直接父角色
+	parentIDs, err := rs.roleParentDao.GetParentIDs(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := rs.roleDao.GetByIDs(ctx, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+	parentList := make([]auth.TrimRoleInfo, 0, len(parents))
+	for _, parent := range parents {
+		parentList = append(parentList, auth.TrimRoleInfo{
+			ID:          parent.ID,
+			Name:        parent.Name,
+			Description: parent.Description,
+		})
+	}
+
 	// 构造响应数据
 	resp := &auth.InfoRoleResponse{
 		ID:          role.ID,
 		Name:        role.Name,
 		Description: role.Description,
 		Status:      role.Status,
+		IsBuiltin:   role.IsBuiltin == 1,
 		CreatedAt:   role.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   role.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ParentList:  parentList,
 		MenuData:    menuPathTree,
 	}
 
@@ -255,10 +537,7 @@ func (rs *RoleService) Info(ctx context.Context, req *auth.InfoRoleRequest) (*au
 // List 获取角色列表（分页）
 func (rs *RoleService) List(ctx context.Context, req *auth.ListRoleRequest) (*auth.ListRoleResponse, error) {
 	// 构建过滤条件
-	filters := map[string]interface{}{
-		entity.RolesColumns.ID:   req.ID,
-		entity.RolesColumns.Name: req.Name,
-	}
+	filters := buildRoleFilters(&req.RoleFilter)
 
 	// 查询数据
 	roles, total, err := rs.roleDao.Query(ctx, req.Page, req.PageSize, filters)
@@ -274,6 +553,7 @@ func (rs *RoleService) List(ctx context.Context, req *auth.ListRoleRequest) (*au
 			ID:          role.ID,
 			Name:        role.Name,
 			Description: role.Description,
+			IsBuiltin:   role.IsBuiltin == 1,
 			CreatedAt:   role.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:   role.UpdatedAt.Format(time.RFC3339),
 		})
@@ -285,7 +565,71 @@ func (rs *RoleService) List(ctx context.Context, req *auth.ListRoleRequest) (*au
 	}, nil
 }
 
-// GetRoleMenuPathTree 根据角色ID获取菜单和路径树，并标识权限
+// buildRoleFilters 将请求中的筛选条件转换为 DAO 查询条件
+func buildRoleFilters(req *auth.RoleFilter) map[string]interface{} {
+	return map[string]interface{}{
+		entity.RolesColumns.ID:   req.ID,
+		entity.RolesColumns.Name: req.Name,
+	}
+}
+
+// roleExportRecord 导出的角色及其直接拥有的接口
+type roleExportRecord struct {
+	*entity.Roles
+	paths []string
+}
+
+// roleExportColumns 角色导出的全部列
+var roleExportColumns = []exportColumn[*roleExportRecord]{
+	{"id", func(r *roleExportRecord) string { return r.ID }},
+	{"name", func(r *roleExportRecord) string { return r.Name }},
+	{"description", func(r *roleExportRecord) string { return r.Description }},
+	{"status", func(r *roleExportRecord) string { return formatExportInt(r.Status) }},
+	{"isBuiltin", func(r *roleExportRecord) string { return strconv.FormatBool(r.IsBuiltin == 1) }},
+	{"paths", func(r *roleExportRecord) string { return strings.Join(r.paths, exportSeparator) }},
+	{"createdAt", func(r *roleExportRecord) string { return formatExportTime(r.CreatedAt) }},
+	{"updatedAt", func(r *roleExportRecord) string { return formatExportTime(r.UpdatedAt) }},
+}
+
+// Export 按筛选条件导出角色为 CSV 或 XLSX 文件，按创建时间倒序，paths 列为角色直接拥有的接口（不含继承的接口）
+// 注意: 数据分批读取并直接写入 w，开始写入后发生的错误无法再以 JSON 形式返回给调用方。
+func (rs *RoleService) Export(ctx context.Context, req *auth.ExportRoleRequest, w io.Writer) error {
+	columns, err := selectExportColumns(roleExportColumns, req.Columns)
+	if err != nil {
+		return err
+	}
+
+	filters := buildRoleFilters(&req.RoleFilter)
+	err = writeExport(w, req.Format, columns, func(write func(records []*roleExportRecord) error) error {
+		return rs.roleDao.FindInBatches(ctx, filters, exportBatchSize, func(roles []*entity.Roles) error {
+			roleIDs := make([]string, 0, len(roles))
+			for _, role := range roles {
+				roleIDs = append(roleIDs, role.ID)
+			}
+			rolePaths, err := rs.rolePathDao.GetPathsByRoleIDs(ctx, roleIDs)
+			if err != nil {
+				return err
+			}
+			paths := make(map[string][]string, len(roles))
+			for _, rolePath := range rolePaths {
+				paths[rolePath.RoleID] = append(paths[rolePath.RoleID], rolePath.Method+" "+rolePath.Path)
+			}
+
+			records := make([]*roleExportRecord, 0, len(roles))
+			for _, role := range roles {
+				records = append(records, &roleExportRecord{Roles: role, paths: paths[role.ID]})
+			}
+			return write(records)
+		})
+	})
+	if err != nil {
+		logger.Logger.Errorf("[ExportRole] Error exporting roles: %v", err)
+		return utils.NewBusinessError(utils.RoleExportFailedCode)
+	}
+	return nil
+}
+
+// GetRoleMenuPathTree 根据角色ID获取菜单和路径树，并分别标识直接拥有和继承的权限
 func (rs *RoleService) GetRoleMenuPathTree(ctx context.Context, roleID string) ([]*auth.RoleMenuNode, error) {
 	menus, err := rs.menuDao.GetAll(ctx)
 	if err != nil {
@@ -297,26 +641,33 @@ func (rs *RoleService) GetRoleMenuPathTree(ctx context.Context, roleID string) (
 		return nil, err
 	}
 
-	// 获取角色路径信息
-	rolePaths, err := rs.rolePathDao.GetByRoleID(ctx, roleID)
+	// 获取角色直接拥有的路径ID集合
+	rolePathIDs, err := rs.getPathIDs(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 获取路径ID集合
-	rolePathIDs := make([]string, len(rolePaths))
-	for i, path := range rolePaths {
-		rolePathIDs[i] = path.ID
+	// 获取从祖先角色继承的路径ID集合
+	hierarchy, err := rs.roleParentDao.GetHierarchy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var inheritedPathIDs []string
+	for _, ancestorID := range hierarchy.Ancestors(roleID) {
+		ancestorPathIDs, err := rs.getPathIDs(ctx, ancestorID)
+		if err != nil {
+			return nil, err
+		}
+		inheritedPathIDs = append(inheritedPathIDs, ancestorPathIDs...)
 	}
 
 	// 构建角色菜单路径树
-	return buildRoleMenuPathTree(menus, paths, rolePathIDs), nil
+	return buildRoleMenuPathTree(menus, paths, rolePathIDs, inheritedPathIDs), nil
 }
 
-// buildRoleMenuPathTree 构建角色菜单路径树，并标识权限
-func buildRoleMenuPathTree(menus []*entity.Menus, paths []*entity.Paths, rolePaths []string) []*auth.RoleMenuNode {
+// buildRoleMenuPathTree 构建角色菜单路径树，并标识直接拥有和继承的权限
+func buildRoleMenuPathTree(menus []*entity.Menus, paths []*entity.Paths, rolePaths, inheritedPaths []string) []*auth.RoleMenuNode {
 	menuMap := make(map[string]*auth.RoleMenuNode)
-	pathPermitted := make(map[string]bool)
 
 	// 初始化菜单节点
 	for _, menu := range menus {
@@ -335,9 +686,11 @@ func buildRoleMenuPathTree(menus []*entity.Menus, paths []*entity.Paths, rolePat
 	// 添加路径节点并标识权限
 	for _, path := range paths {
 		isPermitted := utils.Contains(rolePaths, path.ID)
-		pathPermitted[path.MenuID] = pathPermitted[path.MenuID] || isPermitted
+		isInherited := utils.Contains(inheritedPaths, path.ID)
 
 		if menuNode, exists := menuMap[path.MenuID]; exists {
+			menuNode.IsPermitted = menuNode.IsPermitted || isPermitted
+			menuNode.IsInherited = menuNode.IsInherited || isInherited
 			menuNode.Paths = append(menuNode.Paths, &auth.RolePathInfo{
 				PathInfo: auth.PathInfo{
 					Path:        path.Path,
@@ -345,19 +698,11 @@ func buildRoleMenuPathTree(menus []*entity.Menus, paths []*entity.Paths, rolePat
 					Description: path.Description,
 				},
 				IsPermitted: isPermitted,
+				IsInherited: isInherited,
 			})
 		}
 	}
 
-	// 递归向上更新菜单的权限状态
-	for _, menu := range menus {
-		if menu.ParentID != "" && menu.ParentID != "null" {
-			if parentMenu, exists := menuMap[menu.ParentID]; exists {
-				parentMenu.IsPermitted = parentMenu.IsPermitted || menuMap[menu.ID].IsPermitted
-			}
-		}
-	}
-
 	// 构建树形结构
 	var tree []*auth.RoleMenuNode
 	for _, menu := range menus {
@@ -368,14 +713,161 @@ func buildRoleMenuPathTree(menus []*entity.Menus, paths []*entity.Paths, rolePat
 		}
 	}
 
+	// 递归向上更新菜单的权限状态
+	for _, node := range tree {
+		propagateRoleMenuPermission(node)
+	}
+
 	return tree
 }
 
-// addRoleConflictCheck 检查角色名是否冲突
-func addRoleConflictCheck(ctx context.Context, roleName string, roleDao *dao.RoleDao) (*entity.Roles, error) {
-	conflictingRole, err := roleDao.GetByName(ctx, roleName)
+// propagateRoleMenuPermission 子菜单拥有权限时，父菜单同样标识为拥有权限
+func propagateRoleMenuPermission(node *auth.RoleMenuNode) {
+	for _, child := range node.MenuData {
+		propagateRoleMenuPermission(child)
+		node.IsPermitted = node.IsPermitted || child.IsPermitted
+		node.IsInherited = node.IsInherited || child.IsInherited
+	}
+}
+
+// hierarchyTransaction 开启事务执行 fn，修改继承关系时先持有角色继承关系锁
+// 继承环由多条继承关系共同形成，锁定单个角色无法阻止两个各自检查通过的修改共同形成环，
+// 因此全部继承关系的修改在同一个命名锁下串行执行；不修改继承关系的事务不加锁。
+func hierarchyTransaction(ctx context.Context, lockHierarchy bool, fn func(tx *gorm.DB) error) error {
+	if !lockHierarchy {
+		return db.Client.WithContext(ctx).Transaction(fn)
+	}
+	return db.WithLock(ctx, db.Client, db.LockRoleHierarchy, db.DefaultLockTimeout, func(conn *gorm.DB) error {
+		return conn.Transaction(fn)
+	})
+}
+
+// checkParentsTx 在事务中检查父角色是否全部存在，且不会形成继承环，返回去重并排序后的父角色ID
+// 事务须由 hierarchyTransaction 在持有角色继承关系锁后开启：全部继承关系的修改因此串行执行，
+// 事务读取的继承关系包含此前全部已提交的修改，各自检查通过的修改不会共同形成环。
+// 父角色在事务结束前保持锁定，避免检查通过后被并发删除。
+func (rs *RoleService) checkParentsTx(ctx context.Context, tx *gorm.DB, roleID string, parentIDs []string) ([]string, error) {
+	uniqueParentIDs := uniqueIDs(parentIDs)
+	if len(uniqueParentIDs) == 0 {
+		return uniqueParentIDs, nil
+	}
+
+	roles, err := rs.roleDao.GetByIDsForUpdateTx(ctx, tx, uniqueParentIDs)
+	if err != nil {
+		logger.Logger.Errorf("[CheckParents] Error locking roles: %v", err)
+		return nil, err
+	}
+	existing := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		existing[role.ID] = struct{}{}
+	}
+	for _, parentID := range uniqueParentIDs {
+		if _, ok := existing[parentID]; !ok {
+			return nil, utils.NewBusinessError(utils.RoleNotFoundCode)
+		}
+	}
+
+	hierarchy, err := rs.roleParentDao.GetHierarchyTx(ctx, tx)
+	if err != nil {
+		logger.Logger.Errorf("[CheckParents] Error fetching role hierarchy: %v", err)
+		return nil, err
+	}
+	if hierarchy.CreatesCycle(roleID, uniqueParentIDs) {
+		logger.Logger.Infof("[CheckParents] Role %s cannot inherit from %v", roleID, uniqueParentIDs)
+		return nil, utils.NewBusinessError(utils.RoleHierarchyCycleCode)
+	}
+	return sortedIDs(uniqueParentIDs), nil
+}
+
+// getHierarchyUserIDsTx 在事务中获取角色及其全部子孙角色下的用户ID
+func (rs *RoleService) getHierarchyUserIDsTx(ctx context.Context, tx *gorm.DB, roleID string) ([]string, error) {
+	hierarchy, err := rs.roleParentDao.GetHierarchyTx(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-	return conflictingRole, nil
+	roleIDs := append([]string{roleID}, hierarchy.Descendants(roleID)...)
+	return rs.userRoleDao.GetUserIDsByRoleIDsTx(ctx, tx, roleIDs)
+}
+
+// refreshUserPermissionsTx 在事务中重新计算角色及其全部子孙角色下用户的权限
+// 返回:
+//   - []string: 重新计算了权限的用户ID，事务提交后需使这些用户的权限缓存失效
+func (rs *RoleService) refreshUserPermissionsTx(ctx context.Context, tx *gorm.DB, roleID string) ([]string, error) {
+	userIDs, err := rs.getHierarchyUserIDsTx(ctx, tx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, rs.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, userIDs)
+}
+
+// roleUpdatedEvents 根据编辑前后的快照构建角色变更事件，状态变化时额外发布禁用或启用事件
+// 快照中的 PathIDs、ParentIDs 仅在调整路径、继承关系时填充。
+func roleUpdatedEvents(ctx context.Context, before, after *roleAuditSnapshot, affectedUserIDs []string) []*event.Event {
+	data := &event.RoleData{
+		RoleID:          after.ID,
+		Name:            after.Name,
+		Status:          after.Status,
+		AffectedUserIDs: sortedIDs(affectedUserIDs),
+	}
+	if before.Name != after.Name {
+		data.Changes = append(data.Changes, event.RoleChangeName)
+	}
+	if before.Description != after.Description {
+		data.Changes = append(data.Changes, event.RoleChangeDescription)
+	}
+	if before.Status != after.Status {
+		data.Changes = append(data.Changes, event.RoleChangeStatus)
+	}
+	if !reflect.DeepEqual(before.PathIDs, after.PathIDs) {
+		data.Changes = append(data.Changes, event.RoleChangePaths)
+		data.PathIDs = after.PathIDs
+	}
+	if !reflect.DeepEqual(before.ParentIDs, after.ParentIDs) {
+		data.Changes = append(data.Changes, event.RoleChangeParents)
+		data.ParentIDs = after.ParentIDs
+	}
+	if len(data.Changes) == 0 {
+		return nil
+	}
+
+	events := []*event.Event{event.New(ctx, event.RoleUpdated, event.SubjectRole, after.ID, data)}
+	if before.Status != after.Status {
+		statusEvent := event.RoleEnabled
+		if after.Status != 1 {
+			statusEvent = event.RoleDisabled
+		}
+		events = append(events, event.New(ctx, statusEvent, event.SubjectRole, after.ID, data))
+	}
+	return events
+}
+
+// buildRoleParents 构建角色继承关系
+func buildRoleParents(roleID string, parentIDs []string) []*entity.RoleParents {
+	roleParents := make([]*entity.RoleParents, 0, len(parentIDs))
+	for _, parentID := range parentIDs {
+		roleParents = append(roleParents, &entity.RoleParents{
+			RoleID:   roleID,
+			ParentID: parentID,
+		})
+	}
+	return roleParents
+}
+
+// getPathIDs 获取角色当前的路径ID
+func (rs *RoleService) getPathIDs(ctx context.Context, roleID string) ([]string, error) {
+	return rs.getPathIDsTx(ctx, db.Client, roleID)
+}
+
+// getPathIDsTx 在事务中获取角色当前的路径ID
+func (rs *RoleService) getPathIDsTx(ctx context.Context, tx *gorm.DB, roleID string) ([]string, error) {
+	paths, err := rs.rolePathDao.GetByRoleIDTx(ctx, tx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	pathIDs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		pathIDs = append(pathIDs, path.ID)
+	}
+	return sortedIDs(pathIDs), nil
 }
@@ -0,0 +1,783 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SCIM 列表查询的分页限制
+const (
+	scimDefaultCount = 100 // 默认每页数量
+	ScimMaxResults   = 200 // 每页最大数量，与 ServiceProviderConfig 中的 filter.maxResults 一致
+)
+
+// scimUserAttributes 可用于过滤用户的 SCIM 属性
+var scimUserAttributes = map[string]utils.ScimAttribute{
+	"id":                 {Column: entity.UsersColumns.ID, Type: utils.ScimAttrString},
+	"username":           {Column: entity.UsersColumns.Username, Type: utils.ScimAttrString},
+	"displayname":        {Column: entity.UsersColumns.Nickname, Type: utils.ScimAttrString},
+	"name.formatted":     {Column: entity.UsersColumns.Nickname, Type: utils.ScimAttrString},
+	"emails":             {Column: entity.UsersColumns.Email, Type: utils.ScimAttrString},
+	"emails.value":       {Column: entity.UsersColumns.Email, Type: utils.ScimAttrString},
+	"phonenumbers":       {Column: entity.UsersColumns.Phone, Type: utils.ScimAttrString},
+	"phonenumbers.value": {Column: entity.UsersColumns.Phone, Type: utils.ScimAttrString},
+	"active":             {Column: entity.UsersColumns.Status, Type: utils.ScimAttrBoolean},
+	"meta.created":       {Column: entity.UsersColumns.CreatedAt, Type: utils.ScimAttrDateTime},
+	"meta.lastmodified":  {Column: entity.UsersColumns.UpdatedAt, Type: utils.ScimAttrDateTime},
+	"groups":             {Membership: "id IN (SELECT user_id FROM user_roles WHERE role_id = ?)"},
+	"groups.value":       {Membership: "id IN (SELECT user_id FROM user_roles WHERE role_id = ?)"},
+}
+
+// scimGroupAttributes 可用于过滤组的 SCIM 属性
+var scimGroupAttributes = map[string]utils.ScimAttribute{
+	"id":                {Column: entity.RolesColumns.ID, Type: utils.ScimAttrString},
+	"displayname":       {Column: entity.RolesColumns.Name, Type: utils.ScimAttrString},
+	"meta.created":      {Column: entity.RolesColumns.CreatedAt, Type: utils.ScimAttrDateTime},
+	"meta.lastmodified": {Column: entity.RolesColumns.UpdatedAt, Type: utils.ScimAttrDateTime},
+	"members":           {Membership: "id IN (SELECT role_id FROM user_roles WHERE user_id = ?)"},
+	"members.value":     {Membership: "id IN (SELECT role_id FROM user_roles WHERE user_id = ?)"},
+}
+
+// ScimService SCIM 2.0 用户开通服务，供身份提供商同步业务用户（User）和角色（Group）
+// 新增、编辑、删除复用 UserService 和 RoleService 的冲突检查、权限重新计算、事件和审计逻辑。
+type ScimService struct {
+	userService *UserService
+	roleService *RoleService
+	userDao     *dao.UserDao
+	roleDao     *dao.RoleDao
+	userRoleDao *dao.UserRoleDao
+}
+
+// NewScimService 创建一个新的 ScimService 实例
+func NewScimService() *ScimService {
+	return &ScimService{
+		userService: NewUserService(),
+		roleService: NewRoleService(),
+		userDao:     dao.NewUserDao(),
+		roleDao:     dao.NewRoleDao(),
+		userRoleDao: dao.NewUserRoleDao(),
+	}
+}
+
+// ListUsers 查询用户列表
+func (ss *ScimService) ListUsers(ctx context.Context, query *scim.ListQuery) (*scim.ListResponse, error) {
+	condition, args, err := scimCondition(query.Filter, scimUserAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	startIndex, count := scimPage(query)
+	users, total, err := ss.userDao.QueryByCondition(ctx, condition, args, startIndex-1, count)
+	if err != nil {
+		logger.Logger.Errorf("[ScimListUsers] Error querying users: %v", err)
+		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+
+	resources, err := ss.userResources(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		list = append(list, resource)
+	}
+	return scimListResponse(total, startIndex, list), nil
+}
+
+// GetUser 获取用户
+func (ss *ScimService) GetUser(ctx context.Context, id string) (*scim.User, error) {
+	_, resource, err := ss.loadUser(ctx, id)
+	return resource, err
+}
+
+// CreateUser 新增用户，未提供密码时生成随机密码；新增的用户没有角色，角色通过组的 members 分配
+func (ss *ScimService) CreateUser(ctx context.Context, resource *scim.User) (*scim.User, error) {
+	active := true
+	if resource.Active != nil {
+		active = *resource.Active
+	}
+	req := &auth.AddUserRequest{
+		UserName: resource.UserName,
+		Nickname: scimNickname(resource),
+		Password: resource.Password,
+		Email:    scimPrimaryValue(resource.Emails),
+		Phone:    scimPhone(scimPrimaryValue(resource.PhoneNumbers)),
+		Status:   scimStatus(active),
+	}
+	if req.Password == "" {
+		password, err := utils.GenerateRandomPassword()
+		if err != nil {
+			logger.Logger.Errorf("[ScimCreateUser] utils.GenerateRandomPassword error: %v", err)
+			return nil, utils.NewBusinessError(utils.PasswordGenerationFailedCode)
+		}
+		req.Password = password
+	}
+	if err := validateScimRequest(req, "Status", "RoleIDList"); err != nil {
+		return nil, err
+	}
+
+	user, err := ss.userService.create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return ss.GetUser(ctx, user.ID)
+}
+
+// ReplaceUser 替换用户，未提供 active 时保持原有状态，提供密码时重置密码
+func (ss *ScimService) ReplaceUser(ctx context.Context, id string, resource *scim.User, ifMatch string) (*scim.User, error) {
+	return ss.replaceUser(ctx, id, ifMatch, func(*scim.User) (*scim.User, error) {
+		return resource, nil
+	})
+}
+
+// PatchUser 按 PATCH 操作修改用户
+func (ss *ScimService) PatchUser(ctx context.Context, id string, patch *scim.PatchRequest, ifMatch string) (*scim.User, error) {
+	return ss.replaceUser(ctx, id, ifMatch, func(current *scim.User) (*scim.User, error) {
+		var patched scim.User
+		if err := applyScimPatch(current, patch, &patched); err != nil {
+			return nil, err
+		}
+		return &patched, nil
+	})
+}
+
+// DeleteUser 删除用户
+func (ss *ScimService) DeleteUser(ctx context.Context, id string, ifMatch string) error {
+	_, current, err := ss.loadUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = checkScimVersion(current.Meta.Version, ifMatch); err != nil {
+		return err
+	}
+	return ss.userService.Delete(ctx, &auth.DelUserRequest{ID: id})
+}
+
+// replaceUser 以 SCIM 资源的内容编辑用户，备注和角色保持不变
+// 锁定用户、校验 If-Match、编辑资料和重置密码在同一个事务中完成，并发的修改不会被覆盖，
+// 重置密码失败时资料也不会被修改。
+// 参数:
+//   - build: 根据用户当前的 SCIM 资源构建替换后的资源
+func (ss *ScimService) replaceUser(ctx context.Context, id, ifMatch string,
+	build func(current *scim.User) (*scim.User, error)) (*scim.User, error) {
+	var (
+		rolesChanged  bool
+		passwordReset bool
+	)
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := ss.userDao.GetByIDForUpdateTx(ctx, tx, id)
+		if err != nil {
+			logger.Logger.Errorf("[ScimReplaceUser] Error retrieving user: %v", err)
+			return utils.NewBusinessError(utils.UserQueryFailedCode)
+		}
+		if user == nil {
+			return utils.NewScimError(http.StatusNotFound, "", fmt.Sprintf("User %s not found", id))
+		}
+		currents, err := ss.userResourcesTx(ctx, tx, []*entity.Users{user})
+		if err != nil {
+			return err
+		}
+		if err = checkScimVersion(currents[0].Meta.Version, ifMatch); err != nil {
+			return err
+		}
+		resource, err := build(currents[0])
+		if err != nil {
+			return err
+		}
+
+		status := user.Status
+		if resource.Active != nil {
+			status = scimStatus(*resource.Active)
+		}
+		req := &auth.EditUserRequest{
+			ID:       user.ID,
+			UserName: resource.UserName,
+			Nickname: scimNickname(resource),
+			Email:    scimPrimaryValue(resource.Emails),
+			Phone:    scimPhone(scimPrimaryValue(resource.PhoneNumbers)),
+			Status:   status,
+			Remark:   user.Remark,
+		}
+		if req.UserName == "" {
+			return utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, "userName is required")
+		}
+		if err = validateScimRequest(req, "Status", "RoleIDList"); err != nil {
+			return err
+		}
+
+		if rolesChanged, err = ss.userService.editTx(ctx, tx, user, req); err != nil {
+			return err
+		}
+		if resource.Password == "" {
+			return nil
+		}
+
+		// 以编辑后的资料校验密码强度
+		edited := *user
+		edited.Username = req.UserName
+		edited.Email = req.Email
+		edited.Phone = req.Phone
+		passwordReset = true
+		return ss.userService.resetPasswordTx(ctx, tx, &edited, resource.Password)
+	})
+	var scimErr *utils.ScimError
+	var businessErr *utils.BusinessError
+	if errors.As(err, &scimErr) || errors.As(err, &businessErr) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, utils.NewBusinessError(utils.UserUpdateFailedCode)
+	}
+
+	ss.userService.invalidateEditedUser(ctx, id, rolesChanged)
+	if passwordReset {
+		ss.userService.revokeUserSessions(ctx, id)
+	}
+	return ss.GetUser(ctx, id)
+}
+
+// loadUser 获取用户及其 SCIM 资源
+func (ss *ScimService) loadUser(ctx context.Context, id string) (*entity.Users, *scim.User, error) {
+	user, err := ss.userDao.GetByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[ScimLoadUser] Error retrieving user: %v", err)
+		return nil, nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+	if user == nil {
+		return nil, nil, utils.NewScimError(http.StatusNotFound, "", fmt.Sprintf("User %s not found", id))
+	}
+
+	resources, err := ss.userResources(ctx, []*entity.Users{user})
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, resources[0], nil
+}
+
+// userResources 将用户转换为 SCIM 资源，一次查询全部用户所属的角色
+func (ss *ScimService) userResources(ctx context.Context, users []*entity.Users) ([]*scim.User, error) {
+	return ss.userResourcesTx(ctx, db.Client, users)
+}
+
+// userResourcesTx 在事务中将用户转换为 SCIM 资源
+func (ss *ScimService) userResourcesTx(ctx context.Context, tx *gorm.DB, users []*entity.Users) ([]*scim.User, error) {
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	members, err := ss.userRoleDao.GetMembersByUserIDsTx(ctx, tx, userIDs)
+	if err != nil {
+		logger.Logger.Errorf("[ScimUserResources] Error retrieving user roles: %v", err)
+		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+	groups := make(map[string][]scim.Reference, len(users))
+	for _, member := range members {
+		groups[member.UserID] = append(groups[member.UserID], scim.Reference{Value: member.RoleID, Display: member.RoleName})
+	}
+
+	baseURL := utils.ScimBaseURL(ctx)
+	resources := make([]*scim.User, 0, len(users))
+	for _, user := range users {
+		active := user.Status == 1
+		resource := &scim.User{
+			Schemas:     []string{scim.SchemaUser},
+			ID:          user.ID,
+			UserName:    user.Username,
+			DisplayName: user.Nickname,
+			Active:      &active,
+			Groups:      groups[user.ID],
+		}
+		if user.Nickname != "" {
+			resource.Name = &scim.Name{Formatted: user.Nickname}
+		}
+		if user.Email != "" {
+			resource.Emails = []scim.MultiValued{{Value: user.Email, Type: "work", Primary: true}}
+		}
+		if user.Phone != "" {
+			resource.PhoneNumbers = []scim.MultiValued{{Value: user.Phone, Type: "work", Primary: true}}
+		}
+
+		version, err := utils.ScimETag(resource)
+		if err != nil {
+			logger.Logger.Errorf("[ScimUserResources] Error computing version: %v", err)
+			return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+		}
+		resource.Meta = scimMeta(scim.ResourceTypeUser, baseURL+"/Users/"+user.ID, user.CreatedAt, user.UpdatedAt, version)
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// ListGroups 查询组列表
+// 参数:
+//   - excludeMembers: 是否不返回成员，成员较多时可减少查询；不返回成员时资源没有 meta.version
+func (ss *ScimService) ListGroups(ctx context.Context, query *scim.ListQuery, excludeMembers bool) (*scim.ListResponse, error) {
+	condition, args, err := scimCondition(query.Filter, scimGroupAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	startIndex, count := scimPage(query)
+	roles, total, err := ss.roleDao.QueryByCondition(ctx, excludeBuiltinRole(condition), args, startIndex-1, count)
+	if err != nil {
+		logger.Logger.Errorf("[ScimListGroups] Error querying roles: %v", err)
+		return nil, utils.NewBusinessError(utils.RoleQueryListFailedCode)
+	}
+
+	resources, err := ss.groupResources(ctx, roles, !excludeMembers)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		list = append(list, resource)
+	}
+	return scimListResponse(total, startIndex, list), nil
+}
+
+// GetGroup 获取组
+func (ss *ScimService) GetGroup(ctx context.Context, id string) (*scim.Group, error) {
+	_, resource, err := ss.loadGroup(ctx, id)
+	return resource, err
+}
+
+// CreateGroup 新增组，即新增一个启用的角色并分配成员
+// 创建角色和分配成员在同一个事务中完成，分配失败时不会留下没有成员的角色。
+func (ss *ScimService) CreateGroup(ctx context.Context, resource *scim.Group) (*scim.Group, error) {
+	req := &auth.AddRoleRequest{
+		Name:   resource.DisplayName,
+		Status: 1,
+	}
+	if err := validateScimRequest(req); err != nil {
+		return nil, err
+	}
+
+	var (
+		role           *entity.Roles
+		changedUserIDs []string
+	)
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		memberIDs := scimReferenceIDs(resource.Members)
+		if err := ss.checkMembersTx(ctx, tx, memberIDs); err != nil {
+			return err
+		}
+
+		var err error
+		if role, err = ss.roleService.createTx(ctx, tx, req); err != nil {
+			return err
+		}
+		changedUserIDs, err = ss.userService.updateRoleMembersTx(ctx, tx, role.ID, memberIDs, nil)
+		return err
+	})
+	var scimErr *utils.ScimError
+	var businessErr *utils.BusinessError
+	if errors.As(err, &scimErr) || errors.As(err, &businessErr) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, utils.NewBusinessError(utils.RoleInsertFailedCode)
+	}
+
+	ss.userService.invalidateMembers(ctx, changedUserIDs)
+	return ss.GetGroup(ctx, role.ID)
+}
+
+// ReplaceGroup 替换组的名称和成员
+func (ss *ScimService) ReplaceGroup(ctx context.Context, id string, resource *scim.Group, ifMatch string) (*scim.Group, error) {
+	return ss.replaceGroup(ctx, id, ifMatch, func(*scim.Group) (*scim.Group, error) {
+		return resource, nil
+	})
+}
+
+// PatchGroup 按 PATCH 操作修改组，常用于增删成员
+func (ss *ScimService) PatchGroup(ctx context.Context, id string, patch *scim.PatchRequest, ifMatch string) (*scim.Group, error) {
+	return ss.replaceGroup(ctx, id, ifMatch, func(current *scim.Group) (*scim.Group, error) {
+		var patched scim.Group
+		if err := applyScimPatch(current, patch, &patched); err != nil {
+			return nil, err
+		}
+		return &patched, nil
+	})
+}
+
+// DeleteGroup 删除组，内置角色不通过 SCIM 暴露，因此不会被删除
+func (ss *ScimService) DeleteGroup(ctx context.Context, id string, ifMatch string) error {
+	_, current, err := ss.loadGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = checkScimVersion(current.Meta.Version, ifMatch); err != nil {
+		return err
+	}
+	return ss.roleService.Delete(ctx, &auth.DelRoleRequest{ID: id})
+}
+
+// replaceGroup 以 SCIM 资源的内容编辑角色名称并调整成员，角色的描述、状态、路径和继承关系保持不变
+// 锁定角色和成员关联、校验 If-Match、重命名和调整成员在同一个事务中完成，
+// 成员差异以锁定后重新读取的成员计算，并发的修改不会被覆盖。
+// 参数:
+//   - build: 根据组当前的 SCIM 资源构建替换后的资源
+func (ss *ScimService) replaceGroup(ctx context.Context, id, ifMatch string,
+	build func(current *scim.Group) (*scim.Group, error)) (*scim.Group, error) {
+	var (
+		renamed         bool
+		affectedUserIDs []string
+		changedUserIDs  []string
+	)
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := ss.roleDao.GetByIDForUpdateTx(ctx, tx, id)
+		if err != nil {
+			logger.Logger.Errorf("[ScimReplaceGroup] Error retrieving role: %v", err)
+			return utils.NewBusinessError(utils.RoleQueryListFailedCode)
+		}
+		// 内置超级管理员角色不通过 SCIM 暴露
+		if role == nil || role.IsBuiltin == 1 {
+			return utils.NewScimError(http.StatusNotFound, "", fmt.Sprintf("Group %s not found", id))
+		}
+		currents, err := ss.groupResourcesTx(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}), []*entity.Roles{role}, true)
+		if err != nil {
+			return err
+		}
+		current := currents[0]
+		if err = checkScimVersion(current.Meta.Version, ifMatch); err != nil {
+			return err
+		}
+		resource, err := build(current)
+		if err != nil {
+			return err
+		}
+
+		if resource.DisplayName != role.Name {
+			req := &auth.EditRoleRequest{
+				ID:          role.ID,
+				Name:        resource.DisplayName,
+				Description: role.Description,
+				Status:      role.Status,
+			}
+			if req.Name == "" {
+				return utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, "displayName is required")
+			}
+			if err = validateScimRequest(req); err != nil {
+				return err
+			}
+			if affectedUserIDs, err = ss.roleService.editTx(ctx, tx, role, req); err != nil {
+				return err
+			}
+			renamed = true
+		}
+
+		desired := scimReferenceIDs(resource.Members)
+		existing := scimReferenceIDs(current.Members)
+		var addUserIDs, removeUserIDs []string
+		for _, id := range desired {
+			if !utils.Contains(existing, id) {
+				addUserIDs = append(addUserIDs, id)
+			}
+		}
+		for _, id := range existing {
+			if !utils.Contains(desired, id) {
+				removeUserIDs = append(removeUserIDs, id)
+			}
+		}
+		if err = ss.checkMembersTx(ctx, tx, addUserIDs); err != nil {
+			return err
+		}
+		changedUserIDs, err = ss.userService.updateRoleMembersTx(ctx, tx, role.ID, addUserIDs, removeUserIDs)
+		return err
+	})
+	var scimErr *utils.ScimError
+	var businessErr *utils.BusinessError
+	if errors.As(err, &scimErr) || errors.As(err, &businessErr) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, utils.NewBusinessError(utils.RoleUpdateFailedCode)
+	}
+
+	if renamed {
+		ss.roleService.invalidateEditedRole(ctx, affectedUserIDs)
+	}
+	ss.userService.invalidateMembers(ctx, changedUserIDs)
+	return ss.GetGroup(ctx, id)
+}
+
+// checkMembersTx 在事务中检查要加入组的用户是否都存在
+func (ss *ScimService) checkMembersTx(ctx context.Context, tx *gorm.DB, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	users, err := ss.userDao.GetByIDsTx(ctx, tx, userIDs)
+	if err != nil {
+		logger.Logger.Errorf("[ScimCheckMembers] Error retrieving users: %v", err)
+		return utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+	found := make(map[string]bool, len(users))
+	for _, user := range users {
+		found[user.ID] = true
+	}
+	for _, id := range userIDs {
+		if !found[id] {
+			return utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, fmt.Sprintf("member %s is not an existing user", id))
+		}
+	}
+	return nil
+}
+
+// loadGroup 获取角色及其 SCIM 资源
+func (ss *ScimService) loadGroup(ctx context.Context, id string) (*entity.Roles, *scim.Group, error) {
+	role, err := ss.roleDao.GetByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[ScimLoadGroup] Error retrieving role: %v", err)
+		return nil, nil, utils.NewBusinessError(utils.RoleQueryListFailedCode)
+	}
+	// 内置超级管理员角色不通过 SCIM 暴露，避免身份提供方修改超级管理员的成员
+	if role == nil || role.IsBuiltin == 1 {
+		return nil, nil, utils.NewScimError(http.StatusNotFound, "", fmt.Sprintf("Group %s not found", id))
+	}
+
+	resources, err := ss.groupResources(ctx, []*entity.Roles{role}, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return role, resources[0], nil
+}
+
+// groupResources 将角色转换为 SCIM 资源，一次查询全部角色的成员
+func (ss *ScimService) groupResources(ctx context.Context, roles []*entity.Roles, withMembers bool) ([]*scim.Group, error) {
+	return ss.groupResourcesTx(ctx, db.Client, roles, withMembers)
+}
+
+// groupResourcesTx 在事务中将角色转换为 SCIM 资源，一次查询全部角色的成员
+func (ss *ScimService) groupResourcesTx(ctx context.Context, tx *gorm.DB, roles []*entity.Roles, withMembers bool) ([]*scim.Group, error) {
+	members := make(map[string][]scim.Reference, len(roles))
+	if withMembers {
+		roleIDs := make([]string, 0, len(roles))
+		for _, role := range roles {
+			roleIDs = append(roleIDs, role.ID)
+		}
+		rows, err := ss.userRoleDao.GetMembersByRoleIDsTx(ctx, tx, roleIDs)
+		if err != nil {
+			logger.Logger.Errorf("[ScimGroupResources] Error retrieving role members: %v", err)
+			return nil, utils.NewBusinessError(utils.RoleQueryListFailedCode)
+		}
+		for _, row := range rows {
+			members[row.RoleID] = append(members[row.RoleID], scim.Reference{Value: row.UserID, Display: row.Username})
+		}
+	}
+
+	baseURL := utils.ScimBaseURL(ctx)
+	resources := make([]*scim.Group, 0, len(roles))
+	for _, role := range roles {
+		resource := &scim.Group{
+			Schemas:     []string{scim.SchemaGroup},
+			ID:          role.ID,
+			DisplayName: role.Name,
+			Members:     members[role.ID],
+		}
+
+		version := ""
+		if withMembers {
+			var err error
+			if version, err = utils.ScimETag(resource); err != nil {
+				logger.Logger.Errorf("[ScimGroupResources] Error computing version: %v", err)
+				return nil, utils.NewBusinessError(utils.RoleQueryListFailedCode)
+			}
+		}
+		resource.Meta = scimMeta(scim.ResourceTypeGroup, baseURL+"/Groups/"+role.ID, role.CreatedAt, role.UpdatedAt, version)
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// scimCondition 将过滤表达式转换为 SQL 条件，表达式为空时不过滤
+func scimCondition(filter string, attributes map[string]utils.ScimAttribute) (string, []interface{}, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", nil, nil
+	}
+	parsed, err := utils.ParseScimFilter(filter)
+	if err != nil {
+		return "", nil, utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidFilter, err.Error())
+	}
+	condition, args, err := parsed.ToSQL(attributes)
+	if err != nil {
+		return "", nil, utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidFilter, err.Error())
+	}
+	return condition, args, nil
+}
+
+// excludeBuiltinRole 在角色查询条件中排除内置超级管理员角色
+func excludeBuiltinRole(condition string) string {
+	builtin := entity.RolesColumns.IsBuiltin + " = 0"
+	if condition == "" {
+		return builtin
+	}
+	return builtin + " AND (" + condition + ")"
+}
+
+// scimPage 计算分页参数，startIndex 小于 1 时按 1 处理，count 超过上限时按上限处理
+func scimPage(query *scim.ListQuery) (int, int) {
+	startIndex := query.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count := scimDefaultCount
+	if query.Count != nil {
+		count = *query.Count
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > ScimMaxResults {
+		count = ScimMaxResults
+	}
+	return startIndex, count
+}
+
+// scimListResponse 构建列表响应
+func scimListResponse(total int64, startIndex int, resources []interface{}) *scim.ListResponse {
+	return &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// scimMeta 构建资源元数据
+func scimMeta(resourceType, location string, createdAt, updatedAt time.Time, version string) *scim.Meta {
+	return &scim.Meta{
+		ResourceType: resourceType,
+		Created:      createdAt.Format(time.RFC3339),
+		LastModified: updatedAt.Format(time.RFC3339),
+		Location:     location,
+		Version:      version,
+	}
+}
+
+// checkScimVersion 校验 If-Match 请求头，为空或为 * 时不校验
+func checkScimVersion(version, ifMatch string) error {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return nil
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == strings.TrimPrefix(version, "W/") {
+			return nil
+		}
+	}
+	return utils.NewScimError(http.StatusPreconditionFailed, "", "Resource version does not match If-Match")
+}
+
+// applyScimPatch 将 PATCH 操作应用到资源上，结果写入 target
+func applyScimPatch(current interface{}, patch *scim.PatchRequest, target interface{}) error {
+	if !utils.Contains(patch.Schemas, scim.SchemaPatchOp) {
+		return utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidSyntax, "schemas must contain "+scim.SchemaPatchOp)
+	}
+
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	var resource map[string]interface{}
+	if err = json.Unmarshal(data, &resource); err != nil {
+		return err
+	}
+
+	if err = utils.ApplyScimPatch(resource, patch.Operations); err != nil {
+		return err
+	}
+
+	// 部分身份提供商以字符串表示布尔值，例如 "active": "False"
+	for key, value := range resource {
+		if s, ok := value.(string); ok && strings.EqualFold(key, "active") {
+			if b, err := strconv.ParseBool(s); err == nil {
+				resource[key] = b
+			}
+		}
+	}
+
+	if data, err = json.Marshal(resource); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, target); err != nil {
+		return utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, err.Error())
+	}
+	return nil
+}
+
+// validateScimRequest 校验由 SCIM 资源构建的请求，excludes 为不参与校验的字段
+func validateScimRequest(req interface{}, excludes ...string) error {
+	if err := validator.New().StructExcept(req, excludes...); err != nil {
+		return utils.NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, err.Error())
+	}
+	return nil
+}
+
+// scimNickname 取 displayName 作为昵称，为空时依次使用 name.formatted 和名、姓的组合
+func scimNickname(resource *scim.User) string {
+	if resource.DisplayName != "" {
+		return resource.DisplayName
+	}
+	if resource.Name == nil {
+		return ""
+	}
+	if resource.Name.Formatted != "" {
+		return resource.Name.Formatted
+	}
+	return strings.TrimSpace(resource.Name.GivenName + " " + resource.Name.FamilyName)
+}
+
+// scimPrimaryValue 取多值属性的主值，没有主值时取第一个值
+func scimPrimaryValue(values []scim.MultiValued) string {
+	for _, value := range values {
+		if value.Primary {
+			return value.Value
+		}
+	}
+	if len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+// scimPhone 去除电话号码中的空格、连字符和括号，以符合 E.164 格式
+func scimPhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(phone)
+}
+
+// scimStatus 将 active 转换为用户状态
+func scimStatus(active bool) int8 {
+	if active {
+		return 1
+	}
+	return 0
+}
+
+// scimReferenceIDs 提取引用的资源ID（去重）
+func scimReferenceIDs(references []scim.Reference) []string {
+	ids := make([]string, 0, len(references))
+	for _, reference := range references {
+		if reference.Value != "" {
+			ids = append(ids, reference.Value)
+		}
+	}
+	return uniqueIDs(ids)
+}
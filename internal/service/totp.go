@@ -0,0 +1,300 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryCodeCount = 10 // 每次生成的恢复码数量
+
+	// mfaLockSubject 二次验证失败计数使用的主体类型，与密码登录的失败计数相互独立
+	mfaLockSubject = "mfa"
+)
+
+// totpCodeRegex 6位数字视为 TOTP 验证码，其余视为恢复码
+var totpCodeRegex = regexp.MustCompile(`^\d{6}$`)
+
+type TotpService struct {
+	adminDao        *dao.AdminDao
+	recoveryCodeDao *dao.AdminRecoveryCodeDao
+	limiter         loginLimiter
+}
+
+// NewTotpService 创建一个新的 TotpService 实例
+func NewTotpService() *TotpService {
+	return &TotpService{
+		adminDao:        dao.NewAdminDao(),
+		recoveryCodeDao: dao.NewAdminRecoveryCodeDao(),
+	}
+}
+
+// Enroll 生成新的 TOTP 密钥，需调用 Activate 校验验证码后才会启用
+func (ts *TotpService) Enroll(ctx context.Context, subjectType, adminId string) (*auth.TotpEnrollResponse, error) {
+	admin, err := ts.getAdmin(ctx, subjectType, adminId)
+	if err != nil {
+		return nil, err
+	}
+	if admin.TotpEnabled == 1 {
+		return nil, utils.NewBusinessError(utils.TotpAlreadyEnabledCode)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		logger.Logger.Errorf("[TotpEnroll] Error generating secret: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+
+	updates := map[string]interface{}{
+		entity.AdminsColumns.TotpSecret: secret,
+		entity.AdminsColumns.UpdatedAt:  time.Now(),
+	}
+	if err = ts.adminDao.Update(ctx, admin.ID, updates); err != nil {
+		logger.Logger.Errorf("[TotpEnroll] Error saving secret for admin %s: %v", admin.ID, err)
+		return nil, utils.NewBusinessError(utils.AdminUpdateFailedCode)
+	}
+
+	return &auth.TotpEnrollResponse{
+		Secret:          secret,
+		ProvisioningUri: utils.TOTPProvisioningURI(conf.GlobalConf.System.Name, admin.Username, secret),
+	}, nil
+}
+
+// Activate 校验身份验证器 App 生成的验证码，通过后启用 TOTP 并返回恢复码
+func (ts *TotpService) Activate(ctx context.Context, subjectType, adminId string, req *auth.TotpCodeRequest) (*auth.TotpRecoveryCodesResponse, error) {
+	admin, err := ts.getAdmin(ctx, subjectType, adminId)
+	if err != nil {
+		return nil, err
+	}
+	if admin.TotpEnabled == 1 {
+		return nil, utils.NewBusinessError(utils.TotpAlreadyEnabledCode)
+	}
+	if admin.TotpSecret == "" {
+		return nil, utils.NewBusinessError(utils.TotpNotEnrolledCode)
+	}
+
+	// 启用前只接受 TOTP 验证码，确保密钥已正确绑定到身份验证器
+	if !totpCodeRegex.MatchString(req.Code) {
+		return nil, utils.NewBusinessError(utils.TotpCodeInvalidCode)
+	}
+	if err = ts.CheckCode(ctx, admin, req.Code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			entity.AdminsColumns.TotpEnabled: 1,
+			entity.AdminsColumns.UpdatedAt:   time.Now(),
+		}
+		if err = ts.adminDao.UpdateTx(ctx, tx, admin.ID, updates); err != nil {
+			logger.Logger.Errorf("[TotpActivate] Error enabling totp for admin %s: %v", admin.ID, err)
+			return err
+		}
+
+		codes, err = ts.replaceRecoveryCodesTx(ctx, tx, admin.ID)
+		return err
+	}); err != nil {
+		return nil, utils.NewBusinessError(utils.AdminUpdateFailedCode)
+	}
+
+	return &auth.TotpRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 校验验证码或恢复码后停用 TOTP，并清除密钥与恢复码
+func (ts *TotpService) Disable(ctx context.Context, subjectType, adminId string, req *auth.TotpCodeRequest) error {
+	admin, err := ts.getAdmin(ctx, subjectType, adminId)
+	if err != nil {
+		return err
+	}
+	if admin.TotpEnabled != 1 {
+		return utils.NewBusinessError(utils.TotpNotEnabledCode)
+	}
+	if err = ts.CheckCode(ctx, admin, req.Code); err != nil {
+		return err
+	}
+
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			entity.AdminsColumns.TotpSecret:  gorm.Expr("NULL"),
+			entity.AdminsColumns.TotpEnabled: 0,
+			entity.AdminsColumns.UpdatedAt:   time.Now(),
+		}
+		if err = ts.adminDao.UpdateTx(ctx, tx, admin.ID, updates); err != nil {
+			logger.Logger.Errorf("[TotpDisable] Error disabling totp for admin %s: %v", admin.ID, err)
+			return err
+		}
+
+		if err = ts.recoveryCodeDao.RemoveByAdminIDTx(ctx, tx, admin.ID); err != nil {
+			logger.Logger.Errorf("[TotpDisable] Error removing recovery codes for admin %s: %v", admin.ID, err)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return utils.NewBusinessError(utils.AdminUpdateFailedCode)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes 校验验证码或恢复码后重新生成恢复码，旧的恢复码全部失效
+func (ts *TotpService) RegenerateRecoveryCodes(ctx context.Context, subjectType, adminId string, req *auth.TotpCodeRequest) (*auth.TotpRecoveryCodesResponse, error) {
+	admin, err := ts.getAdmin(ctx, subjectType, adminId)
+	if err != nil {
+		return nil, err
+	}
+	if admin.TotpEnabled != 1 {
+		return nil, utils.NewBusinessError(utils.TotpNotEnabledCode)
+	}
+	if err = ts.CheckCode(ctx, admin, req.Code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		codes, err = ts.replaceRecoveryCodesTx(ctx, tx, admin.ID)
+		return err
+	}); err != nil {
+		return nil, utils.NewBusinessError(utils.AdminUpdateFailedCode)
+	}
+
+	return &auth.TotpRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// CheckCode 校验 TOTP 验证码或恢复码，失败次数过多时按管理员维度锁定
+func (ts *TotpService) CheckCode(ctx context.Context, admin *entity.Admins, code string) error {
+	clientIP := utils.GetClientIP(ctx)
+	if err := ts.limiter.check(ctx, mfaLockSubject, admin.ID, clientIP); err != nil {
+		return err
+	}
+
+	ok, err := ts.verifyCode(ctx, admin, code)
+	if err != nil {
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	if !ok {
+		if err = ts.limiter.fail(ctx, mfaLockSubject, admin.ID, clientIP); err != nil {
+			return err
+		}
+		return utils.NewBusinessError(utils.TotpCodeInvalidCode)
+	}
+
+	ts.limiter.succeed(ctx, mfaLockSubject, admin.ID)
+	return nil
+}
+
+// verifyCode 校验 TOTP 验证码或恢复码，验证码与恢复码均只能使用一次
+func (ts *TotpService) verifyCode(ctx context.Context, admin *entity.Admins, code string) (bool, error) {
+	if totpCodeRegex.MatchString(code) {
+		ok, step := utils.VerifyTOTP(admin.TotpSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		// 同一时间步的验证码只允许使用一次，防止被截获后重放
+		first, err := redis.ConsumeTotpStep(ctx, admin.ID, step, 5*time.Minute)
+		if err != nil {
+			logger.Logger.Errorf("[TotpVerify] Error consuming totp step of admin %s: %v", admin.ID, err)
+			return false, err
+		}
+		return first, nil
+	}
+
+	if admin.TotpEnabled != 1 {
+		return false, nil
+	}
+
+	recoveryCodes, err := ts.recoveryCodeDao.GetUnusedByAdminID(ctx, admin.ID)
+	if err != nil {
+		logger.Logger.Errorf("[TotpVerify] Error fetching recovery codes of admin %s: %v", admin.ID, err)
+		return false, err
+	}
+
+	normalized := utils.NormalizeRecoveryCode(code)
+	for _, recoveryCode := range recoveryCodes {
+		isMatch, err := utils.VerifyPassword(normalized, recoveryCode.CodeHash)
+		if err != nil {
+			logger.Logger.Errorf("[TotpVerify] Error verifying recovery code: %v", err)
+			return false, err
+		}
+		if !isMatch {
+			continue
+		}
+
+		marked, err := ts.recoveryCodeDao.MarkUsed(ctx, recoveryCode.ID)
+		if err != nil {
+			logger.Logger.Errorf("[TotpVerify] Error marking recovery code %s used: %v", recoveryCode.ID, err)
+			return false, err
+		}
+		if marked {
+			logger.Logger.Infof("[TotpVerify] Admin %s used a recovery code", admin.ID)
+		}
+		return marked, nil
+	}
+
+	return false, nil
+}
+
+// replaceRecoveryCodesTx 在事务中生成新的恢复码并替换旧的恢复码，返回明文恢复码
+func (ts *TotpService) replaceRecoveryCodesTx(ctx context.Context, tx *gorm.DB, adminId string) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		logger.Logger.Errorf("[TotpRecoveryCodes] Error generating recovery codes: %v", err)
+		return nil, err
+	}
+
+	records := make([]*entity.AdminRecoveryCodes, 0, len(codes))
+	for _, code := range codes {
+		hashed, err := utils.EncryptPassword(code)
+		if err != nil {
+			logger.Logger.Errorf("[TotpRecoveryCodes] Error encrypting recovery code: %v", err)
+			return nil, err
+		}
+		records = append(records, &entity.AdminRecoveryCodes{
+			ID:        uuid.New().String(),
+			AdminID:   adminId,
+			CodeHash:  hashed,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	if err = ts.recoveryCodeDao.RemoveByAdminIDTx(ctx, tx, adminId); err != nil {
+		logger.Logger.Errorf("[TotpRecoveryCodes] Error removing recovery codes for admin %s: %v", adminId, err)
+		return nil, err
+	}
+	if err = ts.recoveryCodeDao.InsertBatchTx(ctx, tx, records); err != nil {
+		logger.Logger.Errorf("[TotpRecoveryCodes] Error inserting recovery codes for admin %s: %v", adminId, err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// getAdmin 获取当前登录的管理员，TOTP 仅对管理员开放
+func (ts *TotpService) getAdmin(ctx context.Context, subjectType, adminId string) (*entity.Admins, error) {
+	if subjectType != utils.SubjectTypeAdmin {
+		return nil, utils.NewBusinessError(utils.AdminUnauthorizedCode)
+	}
+
+	admin, err := ts.adminDao.GetByID(ctx, adminId)
+	if err != nil {
+		logger.Logger.Errorf("[Totp] Error fetching admin by ID: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+	if admin == nil {
+		return nil, utils.NewBusinessError(utils.AdminNotFoundCode)
+	}
+	return admin, nil
+}
@@ -2,14 +2,20 @@ package service
 
 import (
 	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/event"
 	"ByteScience-WAM-Admin/internal/model/dto/auth"
 	"ByteScience-WAM-Admin/internal/model/entity"
 	"ByteScience-WAM-Admin/internal/utils"
 	"ByteScience-WAM-Admin/pkg/db"
 	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
 	"context"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -18,6 +24,15 @@ type UserService struct {
 	userRoleDao       *dao.UserRoleDao
 	roleDao           *dao.RoleDao
 	userPermissionDao *dao.UserPermissionDao
+	loginLogDao       *dao.LoginLogDao
+	auditLogDao       *dao.AuditLogDao
+	outboxDao         *dao.OutboxDao
+	deliveryDao       *dao.WebhookDeliveryDao
+	passwordHistory   passwordHistory
+	auditTrail        auditTrail
+	loginRecorder     loginRecorder
+	policyCache       policyCache
+	permissionCache   permissionCache
 }
 
 // NewUserService 创建一个新的 UserService 实例
@@ -27,6 +42,15 @@ func NewUserService() *UserService {
 		userRoleDao:       dao.NewUserRoleDao(),
 		roleDao:           dao.NewRoleDao(),
 		userPermissionDao: dao.NewUserPermissionDao(),
+		loginLogDao:       dao.NewLoginLogDao(),
+		auditLogDao:       dao.NewAuditLogDao(),
+		outboxDao:         dao.NewOutboxDao(),
+		deliveryDao:       dao.NewWebhookDeliveryDao(),
+		passwordHistory:   newPasswordHistory(),
+		auditTrail:        newAuditTrail(),
+		loginRecorder:     newLoginRecorder(),
+		policyCache:       newPolicyCache(),
+		permissionCache:   newPermissionCache(),
 	}
 }
 
@@ -43,35 +67,226 @@ func (us *UserService) checkUserExistence(ctx context.Context, userID string) (*
 	return existingUser, nil
 }
 
+// getRoleAssignments 获取用户当前的角色ID及带有效期的角色分配
+func (us *UserService) getRoleAssignments(ctx context.Context, userID string) ([]string, []roleAssignmentSnapshot, error) {
+	return us.getRoleAssignmentsTx(ctx, db.Client, userID)
+}
+
+// getRoleAssignmentsTx 在事务中获取用户当前的角色ID及带有效期的角色分配
+func (us *UserService) getRoleAssignmentsTx(ctx context.Context, tx *gorm.DB, userID string) ([]string, []roleAssignmentSnapshot, error) {
+	assignments, err := us.userRoleDao.GetAssignmentsByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		logger.Logger.Errorf("[GetRoleAssignments] Error retrieving user roles: %v", err)
+		return nil, nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+
+	userRoles := make([]*entity.UserRoles, 0, len(assignments))
+	for _, assignment := range assignments {
+		userRoles = append(userRoles, &entity.UserRoles{
+			UserID:     userID,
+			RoleID:     assignment.ID,
+			ValidFrom:  assignment.ValidFrom,
+			ValidUntil: assignment.ValidUntil,
+		})
+	}
+	roleIDs, snapshots := userRoleSnapshot(userRoles)
+	return roleIDs, snapshots, nil
+}
+
+// buildUserRoles 合并角色ID列表与角色有效期列表，构建用户角色关联
+// 同一角色同时出现在两个列表中时以有效期为准；失效时间必须晚于生效时间。
+func buildUserRoles(userID string, roleIDs []string, assignments []auth.RoleAssignment) ([]*entity.UserRoles, error) {
+	now := time.Now()
+	userRoles := make([]*entity.UserRoles, 0, len(roleIDs)+len(assignments))
+	indexes := make(map[string]int, len(roleIDs)+len(assignments))
+	for _, roleID := range roleIDs {
+		if _, ok := indexes[roleID]; ok {
+			continue
+		}
+		indexes[roleID] = len(userRoles)
+		userRoles = append(userRoles, &entity.UserRoles{UserID: userID, RoleID: roleID, Active: 1})
+	}
+
+	for _, assignment := range assignments {
+		validFrom, err := parseOptionalTime(assignment.ValidFrom)
+		if err != nil {
+			return nil, utils.NewBusinessError(utils.RoleAssignmentInvalidCode)
+		}
+		validUntil, err := parseOptionalTime(assignment.ValidUntil)
+		if err != nil {
+			return nil, utils.NewBusinessError(utils.RoleAssignmentInvalidCode)
+		}
+		if validFrom != nil && validUntil != nil && !validUntil.After(*validFrom) {
+			return nil, utils.NewBusinessError(utils.RoleAssignmentInvalidCode)
+		}
+
+		userRole := &entity.UserRoles{
+			UserID:     userID,
+			RoleID:     assignment.RoleID,
+			ValidFrom:  validFrom,
+			ValidUntil: validUntil,
+		}
+		if userRoleValidAt(validFrom, validUntil, now) {
+			userRole.Active = 1
+		}
+		if i, ok := indexes[assignment.RoleID]; ok {
+			userRoles[i] = userRole
+			continue
+		}
+		indexes[assignment.RoleID] = len(userRoles)
+		userRoles = append(userRoles, userRole)
+	}
+	return userRoles, nil
+}
+
+// userRoleSnapshot 提取用户角色关联中的角色ID及带有效期的角色分配，用于审计
+func userRoleSnapshot(userRoles []*entity.UserRoles) ([]string, []roleAssignmentSnapshot) {
+	roleIDs := make([]string, 0, len(userRoles))
+	var snapshots []roleAssignmentSnapshot
+	for _, userRole := range userRoles {
+		roleIDs = append(roleIDs, userRole.RoleID)
+		if userRole.ValidFrom == nil && userRole.ValidUntil == nil {
+			continue
+		}
+		snapshots = append(snapshots, roleAssignmentSnapshot{
+			RoleID:     userRole.RoleID,
+			ValidFrom:  userRole.ValidFrom,
+			ValidUntil: userRole.ValidUntil,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].RoleID < snapshots[j].RoleID })
+	return sortedIDs(roleIDs), snapshots
+}
+
+// userUpdatedEvents 根据编辑前后的快照构建用户变更事件
+// 资料变化时发布 user.updated，状态变化时额外发布禁用或启用事件，角色分配变化时发布 user.roles_changed。
+func userUpdatedEvents(ctx context.Context, before, after *userAuditSnapshot) []*event.Event {
+	data := &event.UserData{
+		UserID:   after.ID,
+		UserName: after.Username,
+		Status:   after.Status,
+	}
+
+	var events []*event.Event
+	if before.Username != after.Username || before.Nickname != after.Nickname || before.Email != after.Email ||
+		before.Phone != after.Phone || before.Remark != after.Remark || before.Status != after.Status {
+		events = append(events, event.New(ctx, event.UserUpdated, event.SubjectUser, after.ID, data))
+	}
+	if before.Status != after.Status {
+		statusEvent := event.UserEnabled
+		if after.Status != 1 {
+			statusEvent = event.UserDisabled
+		}
+		events = append(events, event.New(ctx, statusEvent, event.SubjectUser, after.ID, data))
+	}
+	if !reflect.DeepEqual(before.RoleIDs, after.RoleIDs) || !roleAssignmentsEqual(before.RoleAssignments, after.RoleAssignments) {
+		rolesData := &event.UserRolesData{
+			UserID:          after.ID,
+			RoleIDs:         append([]string{}, after.RoleIDs...),
+			PreviousRoleIDs: append([]string{}, before.RoleIDs...),
+		}
+		for _, assignment := range after.RoleAssignments {
+			rolesData.Assignments = append(rolesData.Assignments, event.RoleAssignment{
+				RoleID:     assignment.RoleID,
+				ValidFrom:  formatOptionalTime(assignment.ValidFrom),
+				ValidUntil: formatOptionalTime(assignment.ValidUntil),
+			})
+		}
+		events = append(events, event.New(ctx, event.UserRolesChanged, event.SubjectUser, after.ID, rolesData))
+	}
+	return events
+}
+
+// roleAssignmentsEqual 判断两组带有效期的角色分配是否一致，按时刻而非时区比较时间
+func roleAssignmentsEqual(a, b []roleAssignmentSnapshot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].RoleID != b[i].RoleID || !optionalTimeEqual(a[i].ValidFrom, b[i].ValidFrom) ||
+			!optionalTimeEqual(a[i].ValidUntil, b[i].ValidUntil) {
+			return false
+		}
+	}
+	return true
+}
+
+// optionalTimeEqual 判断两个可选时间是否为同一时刻
+func optionalTimeEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// userRoleValidAt 判断角色分配在指定时间是否处于有效期内
+func userRoleValidAt(validFrom, validUntil *time.Time, now time.Time) bool {
+	if validFrom != nil && validFrom.After(now) {
+		return false
+	}
+	return validUntil == nil || validUntil.After(now)
+}
+
+// parseOptionalTime 解析可选的 RFC3339 时间，空字符串返回 nil
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formatOptionalTime 将可选时间格式化为 RFC3339 字符串，nil 返回空字符串
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 // Add 添加用户
 func (us *UserService) Add(ctx context.Context, req *auth.AddUserRequest) error {
+	_, err := us.create(ctx, req)
+	return err
+}
+
+// create 校验冲突并创建用户及其角色关联，返回创建的用户
+func (us *UserService) create(ctx context.Context, req *auth.AddUserRequest) (*entity.Users, error) {
+	// 校验密码强度
+	if err := utils.ValidatePassword(req.Password, req.UserName, req.Email, req.Phone); err != nil {
+		return nil, err
+	}
+
 	// 密码加密
 	hashedPassword, err := utils.EncryptPassword(req.Password)
 	if err != nil {
 		// 记录加密错误的详细信息
 		logger.Logger.Errorf("[AddAdmin] utils.EncryptPassword error: %v", err)
-		return utils.NewBusinessError(utils.PasswordGenerationFailedCode)
+		return nil, utils.NewBusinessError(utils.PasswordGenerationFailedCode)
 	}
 
 	// 检查是否存在冲突的记录
 	conflictingUser, err := us.dao.GetByFields(ctx, req.UserName, req.Email, req.Phone)
 	if err != nil {
 		logger.Logger.Errorf("[AddUser] Error checking user conflicts: %v", err)
-		return utils.NewBusinessError(utils.UserConflictCheckFailedCode)
+		return nil, utils.NewBusinessError(utils.UserConflictCheckFailedCode)
 	}
 
 	if conflictingUser != nil {
 		if conflictingUser.Username == req.UserName {
 			logger.Logger.Infof("[AddUser] Username %s already exists", req.UserName)
-			return utils.NewBusinessError(utils.UsernameAlreadyExistsCode)
+			return nil, utils.NewBusinessError(utils.UsernameAlreadyExistsCode)
 		}
 		if conflictingUser.Email == req.Email {
 			logger.Logger.Infof("[AddUser] Email %s already exists", req.Email)
-			return utils.NewBusinessError(utils.EmailAlreadyExistsCode)
+			return nil, utils.NewBusinessError(utils.EmailAlreadyExistsCode)
 		}
 		if conflictingUser.Phone == req.Phone {
 			logger.Logger.Infof("[AddUser] Phone %s already exists", req.Phone)
-			return utils.NewBusinessError(utils.PhoneAlreadyExistsCode)
+			return nil, utils.NewBusinessError(utils.PhoneAlreadyExistsCode)
 		}
 	}
 
@@ -87,6 +302,14 @@ func (us *UserService) Add(ctx context.Context, req *auth.AddUserRequest) error
 		Remark:    req.Remark,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+
+		PasswordChangedAt: time.Now(),
+	}
+
+	// 构建用户角色关联
+	userRoles, err := buildUserRoles(user.ID, req.RoleIDList, req.RoleAssignmentList)
+	if err != nil {
+		return nil, err
 	}
 
 	// 开启事务
@@ -97,17 +320,7 @@ func (us *UserService) Add(ctx context.Context, req *auth.AddUserRequest) error
 			return err
 		}
 
-		if len(req.RoleIDList) > 0 {
-			// 构建用户角色关联
-			var userRoles []*entity.UserRoles
-			for _, roleID := range req.RoleIDList {
-				userRole := &entity.UserRoles{
-					UserID: user.ID,
-					RoleID: roleID,
-				}
-				userRoles = append(userRoles, userRole)
-			}
-
+		if len(userRoles) > 0 {
 			// 批量插入用户角色关联
 			if err = us.userRoleDao.InsertBatchTx(ctx, tx, userRoles); err != nil {
 				logger.Logger.Errorf("[AddUser] Error assigning roles: %v", err)
@@ -121,107 +334,183 @@ func (us *UserService) Add(ctx context.Context, req *auth.AddUserRequest) error
 			}
 		}
 
+		after := &userAuditSnapshot{Users: user}
+		after.RoleIDs, after.RoleAssignments = userRoleSnapshot(userRoles)
+
+		// 写入事件发件箱
+		if err = event.RecordTx(ctx, tx, event.New(ctx, event.UserCreated, event.SubjectUser, user.ID, &event.UserData{
+			UserID:   user.ID,
+			UserName: user.Username,
+			Status:   user.Status,
+			RoleIDs:  after.RoleIDs,
+		})); err != nil {
+			logger.Logger.Errorf("[AddUser] Error recording events: %v", err)
+			return err
+		}
+
+		// 记录审计日志
+		if err = us.auditTrail.recordTx(ctx, tx, AuditActionCreate, AuditTargetUser, user.ID, nil, after); err != nil {
+			logger.Logger.Errorf("[AddUser] Error recording audit log: %v", err)
+			return err
+		}
+
 		return nil
 	}); err != nil {
-		return utils.NewBusinessError(utils.UserInsertFailedCode)
+		return nil, utils.NewBusinessError(utils.UserInsertFailedCode)
 	}
 
-	return nil
+	if len(userRoles) > 0 {
+		us.permissionCache.invalidateUsers(ctx, user.ID)
+	}
+
+	return user, nil
 }
 
 // Edit 编辑用户
 func (us *UserService) Edit(ctx context.Context, req *auth.EditUserRequest) error {
 	// 检查用户是否存在
-	_, err := us.checkUserExistence(ctx, req.ID)
+	user, err := us.checkUserExistence(ctx, req.ID)
 	if err != nil {
 		return err
 	}
 
+	// 开启事务
+	var rolesChanged bool
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rolesChanged, err = us.editTx(ctx, tx, user, req)
+		return err
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
+	if err != nil {
+		return utils.NewBusinessError(utils.UserUpdateFailedCode)
+	}
+
+	us.invalidateEditedUser(ctx, req.ID, rolesChanged)
+	return nil
+}
+
+// editTx 在事务中校验冲突并编辑用户信息及角色，同时写入事件和审计日志
+// 返回:
+//   - bool: 是否调整了用户的角色，事务提交后需使该用户的权限缓存失效
+func (us *UserService) editTx(ctx context.Context, tx *gorm.DB, user *entity.Users, req *auth.EditUserRequest) (bool, error) {
 	// 检查是否存在冲突的记录
 	conflictingUser, err := us.dao.GetByFields(ctx, req.UserName, req.Email, req.Phone)
 	if err != nil {
 		logger.Logger.Errorf("[EditUser] Error checking user conflicts: %v", err)
-		return utils.NewBusinessError(utils.UserConflictCheckFailedCode)
+		return false, utils.NewBusinessError(utils.UserConflictCheckFailedCode)
 	}
 	if conflictingUser != nil && conflictingUser.ID != req.ID {
 		if conflictingUser.Username == req.UserName {
 			logger.Logger.Infof("[EditUser] Username %s already exists", req.UserName)
-			return utils.NewBusinessError(utils.UsernameAlreadyExistsCode)
+			return false, utils.NewBusinessError(utils.UsernameAlreadyExistsCode)
 		}
 		if conflictingUser.Email == req.Email {
 			logger.Logger.Infof("[EditUser] Email %s already exists", req.Email)
-			return utils.NewBusinessError(utils.EmailAlreadyExistsCode)
+			return false, utils.NewBusinessError(utils.EmailAlreadyExistsCode)
 		}
 		if conflictingUser.Phone == req.Phone {
 			logger.Logger.Infof("[EditUser] Phone %s already exists", req.Phone)
-			return utils.NewBusinessError(utils.PhoneAlreadyExistsCode)
+			return false, utils.NewBusinessError(utils.PhoneAlreadyExistsCode)
 		}
 	}
 
-	// 开启事务
-	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 更新用户信息
-		updates := map[string]interface{}{
-			entity.UsersColumns.Username:  req.UserName,
-			entity.UsersColumns.Nickname:  req.Nickname,
-			entity.UsersColumns.Email:     req.Email,
-			entity.UsersColumns.Phone:     req.Phone,
-			entity.UsersColumns.Status:    req.Status,
-			entity.UsersColumns.Remark:    req.Remark,
-			entity.UsersColumns.UpdatedAt: time.Now(),
-		}
-
-		if err = us.dao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
-			logger.Logger.Errorf("[EditUser] Error updating user: %v", err)
-			return err
+	// 变更前后的快照，用于审计；仅在调整角色时记录角色变化
+	before := &userAuditSnapshot{Users: user}
+	updated := *user
+	updated.Username = req.UserName
+	updated.Nickname = req.Nickname
+	updated.Email = req.Email
+	updated.Phone = req.Phone
+	updated.Status = req.Status
+	updated.Remark = req.Remark
+	after := &userAuditSnapshot{Users: &updated}
+	userRoles, err := buildUserRoles(req.ID, req.RoleIDList, req.RoleAssignmentList)
+	if err != nil {
+		return false, err
+	}
+	if len(userRoles) > 0 {
+		if before.RoleIDs, before.RoleAssignments, err = us.getRoleAssignments(ctx, req.ID); err != nil {
+			return false, err
 		}
+		after.RoleIDs, after.RoleAssignments = userRoleSnapshot(userRoles)
+	}
 
-		if len(req.RoleIDList) > 0 {
-			// 移除旧的角色关联
-			if err = us.userRoleDao.RemoveByUserIDTx(ctx, tx, req.ID); err != nil {
-				logger.Logger.Errorf("[EditUser] Error removing user roles: %v", err)
-				return err
-			}
+	// 更新用户信息
+	updates := map[string]interface{}{
+		entity.UsersColumns.Username:  req.UserName,
+		entity.UsersColumns.Nickname:  req.Nickname,
+		entity.UsersColumns.Email:     req.Email,
+		entity.UsersColumns.Phone:     req.Phone,
+		entity.UsersColumns.Status:    req.Status,
+		entity.UsersColumns.Remark:    req.Remark,
+		entity.UsersColumns.UpdatedAt: time.Now(),
+	}
 
-			// 构建新的用户角色关联
-			var userRoles []*entity.UserRoles
-			for _, roleID := range req.RoleIDList {
-				userRole := &entity.UserRoles{
-					UserID: req.ID,
-					RoleID: roleID,
-				}
-				userRoles = append(userRoles, userRole)
-			}
+	if err = us.dao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+		logger.Logger.Errorf("[EditUser] Error updating user: %v", err)
+		return false, err
+	}
 
-			// 批量插入新的角色关联
-			if err = us.userRoleDao.InsertBatchTx(ctx, tx, userRoles); err != nil {
-				logger.Logger.Errorf("[EditUser] Error assigning new roles: %v", err)
-				return err
-			}
+	if len(userRoles) > 0 {
+		// 移除旧的角色关联
+		if err = us.userRoleDao.RemoveByUserIDTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[EditUser] Error removing user roles: %v", err)
+			return false, err
+		}
 
-			// 更新受影响用户的权限记录
-			if err = us.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, []string{req.ID}); err != nil {
-				logger.Logger.Errorf("[EditUser] Error update user permissions: %v", err)
-				return err
-			}
+		// 批量插入新的角色关联
+		if err = us.userRoleDao.InsertBatchTx(ctx, tx, userRoles); err != nil {
+			logger.Logger.Errorf("[EditUser] Error assigning new roles: %v", err)
+			return false, err
 		}
 
-		return nil
-	}); err != nil {
-		utils.NewBusinessError(utils.UserUpdateFailedCode)
+		// 更新受影响用户的权限记录
+		if err = us.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, []string{req.ID}); err != nil {
+			logger.Logger.Errorf("[EditUser] Error update user permissions: %v", err)
+			return false, err
+		}
 	}
 
-	return nil
+	// 写入事件发件箱
+	if err = event.RecordTx(ctx, tx, userUpdatedEvents(ctx, before, after)...); err != nil {
+		logger.Logger.Errorf("[EditUser] Error recording events: %v", err)
+		return false, err
+	}
+
+	// 记录审计日志
+	if err = us.auditTrail.recordTx(ctx, tx, AuditActionUpdate, AuditTargetUser, req.ID, before, after); err != nil {
+		logger.Logger.Errorf("[EditUser] Error recording audit log: %v", err)
+		return false, err
+	}
+
+	return len(userRoles) > 0, nil
+}
+
+// invalidateEditedUser 用户状态或角色可能已变化，使该用户的权限缓存和权限决策缓存失效
+func (us *UserService) invalidateEditedUser(ctx context.Context, userID string, rolesChanged bool) {
+	if rolesChanged {
+		us.permissionCache.invalidateUsers(ctx, userID)
+	}
+	us.policyCache.invalidateUsers(ctx, userID)
 }
 
 // Delete 删除用户
 func (us *UserService) Delete(ctx context.Context, req *auth.DelUserRequest) error {
 	// 检查用户是否存在
-	if _, err := us.checkUserExistence(ctx, req.ID); err != nil {
+	user, err := us.checkUserExistence(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	// 删除前的快照，用于审计
+	before := &userAuditSnapshot{Users: user}
+	if before.RoleIDs, before.RoleAssignments, err = us.getRoleAssignments(ctx, req.ID); err != nil {
 		return err
 	}
 
-	if err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 执行软删除
 		if err := us.dao.SoftDeleteByIDTx(ctx, tx, req.ID); err != nil {
 			logger.Logger.Errorf("[DeleteUser] Error deleting user: %v", err)
@@ -240,11 +529,30 @@ func (us *UserService) Delete(ctx context.Context, req *auth.DelUserRequest) err
 			return err
 		}
 
+		// 写入事件发件箱
+		if err := event.RecordTx(ctx, tx, event.New(ctx, event.UserDeleted, event.SubjectUser, user.ID, &event.UserData{
+			UserID:   user.ID,
+			UserName: user.Username,
+			Status:   user.Status,
+		})); err != nil {
+			logger.Logger.Errorf("[DeleteUser] Error recording events: %v", err)
+			return err
+		}
+
+		// 记录审计日志
+		if err := us.auditTrail.recordTx(ctx, tx, AuditActionDelete, AuditTargetUser, req.ID, before, nil); err != nil {
+			logger.Logger.Errorf("[DeleteUser] Error recording audit log: %v", err)
+			return err
+		}
+
 		return nil
 	}); err != nil {
 		return utils.NewBusinessError(utils.UserDeleteFailedCode)
 	}
 
+	us.permissionCache.invalidateUsers(ctx, req.ID)
+	us.policyCache.invalidateUsers(ctx, req.ID)
+
 	return nil
 }
 
@@ -255,21 +563,34 @@ func (us *UserService) Info(ctx context.Context, req *auth.InfoUserRequest) (*au
 		return nil, err
 	}
 
-	// 查询用户角色信息
-	roles, err := us.userRoleDao.GetRolesByUserID(ctx, req.ID)
+	// 查询用户角色信息及有效期
+	assignments, err := us.userRoleDao.GetAssignmentsByUserID(ctx, req.ID)
 	if err != nil {
 		logger.Logger.Errorf("[InfoUser] Error retrieving user roles: %v", err)
 		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
 	}
 
-	roleList := make([]auth.TrimRoleInfo, len(roles))
-	for i, role := range roles {
-		roleList[i] = auth.TrimRoleInfo{
-			ID:   role.ID,
-			Name: role.Name,
+	now := time.Now()
+	roleList := make([]auth.UserRoleInfo, len(assignments))
+	for i, assignment := range assignments {
+		roleList[i] = auth.UserRoleInfo{
+			TrimRoleInfo: auth.TrimRoleInfo{
+				ID:   assignment.ID,
+				Name: assignment.Name,
+			},
+			ValidFrom:  formatOptionalTime(assignment.ValidFrom),
+			ValidUntil: formatOptionalTime(assignment.ValidUntil),
+			Active:     userRoleValidAt(assignment.ValidFrom, assignment.ValidUntil, now),
 		}
 	}
 
+	// 查询最近的登录记录
+	recentLogins, err := us.loginRecorder.recent(ctx, utils.SubjectTypeUser, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[InfoUser] Error retrieving recent logins: %v", err)
+		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+
 	return &auth.InfoUserResponse{
 		ID:          user.ID,
 		UserName:    user.Username,
@@ -282,23 +603,14 @@ func (us *UserService) Info(ctx context.Context, req *auth.InfoUserRequest) (*au
 		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
 		RoleList:    roleList,
+
+		RecentLogins: recentLogins,
 	}, nil
 }
 
 // List 用户列表
 func (us *UserService) List(ctx context.Context, req *auth.ListUserRequest) (*auth.ListUserResponse, error) {
-	filters := map[string]interface{}{
-		entity.UsersColumns.ID:       req.ID,
-		entity.UsersColumns.Username: req.UserName,
-		entity.UsersColumns.Email:    req.Email,
-		entity.UsersColumns.Phone:    req.Phone,
-	}
-
-	if req.Status != nil {
-		filters[entity.UsersColumns.Status] = req.Status
-	}
-
-	users, total, err := us.dao.Query(ctx, req.Page, req.PageSize, filters)
+	users, total, err := us.dao.Query(ctx, req.Page, req.PageSize, buildUserFilters(&req.UserFilter))
 	if err != nil {
 		logger.Logger.Errorf("[ListUser] Error querying users: %v", err)
 		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
@@ -326,16 +638,118 @@ func (us *UserService) List(ctx context.Context, req *auth.ListUserRequest) (*au
 	}, nil
 }
 
+// buildUserFilters 将请求中的筛选条件转换为 DAO 查询条件
+func buildUserFilters(req *auth.UserFilter) map[string]interface{} {
+	filters := map[string]interface{}{
+		entity.UsersColumns.ID:       req.ID,
+		entity.UsersColumns.Username: req.UserName,
+		entity.UsersColumns.Email:    req.Email,
+		entity.UsersColumns.Phone:    req.Phone,
+	}
+
+	if req.Status != nil {
+		filters[entity.UsersColumns.Status] = req.Status
+	}
+	return filters
+}
+
+// userExportRecord 导出的用户及其当前有效的角色名称
+type userExportRecord struct {
+	*entity.Users
+	roleNames []string
+}
+
+// userExportColumns 用户导出的全部列
+var userExportColumns = []exportColumn[*userExportRecord]{
+	{"id", func(r *userExportRecord) string { return r.ID }},
+	{"userName", func(r *userExportRecord) string { return r.Username }},
+	{"nickname", func(r *userExportRecord) string { return r.Nickname }},
+	{"email", func(r *userExportRecord) string { return r.Email }},
+	{"phone", func(r *userExportRecord) string { return r.Phone }},
+	{"status", func(r *userExportRecord) string { return formatExportInt(r.Status) }},
+	{"remark", func(r *userExportRecord) string { return r.Remark }},
+	{"roles", func(r *userExportRecord) string { return strings.Join(r.roleNames, exportSeparator) }},
+	{"lastLoginAt", func(r *userExportRecord) string { return formatExportTime(r.LastLoginAt) }},
+	{"createdAt", func(r *userExportRecord) string { return formatExportTime(r.CreatedAt) }},
+	{"updatedAt", func(r *userExportRecord) string { return formatExportTime(r.UpdatedAt) }},
+}
+
+// Export 按筛选条件导出用户为 CSV 或 XLSX 文件，按创建时间倒序，roles 列为当前有效的角色名称
+// 注意: 数据分批读取并直接写入 w，开始写入后发生的错误无法再以 JSON 形式返回给调用方。
+func (us *UserService) Export(ctx context.Context, req *auth.ExportUserRequest, w io.Writer) error {
+	columns, err := selectExportColumns(userExportColumns, req.Columns)
+	if err != nil {
+		return err
+	}
+
+	filters := buildUserFilters(&req.UserFilter)
+	err = writeExport(w, req.Format, columns, func(write func(records []*userExportRecord) error) error {
+		return us.dao.FindInBatches(ctx, filters, exportBatchSize, func(users []*entity.Users) error {
+			userIDs := make([]string, 0, len(users))
+			for _, user := range users {
+				userIDs = append(userIDs, user.ID)
+			}
+			members, err := us.userRoleDao.GetMembersByUserIDs(ctx, userIDs)
+			if err != nil {
+				return err
+			}
+			roleNames := make(map[string][]string, len(users))
+			for _, member := range members {
+				if member.Active {
+					roleNames[member.UserID] = append(roleNames[member.UserID], member.RoleName)
+				}
+			}
+
+			records := make([]*userExportRecord, 0, len(users))
+			for _, user := range users {
+				records = append(records, &userExportRecord{Users: user, roleNames: roleNames[user.ID]})
+			}
+			return write(records)
+		})
+	})
+	if err != nil {
+		logger.Logger.Errorf("[ExportUser] Error exporting users: %v", err)
+		return utils.NewBusinessError(utils.UserExportFailedCode)
+	}
+	return nil
+}
+
 // ResetPassword 重置用户密码
 func (us *UserService) ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) error {
 	// 检查用户是否存在
-	_, err := us.checkUserExistence(ctx, req.ID)
+	user, err := us.checkUserExistence(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return us.resetPasswordTx(ctx, tx, user, req.NewPassword)
+	})
+	if _, ok := err.(*utils.BusinessError); ok {
+		return err
+	}
 	if err != nil {
+		return utils.NewBusinessError(utils.PasswordResetFailedCode)
+	}
+
+	us.revokeUserSessions(ctx, user.ID)
+	return nil
+}
+
+// resetPasswordTx 在事务中校验并重置用户密码，被重置密码的用户下次登录时必须修改密码
+func (us *UserService) resetPasswordTx(ctx context.Context, tx *gorm.DB, user *entity.Users, newPassword string) error {
+	// 校验密码强度
+	if err := utils.ValidatePassword(newPassword, user.Username, user.Email, user.Phone); err != nil {
+		return err
+	}
+
+	// 不允许重复使用最近的密码
+	if err := us.passwordHistory.check(ctx, utils.SubjectTypeUser, user.ID, user.Password, newPassword); err != nil {
 		return err
 	}
 
 	// 密码加密
-	hashedPassword, err := utils.EncryptPassword(req.NewPassword)
+	hashedPassword, err := utils.EncryptPassword(newPassword)
 	if err != nil {
 		// 记录加密错误的详细信息
 		logger.Logger.Errorf("[ResetPassword] utils.EncryptPassword error: %v", err)
@@ -343,15 +757,190 @@ func (us *UserService) ResetPassword(ctx context.Context, req *auth.ResetPasswor
 	}
 
 	// 更新用户密码信息
+	now := time.Now()
 	updates := map[string]interface{}{
-		entity.UsersColumns.Password:  hashedPassword,
-		entity.UsersColumns.UpdatedAt: time.Now(),
+		entity.UsersColumns.Password:           hashedPassword,
+		entity.UsersColumns.PasswordChangedAt:  now,
+		entity.UsersColumns.MustChangePassword: 1,
+		entity.UsersColumns.UpdatedAt:          now,
 	}
-
-	if err = us.dao.Update(ctx, req.ID, updates); err != nil {
+	if err = us.dao.UpdateTx(ctx, tx, user.ID, updates); err != nil {
 		logger.Logger.Errorf("[ResetPassword] Error updating user password: %v", err)
-		return utils.NewBusinessError(utils.PasswordResetFailedCode)
+		return err
 	}
 
+	if err = us.passwordHistory.recordTx(ctx, tx, utils.SubjectTypeUser, user.ID, user.Password); err != nil {
+		logger.Logger.Errorf("[ResetPassword] Error recording password history: %v", err)
+		return err
+	}
+
+	// 记录审计日志，密码本身不会写入审计日志
+	updated := *user
+	updated.PasswordChangedAt = now
+	updated.MustChangePassword = 1
+	if err = us.auditTrail.recordTx(ctx, tx, AuditActionResetPassword, AuditTargetUser, user.ID, user, &updated); err != nil {
+		logger.Logger.Errorf("[ResetPassword] Error recording audit log: %v", err)
+		return err
+	}
 	return nil
 }
+
+// revokeUserSessions 重置密码后用户已有的登录会话全部失效，失败只记录日志
+func (us *UserService) revokeUserSessions(ctx context.Context, userID string) {
+	if err := redis.RevokeUserTokenFamilies(ctx, userID); err != nil {
+		logger.Logger.Errorf("[ResetPassword] Error revoking token families of user %s: %v", userID, err)
+	}
+}
+
+// roleAssignmentSyncBatchSize 每批重新计算权限的用户数量
+const roleAssignmentSyncBatchSize = 200
+
+// SyncRoleAssignments 按有效期激活或失效用户角色，并重新计算受影响用户的权限
+// 返回:
+//   - int: 重新计算了权限的用户数量
+func (us *UserService) SyncRoleAssignments(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		now := time.Now()
+		userIDs, err := us.userRoleDao.GetOutdatedUserIDs(ctx, now, roleAssignmentSyncBatchSize)
+		if err != nil {
+			logger.Logger.Errorf("[SyncRoleAssignments] Error retrieving outdated user roles: %v", err)
+			return total, err
+		}
+		if len(userIDs) == 0 {
+			return total, nil
+		}
+
+		if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := us.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, userIDs); err != nil {
+				return err
+			}
+			return event.RecordTx(ctx, tx, event.New(ctx, event.UserPermissionsChanged, event.SubjectUser, "", &event.UserPermissionsData{
+				UserIDs: sortedIDs(userIDs),
+				Reason:  event.PermissionsReasonSchedule,
+			}))
+		}); err != nil {
+			logger.Logger.Errorf("[SyncRoleAssignments] Error updating user permissions: %v", err)
+			return total, err
+		}
+		us.permissionCache.invalidateUsers(ctx, userIDs...)
+		us.policyCache.invalidateUsers(ctx, userIDs...)
+		total += len(userIDs)
+
+		if len(userIDs) < roleAssignmentSyncBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// updateRoleMembersTx 在事务中调整角色的成员用户，返回成员关系发生变化的用户ID
+// 增删用户角色关联、重新计算受影响用户的权限并记录事件和审计日志；
+// 新增的关联立即永久生效，已有的关联（包括带有效期的关联）保持不变。
+// 调用方需在事务提交后使返回用户的权限缓存和权限决策缓存失效。
+func (us *UserService) updateRoleMembersTx(ctx context.Context, tx *gorm.DB, roleID string, addUserIDs, removeUserIDs []string) ([]string, error) {
+	userIDs := uniqueIDs(append(append([]string{}, addUserIDs...), removeUserIDs...))
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	// 检查用户是否存在
+	users, err := us.dao.GetByIDsTx(ctx, tx, userIDs)
+	if err != nil {
+		logger.Logger.Errorf("[UpdateRoleMembers] Error retrieving users: %v", err)
+		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+	if len(users) != len(userIDs) {
+		return nil, utils.NewBusinessError(utils.UserNotFoundCode)
+	}
+
+	// 变更前后的快照，用于审计和事件；已经是（或不是）成员的用户不做处理
+	type memberChange struct {
+		before *userAuditSnapshot
+		after  *userAuditSnapshot
+	}
+	var (
+		changes        []memberChange
+		changedUserIDs []string
+		removedUserIDs []string
+		userRoles      []*entity.UserRoles
+	)
+	for _, user := range users {
+		before := &userAuditSnapshot{Users: user}
+		if before.RoleIDs, before.RoleAssignments, err = us.getRoleAssignmentsTx(ctx, tx, user.ID); err != nil {
+			return nil, err
+		}
+		after := &userAuditSnapshot{Users: user}
+		isMember := utils.Contains(before.RoleIDs, roleID)
+		switch {
+		case utils.Contains(addUserIDs, user.ID) && !isMember:
+			userRoles = append(userRoles, &entity.UserRoles{UserID: user.ID, RoleID: roleID, Active: 1})
+			after.RoleIDs = sortedIDs(append(append([]string{}, before.RoleIDs...), roleID))
+			after.RoleAssignments = before.RoleAssignments
+		case utils.Contains(removeUserIDs, user.ID) && isMember:
+			removedUserIDs = append(removedUserIDs, user.ID)
+			after.RoleIDs = make([]string, 0, len(before.RoleIDs))
+			for _, id := range before.RoleIDs {
+				if id != roleID {
+					after.RoleIDs = append(after.RoleIDs, id)
+				}
+			}
+			for _, assignment := range before.RoleAssignments {
+				if assignment.RoleID != roleID {
+					after.RoleAssignments = append(after.RoleAssignments, assignment)
+				}
+			}
+		default:
+			continue
+		}
+		changes = append(changes, memberChange{before: before, after: after})
+		changedUserIDs = append(changedUserIDs, user.ID)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	// 移除用户角色关联
+	if err = us.userRoleDao.RemoveUsersFromRoleTx(ctx, tx, roleID, removedUserIDs); err != nil {
+		logger.Logger.Errorf("[UpdateRoleMembers] Error removing user roles: %v", err)
+		return nil, err
+	}
+
+	// 批量插入新的用户角色关联
+	if len(userRoles) > 0 {
+		if err = us.userRoleDao.InsertBatchTx(ctx, tx, userRoles); err != nil {
+			logger.Logger.Errorf("[UpdateRoleMembers] Error assigning roles: %v", err)
+			return nil, err
+		}
+	}
+
+	// 更新受影响用户的权限记录
+	if err = us.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, changedUserIDs); err != nil {
+		logger.Logger.Errorf("[UpdateRoleMembers] Error update user permissions: %v", err)
+		return nil, err
+	}
+
+	for _, change := range changes {
+		// 写入事件发件箱
+		if err = event.RecordTx(ctx, tx, userUpdatedEvents(ctx, change.before, change.after)...); err != nil {
+			logger.Logger.Errorf("[UpdateRoleMembers] Error recording events: %v", err)
+			return nil, err
+		}
+
+		// 记录审计日志
+		if err = us.auditTrail.recordTx(ctx, tx, AuditActionUpdate, AuditTargetUser, change.after.ID, change.before, change.after); err != nil {
+			logger.Logger.Errorf("[UpdateRoleMembers] Error recording audit log: %v", err)
+			return nil, err
+		}
+	}
+
+	return changedUserIDs, nil
+}
+
+// invalidateMembers 角色成员调整提交后使成员关系发生变化的用户的权限缓存和权限决策缓存失效
+func (us *UserService) invalidateMembers(ctx context.Context, userIDs []string) {
+	if len(userIDs) == 0 {
+		return
+	}
+	us.permissionCache.invalidateUsers(ctx, userIDs...)
+	us.policyCache.invalidateUsers(ctx, userIDs...)
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/event"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 批量导入用户
+const (
+	userImportMaxRows       = 5000 // 单个文件最多导入的数据行数
+	userImportBatchSize     = 100  // 每个事务写入的用户数量
+	userImportRoleSeparator = ";"  // roles 列中多个角色名称的分隔符
+)
+
+// 导入文件的列名（不区分大小写）
+const (
+	userImportColumnUserName = "username"
+	userImportColumnPassword = "password"
+	userImportColumnNickname = "nickname"
+	userImportColumnEmail    = "email"
+	userImportColumnPhone    = "phone"
+	userImportColumnStatus   = "status"
+	userImportColumnRemark   = "remark"
+	userImportColumnRoles    = "roles"
+)
+
+// userImportRow 导入文件中的一行数据及其处理结果
+type userImportRow struct {
+	result    *auth.ImportUserRow
+	req       *auth.AddUserRequest
+	roleNames []string
+	user      *entity.Users
+	userRoles []*entity.UserRoles
+}
+
+// fail 记录该行的失败原因
+func (r *userImportRow) fail(err error) {
+	var businessErr *utils.BusinessError
+	if errors.As(err, &businessErr) {
+		r.result.Error = businessErr.Message
+		return
+	}
+	r.result.Error = err.Error()
+}
+
+// Import 从 CSV 或 XLSX 文件批量导入用户
+// 每一行按新增用户的规则校验，角色按名称匹配；校验通过的行分批在事务中写入并计算新用户的权限记录，
+// 某一批写入失败时仅该批的行记为失败。
+func (us *UserService) Import(ctx context.Context, req *auth.ImportUserRequest) (*auth.ImportUserResponse, error) {
+	limits := conf.GlobalConf.System.Import
+	if req.File.Size > limits.MaxFileSize {
+		logger.Logger.Infof("[ImportUser] File %s is too large: %d bytes", req.File.Filename, req.File.Size)
+		return nil, utils.NewBusinessError(utils.UserImportFileTooLargeCode)
+	}
+
+	records, err := utils.ReadSpreadsheet(req.File, utils.SpreadsheetLimits{
+		MaxRows:           userImportMaxRows + 1,
+		UnzipSizeLimit:    limits.UnzipSizeLimit,
+		UnzipXMLSizeLimit: limits.UnzipXMLSizeLimit,
+	})
+	if errors.Is(err, utils.ErrSpreadsheetTooManyRows) {
+		return nil, utils.NewBusinessError(utils.UserImportTooManyRowsCode)
+	}
+	if err != nil {
+		logger.Logger.Infof("[ImportUser] Error reading file %s: %v", req.File.Filename, err)
+		return nil, utils.NewBusinessError(utils.UserImportFileInvalidCode)
+	}
+
+	rows, err := parseUserImportRows(records)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = us.validateImportRows(ctx, rows); err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		us.writeImportRows(ctx, rows)
+	}
+
+	res := &auth.ImportUserResponse{
+		DryRun: req.DryRun,
+		Total:  len(rows),
+		Rows:   make([]auth.ImportUserRow, 0, len(rows)),
+	}
+	for _, row := range rows {
+		row.result.Success = row.result.Error == ""
+		if row.result.Success {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+		res.Rows = append(res.Rows, *row.result)
+	}
+	return res, nil
+}
+
+// parseUserImportRows 按表头解析导入文件的数据行，忽略空行
+func parseUserImportRows(records [][]string) ([]*userImportRow, error) {
+	if len(records) == 0 {
+		return nil, utils.NewBusinessError(utils.UserImportFileInvalidCode)
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{userImportColumnUserName, userImportColumnPassword} {
+		if _, ok := columns[required]; !ok {
+			logger.Logger.Infof("[ImportUser] Missing column %s", required)
+			return nil, utils.NewBusinessError(utils.UserImportFileInvalidCode)
+		}
+	}
+
+	rows := make([]*userImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		cell := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := &userImportRow{
+			result: &auth.ImportUserRow{Row: i + 2, UserName: cell(userImportColumnUserName)},
+			req: &auth.AddUserRequest{
+				UserName:   cell(userImportColumnUserName),
+				Nickname:   cell(userImportColumnNickname),
+				Password:   cell(userImportColumnPassword),
+				Email:      cell(userImportColumnEmail),
+				Phone:      cell(userImportColumnPhone),
+				Status:     1,
+				Remark:     cell(userImportColumnRemark),
+				RoleIDList: []string{},
+			},
+		}
+		switch status := cell(userImportColumnStatus); status {
+		case "", "1":
+		case "0":
+			row.req.Status = 0
+		default:
+			row.result.Error = "Field 'Status' oneof (expected: 0 1)"
+		}
+		for _, name := range strings.Split(cell(userImportColumnRoles), userImportRoleSeparator) {
+			if name = strings.TrimSpace(name); name != "" {
+				row.roleNames = append(row.roleNames, name)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// validateImportRows 校验每一行：参数规则、密码策略、角色名称、文件内重复及与已有用户的冲突
+// 校验失败的原因记录在行结果中，只有查询失败时返回错误
+func (us *UserService) validateImportRows(ctx context.Context, rows []*userImportRow) error {
+	// 按名称匹配角色
+	var roleNames []string
+	for _, row := range rows {
+		roleNames = append(roleNames, row.roleNames...)
+	}
+	roles, err := us.roleDao.GetByNames(ctx, uniqueIDs(roleNames))
+	if err != nil {
+		logger.Logger.Errorf("[ImportUser] Error retrieving roles: %v", err)
+		return utils.NewBusinessError(utils.RoleQueryListFailedCode)
+	}
+	roleIDs := make(map[string]string, len(roles))
+	for _, role := range roles {
+		roleIDs[role.Name] = role.ID
+	}
+
+	// 记录文件内已出现的用户名、邮箱和手机号所在的行
+	seen := map[string]map[string]int{
+		userImportColumnUserName: {},
+		userImportColumnEmail:    {},
+		userImportColumnPhone:    {},
+	}
+	// 通过以上校验、待检查与已有用户冲突的行
+	var candidates []*userImportRow
+
+	for _, row := range rows {
+		if row.result.Error != "" {
+			continue
+		}
+
+		if err = utils.ValidateStruct(row.req, "Status"); err != nil {
+			row.fail(err)
+			continue
+		}
+		if err = utils.ValidatePassword(row.req.Password, row.req.UserName, row.req.Email, row.req.Phone); err != nil {
+			row.fail(err)
+			continue
+		}
+
+		for _, name := range row.roleNames {
+			roleID, ok := roleIDs[name]
+			if !ok {
+				row.result.Error = fmt.Sprintf("Role '%s' not found", name)
+				break
+			}
+			row.req.RoleIDList = append(row.req.RoleIDList, roleID)
+		}
+		if row.result.Error != "" {
+			continue
+		}
+
+		// 检查文件内是否重复
+		identities := [][2]string{
+			{userImportColumnUserName, row.req.UserName},
+			{userImportColumnEmail, row.req.Email},
+			{userImportColumnPhone, row.req.Phone},
+		}
+		for _, identity := range identities {
+			if first, ok := seen[identity[0]][identity[1]]; ok && identity[1] != "" {
+				row.result.Error = fmt.Sprintf("Duplicate %s with row %d", identity[0], first)
+				break
+			}
+		}
+		if row.result.Error != "" {
+			continue
+		}
+		for _, identity := range identities {
+			if identity[1] != "" {
+				seen[identity[0]][identity[1]] = row.result.Row
+			}
+		}
+
+		candidates = append(candidates, row)
+	}
+
+	// 按批检查是否与已有用户冲突
+	for start := 0; start < len(candidates); start += userImportBatchSize {
+		end := start + userImportBatchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		if err = us.checkImportConflicts(ctx, candidates[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkImportConflicts 一次查询一批行的用户名、邮箱和手机号，与已有用户冲突的行记为失败
+func (us *UserService) checkImportConflicts(ctx context.Context, rows []*userImportRow) error {
+	var usernames, emails, phones []string
+	for _, row := range rows {
+		usernames = append(usernames, row.req.UserName)
+		if row.req.Email != "" {
+			emails = append(emails, row.req.Email)
+		}
+		if row.req.Phone != "" {
+			phones = append(phones, row.req.Phone)
+		}
+	}
+
+	users, err := us.dao.GetByIdentities(ctx, usernames, emails, phones)
+	if err != nil {
+		logger.Logger.Errorf("[ImportUser] Error checking user conflicts: %v", err)
+		return utils.NewBusinessError(utils.UserConflictCheckFailedCode)
+	}
+	existing := map[string]map[string]bool{
+		userImportColumnUserName: {},
+		userImportColumnEmail:    {},
+		userImportColumnPhone:    {},
+	}
+	for _, user := range users {
+		existing[userImportColumnUserName][user.Username] = true
+		existing[userImportColumnEmail][user.Email] = true
+		existing[userImportColumnPhone][user.Phone] = true
+	}
+
+	for _, row := range rows {
+		switch {
+		case existing[userImportColumnUserName][row.req.UserName]:
+			row.fail(utils.NewBusinessError(utils.UsernameAlreadyExistsCode))
+		case row.req.Email != "" && existing[userImportColumnEmail][row.req.Email]:
+			row.fail(utils.NewBusinessError(utils.EmailAlreadyExistsCode))
+		case row.req.Phone != "" && existing[userImportColumnPhone][row.req.Phone]:
+			row.fail(utils.NewBusinessError(utils.PhoneAlreadyExistsCode))
+		}
+	}
+	return nil
+}
+
+// writeImportRows 分批写入校验通过的行，写入失败的批次中的行记为失败
+func (us *UserService) writeImportRows(ctx context.Context, rows []*userImportRow) {
+	var valid []*userImportRow
+	for _, row := range rows {
+		if row.result.Error == "" {
+			valid = append(valid, row)
+		}
+	}
+
+	for start := 0; start < len(valid); start += userImportBatchSize {
+		end := start + userImportBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+
+		batch := us.buildImportBatch(valid[start:end])
+		if len(batch) == 0 {
+			continue
+		}
+		if err := us.insertImportBatch(ctx, batch); err != nil {
+			for _, row := range batch {
+				row.fail(utils.NewBusinessError(utils.UserInsertFailedCode))
+			}
+			continue
+		}
+
+		var permissionUserIDs []string
+		for _, row := range batch {
+			row.result.UserID = row.user.ID
+			if len(row.userRoles) > 0 {
+				permissionUserIDs = append(permissionUserIDs, row.user.ID)
+			}
+		}
+		if len(permissionUserIDs) > 0 {
+			us.permissionCache.invalidateUsers(ctx, permissionUserIDs...)
+		}
+	}
+}
+
+// buildImportBatch 构建一批用户实体及用户角色关联，密码加密失败的行记为失败
+func (us *UserService) buildImportBatch(rows []*userImportRow) []*userImportRow {
+	batch := make([]*userImportRow, 0, len(rows))
+	for _, row := range rows {
+		hashedPassword, err := utils.EncryptPassword(row.req.Password)
+		if err != nil {
+			logger.Logger.Errorf("[ImportUser] utils.EncryptPassword error: %v", err)
+			row.fail(utils.NewBusinessError(utils.PasswordGenerationFailedCode))
+			continue
+		}
+
+		now := time.Now()
+		row.user = &entity.Users{
+			ID:        uuid.New().String(),
+			Username:  row.req.UserName,
+			Password:  hashedPassword,
+			Nickname:  row.req.Nickname,
+			Email:     row.req.Email,
+			Phone:     row.req.Phone,
+			Status:    row.req.Status,
+			Remark:    row.req.Remark,
+			CreatedAt: now,
+			UpdatedAt: now,
+
+			PasswordChangedAt: now,
+		}
+		if row.userRoles, err = buildUserRoles(row.user.ID, row.req.RoleIDList, nil); err != nil {
+			row.fail(err)
+			continue
+		}
+		batch = append(batch, row)
+	}
+	return batch
+}
+
+// insertImportBatch 在一个事务中写入一批用户、用户角色关联、权限记录、事件和审计日志
+func (us *UserService) insertImportBatch(ctx context.Context, batch []*userImportRow) error {
+	users := make([]*entity.Users, 0, len(batch))
+	var (
+		userRoles         []*entity.UserRoles
+		permissionUserIDs []string
+	)
+	for _, row := range batch {
+		users = append(users, row.user)
+		userRoles = append(userRoles, row.userRoles...)
+		if len(row.userRoles) > 0 {
+			permissionUserIDs = append(permissionUserIDs, row.user.ID)
+		}
+	}
+
+	return db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 批量插入用户
+		if err := us.dao.InsertBatchTx(ctx, tx, users); err != nil {
+			logger.Logger.Errorf("[ImportUser] Error inserting users: %v", err)
+			return err
+		}
+
+		if len(userRoles) > 0 {
+			// 批量插入用户角色关联
+			if err := us.userRoleDao.InsertBatchTx(ctx, tx, userRoles); err != nil {
+				logger.Logger.Errorf("[ImportUser] Error assigning roles: %v", err)
+				return err
+			}
+
+			// 更新新用户的权限记录
+			if err := us.userPermissionDao.UpdateUserPermissionsTx(ctx, tx, permissionUserIDs); err != nil {
+				logger.Logger.Errorf("[ImportUser] Error update user permissions: %v", err)
+				return err
+			}
+		}
+
+		for _, row := range batch {
+			after := &userAuditSnapshot{Users: row.user}
+			after.RoleIDs, after.RoleAssignments = userRoleSnapshot(row.userRoles)
+
+			// 写入事件发件箱
+			if err := event.RecordTx(ctx, tx, event.New(ctx, event.UserCreated, event.SubjectUser, row.user.ID, &event.UserData{
+				UserID:   row.user.ID,
+				UserName: row.user.Username,
+				Status:   row.user.Status,
+				RoleIDs:  after.RoleIDs,
+			})); err != nil {
+				logger.Logger.Errorf("[ImportUser] Error recording events: %v", err)
+				return err
+			}
+
+			// 记录审计日志
+			if err := us.auditTrail.recordTx(ctx, tx, AuditActionCreate, AuditTargetUser, row.user.ID, nil, after); err != nil {
+				logger.Logger.Errorf("[ImportUser] Error recording audit log: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
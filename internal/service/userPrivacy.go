@@ -0,0 +1,343 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/event"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// erasedUsernamePrefix 擦除后的用户名前缀，用户名为 前缀 + 用户ID，保证唯一且不含个人信息
+const erasedUsernamePrefix = "erased-"
+
+// userEventNameField 用户事件数据（event.UserData）中用户名的字段名
+const userEventNameField = "userName"
+
+// getUserWithDeleted 获取用户，包括已软删除的用户
+func (us *UserService) getUserWithDeleted(ctx context.Context, userID string) (*entity.Users, error) {
+	user, err := us.dao.GetByIDWithDeleted(ctx, userID)
+	if err != nil {
+		logger.Logger.Errorf("[GetUserWithDeleted] Error retrieving user: %v", err)
+		return nil, utils.NewBusinessError(utils.UserQueryFailedCode)
+	}
+	if user == nil {
+		return nil, utils.NewBusinessError(utils.UserNotFoundCode)
+	}
+	return user, nil
+}
+
+// loginIdentifiers 返回用户可用于登录的标识（用户名、邮箱、手机号），用于匹配账号不存在时记录的登录日志
+func loginIdentifiers(user *entity.Users) []string {
+	var identifiers []string
+	for _, identifier := range []string{user.Username, user.Email, user.Phone} {
+		if identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return identifiers
+}
+
+// PersonalData 导出用户的全部个人数据：用户资料、角色、权限、登录记录和审计日志
+func (us *UserService) PersonalData(ctx context.Context, req *auth.PersonalDataRequest) (*auth.PersonalDataResponse, error) {
+	user, err := us.getUserWithDeleted(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := us.userRoleDao.GetAssignmentsByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Logger.Errorf("[PersonalData] Error retrieving user roles: %v", err)
+		return nil, utils.NewBusinessError(utils.UserDataExportFailedCode)
+	}
+
+	permissionPaths, err := us.userPermissionDao.GetPermissionPathsByUserIDs(ctx, []string{user.ID})
+	if err != nil {
+		logger.Logger.Errorf("[PersonalData] Error retrieving user permissions: %v", err)
+		return nil, utils.NewBusinessError(utils.UserDataExportFailedCode)
+	}
+
+	loginLogs, err := us.loginLogDao.GetByAccount(ctx, utils.SubjectTypeUser, user.ID, loginIdentifiers(user))
+	if err != nil {
+		logger.Logger.Errorf("[PersonalData] Error retrieving login logs: %v", err)
+		return nil, utils.NewBusinessError(utils.UserDataExportFailedCode)
+	}
+
+	auditLogs, err := us.auditLogDao.GetBySubject(ctx, utils.SubjectTypeUser, user.ID)
+	if err != nil {
+		logger.Logger.Errorf("[PersonalData] Error retrieving audit logs: %v", err)
+		return nil, utils.NewBusinessError(utils.UserDataExportFailedCode)
+	}
+
+	// 转换数据格式为响应模型
+	res := &auth.PersonalDataResponse{
+		ExportedAt: time.Now().Format(time.RFC3339),
+		User: auth.PersonalDataUser{
+			ID:                 user.ID,
+			UserName:           user.Username,
+			Nickname:           user.Nickname,
+			Email:              user.Email,
+			Phone:              user.Phone,
+			Status:             user.Status,
+			Remark:             user.Remark,
+			LastLoginAt:        formatExportTime(user.LastLoginAt),
+			PasswordChangedAt:  formatExportTime(user.PasswordChangedAt),
+			MustChangePassword: user.MustChangePassword,
+			CreatedAt:          formatExportTime(user.CreatedAt),
+			UpdatedAt:          formatExportTime(user.UpdatedAt),
+			DeletedAt:          formatExportTime(user.DeletedAt),
+		},
+		Roles:       make([]auth.PersonalDataRole, 0, len(assignments)),
+		Permissions: make([]auth.PersonalDataPermission, 0, len(permissionPaths[user.ID])),
+		LoginLogs:   make([]auth.LoginLogInfo, 0, len(loginLogs)),
+		AuditLogs:   make([]auth.AuditLogInfo, 0, len(auditLogs)),
+	}
+	for _, assignment := range assignments {
+		res.Roles = append(res.Roles, auth.PersonalDataRole{
+			RoleID:     assignment.ID,
+			Name:       assignment.Name,
+			ValidFrom:  formatOptionalTime(assignment.ValidFrom),
+			ValidUntil: formatOptionalTime(assignment.ValidUntil),
+			Active:     assignment.Active == 1,
+		})
+	}
+	for _, path := range permissionPaths[user.ID] {
+		res.Permissions = append(res.Permissions, auth.PersonalDataPermission{
+			Method: path.Method,
+			Path:   path.Path,
+		})
+	}
+	for _, loginLog := range loginLogs {
+		res.LoginLogs = append(res.LoginLogs, loginLogInfo(loginLog))
+	}
+	for _, auditLog := range auditLogs {
+		res.AuditLogs = append(res.AuditLogs, auditLogInfo(auditLog))
+	}
+
+	return res, nil
+}
+
+// ErasureToken 申请擦除用户个人数据的确认令牌
+// 令牌只返回一次，服务端只保存摘要，且只能由申请人在有效期内对同一用户使用一次。
+func (us *UserService) ErasureToken(ctx context.Context, req *auth.ErasureTokenRequest) (*auth.ErasureTokenResponse, error) {
+	if _, err := us.getUserWithDeleted(ctx, req.ID); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateConfirmationToken()
+	if err != nil {
+		logger.Logger.Errorf("[ErasureToken] Error generating confirmation token: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+
+	actorID, _ := utils.GetActor(ctx)
+	ttl := conf.GlobalConf.System.Security.ErasureTokenTTL
+	if err = redis.SaveErasureToken(ctx, actorID, req.ID, utils.HashConfirmationToken(token), ttl); err != nil {
+		logger.Logger.Errorf("[ErasureToken] Error saving confirmation token: %v", err)
+		return nil, utils.NewBusinessError(utils.InternalError)
+	}
+
+	return &auth.ErasureTokenResponse{
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl).Format(time.RFC3339),
+	}, nil
+}
+
+// Erase 擦除用户个人数据
+// 用户名替换为 erased-<用户ID>，昵称、邮箱、手机号、备注置空，密码替换为随机密码并禁用用户；
+// 同时匿名化登录日志、审计日志、事件发件箱和 Webhook 投递记录中的个人信息并清除历史密码。
+// 用户ID及角色、审计等关联记录保持不变。
+func (us *UserService) Erase(ctx context.Context, req *auth.EraseUserRequest) error {
+	user, err := us.getUserWithDeleted(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	// 校验并作废确认令牌，令牌只能使用一次
+	actorID, _ := utils.GetActor(ctx)
+	valid, err := redis.ConsumeErasureToken(ctx, actorID, req.ID, utils.HashConfirmationToken(req.Token))
+	if err != nil {
+		logger.Logger.Errorf("[EraseUser] Error consuming confirmation token: %v", err)
+		return utils.NewBusinessError(utils.InternalError)
+	}
+	if !valid {
+		return utils.NewBusinessError(utils.ErasureTokenInvalidCode)
+	}
+
+	// 替换为无人知晓的随机密码
+	password, err := utils.GenerateRandomPassword()
+	if err != nil {
+		logger.Logger.Errorf("[EraseUser] utils.GenerateRandomPassword error: %v", err)
+		return utils.NewBusinessError(utils.PasswordGenerationFailedCode)
+	}
+	hashedPassword, err := utils.EncryptPassword(password)
+	if err != nil {
+		logger.Logger.Errorf("[EraseUser] utils.EncryptPassword error: %v", err)
+		return utils.NewBusinessError(utils.PasswordGenerationFailedCode)
+	}
+
+	erasedUsername := erasedUsernamePrefix + user.ID
+	updates := map[string]interface{}{
+		entity.UsersColumns.Username:  erasedUsername,
+		entity.UsersColumns.Nickname:  nil,
+		entity.UsersColumns.Email:     nil,
+		entity.UsersColumns.Phone:     nil,
+		entity.UsersColumns.Remark:    nil,
+		entity.UsersColumns.Password:  hashedPassword,
+		entity.UsersColumns.Status:    0,
+		entity.UsersColumns.UpdatedAt: time.Now(),
+	}
+
+	// 审计日志快照中的个人信息替换为擦除后的值
+	replacements := map[string]interface{}{
+		entity.UsersColumns.Username: erasedUsername,
+		entity.UsersColumns.Nickname: "",
+		entity.UsersColumns.Email:    "",
+		entity.UsersColumns.Phone:    "",
+		entity.UsersColumns.Remark:   "",
+	}
+
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := us.dao.UpdateTx(ctx, tx, user.ID, updates); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error updating user: %v", err)
+			return err
+		}
+
+		if err := us.passwordHistory.removeTx(ctx, tx, utils.SubjectTypeUser, user.ID); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error removing password history: %v", err)
+			return err
+		}
+
+		// 登录日志保留账号ID，登录标识替换为擦除后的用户名
+		if err := us.loginLogDao.AnonymizeByAccountTx(ctx, tx, utils.SubjectTypeUser, user.ID,
+			loginIdentifiers(user), erasedUsername); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error anonymizing login logs: %v", err)
+			return err
+		}
+
+		if err := us.anonymizeAuditLogsTx(ctx, tx, user.ID, replacements); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error anonymizing audit logs: %v", err)
+			return err
+		}
+
+		// 已记录的用户事件及其 Webhook 投递内容中的用户名替换为擦除后的用户名
+		if err := us.outboxDao.AnonymizeSubjectFieldTx(ctx, tx, event.SubjectUser, user.ID,
+			userEventNameField, erasedUsername); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error anonymizing outbox payloads: %v", err)
+			return err
+		}
+		if err := us.deliveryDao.AnonymizeSubjectFieldTx(ctx, tx, event.SubjectUser, user.ID,
+			userEventNameField, erasedUsername); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error anonymizing webhook delivery payloads: %v", err)
+			return err
+		}
+
+		// 已删除的用户不再发布变更事件
+		if user.DeletedAt.IsZero() {
+			before := &userAuditSnapshot{Users: user}
+			after := &userAuditSnapshot{Users: &entity.Users{ID: user.ID, Username: erasedUsername}}
+			if err := event.RecordTx(ctx, tx, userUpdatedEvents(ctx, before, after)...); err != nil {
+				logger.Logger.Errorf("[EraseUser] Error recording events: %v", err)
+				return err
+			}
+		}
+
+		// 审计日志只记录擦除后的用户名，不保留被擦除的个人信息
+		if err := us.auditTrail.recordTx(ctx, tx, AuditActionErase, AuditTargetUser, user.ID, nil, map[string]interface{}{
+			entity.UsersColumns.Username: erasedUsername,
+			entity.UsersColumns.Status:   0,
+		}); err != nil {
+			logger.Logger.Errorf("[EraseUser] Error recording audit log: %v", err)
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return utils.NewBusinessError(utils.UserEraseFailedCode)
+	}
+
+	// 擦除后用户已有的登录会话全部失效
+	if err = redis.RevokeUserTokenFamilies(ctx, user.ID); err != nil {
+		logger.Logger.Errorf("[EraseUser] Error revoking token families of user %s: %v", user.ID, err)
+	}
+	us.permissionCache.invalidateUsers(ctx, user.ID)
+	us.policyCache.invalidateUsers(ctx, user.ID)
+
+	return nil
+}
+
+// anonymizeAuditLogsTx 在事务中匿名化与用户相关的审计日志
+// 以用户为操作对象的记录替换变更快照中的个人信息，由用户本人操作的记录清除客户端IP和 User-Agent。
+func (us *UserService) anonymizeAuditLogsTx(ctx context.Context, tx *gorm.DB, userID string, replacements map[string]interface{}) error {
+	auditLogs, err := us.auditLogDao.GetBySubjectTx(ctx, tx, utils.SubjectTypeUser, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, auditLog := range auditLogs {
+		updates := make(map[string]interface{})
+		if auditLog.TargetType == AuditTargetUser && auditLog.TargetID == userID {
+			for column, values := range map[string]string{
+				entity.AuditLogsColumns.OldValues: auditLog.OldValues,
+				entity.AuditLogsColumns.NewValues: auditLog.NewValues,
+			} {
+				anonymized, changed, err := anonymizeAuditValues(values, replacements)
+				if err != nil {
+					return err
+				}
+				if changed {
+					updates[column] = anonymized
+				}
+			}
+		}
+		if auditLog.ActorType == utils.SubjectTypeUser && auditLog.ActorID == userID &&
+			(auditLog.ClientIP != "" || auditLog.UserAgent != "") {
+			updates[entity.AuditLogsColumns.ClientIP] = ""
+			updates[entity.AuditLogsColumns.UserAgent] = ""
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		if err = us.auditLogDao.UpdateTx(ctx, tx, auditLog.ID, updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anonymizeAuditValues 替换审计快照（JSON 对象）中出现的字段，返回替换后的 JSON 及是否发生变化
+func anonymizeAuditValues(values string, replacements map[string]interface{}) (string, bool, error) {
+	if values == "" {
+		return values, false, nil
+	}
+
+	snapshot := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(values), &snapshot); err != nil {
+		return "", false, err
+	}
+
+	changed := false
+	for field, replacement := range replacements {
+		if value, ok := snapshot[field]; ok && value != replacement {
+			snapshot[field] = replacement
+			changed = true
+		}
+	}
+	if !changed {
+		return values, false, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
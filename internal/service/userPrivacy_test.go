@@ -0,0 +1,81 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAnonymizeAuditValues(t *testing.T) {
+	replacements := map[string]interface{}{
+		"username": "erased-u1",
+		"email":    "",
+		"phone":    "",
+	}
+	tests := []struct {
+		name        string
+		values      string
+		want        string
+		wantChanged bool
+		wantErr     bool
+	}{
+		{name: "empty", values: "", want: "", wantChanged: false},
+		{
+			name:        "personal fields replaced",
+			values:      `{"username":"alice","email":"alice@example.com","status":1}`,
+			want:        `{"username":"erased-u1","email":"","status":1}`,
+			wantChanged: true,
+		},
+		{
+			name:        "missing fields not added",
+			values:      `{"status":1}`,
+			want:        `{"status":1}`,
+			wantChanged: false,
+		},
+		{
+			name:        "already anonymized",
+			values:      `{"username":"erased-u1","phone":""}`,
+			want:        `{"username":"erased-u1","phone":""}`,
+			wantChanged: false,
+		},
+		{
+			name:        "null value replaced",
+			values:      `{"phone":null,"roleIds":["r1"]}`,
+			want:        `{"phone":"","roleIds":["r1"]}`,
+			wantChanged: true,
+		},
+		{name: "invalid json", values: `{"username":`, wantErr: true},
+		{name: "not an object", values: `["alice"]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := anonymizeAuditValues(tt.values, replacements)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("anonymizeAuditValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("anonymizeAuditValues() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !changed {
+				if got != tt.values {
+					t.Errorf("anonymizeAuditValues() = %s, want unchanged %s", got, tt.values)
+				}
+				return
+			}
+			var gotSnapshot, wantSnapshot map[string]interface{}
+			if err = json.Unmarshal([]byte(got), &gotSnapshot); err != nil {
+				t.Fatalf("anonymizeAuditValues() returned invalid json %s: %v", got, err)
+			}
+			if err = json.Unmarshal([]byte(tt.want), &wantSnapshot); err != nil {
+				t.Fatalf("invalid want: %v", err)
+			}
+			if !reflect.DeepEqual(gotSnapshot, wantSnapshot) {
+				t.Errorf("anonymizeAuditValues() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
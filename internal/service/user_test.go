@@ -0,0 +1,125 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/utils"
+	"testing"
+	"time"
+)
+
+func TestUserRoleValidAt(t *testing.T) {
+	now := time.Date(2024, 11, 18, 10, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+	tests := []struct {
+		name       string
+		validFrom  *time.Time
+		validUntil *time.Time
+		want       bool
+	}{
+		{name: "no bounds", want: true},
+		{name: "started", validFrom: &before, want: true},
+		{name: "starts exactly now", validFrom: &now, want: true},
+		{name: "not yet started", validFrom: &after, want: false},
+		{name: "not yet expired", validUntil: &after, want: true},
+		{name: "expires exactly now", validUntil: &now, want: false},
+		{name: "expired", validUntil: &before, want: false},
+		{name: "within window", validFrom: &before, validUntil: &after, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userRoleValidAt(tt.validFrom, tt.validUntil, now); got != tt.want {
+				t.Errorf("userRoleValidAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildUserRoles(t *testing.T) {
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	type wantRole struct {
+		roleID        string
+		active        int8
+		hasValidFrom  bool
+		hasValidUntil bool
+	}
+	tests := []struct {
+		name        string
+		roleIDs     []string
+		assignments []auth.RoleAssignment
+		want        []wantRole
+		wantCode    int
+	}{
+		{
+			name:    "role ids deduplicated",
+			roleIDs: []string{"r1", "r2", "r1"},
+			want:    []wantRole{{roleID: "r1", active: 1}, {roleID: "r2", active: 1}},
+		},
+		{
+			name:        "assignment overrides plain role id",
+			roleIDs:     []string{"r1", "r2"},
+			assignments: []auth.RoleAssignment{{RoleID: "r1", ValidUntil: future}},
+			want:        []wantRole{{roleID: "r1", active: 1, hasValidUntil: true}, {roleID: "r2", active: 1}},
+		},
+		{
+			name:        "assignment appended",
+			roleIDs:     []string{"r1"},
+			assignments: []auth.RoleAssignment{{RoleID: "r2", ValidFrom: past, ValidUntil: future}},
+			want:        []wantRole{{roleID: "r1", active: 1}, {roleID: "r2", active: 1, hasValidFrom: true, hasValidUntil: true}},
+		},
+		{
+			name:        "future assignment inactive",
+			assignments: []auth.RoleAssignment{{RoleID: "r1", ValidFrom: future}},
+			want:        []wantRole{{roleID: "r1", active: 0, hasValidFrom: true}},
+		},
+		{
+			name:        "expired assignment inactive",
+			assignments: []auth.RoleAssignment{{RoleID: "r1", ValidUntil: past}},
+			want:        []wantRole{{roleID: "r1", active: 0, hasValidUntil: true}},
+		},
+		{
+			name:        "later assignment wins",
+			assignments: []auth.RoleAssignment{{RoleID: "r1", ValidUntil: past}, {RoleID: "r1", ValidUntil: future}},
+			want:        []wantRole{{roleID: "r1", active: 1, hasValidUntil: true}},
+		},
+		{
+			name:        "until not after from",
+			assignments: []auth.RoleAssignment{{RoleID: "r1", ValidFrom: future, ValidUntil: past}},
+			wantCode:    utils.RoleAssignmentInvalidCode,
+		},
+		{
+			name:        "invalid time",
+			assignments: []auth.RoleAssignment{{RoleID: "r1", ValidFrom: "2024-11-18"}},
+			wantCode:    utils.RoleAssignmentInvalidCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildUserRoles("u1", tt.roleIDs, tt.assignments)
+			if tt.wantCode != utils.Success {
+				businessErr, ok := err.(*utils.BusinessError)
+				if !ok || businessErr.Code != tt.wantCode {
+					t.Fatalf("buildUserRoles() error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildUserRoles() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("buildUserRoles() returned %d roles, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				userRole := got[i]
+				if userRole.UserID != "u1" || userRole.RoleID != want.roleID || userRole.Active != want.active ||
+					(userRole.ValidFrom != nil) != want.hasValidFrom || (userRole.ValidUntil != nil) != want.hasValidUntil {
+					t.Errorf("buildUserRoles()[%d] = %+v, want %+v", i, userRole, want)
+				}
+			}
+		})
+	}
+}
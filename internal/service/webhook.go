@@ -0,0 +1,349 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/dao"
+	"ByteScience-WAM-Admin/internal/model/dto/auth"
+	"ByteScience-WAM-Admin/internal/model/entity"
+	"ByteScience-WAM-Admin/internal/utils"
+	"ByteScience-WAM-Admin/pkg/db"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookService struct {
+	webhookDao  *dao.WebhookDao
+	deliveryDao *dao.WebhookDeliveryDao
+	auditTrail  auditTrail
+}
+
+// NewWebhookService 创建一个新的 WebhookService 实例
+func NewWebhookService() *WebhookService {
+	return &WebhookService{
+		webhookDao:  dao.NewWebhookDao(),
+		deliveryDao: dao.NewWebhookDeliveryDao(),
+		auditTrail:  newAuditTrail(),
+	}
+}
+
+// List 获取 Webhook 订阅列表（分页），不返回签名密钥
+func (ws *WebhookService) List(ctx context.Context, req *auth.ListWebhookRequest) (*auth.ListWebhookResponse, error) {
+	filters := map[string]interface{}{}
+	if req.Status != nil {
+		filters[entity.WebhooksColumns.Status] = *req.Status
+	}
+
+	webhooks, total, err := ws.webhookDao.Query(ctx, req.Page, req.PageSize, filters)
+	if err != nil {
+		logger.Logger.Errorf("[GetWebhookList] Error fetching webhooks: %v", err)
+		return nil, utils.NewBusinessError(utils.WebhookQueryFailedCode)
+	}
+
+	list := make([]auth.WebhookInfo, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		eventTypes := []string{}
+		if err = json.Unmarshal([]byte(webhook.Events), &eventTypes); err != nil {
+			logger.Logger.Errorf("[GetWebhookList] Error parsing events of webhook %s: %v", webhook.ID, err)
+		}
+		list = append(list, auth.WebhookInfo{
+			ID:            webhook.ID,
+			Name:          webhook.Name,
+			URL:           webhook.URL,
+			EventTypeList: eventTypes,
+			Status:        webhook.Status,
+			CreatedAt:     webhook.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:     webhook.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &auth.ListWebhookResponse{
+		Total: total,
+		List:  list,
+	}, nil
+}
+
+// Add 新增 Webhook 订阅，未指定签名密钥时自动生成，密钥只在新增和更换时返回
+func (ws *WebhookService) Add(ctx context.Context, req *auth.AddWebhookRequest) (*auth.AddWebhookResponse, error) {
+	if err := checkWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := marshalWebhookEvents(req.EventTypeList)
+	if err != nil {
+		logger.Logger.Errorf("[AddWebhook] Error encoding events: %v", err)
+		return nil, utils.NewBusinessError(utils.WebhookInsertFailedCode)
+	}
+
+	secret, err := webhookSecret(req.Secret)
+	if err != nil {
+		logger.Logger.Errorf("[AddWebhook] Error generating secret: %v", err)
+		return nil, utils.NewBusinessError(utils.WebhookInsertFailedCode)
+	}
+
+	webhook := &entity.Webhooks{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		URL:       req.URL,
+		Events:    events,
+		Secret:    secret,
+		Status:    req.Status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ws.webhookDao.InsertTx(ctx, tx, webhook); err != nil {
+			logger.Logger.Errorf("[AddWebhook] Error inserting webhook: %v", err)
+			return err
+		}
+		if err := ws.auditTrail.recordTx(ctx, tx, AuditActionCreate, AuditTargetWebhook, webhook.ID, nil, webhook); err != nil {
+			logger.Logger.Errorf("[AddWebhook] Error recording audit log: %v", err)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, utils.NewBusinessError(utils.WebhookInsertFailedCode)
+	}
+
+	return &auth.AddWebhookResponse{ID: webhook.ID, Secret: secret}, nil
+}
+
+// Edit 编辑 Webhook 订阅，新的回调地址对尚未投递成功的记录同样生效
+func (ws *WebhookService) Edit(ctx context.Context, req *auth.EditWebhookRequest) error {
+	webhook, err := ws.checkWebhookExistence(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err = checkWebhookURL(req.URL); err != nil {
+		return err
+	}
+	events, err := marshalWebhookEvents(req.EventTypeList)
+	if err != nil {
+		logger.Logger.Errorf("[EditWebhook] Error encoding events: %v", err)
+		return utils.NewBusinessError(utils.WebhookUpdateFailedCode)
+	}
+
+	updates := map[string]interface{}{
+		entity.WebhooksColumns.Name:      req.Name,
+		entity.WebhooksColumns.URL:       req.URL,
+		entity.WebhooksColumns.Events:    events,
+		entity.WebhooksColumns.Status:    req.Status,
+		entity.WebhooksColumns.UpdatedAt: time.Now(),
+	}
+	updated := *webhook
+	updated.Name = req.Name
+	updated.URL = req.URL
+	updated.Events = events
+	updated.Status = req.Status
+
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ws.webhookDao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+			logger.Logger.Errorf("[EditWebhook] Error updating webhook: %v", err)
+			return err
+		}
+		if err := ws.auditTrail.recordTx(ctx, tx, AuditActionUpdate, AuditTargetWebhook, req.ID, webhook, &updated); err != nil {
+			logger.Logger.Errorf("[EditWebhook] Error recording audit log: %v", err)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return utils.NewBusinessError(utils.WebhookUpdateFailedCode)
+	}
+
+	return nil
+}
+
+// RotateSecret 更换 Webhook 签名密钥，未指定新密钥时自动生成，新密钥只在此时返回
+// 新密钥对尚未投递成功的记录同样生效。
+func (ws *WebhookService) RotateSecret(ctx context.Context, req *auth.RotateWebhookSecretRequest) (*auth.RotateWebhookSecretResponse, error) {
+	webhook, err := ws.checkWebhookExistence(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := webhookSecret(req.Secret)
+	if err != nil {
+		logger.Logger.Errorf("[RotateWebhookSecret] Error generating secret: %v", err)
+		return nil, utils.NewBusinessError(utils.WebhookUpdateFailedCode)
+	}
+
+	updates := map[string]interface{}{
+		entity.WebhooksColumns.Secret:    secret,
+		entity.WebhooksColumns.UpdatedAt: time.Now(),
+	}
+	updated := *webhook
+	updated.Secret = secret
+
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ws.webhookDao.UpdateTx(ctx, tx, req.ID, updates); err != nil {
+			logger.Logger.Errorf("[RotateWebhookSecret] Error updating webhook secret: %v", err)
+			return err
+		}
+		if err := ws.auditTrail.recordTx(ctx, tx, AuditActionRotateSecret, AuditTargetWebhook, req.ID, webhook, &updated); err != nil {
+			logger.Logger.Errorf("[RotateWebhookSecret] Error recording audit log: %v", err)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, utils.NewBusinessError(utils.WebhookUpdateFailedCode)
+	}
+
+	return &auth.RotateWebhookSecretResponse{Secret: secret}, nil
+}
+
+// Delete 删除 Webhook 订阅，尚未投递的记录标记为投递失败，投递记录保留以供查询
+func (ws *WebhookService) Delete(ctx context.Context, req *auth.DelWebhookRequest) error {
+	webhook, err := ws.checkWebhookExistence(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ws.webhookDao.SoftDeleteTx(ctx, tx, req.ID); err != nil {
+			logger.Logger.Errorf("[DeleteWebhook] Error soft deleting webhook: %v", err)
+			return err
+		}
+		if err := ws.deliveryDao.FailPendingByWebhookIDTx(ctx, tx, req.ID, "webhook deleted"); err != nil {
+			logger.Logger.Errorf("[DeleteWebhook] Error failing pending deliveries: %v", err)
+			return err
+		}
+		if err := ws.auditTrail.recordTx(ctx, tx, AuditActionDelete, AuditTargetWebhook, req.ID, webhook, nil); err != nil {
+			logger.Logger.Errorf("[DeleteWebhook] Error recording audit log: %v", err)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return utils.NewBusinessError(utils.WebhookDeleteFailedCode)
+	}
+
+	return nil
+}
+
+// ListDeliveries 获取 Webhook 投递记录列表（分页）
+func (ws *WebhookService) ListDeliveries(ctx context.Context, req *auth.ListWebhookDeliveryRequest) (*auth.ListWebhookDeliveryResponse, error) {
+	filters := map[string]interface{}{
+		entity.WebhookDeliveriesColumns.WebhookID: req.WebhookID,
+		entity.WebhookDeliveriesColumns.EventID:   req.EventID,
+		entity.WebhookDeliveriesColumns.EventType: req.EventType,
+	}
+	if req.Status != nil {
+		filters[entity.WebhookDeliveriesColumns.Status] = *req.Status
+	}
+
+	deliveries, total, err := ws.deliveryDao.Query(ctx, req.Page, req.PageSize, filters)
+	if err != nil {
+		logger.Logger.Errorf("[GetWebhookDeliveryList] Error fetching webhook deliveries: %v", err)
+		return nil, utils.NewBusinessError(utils.WebhookLogQueryFailedCode)
+	}
+
+	list := make([]auth.WebhookDeliveryInfo, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		info := auth.WebhookDeliveryInfo{
+			ID:             delivery.ID,
+			WebhookID:      delivery.WebhookID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt.Format(time.RFC3339),
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			DurationMs:     delivery.DurationMs,
+			CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		}
+		if delivery.DeliveredAt != nil {
+			info.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+		}
+		list = append(list, info)
+	}
+
+	return &auth.ListWebhookDeliveryResponse{
+		Total: total,
+		List:  list,
+	}, nil
+}
+
+// Redeliver 重新投递一条投递记录，无论此前是否投递成功；尝试次数清零，由回调任务在下次运行时立即投递
+// 重新投递使用相同的投递ID，接收方可据此去重。
+func (ws *WebhookService) Redeliver(ctx context.Context, req *auth.RedeliverWebhookRequest) error {
+	delivery, err := ws.deliveryDao.GetByID(ctx, req.ID)
+	if err != nil {
+		logger.Logger.Errorf("[RedeliverWebhook] Error fetching webhook delivery %s: %v", req.ID, err)
+		return utils.NewBusinessError(utils.WebhookRedeliverFailedCode)
+	}
+	if delivery == nil {
+		return utils.NewBusinessError(utils.WebhookDeliveryNotFoundCode)
+	}
+
+	webhook, err := ws.checkWebhookExistence(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook.Status != dao.WebhookStatusEnabled {
+		return utils.NewBusinessError(utils.WebhookDisabledCode)
+	}
+
+	if err = ws.deliveryDao.Requeue(ctx, delivery.ID); err != nil {
+		logger.Logger.Errorf("[RedeliverWebhook] Error requeuing webhook delivery %s: %v", delivery.ID, err)
+		return utils.NewBusinessError(utils.WebhookRedeliverFailedCode)
+	}
+	return nil
+}
+
+// checkWebhookExistence 检查 Webhook 订阅是否存在
+func (ws *WebhookService) checkWebhookExistence(ctx context.Context, id string) (*entity.Webhooks, error) {
+	webhook, err := ws.webhookDao.GetByID(ctx, id)
+	if err != nil {
+		logger.Logger.Errorf("[CheckWebhookExistence] Error fetching webhook %s: %v", id, err)
+		return nil, utils.NewBusinessError(utils.WebhookQueryFailedCode)
+	}
+	if webhook == nil {
+		return nil, utils.NewBusinessError(utils.WebhookNotFoundCode)
+	}
+	return webhook, nil
+}
+
+// checkWebhookURL 回调地址只允许 http 和 https
+// 未开启 system.webhook.allowPrivate 时，主机为 localhost 或非公网 IP 的地址直接拒绝；
+// 域名解析到的地址在回调建立连接时检查。
+func checkWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return utils.NewBusinessError(utils.WebhookURLInvalidCode)
+	}
+	if conf.GlobalConf.System.Webhook.AllowPrivate {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return utils.NewBusinessError(utils.WebhookURLInvalidCode)
+	}
+	if ip := net.ParseIP(host); ip != nil && !utils.IsPublicIP(ip) {
+		return utils.NewBusinessError(utils.WebhookURLInvalidCode)
+	}
+	return nil
+}
+
+// webhookSecret 返回指定的签名密钥，未指定时自动生成
+func webhookSecret(secret string) (string, error) {
+	if secret != "" {
+		return secret, nil
+	}
+	return utils.GenerateWebhookSecret()
+}
+
+// marshalWebhookEvents 将订阅的事件类型去重排序后编码为 JSON 数组
+func marshalWebhookEvents(eventTypes []string) (string, error) {
+	unique := uniqueIDs(eventTypes)
+	sort.Strings(unique)
+	events, err := json.Marshal(unique)
+	return string(events), err
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"testing"
+)
+
+func TestCheckWebhookURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		{name: "https", url: "https://crm.example.com/hooks/wam"},
+		{name: "public ip", url: "http://93.184.216.34:8080/hook"},
+		{name: "unsupported scheme", url: "ftp://crm.example.com/hook", wantErr: true},
+		{name: "missing host", url: "https:///hook", wantErr: true},
+		{name: "localhost", url: "http://localhost:8080/hook", wantErr: true},
+		{name: "localhost subdomain", url: "http://api.localhost/hook", wantErr: true},
+		{name: "loopback ip", url: "http://127.0.0.1/hook", wantErr: true},
+		{name: "metadata ip", url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "private ipv6", url: "http://[fd00::1]/hook", wantErr: true},
+		{name: "private allowed", url: "http://10.0.0.5/hook", allowPrivate: true},
+		{name: "scheme checked when private allowed", url: "file:///etc/passwd", allowPrivate: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := conf.GlobalConf
+			t.Cleanup(func() { conf.GlobalConf = original })
+			server := &conf.Server{}
+			server.System.Webhook.AllowPrivate = tt.allowPrivate
+			conf.GlobalConf = server
+
+			if err := checkWebhookURL(tt.url); (err != nil) != tt.wantErr {
+				t.Errorf("checkWebhookURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package task
+
+import (
+	"ByteScience-WAM-Admin/pkg/logger"
+	"ByteScience-WAM-Admin/pkg/redis"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minLockTTL 任务锁的最短过期时间，任务执行期间按过期时间的三分之一续期
+const minLockTTL = 30 * time.Second
+
+// Task 后台定时任务
+type Task struct {
+	// Name 任务名称，同时用作分布式锁的名称，多实例部署时同一时刻只有一个实例执行
+	Name string
+	// Interval 执行间隔，小于等于 0 时不启用
+	Interval time.Duration
+	// Run 任务执行函数，ctx 取消（服务停止或任务锁丢失）后应尽快返回
+	Run func(ctx context.Context) error
+}
+
+// Scheduler 后台定时任务调度器
+type Scheduler struct {
+	tasks []Task
+	wg    sync.WaitGroup
+}
+
+// NewScheduler 创建 Scheduler 实例
+func NewScheduler(tasks ...Task) *Scheduler {
+	return &Scheduler{tasks: tasks}
+}
+
+// Start 启动全部已启用的任务，ctx 取消后任务停止
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, t := range s.tasks {
+		if t.Interval <= 0 {
+			logger.Logger.Infof("[Task] %s is disabled", t.Name)
+			continue
+		}
+		s.wg.Add(1)
+		go func(t Task) {
+			defer s.wg.Done()
+			s.loop(ctx, t)
+		}(t)
+	}
+}
+
+// Wait 等待全部任务退出
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop 按间隔循环执行任务
+func (s *Scheduler) loop(ctx context.Context, t Task) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, t)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 获取分布式锁后执行一次任务，任务 panic 不会影响调度器
+// 任务执行期间锁会定期续期；续期失败说明锁已丢失，此时取消任务的 ctx，避免与获取到锁的其他实例同时执行。
+func (s *Scheduler) runOnce(ctx context.Context, t Task) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Logger.Errorf("[Task] %s panicked: %v", t.Name, r)
+		}
+	}()
+
+	name := "task:" + t.Name
+	owner := uuid.NewString()
+	ttl := max(t.Interval, minLockTTL)
+	locked, err := redis.TryLock(ctx, name, owner, ttl)
+	if err != nil {
+		logger.Logger.Errorf("[Task] %s failed to acquire lock: %v", t.Name, err)
+		return
+	}
+	if !locked {
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	watchdogDone := make(chan struct{})
+	go func() {
+		defer close(watchdogDone)
+		keepLock(runCtx, cancel, t.Name, name, owner, ttl)
+	}()
+	defer func() {
+		cancel()
+		<-watchdogDone
+		if err := redis.Unlock(context.Background(), name, owner); err != nil {
+			logger.Logger.Errorf("[Task] %s failed to release lock: %v", t.Name, err)
+		}
+	}()
+
+	if err = t.Run(runCtx); err != nil {
+		logger.Logger.Errorf("[Task] %s failed: %v", t.Name, err)
+	}
+}
+
+// keepLock 在 ctx 取消前每隔 ttl/3 续期一次任务锁
+// 锁已被其他持有者获取，或续期持续失败直到锁即将过期时调用 cancel 停止任务。
+func keepLock(ctx context.Context, cancel context.CancelFunc, taskName, name, owner string, ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	lockedUntil := time.Now().Add(ttl)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		extended, err := redis.ExtendLock(ctx, name, owner, ttl)
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err == nil && extended:
+			lockedUntil = time.Now().Add(ttl)
+		case err == nil:
+			logger.Logger.Errorf("[Task] %s lost its lock, stopping", taskName)
+			cancel()
+			return
+		case time.Until(lockedUntil) <= ttl/3:
+			logger.Logger.Errorf("[Task] %s failed to extend its lock before expiry, stopping: %v", taskName, err)
+			cancel()
+			return
+		default:
+			logger.Logger.Errorf("[Task] %s failed to extend its lock: %v", taskName, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/event"
+	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+)
+
+// Tasks 返回全部后台定时任务
+func Tasks() []Task {
+	return []Task{
+		{
+			Name:     "role_assignment",
+			Interval: conf.GlobalConf.System.Task.RoleAssignmentInterval,
+			Run:      syncRoleAssignments,
+		},
+		{
+			Name:     "outbox_relay",
+			Interval: conf.GlobalConf.System.Outbox.RelayInterval,
+			Run:      relayOutbox,
+		},
+		{
+			Name:     "outbox_purge",
+			Interval: conf.GlobalConf.System.Outbox.PurgeInterval,
+			Run:      purgeOutbox,
+		},
+		{
+			Name:     "webhook_delivery",
+			Interval: conf.GlobalConf.System.Webhook.DeliveryInterval,
+			Run:      deliverWebhooks,
+		},
+		{
+			Name:     "recycle_bin_purge",
+			Interval: conf.GlobalConf.System.RecycleBin.PurgeInterval,
+			Run:      purgeRecycleBin,
+		},
+	}
+}
+
+// syncRoleAssignments 按有效期激活或失效用户角色
+func syncRoleAssignments(ctx context.Context) error {
+	count, err := service.NewUserService().SyncRoleAssignments(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.Logger.Infof("[Task] role_assignment refreshed permissions of %d users", count)
+	}
+	return nil
+}
+
+// relayOutbox 将事件发件箱中到期的待投递事件投递到配置的投递目标
+func relayOutbox(ctx context.Context) error {
+	count, err := event.NewRelay(event.Sinks()...).Run(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.Logger.Infof("[Task] outbox_relay delivered %d events", count)
+	}
+	return nil
+}
+
+// purgeOutbox 永久删除超过保留时长的已投递事件和死信事件
+func purgeOutbox(ctx context.Context) error {
+	count, err := service.NewOutboxService().PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.Logger.Infof("[Task] outbox_purge purged %d events", count)
+	}
+	return nil
+}
+
+// deliverWebhooks 回调 Webhook 投递记录中到期的待投递记录
+func deliverWebhooks(ctx context.Context) error {
+	count, err := event.NewWebhookDispatcher().Run(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.Logger.Infof("[Task] webhook_delivery delivered %d webhooks", count)
+	}
+	return nil
+}
+
+// purgeRecycleBin 永久删除在回收站中超过保留时长的记录
+func purgeRecycleBin(ctx context.Context) error {
+	count, err := service.NewRecycleBinService().PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.Logger.Infof("[Task] recycle_bin_purge purged %d records", count)
+	}
+	return nil
+}
@@ -22,3 +22,11 @@ func NewBusinessError(code int) *BusinessError {
 		Message: ErrorMessages[code],
 	}
 }
+
+// NewBusinessErrorWithMessage 创建一个带自定义信息的业务错误
+func NewBusinessErrorWithMessage(code int, message string) *BusinessError {
+	return &BusinessError{
+		Code:    code,
+		Message: message,
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// confirmationTokenLen 确认令牌的随机字节数
+const confirmationTokenLen = 32
+
+// GenerateConfirmationToken 生成用于二次确认危险操作的随机令牌（十六进制编码）
+func GenerateConfirmationToken() (string, error) {
+	token := make([]byte, confirmationTokenLen)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// HashConfirmationToken 计算确认令牌的 SHA-256 摘要，服务端只保存摘要
+func HashConfirmationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求ID
+const (
+	RequestIDHeader = "X-Request-ID" // 请求ID请求头/响应头
+	RequestIDKey    = "requestId"    // 请求ID在 gin 上下文中的键
+)
+
+// GetClientIP 从请求上下文中获取客户端IP，非 HTTP 请求上下文返回空字符串
+func GetClientIP(ctx context.Context) string {
+	if c, ok := ctx.(*gin.Context); ok {
+		return c.ClientIP()
+	}
+	return ""
+}
+
+// GetUserAgent 从请求上下文中获取客户端 User-Agent，非 HTTP 请求上下文返回空字符串
+func GetUserAgent(ctx context.Context) string {
+	if c, ok := ctx.(*gin.Context); ok {
+		return c.Request.UserAgent()
+	}
+	return ""
+}
+
+// GetRequestID 从请求上下文中获取请求ID，非 HTTP 请求上下文返回空字符串
+func GetRequestID(ctx context.Context) string {
+	if c, ok := ctx.(*gin.Context); ok {
+		return c.GetString(RequestIDKey)
+	}
+	return ""
+}
+
+// GetActor 从请求上下文中获取 JWT 解析出的调用方ID及主体类型，非 HTTP 请求上下文返回空字符串
+func GetActor(ctx context.Context) (string, string) {
+	if c, ok := ctx.(*gin.Context); ok {
+		return c.GetString("userId"), c.GetString("subjectType")
+	}
+	return "", ""
+}
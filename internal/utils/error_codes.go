@@ -13,6 +13,13 @@ const (
 	UsernameAlreadyExistsCode  = 1004 // 用户名已存在
 	EmailAlreadyExistsCode     = 1005 // 邮箱已存在
 	PhoneAlreadyExistsCode     = 1006 // 手机号已存在
+	UserDisabledCode           = 1007 // 用户已被禁用
+	AccountLockedCode          = 1008 // 登录失败次数过多，账号已被临时锁定
+	RoleAssignmentInvalidCode  = 1009 // 角色有效期无效
+	UserImportFileInvalidCode  = 1010 // 导入文件无效
+	UserImportTooManyRowsCode  = 1011 // 导入文件行数超出限制
+	ErasureTokenInvalidCode    = 1012 // 擦除确认令牌无效、已过期或已使用
+	UserImportFileTooLargeCode = 1013 // 导入文件超出大小限制
 
 	// 管理员模块
 	AdminAlreadyExistsCode         = 1101 // 管理员已存在
@@ -32,6 +39,10 @@ const (
 	RoleNameAlreadyExistsCode       = 1206 // 角色名称已存在
 	RoleNotFoundCode                = 1207 // 角色未找到
 	RoleAssignmentFailedCode        = 1208 // 角色分配失败
+	TokenRevokedCode                = 1209 // 令牌已吊销
+	RoleBuiltinProtectedCode        = 1210 // 内置角色不可删除、重命名或禁用
+	RoleBuiltinLastAdminCode        = 1211 // 内置超级管理员角色至少保留一名管理员
+	RoleHierarchyCycleCode          = 1212 // 角色继承关系存在环
 
 	// 密码相关
 	PasswordIncorrectCode        = 1301 // 密码不正确
@@ -42,6 +53,44 @@ const (
 	OldPasswordIncorrectCode     = 1306 // 旧密码不正确
 	PasswordGenerationFailedCode = 1307 // 密码生成失败
 	NewPasswordSameAsOldCode     = 1308 // 新密码与旧密码相同
+	PasswordReusedCode           = 1309 // 新密码与最近使用过的密码相同
+	PasswordChangeRequiredCode   = 1310 // 密码已过期或被重置，必须先修改密码
+
+	// 二次验证
+	TotpNotEnrolledCode     = 1401 // 未绑定TOTP
+	TotpAlreadyEnabledCode  = 1402 // TOTP已启用
+	TotpNotEnabledCode      = 1403 // TOTP未启用
+	TotpCodeInvalidCode     = 1404 // 验证码或恢复码无效
+	MfaChallengeInvalidCode = 1405 // 二次验证挑战令牌无效或已过期
+
+	// 菜单模块
+	MenuNotFoundCode       = 1501 // 菜单未找到
+	MenuParentNotFoundCode = 1502 // 父菜单未找到
+	MenuHasChildrenCode    = 1503 // 菜单下存在子菜单或接口
+	MenuMoveCycleCode      = 1504 // 不能移动到自身或子孙菜单下
+	MenuSortMismatchCode   = 1505 // 排序列表与同级菜单不一致
+
+	// 接口模块
+	PathNotFoundCode         = 1601 // 接口未找到
+	PathAlreadyExistsCode    = 1602 // 接口已存在
+	PathSyncMenuRequiredCode = 1603 // 同步新增接口时未指定所属菜单
+
+	// 发件箱模块
+	OutboxNotFoundCode = 1701 // 发件箱记录未找到或已投递成功
+
+	// Webhook 模块
+	WebhookNotFoundCode         = 1801 // Webhook 订阅未找到
+	WebhookURLInvalidCode       = 1802 // 回调地址不是 http 或 https 地址，或指向非公网地址
+	WebhookDisabledCode         = 1803 // Webhook 订阅已禁用
+	WebhookDeliveryNotFoundCode = 1804 // Webhook 投递记录未找到
+
+	// 导出模块
+	ExportColumnInvalidCode = 1901 // 导出列不存在
+
+	// 回收站模块
+	RecycleBinNotFoundCode        = 1951 // 回收站中不存在该记录
+	RecycleBinParentDeletedCode   = 1952 // 所属的父菜单或菜单已被删除，须先恢复
+	RecycleBinMenuHasChildrenCode = 1953 // 菜单下仍有子菜单或接口，须先永久删除
 
 	// 接口错误
 	AdminInsertFailedCode       = 2001 // 插入管理员失败
@@ -58,6 +107,36 @@ const (
 	UserInsertFailedCode        = 2012 // 用户插入失败
 	UserConflictCheckFailedCode = 2013 // 用户冲突检测失败
 	UserUpdateFailedCode        = 2014 // 用户更新失败
+	AuditLogQueryListFailedCode = 2015 // 审计日志查询失败
+	LoginLogQueryListFailedCode = 2016 // 登录日志查询失败
+	LoginLogExportFailedCode    = 2017 // 登录日志导出失败
+	MenuInsertFailedCode        = 2018 // 插入菜单失败
+	MenuUpdateFailedCode        = 2019 // 更新菜单失败
+	MenuDeleteFailedCode        = 2020 // 删除菜单失败
+	MenuQueryFailedCode         = 2021 // 查询菜单失败
+	PathInsertFailedCode        = 2022 // 插入接口失败
+	PathUpdateFailedCode        = 2023 // 更新接口失败
+	PathDeleteFailedCode        = 2024 // 删除接口失败
+	PathQueryFailedCode         = 2025 // 查询接口失败
+	PathSyncFailedCode          = 2026 // 同步接口失败
+	PolicyDecisionFailedCode    = 2027 // 权限决策失败
+	OutboxQueryFailedCode       = 2028 // 查询发件箱失败
+	OutboxUpdateFailedCode      = 2029 // 更新发件箱失败
+	WebhookInsertFailedCode     = 2030 // 插入 Webhook 订阅失败
+	WebhookUpdateFailedCode     = 2031 // 更新 Webhook 订阅失败
+	WebhookDeleteFailedCode     = 2032 // 删除 Webhook 订阅失败
+	WebhookQueryFailedCode      = 2033 // 查询 Webhook 订阅失败
+	WebhookLogQueryFailedCode   = 2034 // 查询 Webhook 投递记录失败
+	WebhookRedeliverFailedCode  = 2035 // 重新投递 Webhook 失败
+	UserImportFailedCode        = 2036 // 用户导入失败
+	UserExportFailedCode        = 2037 // 用户导出失败
+	AdminExportFailedCode       = 2038 // 管理员导出失败
+	RoleExportFailedCode        = 2039 // 角色导出失败
+	RecycleBinQueryFailedCode   = 2040 // 查询回收站失败
+	RecycleBinRestoreFailedCode = 2041 // 恢复回收站记录失败
+	RecycleBinPurgeFailedCode   = 2042 // 永久删除回收站记录失败
+	UserDataExportFailedCode    = 2043 // 导出用户个人数据失败
+	UserEraseFailedCode         = 2044 // 擦除用户个人数据失败
 )
 
 // ErrorMessages 错误信息映射
@@ -73,6 +152,13 @@ var ErrorMessages = map[int]string{
 	UsernameAlreadyExistsCode:  "Username already exists",
 	EmailAlreadyExistsCode:     "Email already exists",
 	PhoneAlreadyExistsCode:     "Phone number already exists",
+	UserDisabledCode:           "User is disabled",
+	AccountLockedCode:          "Too many failed attempts, please try again later",
+	RoleAssignmentInvalidCode:  "Role assignment must expire after it becomes valid",
+	UserImportFileInvalidCode:  "Import file is invalid or in an unsupported format",
+	UserImportTooManyRowsCode:  "Import file has too many rows",
+	ErasureTokenInvalidCode:    "Erasure confirmation token is invalid or expired",
+	UserImportFileTooLargeCode: "Import file is too large",
 
 	// 管理员模块
 	AdminAlreadyExistsCode:         "Admin already exists",
@@ -93,6 +179,10 @@ var ErrorMessages = map[int]string{
 	RoleNotFoundCode:                "Role not found",
 	RoleAssignmentFailedCode:        "Failed to assign role",
 	RoleNameAlreadyExistsCode:       "Role name already exists",
+	TokenRevokedCode:                "Token revoked",
+	RoleBuiltinProtectedCode:        "Built-in role cannot be deleted, renamed or disabled",
+	RoleBuiltinLastAdminCode:        "Built-in super admin role must keep at least one admin",
+	RoleHierarchyCycleCode:          "Role cannot inherit from itself or its descendants",
 
 	// 密码相关
 	PasswordIncorrectCode:        "Incorrect password",
@@ -102,6 +192,44 @@ var ErrorMessages = map[int]string{
 	PasswordMismatchCode:         "Passwords do not match",
 	OldPasswordIncorrectCode:     "Old password is incorrect",
 	PasswordGenerationFailedCode: "Failed to generate password",
+	PasswordReusedCode:           "Password was used recently",
+	PasswordChangeRequiredCode:   "Password change required",
+
+	// 二次验证
+	TotpNotEnrolledCode:     "TOTP is not enrolled",
+	TotpAlreadyEnabledCode:  "TOTP is already enabled",
+	TotpNotEnabledCode:      "TOTP is not enabled",
+	TotpCodeInvalidCode:     "Invalid verification code",
+	MfaChallengeInvalidCode: "MFA challenge is invalid or expired",
+
+	// 菜单模块
+	MenuNotFoundCode:       "Menu not found",
+	MenuParentNotFoundCode: "Parent menu not found",
+	MenuHasChildrenCode:    "Menu has child menus or paths",
+	MenuMoveCycleCode:      "Menu cannot be moved under itself or its descendants",
+	MenuSortMismatchCode:   "Sort list does not match the sibling menus",
+
+	// 接口模块
+	PathNotFoundCode:         "Path not found",
+	PathAlreadyExistsCode:    "Path with the same method already exists",
+	PathSyncMenuRequiredCode: "Menu is required to add new paths",
+
+	// 发件箱模块
+	OutboxNotFoundCode: "Outbox event not found or already delivered",
+
+	// Webhook 模块
+	WebhookNotFoundCode:         "Webhook not found",
+	WebhookURLInvalidCode:       "Webhook URL must be a public http or https URL",
+	WebhookDisabledCode:         "Webhook is disabled",
+	WebhookDeliveryNotFoundCode: "Webhook delivery not found",
+
+	// 导出模块
+	ExportColumnInvalidCode: "Unknown export column",
+
+	// 回收站模块
+	RecycleBinNotFoundCode:        "Record not found in recycle bin",
+	RecycleBinParentDeletedCode:   "Parent menu has been deleted, restore it first",
+	RecycleBinMenuHasChildrenCode: "Menu still has child menus or paths, purge them first",
 
 	// 接口错误
 	AdminInsertFailedCode:       "Failed to insert admin",
@@ -118,4 +246,34 @@ var ErrorMessages = map[int]string{
 	UserQueryFailedCode:         "Failed to query user",
 	UserQueryListFailedCode:     "Failed to query user list",
 	UserInsertFailedCode:        "Failed to insert user",
+	AuditLogQueryListFailedCode: "Failed to query audit log list",
+	LoginLogQueryListFailedCode: "Failed to query login log list",
+	LoginLogExportFailedCode:    "Failed to export login logs",
+	MenuInsertFailedCode:        "Failed to insert menu",
+	MenuUpdateFailedCode:        "Failed to update menu",
+	MenuDeleteFailedCode:        "Failed to delete menu",
+	MenuQueryFailedCode:         "Failed to query menu",
+	PathInsertFailedCode:        "Failed to insert path",
+	PathUpdateFailedCode:        "Failed to update path",
+	PathDeleteFailedCode:        "Failed to delete path",
+	PathQueryFailedCode:         "Failed to query path",
+	PathSyncFailedCode:          "Failed to sync paths",
+	PolicyDecisionFailedCode:    "Failed to decide policy",
+	OutboxQueryFailedCode:       "Failed to query outbox",
+	OutboxUpdateFailedCode:      "Failed to update outbox",
+	WebhookInsertFailedCode:     "Failed to insert webhook",
+	WebhookUpdateFailedCode:     "Failed to update webhook",
+	WebhookDeleteFailedCode:     "Failed to delete webhook",
+	WebhookQueryFailedCode:      "Failed to query webhook",
+	WebhookLogQueryFailedCode:   "Failed to query webhook deliveries",
+	WebhookRedeliverFailedCode:  "Failed to redeliver webhook",
+	UserImportFailedCode:        "Failed to import users",
+	UserExportFailedCode:        "Failed to export users",
+	AdminExportFailedCode:       "Failed to export admins",
+	RoleExportFailedCode:        "Failed to export roles",
+	RecycleBinQueryFailedCode:   "Failed to query recycle bin",
+	RecycleBinRestoreFailedCode: "Failed to restore record",
+	RecycleBinPurgeFailedCode:   "Failed to purge record",
+	UserDataExportFailedCode:    "Failed to export personal data",
+	UserEraseFailedCode:         "Failed to erase personal data",
 }
@@ -1,17 +1,99 @@
 package utils
 
 import (
+	"errors"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 	"time"
 )
 
+// 令牌类型
+const (
+	AccessTokenType  = "access"  // 访问令牌
+	RefreshTokenType = "refresh" // 刷新令牌
+	MfaTokenType     = "mfa"     // 二次验证挑战令牌，仅用于换取正式令牌
+)
+
+// 令牌权限范围，为空表示不受限制
+const (
+	PasswordChangeScope = "password_change" // 密码已过期或被重置，只允许修改密码
+)
+
+// 令牌主体类型
+const (
+	SubjectTypeAdmin = "admin" // 管理员
+	SubjectTypeUser  = "user"  // 业务用户
+	SubjectTypeScim  = "scim"  // SCIM 客户端（身份提供商），仅作为操作人类型
+)
+
+// TokenClaims 生成令牌时需要写入的业务载荷
+type TokenClaims struct {
+	UserId      string // 用户ID（管理员ID或业务用户ID）
+	SubjectType string // 主体类型（admin、user）
+	Type        string // 令牌类型（access、refresh、mfa）
+	FamilyId    string // 令牌家族ID，同一次登录派生出的所有令牌共享
+	Scope       string // 令牌权限范围，为空表示不受限制
+}
+
 // GetToken 生成token
-func GetToken(secretKey string, seconds int64, userId string) (string, error) {
+// 返回:
+//   - string: 签名后的 token
+//   - string: token 的唯一标识 jti
+//   - error: 处理过程中可能出现的错误
+func GetToken(secretKey string, seconds int64, tokenClaims TokenClaims) (string, string, error) {
+	jti := uuid.New().String()
+	now := time.Now().Unix()
+
 	claims := make(jwt.MapClaims)
-	claims["exp"] = time.Now().Unix() + seconds
-	claims["iat"] = time.Now().Unix()
-	claims["userId"] = userId
+	claims["exp"] = now + seconds
+	claims["iat"] = now
+	claims["jti"] = jti
+	claims["userId"] = tokenClaims.UserId
+	claims["subjectType"] = tokenClaims.SubjectType
+	claims["typ"] = tokenClaims.Type
+	claims["fid"] = tokenClaims.FamilyId
+	if tokenClaims.Scope != "" {
+		claims["scope"] = tokenClaims.Scope
+	}
 	token := jwt.New(jwt.SigningMethodHS256)
 	token.Claims = claims
-	return token.SignedString([]byte(secretKey))
+
+	signed, err := token.SignedString([]byte(secretKey))
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
+}
+
+// ParseToken 解析并校验token
+// 注意: 过期的 token 会返回 jwt 库原始错误（"Token is expired"），调用方可据此区分过期与无效
+func ParseToken(secretKey, tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// 检查 token 是否使用了 HS256 签名方法
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, NewBusinessError(InvalidTokenCode)
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// ClaimString 读取字符串类型的载荷字段
+func ClaimString(claims jwt.MapClaims, key string) string {
+	value, _ := claims[key].(string)
+	return value
+}
+
+// ClaimExpireAt 读取 token 的过期时间
+func ClaimExpireAt(claims jwt.MapClaims) time.Time {
+	exp, _ := claims["exp"].(float64)
+	return time.Unix(int64(exp), 0)
 }
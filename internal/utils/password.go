@@ -1,10 +1,17 @@
 package utils
 
 import (
+	"ByteScience-WAM-Admin/conf"
+	"crypto/rand"
 	"errors"
+	"math/big"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyPasswordHash 用于账号不存在时执行一次等价的密码校验，避免通过响应耗时判断账号是否存在
+const dummyPasswordHash = "$2a$10$xgNuosM0qpj0dFogAXn/H.2Nk8WaF.pt3tX7nri1lHRqvwUQMfPzu"
+
 // EncryptPassword 对明文密码进行加密处理
 // 参数:
 //   - plainPassword: 明文密码，不能为空
@@ -46,3 +53,69 @@ func VerifyPassword(plainPassword, hashedPassword string) (bool, error) {
 	}
 	return true, nil
 }
+
+// VerifyDummyPassword 对一个固定的哈希执行密码校验，结果恒为不匹配
+// 使用场景: 登录时账号不存在，仍然消耗与真实校验相同的时间。
+func VerifyDummyPassword(plainPassword string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(plainPassword))
+}
+
+// 随机密码使用的字符集，每类字符至少出现一次
+var randomPasswordCharsets = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnopqrstuvwxyz",
+	"23456789",
+	"!@#$%^&*-_=+",
+}
+
+// GenerateRandomPassword 生成满足密码策略的随机密码
+// 用于由外部系统开通、未提供初始密码的账号，长度取 24 与策略最小长度中的较大值，且不超过策略最大长度。
+func GenerateRandomPassword() (string, error) {
+	policy := conf.GlobalConf.System.Security.PasswordPolicy
+	length := 24
+	if policy.MinLength > length {
+		length = policy.MinLength
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		length = policy.MaxLength
+	}
+	if length < len(randomPasswordCharsets) {
+		length = len(randomPasswordCharsets)
+	}
+
+	all := ""
+	for _, charset := range randomPasswordCharsets {
+		all += charset
+	}
+	password := make([]byte, length)
+	for i := range password {
+		charset := all
+		if i < len(randomPasswordCharsets) {
+			charset = randomPasswordCharsets[i]
+		}
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// 打乱顺序，避免各类字符固定出现在开头
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+// randomChar 从字符集中随机取一个字符
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
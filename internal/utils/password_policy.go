@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/pkg/logger"
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// builtinWeakPasswords 内置的常见弱密码词典（小写）
+var builtinWeakPasswords = []string{
+	"password", "passw0rd", "p@ssw0rd", "p@ssword", "123456", "12345678", "123456789", "1234567890",
+	"qwerty", "qwertyuiop", "qwe123", "asdfgh", "asdfghjkl", "zxcvbnm", "1q2w3e4r", "1qaz2wsx",
+	"abc123", "abcdef", "111111", "000000", "888888", "666666", "iloveyou", "welcome", "letmein",
+	"admin", "administrator", "root", "changeme", "default", "secret", "monkey", "dragon",
+	"sunshine", "princess", "football", "baseball", "master", "superman", "trustno1", "test",
+}
+
+var (
+	dictionaryOnce sync.Once
+	dictionary     map[string]struct{}
+)
+
+// ValidatePassword 按配置的密码策略校验密码强度
+// 参数:
+//   - password: 明文密码
+//   - identities: 账号的用户名、邮箱、手机号等标识，用于相似度校验，可为空
+//
+// 返回:
+//   - error: 不满足策略时返回 PasswordTooWeakCode，错误信息中列出全部不满足的规则
+func ValidatePassword(password string, identities ...string) error {
+	policy := conf.GlobalConf.System.Security.PasswordPolicy
+
+	var reasons []string
+	if policy.MinLength > 0 && len([]rune(password)) < policy.MinLength {
+		reasons = append(reasons, fmt.Sprintf("must be at least %d characters", policy.MinLength))
+	}
+	if policy.MaxLength > 0 && len(password) > policy.MaxLength {
+		reasons = append(reasons, fmt.Sprintf("must be at most %d bytes", policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsSpace(r):
+		default:
+			hasSymbol = true
+		}
+	}
+	if policy.RequireUpper && !hasUpper {
+		reasons = append(reasons, "must contain an uppercase letter")
+	}
+	if policy.RequireLower && !hasLower {
+		reasons = append(reasons, "must contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		reasons = append(reasons, "must contain a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		reasons = append(reasons, "must contain a special character")
+	}
+	if policy.MinCharClasses > 0 {
+		classes := 0
+		for _, has := range []bool{hasUpper, hasLower, hasDigit, hasSymbol} {
+			if has {
+				classes++
+			}
+		}
+		if classes < policy.MinCharClasses {
+			reasons = append(reasons, fmt.Sprintf(
+				"must contain at least %d of: uppercase letters, lowercase letters, digits, special characters",
+				policy.MinCharClasses))
+		}
+	}
+
+	if policy.CheckDictionary && isDictionaryPassword(policy, password) {
+		reasons = append(reasons, "is too common")
+	}
+	if policy.CheckSimilarity && isSimilarToIdentity(password, identities) {
+		reasons = append(reasons, "must not contain or resemble the username, email or phone")
+	}
+
+	if len(reasons) > 0 {
+		return NewBusinessErrorWithMessage(PasswordTooWeakCode,
+			ErrorMessages[PasswordTooWeakCode]+": password "+strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+// isDictionaryPassword 判断密码是否为常见弱密码，忽略大小写及首尾附加的数字和符号（例如 Password123!）
+func isDictionaryPassword(policy conf.PasswordPolicy, password string) bool {
+	dictionaryOnce.Do(func() {
+		dictionary = loadDictionary(policy)
+	})
+
+	lower := strings.ToLower(password)
+	if _, ok := dictionary[lower]; ok {
+		return true
+	}
+	core := strings.TrimFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if core == "" {
+		return false
+	}
+	_, ok := dictionary[core]
+	return ok
+}
+
+// loadDictionary 合并内置词典、配置词条及词典文件
+func loadDictionary(policy conf.PasswordPolicy) map[string]struct{} {
+	words := make(map[string]struct{}, len(builtinWeakPasswords)+len(policy.Dictionary))
+	for _, word := range append(builtinWeakPasswords, policy.Dictionary...) {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words[word] = struct{}{}
+		}
+	}
+
+	if policy.DictionaryFile == "" {
+		return words
+	}
+	file, err := os.Open(policy.DictionaryFile)
+	if err != nil {
+		// 词典文件不可用时仍使用内置词典，不影响业务
+		logger.Logger.Errorf("[PasswordPolicy] Error opening dictionary %s: %v", policy.DictionaryFile, err)
+		return words
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.ToLower(strings.TrimSpace(scanner.Text())); word != "" {
+			words[word] = struct{}{}
+		}
+	}
+	return words
+}
+
+// isSimilarToIdentity 判断密码是否包含账号标识（或其倒序），或被账号标识包含
+func isSimilarToIdentity(password string, identities []string) bool {
+	lower := strings.ToLower(password)
+	for _, identity := range identities {
+		identity = strings.ToLower(strings.TrimSpace(identity))
+		// 邮箱只比较 @ 之前的部分，手机号忽略开头的 +
+		if at := strings.Index(identity, "@"); at > 0 {
+			identity = identity[:at]
+		}
+		identity = strings.TrimPrefix(identity, "+")
+		if len(identity) < 3 {
+			continue
+		}
+
+		if strings.Contains(lower, identity) || strings.Contains(lower, reverseString(identity)) ||
+			strings.Contains(identity, lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// reverseString 字符串倒序
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
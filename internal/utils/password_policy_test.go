@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// withPasswordPolicy 在测试期间使用指定的密码策略
+func withPasswordPolicy(t *testing.T, policy conf.PasswordPolicy) {
+	t.Helper()
+	previous := conf.GlobalConf
+	server := &conf.Server{}
+	server.System.Security.PasswordPolicy = policy
+	conf.GlobalConf = server
+	t.Cleanup(func() {
+		conf.GlobalConf = previous
+	})
+}
+
+func TestValidatePassword(t *testing.T) {
+	withPasswordPolicy(t, conf.PasswordPolicy{
+		MinLength:       8,
+		MaxLength:       72,
+		MinCharClasses:  3,
+		CheckDictionary: true,
+		CheckSimilarity: true,
+	})
+
+	tests := []struct {
+		name       string
+		password   string
+		identities []string
+		wantReason string // 为空表示应通过校验
+	}{
+		{"strong", "Tr0ub4dor&3", nil, ""},
+		{"too short", "Ab1!", nil, "at least 8 characters"},
+		{"too long", "Aa1!" + strings.Repeat("x", 69), nil, "at most 72 bytes"},
+		{"multi-byte length counted in runes", "密码密码Ab1!", nil, ""},
+		{"too few classes", "abcdefgh12", nil, "at least 3 of"},
+		{"dictionary word", "Password", nil, "at least 3 of"},
+		{"dictionary word with affixes", "Password123!", nil, "too common"},
+		{"contains username", "xJohnDoe1!", []string{"johndoe"}, "resemble"},
+		{"contains reversed username", "xEODNHOJ1!", []string{"johndoe"}, "resemble"},
+		{"contains email local part", "Alice.Smith#9", []string{"alice.smith@example.com"}, "resemble"},
+		{"contains phone", "Zz!8613800138000", []string{"+8613800138000"}, "resemble"},
+		{"short identity ignored", "Qu1ck-Fox-Jumps", []string{"ox"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password, tt.identities...)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("ValidatePassword(%q) error = %v, want nil", tt.password, err)
+				}
+				return
+			}
+			businessErr, ok := err.(*BusinessError)
+			if !ok || businessErr.Code != PasswordTooWeakCode {
+				t.Fatalf("ValidatePassword(%q) error = %v, want PasswordTooWeakCode", tt.password, err)
+			}
+			if !strings.Contains(businessErr.Message, tt.wantReason) {
+				t.Errorf("ValidatePassword(%q) message = %q, want it to contain %q", tt.password, businessErr.Message, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordRequiredClasses(t *testing.T) {
+	withPasswordPolicy(t, conf.PasswordPolicy{
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	})
+
+	tests := []struct {
+		password   string
+		wantReason string
+	}{
+		{"Abcdef1!", ""},
+		{"abcdef1!", "uppercase letter"},
+		{"ABCDEF1!", "lowercase letter"},
+		{"Abcdefg!", "digit"},
+		{"Abcdefg1", "special character"},
+	}
+	for _, tt := range tests {
+		err := ValidatePassword(tt.password)
+		if tt.wantReason == "" {
+			if err != nil {
+				t.Errorf("ValidatePassword(%q) error = %v, want nil", tt.password, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantReason) {
+			t.Errorf("ValidatePassword(%q) error = %v, want it to mention %q", tt.password, err, tt.wantReason)
+		}
+	}
+}
+
+func TestIsSimilarToIdentity(t *testing.T) {
+	tests := []struct {
+		password   string
+		identities []string
+		want       bool
+	}{
+		{"john2024!", []string{"John"}, true},
+		{"nhoj2024!", []string{"john"}, true},
+		{"jo", []string{"john"}, true},
+		{"Secure#Pass1", []string{"john", "john@example.com", "+8613800138000"}, false},
+		{"x13800138000", []string{"+13800138000"}, true},
+		{"anything", []string{"", "  "}, false},
+	}
+	for _, tt := range tests {
+		if got := isSimilarToIdentity(tt.password, tt.identities); got != tt.want {
+			t.Errorf("isSimilarToIdentity(%q, %q) = %v, want %v", tt.password, tt.identities, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateRandomPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     conf.PasswordPolicy
+		wantLength int
+	}{
+		{"default length", conf.PasswordPolicy{MinLength: 8, MaxLength: 72}, 24},
+		{"min length above default", conf.PasswordPolicy{MinLength: 32, MaxLength: 72}, 32},
+		{"max length below default", conf.PasswordPolicy{MinLength: 8, MaxLength: 16}, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withPasswordPolicy(t, tt.policy)
+			password, err := GenerateRandomPassword()
+			if err != nil {
+				t.Fatalf("GenerateRandomPassword() error = %v", err)
+			}
+			if len(password) != tt.wantLength {
+				t.Errorf("len(password) = %d, want %d", len(password), tt.wantLength)
+			}
+
+			var hasUpper, hasLower, hasDigit, hasSymbol bool
+			for _, r := range password {
+				switch {
+				case unicode.IsUpper(r):
+					hasUpper = true
+				case unicode.IsLower(r):
+					hasLower = true
+				case unicode.IsDigit(r):
+					hasDigit = true
+				default:
+					hasSymbol = true
+				}
+			}
+			if !hasUpper || !hasLower || !hasDigit || !hasSymbol {
+				t.Errorf("password %q does not contain every character class", password)
+			}
+		})
+	}
+}
@@ -62,3 +62,34 @@ func RegisterRoute[T any, R any](group *gin.RouterGroup, method, path string, ha
 		SendResponse(ctx, http.StatusOK, SuccessResponse(res))
 	})...)
 }
+
+// RegisterStreamRoute 封装了请求参数绑定，用于由处理函数自行写出响应体的接口（例如文件导出）
+// 处理函数在写出任何数据之前返回错误时按统一格式返回 JSON 错误；已开始写出后只能中断连接
+func RegisterStreamRoute[T any](group *gin.RouterGroup, method, path string, handlerFunc func(ctx *gin.Context, req *T) error, middlewares ...gin.HandlerFunc) {
+	group.Handle(method, path, append(middlewares, func(ctx *gin.Context) {
+		// 执行请求参数绑定和校验
+		req, err := bindAndValidate[T](ctx, method)
+		if err != nil {
+			SendResponse(ctx, http.StatusBadRequest, ErrorResponse(BadRequest, err.Error()))
+			return
+		}
+
+		// 调用实际的处理函数
+		if err = handlerFunc(ctx, req); err != nil {
+			if ctx.Writer.Written() {
+				_ = ctx.Error(err)
+				ctx.Abort()
+				return
+			}
+
+			// 未写出数据时清除处理函数设置的文件响应头，恢复为 JSON 响应
+			ctx.Header("Content-Type", "")
+			ctx.Header("Content-Disposition", "")
+			if businessErr, ok := err.(*BusinessError); ok {
+				SendResponse(ctx, http.StatusBadRequest, ErrorResponse(businessErr.Code, businessErr.Message))
+				return
+			}
+			SendResponse(ctx, http.StatusInternalServerError, ErrorResponse(InternalError, err.Error()))
+		}
+	})...)
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIM 接口
+const (
+	ScimBasePath    = "/scim/v2"              // SCIM 接口的路由前缀
+	ScimContentType = "application/scim+json" // SCIM 请求和响应的内容类型
+)
+
+// ScimError SCIM 协议错误，按 RFC 7644 3.12 的错误格式返回给调用方
+type ScimError struct {
+	Status   int    // HTTP 状态码
+	ScimType string // 错误类型，见 scim.Error* 常量，可为空
+	Detail   string // 错误详情
+}
+
+// 实现 error 接口
+func (e *ScimError) Error() string {
+	return fmt.Sprintf("Status: %d, ScimType: %s, Detail: %s", e.Status, e.ScimType, e.Detail)
+}
+
+// NewScimError 创建一个新的 SCIM 协议错误
+func NewScimError(status int, scimType, detail string) *ScimError {
+	return &ScimError{
+		Status:   status,
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// SendScimResponse 发送 SCIM 响应，body 为空时只返回状态码
+func SendScimResponse(ctx *gin.Context, statusCode int, body interface{}) {
+	if body == nil {
+		ctx.Status(statusCode)
+		ctx.Abort()
+		return
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		SendScimError(ctx, NewScimError(http.StatusInternalServerError, "", err.Error()))
+		return
+	}
+	ctx.Data(statusCode, ScimContentType, data)
+	ctx.Abort()
+}
+
+// SendScimError 发送 SCIM 错误响应
+func SendScimError(ctx *gin.Context, err *ScimError) {
+	data, _ := json.Marshal(&scim.Error{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(err.Status),
+		ScimType: err.ScimType,
+		Detail:   err.Detail,
+	})
+	ctx.Data(err.Status, ScimContentType, data)
+	ctx.Abort()
+}
+
+// ScimBaseURL 根据请求推断 SCIM 接口的根地址，用于资源的 meta.location，非 HTTP 请求上下文返回空字符串
+func ScimBaseURL(ctx context.Context) string {
+	c, ok := ctx.(*gin.Context)
+	if !ok {
+		return ""
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + ScimBasePath
+}
+
+// ScimETag 计算资源的弱 ETag，资源内容（不含 meta）不变时 ETag 不变
+func ScimETag(resource interface{}) (string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
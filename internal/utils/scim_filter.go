@@ -0,0 +1,509 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SCIM 过滤表达式中可用于数据库查询的属性类型
+const (
+	ScimAttrString   = "string"   // 字符串
+	ScimAttrBoolean  = "boolean"  // 布尔值，数据库中以 1/0 存储
+	ScimAttrDateTime = "dateTime" // 时间，比较值为 RFC3339 格式
+)
+
+// scimCorePrefixes 核心 schema 的属性全名前缀，解析属性路径时去除
+var scimCorePrefixes = []string{
+	"urn:ietf:params:scim:schemas:core:2.0:user:",
+	"urn:ietf:params:scim:schemas:core:2.0:group:",
+}
+
+// scimCompareOps 比较运算符
+var scimCompareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// ScimFilter SCIM 过滤表达式（RFC 7644 3.4.2.2）的语法树节点
+type ScimFilter struct {
+	Op    string      // 运算符：and、or、not、pr 或比较运算符（eq、ne、co、sw、ew、gt、ge、lt、le）
+	Attr  string      // 属性路径，仅 pr 和比较运算符使用，例如 emails.value
+	Value interface{} // 比较值（string、float64、bool 或 nil）
+	Left  *ScimFilter // and、or 的左操作数，not 的操作数
+	Right *ScimFilter // and、or 的右操作数
+}
+
+// ScimAttribute 可用于过滤的 SCIM 属性与数据库列的对应关系
+type ScimAttribute struct {
+	Column string // 列名
+	Type   string // 属性类型，见 ScimAttr* 常量
+	// Membership 多值引用属性（例如 groups、members）的成员条件，唯一参数为被引用资源的ID；设置后仅支持 eq
+	Membership string
+}
+
+// ParseScimFilter 解析 SCIM 过滤表达式
+// 支持 and、or、not、括号以及 attr[filter] 形式的值过滤，值过滤会展开为对子属性的比较，
+// 例如 members[value eq "id"] 等价于 members.value eq "id"。
+func ParseScimFilter(expr string) (*ScimFilter, error) {
+	tokens, err := scanScimFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &scimFilterParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != scimTokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return filter, nil
+}
+
+// NormalizeScimAttr 去除属性路径中核心 schema 的前缀
+func NormalizeScimAttr(attr string) string {
+	lower := strings.ToLower(attr)
+	for _, prefix := range scimCorePrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return attr[len(prefix):]
+		}
+	}
+	return attr
+}
+
+// ToSQL 将过滤表达式转换为 SQL 条件，attributes 的键为小写的属性路径
+func (f *ScimFilter) ToSQL(attributes map[string]ScimAttribute) (string, []interface{}, error) {
+	switch f.Op {
+	case "and", "or":
+		left, leftArgs, err := f.Left.ToSQL(attributes)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := f.Right.ToSQL(attributes)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + left + " " + strings.ToUpper(f.Op) + " " + right + ")", append(leftArgs, rightArgs...), nil
+	case "not":
+		inner, args, err := f.Left.ToSQL(attributes)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + inner + ")", args, nil
+	}
+
+	attribute, ok := attributes[strings.ToLower(f.Attr)]
+	if !ok {
+		return "", nil, fmt.Errorf("attribute %q is not filterable", f.Attr)
+	}
+
+	if attribute.Membership != "" {
+		id, ok := f.Value.(string)
+		if f.Op != "eq" || !ok {
+			return "", nil, fmt.Errorf("attribute %q only supports eq with a string value", f.Attr)
+		}
+		return attribute.Membership, []interface{}{id}, nil
+	}
+
+	column := attribute.Column
+	if f.Op == "pr" {
+		if attribute.Type == ScimAttrString {
+			return "(" + column + " IS NOT NULL AND " + column + " <> '')", nil, nil
+		}
+		return column + " IS NOT NULL", nil, nil
+	}
+
+	if f.Value == nil {
+		switch f.Op {
+		case "eq":
+			return column + " IS NULL", nil, nil
+		case "ne":
+			return column + " IS NOT NULL", nil, nil
+		}
+		return "", nil, fmt.Errorf("operator %s does not support null", f.Op)
+	}
+
+	value, err := scimColumnValue(attribute, f.Op, f.Value)
+	if err != nil {
+		return "", nil, fmt.Errorf("attribute %q: %w", f.Attr, err)
+	}
+
+	switch f.Op {
+	case "eq":
+		return column + " = ?", []interface{}{value}, nil
+	case "ne":
+		return "(" + column + " <> ? OR " + column + " IS NULL)", []interface{}{value}, nil
+	case "co":
+		return column + " LIKE ?", []interface{}{"%" + escapeLike(value.(string)) + "%"}, nil
+	case "sw":
+		return column + " LIKE ?", []interface{}{escapeLike(value.(string)) + "%"}, nil
+	case "ew":
+		return column + " LIKE ?", []interface{}{"%" + escapeLike(value.(string))}, nil
+	case "gt":
+		return column + " > ?", []interface{}{value}, nil
+	case "ge":
+		return column + " >= ?", []interface{}{value}, nil
+	case "lt":
+		return column + " < ?", []interface{}{value}, nil
+	case "le":
+		return column + " <= ?", []interface{}{value}, nil
+	}
+	return "", nil, fmt.Errorf("unsupported operator %s", f.Op)
+}
+
+// scimColumnValue 按属性类型校验比较值并转换为数据库中的值
+func scimColumnValue(attribute ScimAttribute, op string, value interface{}) (interface{}, error) {
+	switch attribute.Type {
+	case ScimAttrBoolean:
+		b, ok := value.(bool)
+		if !ok || (op != "eq" && op != "ne") {
+			return nil, fmt.Errorf("only eq and ne with a boolean value are supported")
+		}
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case ScimAttrDateTime:
+		s, ok := value.(string)
+		if !ok || op == "co" || op == "sw" || op == "ew" {
+			return nil, fmt.Errorf("only eq, ne, gt, ge, lt and le with a date-time value are supported")
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date-time %q", s)
+		}
+		return t, nil
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("a string value is required")
+		}
+		return s, nil
+	}
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Match 判断多值属性的元素是否满足过滤表达式，用于 PATCH 路径中的值过滤
+// 字符串比较不区分大小写。
+func (f *ScimFilter) Match(element map[string]interface{}) bool {
+	switch f.Op {
+	case "and":
+		return f.Left.Match(element) && f.Right.Match(element)
+	case "or":
+		return f.Left.Match(element) || f.Right.Match(element)
+	case "not":
+		return !f.Left.Match(element)
+	}
+
+	actual, ok := element[findScimKey(element, f.Attr)]
+	if f.Op == "pr" {
+		return ok && actual != nil && actual != ""
+	}
+
+	switch expected := f.Value.(type) {
+	case nil:
+		isNull := !ok || actual == nil
+		return (f.Op == "eq") == isNull
+	case bool:
+		b, isBool := actual.(bool)
+		if f.Op == "ne" {
+			return !isBool || b != expected
+		}
+		return f.Op == "eq" && isBool && b == expected
+	case float64:
+		n, isNumber := actual.(float64)
+		if !isNumber {
+			return f.Op == "ne"
+		}
+		return compareScimValues(f.Op, strconv.FormatFloat(n, 'f', -1, 64), strconv.FormatFloat(expected, 'f', -1, 64), n-expected)
+	case string:
+		s, isString := actual.(string)
+		if !isString {
+			return f.Op == "ne"
+		}
+		a, e := strings.ToLower(s), strings.ToLower(expected)
+		return compareScimValues(f.Op, a, e, float64(strings.Compare(a, e)))
+	}
+	return false
+}
+
+// compareScimValues 按运算符比较两个已规范化的值，diff 为两者的大小关系
+func compareScimValues(op, actual, expected string, diff float64) bool {
+	switch op {
+	case "eq":
+		return actual == expected
+	case "ne":
+		return actual != expected
+	case "co":
+		return strings.Contains(actual, expected)
+	case "sw":
+		return strings.HasPrefix(actual, expected)
+	case "ew":
+		return strings.HasSuffix(actual, expected)
+	case "gt":
+		return diff > 0
+	case "ge":
+		return diff >= 0
+	case "lt":
+		return diff < 0
+	case "le":
+		return diff <= 0
+	}
+	return false
+}
+
+// scimEqualities 提取由 and 连接的 eq 比较，用于在 PATCH 时按值过滤创建缺失的元素
+func (f *ScimFilter) scimEqualities() (map[string]interface{}, bool) {
+	switch f.Op {
+	case "eq":
+		return map[string]interface{}{f.Attr: f.Value}, true
+	case "and":
+		left, ok := f.Left.scimEqualities()
+		if !ok {
+			return nil, false
+		}
+		right, ok := f.Right.scimEqualities()
+		if !ok {
+			return nil, false
+		}
+		for k, v := range right {
+			left[k] = v
+		}
+		return left, true
+	}
+	return nil, false
+}
+
+// prefix 为表达式中的全部属性加上前缀，用于展开 attr[filter]
+func (f *ScimFilter) prefix(attr string) {
+	if f == nil {
+		return
+	}
+	if f.Attr != "" {
+		f.Attr = attr + "." + f.Attr
+	}
+	f.Left.prefix(attr)
+	f.Right.prefix(attr)
+}
+
+// SCIM 过滤表达式的词法单元类型
+const (
+	scimTokenEOF = iota
+	scimTokenWord
+	scimTokenString
+	scimTokenNumber
+	scimTokenLParen
+	scimTokenRParen
+	scimTokenLBracket
+	scimTokenRBracket
+)
+
+// scimToken SCIM 过滤表达式的词法单元
+type scimToken struct {
+	kind  int
+	text  string
+	value interface{}
+	pos   int
+}
+
+// scanScimFilter 将过滤表达式拆分为词法单元
+func scanScimFilter(expr string) ([]scimToken, error) {
+	var tokens []scimToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			kind := map[byte]int{'(': scimTokenLParen, ')': scimTokenRParen, '[': scimTokenLBracket, ']': scimTokenRBracket}[c]
+			tokens = append(tokens, scimToken{kind: kind, text: string(c), pos: i})
+			i++
+		case c == '"':
+			// 按 JSON 字符串解析，支持转义字符
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			var s string
+			if err := json.Unmarshal([]byte(expr[i:end+1]), &s); err != nil {
+				return nil, fmt.Errorf("invalid string at position %d", i)
+			}
+			tokens = append(tokens, scimToken{kind: scimTokenString, text: expr[i : end+1], value: s, pos: i})
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(expr) && strings.IndexByte("0123456789.eE+-", expr[end]) >= 0 {
+				end++
+			}
+			n, err := strconv.ParseFloat(expr[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number at position %d", i)
+			}
+			tokens = append(tokens, scimToken{kind: scimTokenNumber, text: expr[i:end], value: n, pos: i})
+			i = end
+		case isScimWordChar(c):
+			end := i + 1
+			for end < len(expr) && isScimWordChar(expr[end]) {
+				end++
+			}
+			tokens = append(tokens, scimToken{kind: scimTokenWord, text: expr[i:end], pos: i})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	return append(tokens, scimToken{kind: scimTokenEOF, pos: len(expr)}), nil
+}
+
+// isScimWordChar 判断字符是否可以出现在属性路径或关键字中
+func isScimWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == ':' || c == '$'
+}
+
+// scimFilterParser SCIM 过滤表达式的递归下降解析器，优先级从低到高为 or、and、not
+type scimFilterParser struct {
+	tokens []scimToken
+	pos    int
+}
+
+func (p *scimFilterParser) peek() scimToken {
+	return p.tokens[p.pos]
+}
+
+func (p *scimFilterParser) next() scimToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != scimTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// peekKeyword 判断下一个词法单元是否为指定关键字（不区分大小写）
+func (p *scimFilterParser) peekKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == scimTokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+// expect 读取指定类型的词法单元
+func (p *scimFilterParser) expect(kind int, text string) error {
+	if tok := p.next(); tok.kind != kind {
+		return fmt.Errorf("expected %q at position %d", text, tok.pos)
+	}
+	return nil
+}
+
+func (p *scimFilterParser) parseOr() (*ScimFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &ScimFilter{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd() (*ScimFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &ScimFilter{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseUnary() (*ScimFilter, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == scimTokenLParen:
+		return p.parseGroup(scimTokenRParen, ")")
+	case tok.kind == scimTokenWord && strings.EqualFold(tok.text, "not") && p.peek().kind == scimTokenLParen:
+		p.next()
+		inner, err := p.parseGroup(scimTokenRParen, ")")
+		if err != nil {
+			return nil, err
+		}
+		return &ScimFilter{Op: "not", Left: inner}, nil
+	case tok.kind != scimTokenWord:
+		return nil, fmt.Errorf("expected attribute at position %d", tok.pos)
+	}
+
+	attr := NormalizeScimAttr(tok.text)
+	if p.peek().kind == scimTokenLBracket {
+		p.next()
+		inner, err := p.parseGroup(scimTokenRBracket, "]")
+		if err != nil {
+			return nil, err
+		}
+		inner.prefix(attr)
+		return inner, nil
+	}
+
+	opTok := p.next()
+	op := strings.ToLower(opTok.text)
+	if opTok.kind != scimTokenWord || (op != "pr" && !scimCompareOps[op]) {
+		return nil, fmt.Errorf("expected operator at position %d", opTok.pos)
+	}
+	if op == "pr" {
+		return &ScimFilter{Op: op, Attr: attr}, nil
+	}
+
+	valueTok := p.next()
+	filter := &ScimFilter{Op: op, Attr: attr}
+	switch valueTok.kind {
+	case scimTokenString, scimTokenNumber:
+		filter.Value = valueTok.value
+	case scimTokenWord:
+		switch strings.ToLower(valueTok.text) {
+		case "true":
+			filter.Value = true
+		case "false":
+			filter.Value = false
+		case "null":
+			filter.Value = nil
+		default:
+			return nil, fmt.Errorf("invalid value %q at position %d", valueTok.text, valueTok.pos)
+		}
+	default:
+		return nil, fmt.Errorf("expected value at position %d", valueTok.pos)
+	}
+	return filter, nil
+}
+
+// parseGroup 解析括号内的表达式，直到对应的右括号
+func (p *scimFilterParser) parseGroup(closeKind int, closeText string) (*ScimFilter, error) {
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(closeKind, closeText); err != nil {
+		return nil, err
+	}
+	return inner, nil
+}
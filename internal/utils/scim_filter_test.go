@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScimFilterToSQL(t *testing.T) {
+	attributes := map[string]ScimAttribute{
+		"username":          {Column: "username", Type: ScimAttrString},
+		"active":            {Column: "status", Type: ScimAttrBoolean},
+		"meta.lastmodified": {Column: "updated_at", Type: ScimAttrDateTime},
+		"groups.value":      {Membership: "id IN (SELECT user_id FROM user_roles WHERE role_id = ?)"},
+	}
+	lastModified := time.Date(2024, 11, 18, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		filter   string
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{name: "eq", filter: `userName eq "alice"`, wantSQL: "username = ?", wantArgs: []interface{}{"alice"}},
+		{name: "core schema prefix and keyword case", filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName EQ "alice"`, wantSQL: "username = ?", wantArgs: []interface{}{"alice"}},
+		{name: "escaped quote", filter: `userName eq "a\"b"`, wantSQL: "username = ?", wantArgs: []interface{}{`a"b`}},
+		{name: "ne includes null", filter: `userName ne "alice"`, wantSQL: "(username <> ? OR username IS NULL)", wantArgs: []interface{}{"alice"}},
+		{name: "co escapes wildcards", filter: `userName co "a_b%"`, wantSQL: "username LIKE ?", wantArgs: []interface{}{`%a\_b\%%`}},
+		{name: "sw", filter: `userName sw "al"`, wantSQL: "username LIKE ?", wantArgs: []interface{}{"al%"}},
+		{name: "ew", filter: `userName ew "ce"`, wantSQL: "username LIKE ?", wantArgs: []interface{}{"%ce"}},
+		{name: "pr string", filter: `userName pr`, wantSQL: "(username IS NOT NULL AND username <> '')"},
+		{name: "eq null", filter: `userName eq null`, wantSQL: "username IS NULL"},
+		{name: "boolean true", filter: `active eq true`, wantSQL: "status = ?", wantArgs: []interface{}{1}},
+		{name: "boolean false", filter: `active ne false`, wantSQL: "(status <> ? OR status IS NULL)", wantArgs: []interface{}{0}},
+		{name: "date-time", filter: `meta.lastModified gt "2024-11-18T10:00:00Z"`, wantSQL: "updated_at > ?", wantArgs: []interface{}{lastModified}},
+		{name: "membership value filter", filter: `groups[value eq "r1"]`, wantSQL: "id IN (SELECT user_id FROM user_roles WHERE role_id = ?)", wantArgs: []interface{}{"r1"}},
+		{
+			name:     "and binds tighter than or",
+			filter:   `userName eq "a" and not (active eq false) or userName eq "b"`,
+			wantSQL:  "((username = ? AND NOT (status = ?)) OR username = ?)",
+			wantArgs: []interface{}{"a", 0, "b"},
+		},
+		{
+			name:     "parentheses",
+			filter:   `userName eq "a" and (active eq true or userName eq "b")`,
+			wantSQL:  "(username = ? AND (status = ? OR username = ?))",
+			wantArgs: []interface{}{"a", 1, "b"},
+		},
+		{name: "unknown attribute", filter: `title eq "x"`, wantErr: true},
+		{name: "boolean with string value", filter: `active eq "yes"`, wantErr: true},
+		{name: "boolean with gt", filter: `active gt true`, wantErr: true},
+		{name: "invalid date-time", filter: `meta.lastModified gt "yesterday"`, wantErr: true},
+		{name: "date-time with co", filter: `meta.lastModified co "2024"`, wantErr: true},
+		{name: "string with number", filter: `userName gt 5`, wantErr: true},
+		{name: "membership with ne", filter: `groups[value ne "r1"]`, wantErr: true},
+		{name: "null with gt", filter: `userName gt null`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseScimFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseScimFilter() error = %v", err)
+			}
+			sql, args, err := filter.ToSQL(attributes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToSQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("ToSQL() sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if len(args) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Errorf("ToSQL() args = %v, want %v", args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestParseScimFilterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+	}{
+		{name: "empty", filter: ""},
+		{name: "missing value", filter: `userName eq`},
+		{name: "unknown operator", filter: `userName is "a"`},
+		{name: "unterminated string", filter: `userName eq "a`},
+		{name: "unclosed parenthesis", filter: `(userName eq "a"`},
+		{name: "unclosed bracket", filter: `emails[type eq "work"`},
+		{name: "trailing token", filter: `userName eq "a" "b"`},
+		{name: "bare word value", filter: `userName eq alice`},
+		{name: "dangling and", filter: `userName eq "a" and`},
+		{name: "unexpected character", filter: `userName eq "a" # x`},
+		{name: "invalid number", filter: `age gt 1-2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if filter, err := ParseScimFilter(tt.filter); err == nil {
+				t.Errorf("ParseScimFilter() = %+v, want error", filter)
+			}
+		})
+	}
+}
+
+func TestScimFilterMatch(t *testing.T) {
+	element := map[string]interface{}{
+		"type":    "Work",
+		"value":   "alice@example.com",
+		"display": "",
+		"primary": true,
+		"weight":  float64(3),
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{name: "string eq ignores case", filter: `type eq "work"`, want: true},
+		{name: "attribute name ignores case", filter: `TYPE eq "work"`, want: true},
+		{name: "string ne", filter: `type ne "home"`, want: true},
+		{name: "sw", filter: `value sw "alice@"`, want: true},
+		{name: "ew", filter: `value ew "@example.org"`, want: false},
+		{name: "co", filter: `value co "EXAMPLE"`, want: true},
+		{name: "string gt", filter: `type gt "home"`, want: true},
+		{name: "boolean eq", filter: `primary eq true`, want: true},
+		{name: "boolean ne", filter: `primary ne true`, want: false},
+		{name: "missing boolean ne", filter: `verified ne true`, want: true},
+		{name: "number ge", filter: `weight ge 3`, want: true},
+		{name: "number lt", filter: `weight lt 3`, want: false},
+		{name: "type mismatch eq", filter: `weight eq "3"`, want: false},
+		{name: "type mismatch ne", filter: `weight ne "3"`, want: true},
+		{name: "pr", filter: `value pr`, want: true},
+		{name: "pr empty string", filter: `display pr`, want: false},
+		{name: "pr missing", filter: `verified pr`, want: false},
+		{name: "missing eq null", filter: `verified eq null`, want: true},
+		{name: "present ne null", filter: `value ne null`, want: true},
+		{name: "and", filter: `type eq "work" and primary eq false`, want: false},
+		{name: "or", filter: `type eq "home" or primary eq true`, want: true},
+		{name: "not", filter: `not (type eq "work")`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseScimFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseScimFilter() error = %v", err)
+			}
+			if got := filter.Match(element); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// scimPath PATCH 操作的属性路径，形如 attr、attr.subAttr、attr[filter] 或 attr[filter].subAttr
+type scimPath struct {
+	Attr    string
+	Filter  *ScimFilter
+	SubAttr string
+}
+
+// ApplyScimPatch 将 PATCH 操作（RFC 7644 3.5.2）依次应用到以 map 表示的资源上
+// 属性名不区分大小写；扩展 schema（非核心 schema 的 URN 前缀）的属性不受支持，会被忽略。
+// 按值过滤移除时没有匹配的元素视为成功；按值过滤新增或替换时没有匹配的元素，会按过滤条件中的 eq 比较创建该元素。
+func ApplyScimPatch(resource map[string]interface{}, operations []scim.PatchOperation) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "remove" && op != "replace" {
+			return NewScimError(http.StatusBadRequest, scim.ErrorInvalidSyntax, fmt.Sprintf("unsupported operation %q", operation.Op))
+		}
+
+		var value interface{}
+		if len(operation.Value) > 0 {
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return NewScimError(http.StatusBadRequest, scim.ErrorInvalidSyntax, "invalid operation value")
+			}
+		}
+
+		if operation.Path == "" {
+			if op == "remove" {
+				return NewScimError(http.StatusBadRequest, scim.ErrorNoTarget, "path is required for remove")
+			}
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, "value must be an object when path is omitted")
+			}
+			for attr, attrValue := range values {
+				path, err := parseScimPath(attr)
+				if err != nil {
+					return err
+				}
+				if path == nil {
+					continue
+				}
+				if err = applyScimOperation(resource, op, path, attrValue); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		path, err := parseScimPath(operation.Path)
+		if err != nil {
+			return err
+		}
+		if path == nil {
+			continue
+		}
+		if op != "remove" && value == nil {
+			return NewScimError(http.StatusBadRequest, scim.ErrorInvalidValue, "value is required for "+op)
+		}
+		if err = applyScimOperation(resource, op, path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseScimPath 解析 PATCH 操作的属性路径，扩展 schema 的属性返回 nil
+func parseScimPath(raw string) (*scimPath, error) {
+	path := NormalizeScimAttr(strings.TrimSpace(raw))
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		return nil, nil
+	}
+
+	result := &scimPath{}
+	if open := strings.IndexByte(path, '['); open >= 0 {
+		closing := strings.LastIndexByte(path, ']')
+		if closing < open {
+			return nil, NewScimError(http.StatusBadRequest, scim.ErrorInvalidPath, fmt.Sprintf("invalid path %q", raw))
+		}
+		filter, err := ParseScimFilter(path[open+1 : closing])
+		if err != nil {
+			return nil, NewScimError(http.StatusBadRequest, scim.ErrorInvalidPath, fmt.Sprintf("invalid path %q: %v", raw, err))
+		}
+		result.Attr, result.Filter = path[:open], filter
+		rest := path[closing+1:]
+		if rest != "" {
+			if !strings.HasPrefix(rest, ".") {
+				return nil, NewScimError(http.StatusBadRequest, scim.ErrorInvalidPath, fmt.Sprintf("invalid path %q", raw))
+			}
+			result.SubAttr = rest[1:]
+		}
+	} else if dot := strings.IndexByte(path, '.'); dot >= 0 {
+		result.Attr, result.SubAttr = path[:dot], path[dot+1:]
+	} else {
+		result.Attr = path
+	}
+
+	if result.Attr == "" || strings.ContainsAny(result.SubAttr, ".[]") {
+		return nil, NewScimError(http.StatusBadRequest, scim.ErrorInvalidPath, fmt.Sprintf("invalid path %q", raw))
+	}
+	return result, nil
+}
+
+// applyScimOperation 将单个操作应用到资源上
+func applyScimOperation(resource map[string]interface{}, op string, path *scimPath, value interface{}) error {
+	key := findScimKey(resource, path.Attr)
+
+	// 按值过滤选中多值属性的元素
+	if path.Filter != nil {
+		elements, _ := resource[key].([]interface{})
+		matched := false
+		kept := make([]interface{}, 0, len(elements))
+		for _, element := range elements {
+			item, ok := element.(map[string]interface{})
+			if !ok || !path.Filter.Match(item) {
+				kept = append(kept, element)
+				continue
+			}
+			matched = true
+			switch {
+			case op == "remove" && path.SubAttr == "":
+				continue
+			case op == "remove":
+				delete(item, findScimKey(item, path.SubAttr))
+			case path.SubAttr != "":
+				item[findScimKey(item, path.SubAttr)] = value
+			case op == "replace":
+				element = value
+			default:
+				element = mergeScimValue(item, value)
+			}
+			kept = append(kept, element)
+		}
+
+		if !matched && op != "remove" {
+			item, ok := path.Filter.scimEqualities()
+			if !ok {
+				return NewScimError(http.StatusBadRequest, scim.ErrorNoTarget, fmt.Sprintf("no value matches %q", path.Attr))
+			}
+			if path.SubAttr != "" {
+				item[path.SubAttr] = value
+			} else if values, ok := value.(map[string]interface{}); ok {
+				for k, v := range values {
+					item[k] = v
+				}
+			}
+			kept = append(kept, item)
+		}
+		resource[key] = kept
+		return nil
+	}
+
+	// 子属性
+	if path.SubAttr != "" {
+		switch target := resource[key].(type) {
+		case map[string]interface{}:
+			if op == "remove" {
+				delete(target, findScimKey(target, path.SubAttr))
+			} else {
+				target[findScimKey(target, path.SubAttr)] = value
+			}
+		case []interface{}:
+			// 多值属性的子属性作用于全部元素，没有元素时新增一个
+			if len(target) == 0 && op != "remove" {
+				resource[key] = []interface{}{map[string]interface{}{path.SubAttr: value}}
+				return nil
+			}
+			for _, element := range target {
+				if item, ok := element.(map[string]interface{}); ok {
+					if op == "remove" {
+						delete(item, findScimKey(item, path.SubAttr))
+					} else {
+						item[findScimKey(item, path.SubAttr)] = value
+					}
+				}
+			}
+		default:
+			if op != "remove" {
+				resource[key] = map[string]interface{}{path.SubAttr: value}
+			}
+		}
+		return nil
+	}
+
+	switch op {
+	case "remove":
+		// 部分身份提供商以 value 指定要从多值属性中移除的元素
+		existing, isArray := resource[key].([]interface{})
+		removing, hasValues := value.([]interface{})
+		if !isArray || !hasValues {
+			delete(resource, key)
+			return nil
+		}
+		kept := make([]interface{}, 0, len(existing))
+		for _, element := range existing {
+			if !containsScimElement(removing, element) {
+				kept = append(kept, element)
+			}
+		}
+		resource[key] = kept
+	case "add":
+		if existing, ok := resource[key].([]interface{}); ok {
+			additions, ok := value.([]interface{})
+			if !ok {
+				additions = []interface{}{value}
+			}
+			for _, element := range additions {
+				if !containsScimElement(existing, element) {
+					existing = append(existing, element)
+				}
+			}
+			resource[key] = existing
+			return nil
+		}
+		resource[key] = mergeScimValue(resource[key], value)
+	default:
+		resource[key] = mergeScimValue(resource[key], value)
+	}
+	return nil
+}
+
+// mergeScimValue 合并复杂属性，未指定的子属性保持不变；其他类型的值直接替换
+func mergeScimValue(existing, value interface{}) interface{} {
+	target, ok := existing.(map[string]interface{})
+	values, isMap := value.(map[string]interface{})
+	if !ok || !isMap {
+		return value
+	}
+	for k, v := range values {
+		target[findScimKey(target, k)] = v
+	}
+	return target
+}
+
+// containsScimElement 判断多值属性中是否已有该元素，复杂元素按 value 子属性比较
+func containsScimElement(elements []interface{}, element interface{}) bool {
+	item, isMap := element.(map[string]interface{})
+	for _, existing := range elements {
+		existingItem, ok := existing.(map[string]interface{})
+		if isMap && ok {
+			v, hasValue := item[findScimKey(item, "value")]
+			ev, existingHasValue := existingItem[findScimKey(existingItem, "value")]
+			if hasValue && existingHasValue {
+				if v == ev {
+					return true
+				}
+				continue
+			}
+		}
+		if reflect.DeepEqual(existing, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// findScimKey 查找与属性名匹配（不区分大小写）的已有键，没有时返回属性名本身
+func findScimKey(m map[string]interface{}, attr string) string {
+	if _, ok := m[attr]; ok {
+		return attr
+	}
+	for key := range m {
+		if strings.EqualFold(key, attr) {
+			return key
+		}
+	}
+	return attr
+}
@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"ByteScience-WAM-Admin/internal/model/dto/scim"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyScimPatch(t *testing.T) {
+	const user = `{
+		"userName": "alice",
+		"active": true,
+		"name": {"givenName": "Alice", "familyName": "Liu"},
+		"emails": [
+			{"value": "alice@example.com", "type": "work", "primary": true},
+			{"value": "alice@home.example", "type": "home"}
+		],
+		"groups": [{"value": "r1"}, {"value": "r2"}]
+	}`
+	tests := []struct {
+		name         string
+		resource     string
+		operations   []scim.PatchOperation
+		want         string
+		wantScimType string
+	}{
+		{
+			name:       "replace attribute",
+			resource:   `{"userName": "alice", "active": true}`,
+			operations: []scim.PatchOperation{{Op: "Replace", Path: "active", Value: json.RawMessage(`false`)}},
+			want:       `{"userName": "alice", "active": false}`,
+		},
+		{
+			name:       "attribute name ignores case",
+			resource:   `{"userName": "alice"}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: "USERNAME", Value: json.RawMessage(`"bob"`)}},
+			want:       `{"userName": "bob"}`,
+		},
+		{
+			name:       "core schema prefix",
+			resource:   `{"userName": "alice"}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: "urn:ietf:params:scim:schemas:core:2.0:User:userName", Value: json.RawMessage(`"bob"`)}},
+			want:       `{"userName": "bob"}`,
+		},
+		{
+			name:       "extension attribute ignored",
+			resource:   `{"userName": "alice"}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", Value: json.RawMessage(`"R&D"`)}},
+			want:       `{"userName": "alice"}`,
+		},
+		{
+			name:       "sub-attribute",
+			resource:   `{"name": {"givenName": "Alice", "familyName": "Liu"}}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: "name.givenName", Value: json.RawMessage(`"Ann"`)}},
+			want:       `{"name": {"givenName": "Ann", "familyName": "Liu"}}`,
+		},
+		{
+			name:       "complex value merged",
+			resource:   `{"name": {"givenName": "Alice", "familyName": "Liu"}}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: "name", Value: json.RawMessage(`{"familyName": "Wang"}`)}},
+			want:       `{"name": {"givenName": "Alice", "familyName": "Wang"}}`,
+		},
+		{
+			name:       "no path",
+			resource:   `{"userName": "alice", "active": true}`,
+			operations: []scim.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"active": false, "name.givenName": "Ann"}`)}},
+			want:       `{"userName": "alice", "active": false, "name": {"givenName": "Ann"}}`,
+		},
+		{
+			name:       "remove attribute",
+			resource:   `{"userName": "alice", "nickName": "al"}`,
+			operations: []scim.PatchOperation{{Op: "remove", Path: "nickName"}},
+			want:       `{"userName": "alice"}`,
+		},
+		{
+			name:       "add to multi-valued attribute skips existing values",
+			resource:   user,
+			operations: []scim.PatchOperation{{Op: "add", Path: "groups", Value: json.RawMessage(`[{"value": "r2"}, {"value": "r3"}]`)}},
+			want: `{
+				"userName": "alice", "active": true, "name": {"givenName": "Alice", "familyName": "Liu"},
+				"emails": [{"value": "alice@example.com", "type": "work", "primary": true}, {"value": "alice@home.example", "type": "home"}],
+				"groups": [{"value": "r1"}, {"value": "r2"}, {"value": "r3"}]
+			}`,
+		},
+		{
+			name:       "remove by value",
+			resource:   `{"groups": [{"value": "r1"}, {"value": "r2"}]}`,
+			operations: []scim.PatchOperation{{Op: "remove", Path: "groups", Value: json.RawMessage(`[{"value": "r1"}]`)}},
+			want:       `{"groups": [{"value": "r2"}]}`,
+		},
+		{
+			name:       "remove by value filter",
+			resource:   `{"groups": [{"value": "r1"}, {"value": "r2"}]}`,
+			operations: []scim.PatchOperation{{Op: "remove", Path: `groups[value eq "r1"]`}},
+			want:       `{"groups": [{"value": "r2"}]}`,
+		},
+		{
+			name:       "remove by value filter without match",
+			resource:   `{"groups": [{"value": "r1"}]}`,
+			operations: []scim.PatchOperation{{Op: "remove", Path: `groups[value eq "r9"]`}},
+			want:       `{"groups": [{"value": "r1"}]}`,
+		},
+		{
+			name:       "replace sub-attribute by value filter",
+			resource:   `{"emails": [{"value": "a@work.example", "type": "work"}, {"value": "a@home.example", "type": "home"}]}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"b@work.example"`)}},
+			want:       `{"emails": [{"value": "b@work.example", "type": "work"}, {"value": "a@home.example", "type": "home"}]}`,
+		},
+		{
+			name:       "value filter creates missing element",
+			resource:   `{"emails": [{"value": "a@home.example", "type": "home"}]}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"a@work.example"`)}},
+			want:       `{"emails": [{"value": "a@home.example", "type": "home"}, {"value": "a@work.example", "type": "work"}]}`,
+		},
+		{
+			name:       "sub-attribute of empty multi-valued attribute",
+			resource:   `{"emails": []}`,
+			operations: []scim.PatchOperation{{Op: "replace", Path: "emails.value", Value: json.RawMessage(`"a@example.com"`)}},
+			want:       `{"emails": [{"value": "a@example.com"}]}`,
+		},
+		{
+			name:     "operations applied in order",
+			resource: `{"groups": [{"value": "r1"}]}`,
+			operations: []scim.PatchOperation{
+				{Op: "remove", Path: "groups"},
+				{Op: "add", Path: "groups", Value: json.RawMessage(`[{"value": "r2"}]`)},
+			},
+			want: `{"groups": [{"value": "r2"}]}`,
+		},
+		{
+			name:         "unsupported operation",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "move", Path: "userName"}},
+			wantScimType: scim.ErrorInvalidSyntax,
+		},
+		{
+			name:         "invalid value",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "replace", Path: "userName", Value: json.RawMessage(`{`)}},
+			wantScimType: scim.ErrorInvalidSyntax,
+		},
+		{
+			name:         "remove without path",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "remove"}},
+			wantScimType: scim.ErrorNoTarget,
+		},
+		{
+			name:         "no path with non-object value",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "replace", Value: json.RawMessage(`"alice"`)}},
+			wantScimType: scim.ErrorInvalidValue,
+		},
+		{
+			name:         "missing value",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "add", Path: "userName"}},
+			wantScimType: scim.ErrorInvalidValue,
+		},
+		{
+			name:         "invalid filter in path",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "remove", Path: `groups[value eq]`}},
+			wantScimType: scim.ErrorInvalidPath,
+		},
+		{
+			name:         "nested sub-attribute",
+			resource:     `{}`,
+			operations:   []scim.PatchOperation{{Op: "replace", Path: "name.givenName.first", Value: json.RawMessage(`"A"`)}},
+			wantScimType: scim.ErrorInvalidPath,
+		},
+		{
+			name:         "value filter without equalities cannot create element",
+			resource:     `{"emails": []}`,
+			operations:   []scim.PatchOperation{{Op: "replace", Path: `emails[type ne "work"].value`, Value: json.RawMessage(`"a@example.com"`)}},
+			wantScimType: scim.ErrorNoTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resource map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.resource), &resource); err != nil {
+				t.Fatalf("invalid resource: %v", err)
+			}
+			err := ApplyScimPatch(resource, tt.operations)
+			if tt.wantScimType != "" {
+				var scimErr *ScimError
+				if !errors.As(err, &scimErr) || scimErr.ScimType != tt.wantScimType {
+					t.Fatalf("ApplyScimPatch() error = %v, want scimType %s", err, tt.wantScimType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyScimPatch() error = %v", err)
+			}
+			var want map[string]interface{}
+			if err = json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid want: %v", err)
+			}
+			if !reflect.DeepEqual(resource, want) {
+				got, _ := json.Marshal(resource)
+				t.Errorf("ApplyScimPatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
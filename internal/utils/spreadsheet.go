@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 表格文件格式
+const (
+	SpreadsheetFormatCSV  = "csv"  // CSV，UTF-8 编码
+	SpreadsheetFormatXLSX = "xlsx" // Excel 工作簿
+)
+
+// ErrSpreadsheetTooManyRows 表格行数超出限制
+var ErrSpreadsheetTooManyRows = errors.New("spreadsheet has too many rows")
+
+// SpreadsheetLimits 读取表格文件的限制
+type SpreadsheetLimits struct {
+	MaxRows           int   // 允许的最大行数（含表头），超出时返回 ErrSpreadsheetTooManyRows
+	UnzipSizeLimit    int64 // XLSX 解压后的最大总字节数，0 表示使用 excelize 的默认值
+	UnzipXMLSizeLimit int64 // XLSX 工作表解压到内存的最大字节数，超出后暂存到临时文件，0 表示使用 excelize 的默认值
+}
+
+// ReadSpreadsheet 读取上传的 CSV 或 XLSX 文件的全部行，按扩展名识别格式
+// CSV 须为 UTF-8 编码（可带 BOM）；XLSX 只读取第一个工作表。空行保留为空切片，以保证行号与文件一致。
+func ReadSpreadsheet(header *multipart.FileHeader, limits SpreadsheetLimits) ([][]string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".csv":
+		return readCSV(file, limits.MaxRows)
+	case ".xlsx":
+		return readXLSX(file, limits)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q", filepath.Ext(header.Filename))
+	}
+}
+
+// readCSV 读取 CSV 文件
+func readCSV(r io.Reader, maxRows int) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var rows [][]string
+	lastLine := 0 // 上一条记录结束的行号
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 跳过的空行补为空切片；带引号的字段跨行时整条记录仍只算一行，与电子表格中的行号一致
+		line, _ := reader.FieldPos(0)
+		blanks := line - lastLine - 1
+		if len(rows)+blanks >= maxRows {
+			return nil, ErrSpreadsheetTooManyRows
+		}
+		for i := 0; i < blanks; i++ {
+			rows = append(rows, nil)
+		}
+		last := len(record) - 1
+		lastLine, _ = reader.FieldPos(last)
+		lastLine += strings.Count(record[last], "\n")
+		if len(rows) == 0 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// readXLSX 读取 XLSX 文件的第一个工作表，解压后超出大小限制时返回错误
+func readXLSX(r io.Reader, limits SpreadsheetLimits) ([][]string, error) {
+	workbook, err := excelize.OpenReader(r, excelize.Options{
+		UnzipSizeLimit:    limits.UnzipSizeLimit,
+		UnzipXMLSizeLimit: limits.UnzipXMLSizeLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer workbook.Close()
+
+	sheets := workbook.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no sheet")
+	}
+
+	iterator, err := workbook.Rows(sheets[0])
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	var rows [][]string
+	for iterator.Next() {
+		if len(rows) >= limits.MaxRows {
+			return nil, ErrSpreadsheetTooManyRows
+		}
+		columns, err := iterator.Columns()
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, columns)
+	}
+	return rows, iterator.Error()
+}
+
+// SpreadsheetContentType 返回表格文件格式对应的响应内容类型，未指定格式时按 CSV 处理
+func SpreadsheetContentType(format string) string {
+	if format == SpreadsheetFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// SpreadsheetFileName 生成带时间戳的导出文件名，未指定格式时按 CSV 处理
+func SpreadsheetFileName(prefix, format string) string {
+	if format == "" {
+		format = SpreadsheetFormatCSV
+	}
+	return fmt.Sprintf("%s_%s.%s", prefix, time.Now().Format("20060102150405"), format)
+}
+
+// SpreadsheetWriter 按行写出 CSV 或 XLSX 文件
+type SpreadsheetWriter interface {
+	// Write 写入一行
+	Write(row []string) error
+	// Flush 将已写入的行输出到底层 io.Writer；XLSX 须在 Close 时整体输出，调用 Flush 不会输出数据
+	Flush() error
+	// Close 完成写入并输出剩余数据
+	Close() error
+	// Abort 放弃写入并释放资源，不再输出数据
+	Abort()
+}
+
+// NewSpreadsheetWriter 创建写出到 w 的表格写入器，未指定格式时按 CSV 处理
+// CSV 中以公式字符开头的单元格会加上单引号前缀，防止在电子表格中被当作公式执行。
+func NewSpreadsheetWriter(w io.Writer, format string) (SpreadsheetWriter, error) {
+	switch format {
+	case "", SpreadsheetFormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case SpreadsheetFormatXLSX:
+		workbook := excelize.NewFile()
+		stream, err := workbook.NewStreamWriter(workbook.GetSheetList()[0])
+		if err != nil {
+			_ = workbook.Close()
+			return nil, err
+		}
+		return &xlsxWriter{w: w, workbook: workbook, stream: stream}, nil
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet format %q", format)
+	}
+}
+
+// csvWriter CSV 写入器
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (cw *csvWriter) Write(row []string) error {
+	safe := make([]string, len(row))
+	for i, value := range row {
+		safe[i] = CSVSafe(value)
+	}
+	return cw.writer.Write(safe)
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	return cw.Flush()
+}
+
+func (cw *csvWriter) Abort() {}
+
+// xlsxWriter XLSX 写入器，行数据先写入流式工作表（超出内存阈值时暂存到临时文件），Close 时整体输出
+type xlsxWriter struct {
+	w        io.Writer
+	workbook *excelize.File
+	stream   *excelize.StreamWriter
+	rows     int
+}
+
+func (xw *xlsxWriter) Write(row []string) error {
+	xw.rows++
+	cell, err := excelize.CoordinatesToCellName(1, xw.rows)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(row))
+	for i, value := range row {
+		values[i] = value
+	}
+	return xw.stream.SetRow(cell, values)
+}
+
+func (xw *xlsxWriter) Flush() error {
+	return nil
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.workbook.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.workbook.Write(xw.w)
+}
+
+func (xw *xlsxWriter) Abort() {
+	_ = xw.workbook.Close()
+}
+
+// CSVSafe 防止用户可控的字段在电子表格中被当作公式执行，E.164 格式的手机号保持原样
+func CSVSafe(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if value[0] == '+' && len(value) > 1 && strings.Trim(value[1:], "0123456789") == "" {
+		return value
+	}
+	return "'" + value
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		maxRows int
+		want    [][]string
+		wantErr error
+	}{
+		{
+			name:    "header and rows",
+			content: "userName,password\nuser1,Passw0rd!\nuser2,Passw0rd!\n",
+			maxRows: 10,
+			want:    [][]string{{"userName", "password"}, {"user1", "Passw0rd!"}, {"user2", "Passw0rd!"}},
+		},
+		{
+			name:    "byte order mark removed",
+			content: "\ufeffuserName,password\nuser1,Passw0rd!\n",
+			maxRows: 10,
+			want:    [][]string{{"userName", "password"}, {"user1", "Passw0rd!"}},
+		},
+		{
+			name:    "blank lines keep row numbers",
+			content: "userName\n\n\nuser1\n",
+			maxRows: 10,
+			want:    [][]string{{"userName"}, nil, nil, {"user1"}},
+		},
+		{
+			name:    "variable field count",
+			content: "userName,password,remark\nuser1,Passw0rd!\n",
+			maxRows: 10,
+			want:    [][]string{{"userName", "password", "remark"}, {"user1", "Passw0rd!"}},
+		},
+		{
+			name:    "quoted field with newline",
+			content: "userName,remark\nuser1,\"line1\nline2\"\nuser2,x\n",
+			maxRows: 10,
+			want:    [][]string{{"userName", "remark"}, {"user1", "line1\nline2"}, {"user2", "x"}},
+		},
+		{
+			name:    "blank line after multi-line field",
+			content: "userName,remark\nuser1,\"line1\nline2\"\n\nuser2,x\n",
+			maxRows: 10,
+			want:    [][]string{{"userName", "remark"}, {"user1", "line1\nline2"}, nil, {"user2", "x"}},
+		},
+		{
+			name:    "multi-line field counts as one row",
+			content: "userName,remark\nuser1,\"line1\nline2\nline3\"\n",
+			maxRows: 2,
+			want:    [][]string{{"userName", "remark"}, {"user1", "line1\nline2\nline3"}},
+		},
+		{
+			name:    "exactly max rows",
+			content: "userName\nuser1\nuser2\n",
+			maxRows: 3,
+			want:    [][]string{{"userName"}, {"user1"}, {"user2"}},
+		},
+		{
+			name:    "too many rows",
+			content: "userName\nuser1\nuser2\nuser3\n",
+			maxRows: 3,
+			wantErr: ErrSpreadsheetTooManyRows,
+		},
+		{
+			name:    "blank lines count towards max rows",
+			content: "userName\n\n\nuser1\n",
+			maxRows: 3,
+			wantErr: ErrSpreadsheetTooManyRows,
+		},
+		{
+			name:    "empty file",
+			content: "",
+			maxRows: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCSV(strings.NewReader(tt.content), tt.maxRows)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("readCSV() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := readCSV(strings.NewReader("userName\n\"unterminated\n"), 10); err == nil {
+		t.Error("readCSV() with malformed quotes returned no error")
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	workbook := excelize.NewFile()
+	sheet := workbook.GetSheetList()[0]
+	for i, row := range [][]interface{}{{"userName", "remark"}, {"user1", strings.Repeat("x", 4096)}} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := workbook.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatalf("SetSheetRow() error = %v", err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := workbook.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	_ = workbook.Close()
+
+	tests := []struct {
+		name     string
+		limits   SpreadsheetLimits
+		wantRows int
+		wantErr  bool
+	}{
+		{name: "within limits", limits: SpreadsheetLimits{MaxRows: 10}, wantRows: 2},
+		{name: "too many rows", limits: SpreadsheetLimits{MaxRows: 1}, wantErr: true},
+		{name: "unzip size limit exceeded", limits: SpreadsheetLimits{MaxRows: 10, UnzipSizeLimit: 1024, UnzipXMLSizeLimit: 1024}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readXLSX(bytes.NewReader(buf.Bytes()), tt.limits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readXLSX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(rows) != tt.wantRows {
+				t.Errorf("readXLSX() returned %d rows, want %d", len(rows), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "empty", value: "", want: ""},
+		{name: "plain text", value: "alice", want: "alice"},
+		{name: "formula", value: "=HYPERLINK(\"http://x\")", want: "'=HYPERLINK(\"http://x\")"},
+		{name: "plus formula", value: "+1+2", want: "'+1+2"},
+		{name: "minus", value: "-2+3", want: "'-2+3"},
+		{name: "at sign", value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", value: "\t=1", want: "'\t=1"},
+		{name: "carriage return", value: "\r=1", want: "'\r=1"},
+		{name: "e164 phone kept", value: "+8613800138000", want: "+8613800138000"},
+		{name: "lone plus", value: "+", want: "'+"},
+		{name: "phone with spaces", value: "+86 138", want: "'+86 138"},
+		{name: "formula character later", value: "a=b", want: "a=b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CSVSafe(tt.value); got != tt.want {
+				t.Errorf("CSVSafe(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录 B 中 SHA1 测试向量的密钥 "12345678901234567890"（Base32）
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHotp(t *testing.T) {
+	// RFC 4226 附录 D 的测试向量
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		counter int64
+		want    string
+	}{
+		{0, "755224"},
+		{1, "287082"},
+		{2, "359152"},
+		{3, "969429"},
+		{9, "520489"},
+	}
+	for _, tt := range tests {
+		if got := hotp(key, tt.counter); got != tt.want {
+			t.Errorf("hotp(%d) = %s, want %s", tt.counter, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	// RFC 6238 附录 B：59 秒时（第 1 个时间步）8 位验证码为 94287082，6 位为 287082
+	at := time.Unix(59, 0)
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		now      time.Time
+		wantOK   bool
+		wantStep int64
+	}{
+		{"current step", rfc6238Secret, "287082", at, true, 1},
+		{"lower case secret with spaces", " gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", "287082", at, true, 1},
+		{"previous step within skew", rfc6238Secret, "287082", at.Add(30 * time.Second), true, 1},
+		{"next step within skew", rfc6238Secret, "287082", time.Unix(0, 0), true, 1},
+		{"outside skew", rfc6238Secret, "287082", at.Add(90 * time.Second), false, 0},
+		{"wrong code", rfc6238Secret, "000000", at, false, 0},
+		{"wrong length", rfc6238Secret, "28708", at, false, 0},
+		{"invalid secret", "not base32!", "287082", at, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, step := VerifyTOTP(tt.secret, tt.code, tt.now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("VerifyTOTP() = %v, %d, want %v, %d", ok, step, tt.wantOK, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != totpSecretLen {
+		t.Errorf("secret length = %d, want %d", len(key), totpSecretLen)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("len(codes) = %d, want 10", len(codes))
+	}
+
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q does not match xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+		if NormalizeRecoveryCode(code) != code {
+			t.Errorf("NormalizeRecoveryCode(%q) changed a generated code", code)
+		}
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"abcde-fghij", "abcde-fghij"},
+		{"ABCDE-FGHIJ", "abcde-fghij"},
+		{"  abcde-fghij\n", "abcde-fghij"},
+		{"abcde - fghij", "abcde-fghij"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRecoveryCode(tt.code); got != tt.want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	got := TOTPProvisioningURI("WAM Admin", "john@example.com", rfc6238Secret)
+	want := "otpauth://totp/WAM%20Admin:john@example.com?algorithm=SHA1&digits=6&issuer=WAM+Admin&period=30&secret=" + rfc6238Secret
+	if got != want {
+		t.Errorf("TOTPProvisioningURI() = %s, want %s", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,7 +12,12 @@ func bindAndValidate[T any](ctx *gin.Context, method string) (*T, error) {
 	var req T
 	var err error
 
-	err = ctx.ShouldBindJSON(&req)
+	// 文件上传等 multipart/form-data 请求按表单绑定，其他请求按 JSON 绑定
+	if ctx.ContentType() == binding.MIMEMultipartPOSTForm {
+		err = ctx.ShouldBindWith(&req, binding.FormMultipart)
+	} else {
+		err = ctx.ShouldBindJSON(&req)
+	}
 
 	// 请求参数绑定失败
 	if err != nil {
@@ -19,10 +25,25 @@ func bindAndValidate[T any](ctx *gin.Context, method string) (*T, error) {
 	}
 
 	// 参数校验
+	if err = ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// ValidateStruct 按结构体的 validate 标签校验参数，返回第一个校验错误
+// excludeFields 为不参与校验的字段名，用于调用方自行校验的字段
+func ValidateStruct(s interface{}, excludeFields ...string) error {
 	validate := validator.New()
 
 	// 执行校验
-	err = validate.Struct(req)
+	var err error
+	if len(excludeFields) > 0 {
+		err = validate.StructExcept(s, excludeFields...)
+	} else {
+		err = validate.Struct(s)
+	}
 	if err != nil {
 		// 处理校验错误，返回最关键的错误信息
 		var validationErrors []string
@@ -41,10 +62,10 @@ func bindAndValidate[T any](ctx *gin.Context, method string) (*T, error) {
 
 		// 如果有错误，返回第一个错误信息
 		if len(validationErrors) > 0 {
-			return nil, fmt.Errorf(validationErrors[0])
+			return fmt.Errorf(validationErrors[0])
 		}
-		return nil, fmt.Errorf("Invalid Request Parameters")
+		return fmt.Errorf("Invalid Request Parameters")
 	}
 
-	return &req, nil
+	return nil
 }
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+)
+
+// webhookSecretLen 自动生成的 Webhook 签名密钥字节数
+const webhookSecretLen = 32
+
+// GenerateWebhookSecret 生成随机的 Webhook 签名密钥（十六进制编码）
+func GenerateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// SignWebhookPayload 计算 Webhook 请求签名
+// 签名内容为 "{timestamp}.{body}"，算法为 HMAC-SHA256，结果以 "sha256=" 加十六进制摘要表示。
+// 接收方使用相同的密钥重新计算并以常量时间比较，同时应拒绝时间戳过旧的请求以防止重放。
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrWebhookAddressForbidden 回调地址不是公网地址
+var ErrWebhookAddressForbidden = errors.New("webhook address is not a public address")
+
+// nonPublicNetworks net.IP 自带判断方法之外的非公网地址段
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // 本网络
+	"100.64.0.0/10",  // 运营商级 NAT，部分云厂商的元数据服务位于此网段
+	"192.0.0.0/24",   // IETF 协议分配
+	"198.18.0.0/15",  // 基准测试
+	"240.0.0.0/4",    // 保留地址及广播地址
+	"64:ff9b::/96",   // NAT64，可映射到任意 IPv4 地址
+	"64:ff9b:1::/48", // 本地 NAT64
+	"2001:db8::/32",  // 文档示例
+)
+
+// mustParseCIDRs 解析地址段，格式错误时 panic
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// IsPublicIP 判断 IP 是否为公网地址
+// 回环、私有、链路本地（含 169.254.169.254 云元数据地址）、组播、未指定地址以及其他保留地址段均不是公网地址。
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// WebhookDialControl 用作 net.Dialer.Control，在建立连接前检查实际连接的 IP 是否为公网地址
+// 检查发生在 DNS 解析之后，回调地址的域名解析到内网地址或被重绑定到内网地址时同样会被拒绝。
+func WebhookDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressForbidden, host)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{
+			name:      "event payload",
+			secret:    "9f86d081884c7d659a2feaa0c55ad015",
+			timestamp: 1731924000,
+			body:      `{"id":"e1"}`,
+			want:      "sha256=8fb8304761c541bb52ee88abc941011e60c4d8251dd16c16a6b78a887671ba4c",
+		},
+		{
+			name:      "timestamp is signed",
+			secret:    "9f86d081884c7d659a2feaa0c55ad015",
+			timestamp: 1731924001,
+			body:      `{"id":"e1"}`,
+			want:      "sha256=28153604c1d60b1373c4b0c9768c221f3ce298b76e8fc9fd96ad8566b326e751",
+		},
+		{
+			name:      "secret is used",
+			secret:    "other-secret-value",
+			timestamp: 1731924000,
+			body:      `{"id":"e1"}`,
+			want:      "sha256=58230501809fd7e51c8cd9f51486787fb2dcc7b455d4230bfe7e11ac5d025f77",
+		},
+		{
+			name:      "empty body",
+			secret:    "secret",
+			timestamp: 0,
+			body:      "",
+			want:      "sha256=3445798a051818ef95def46c2eb62b43d377ce6e3c29b4d0aec3da0e59577f79",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SignWebhookPayload(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("SignWebhookPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1", want: false},
+		{ip: "::1", want: false},
+		{ip: "10.1.2.3", want: false},
+		{ip: "172.16.0.1", want: false},
+		{ip: "192.168.1.1", want: false},
+		{ip: "169.254.169.254", want: false},
+		{ip: "100.100.100.200", want: false},
+		{ip: "0.0.0.0", want: false},
+		{ip: "::", want: false},
+		{ip: "224.0.0.1", want: false},
+		{ip: "255.255.255.255", want: false},
+		{ip: "fd00:ec2::254", want: false},
+		{ip: "fe80::1", want: false},
+		{ip: "::ffff:127.0.0.1", want: false},
+		{ip: "::ffff:10.0.0.1", want: false},
+		{ip: "64:ff9b::a00:1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{name: "public ipv4", address: "93.184.216.34:443"},
+		{name: "public ipv6", address: "[2606:2800:220:1:248:1893:25c8:1946]:443"},
+		{name: "loopback", address: "127.0.0.1:80", wantErr: true},
+		{name: "metadata", address: "169.254.169.254:80", wantErr: true},
+		{name: "private ipv6", address: "[fd00::1]:443", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WebhookDialControl("tcp", tt.address, nil)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("WebhookDialControl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrWebhookAddressForbidden) {
+				t.Errorf("WebhookDialControl() error = %v, want ErrWebhookAddressForbidden", err)
+			}
+		})
+	}
+}
@@ -9,9 +9,6 @@ import (
 )
 
 func main() {
-	engin := gin.Default()
-	defer internal.ServerExit(engin)
-
 	// 1. 尝试加载 .env 文件
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found or error loading .env file")
@@ -20,5 +17,16 @@ func main() {
 	// 2. 获取环境变量，优先从 .env 文件中获取
 	ginMode := os.Getenv("GIN_MODE_ADMIN")
 
+	// 3. 数据库迁移命令：go run main.go migrate up | down [N] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := internal.Migrate(ginMode, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	engin := gin.Default()
+	defer internal.ServerExit(engin)
+
 	internal.ServerStart(engin, ginMode)
 }
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"ByteScience-WAM-Admin/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit 请求体大小限制中间件
+// 声明的 Content-Length 超出限制时直接拒绝；未声明长度时读取超出限制的部分会返回错误，请求参数绑定随之失败
+func BodyLimit(limit int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.ContentLength > limit {
+			utils.SendResponse(ctx, http.StatusRequestEntityTooLarge, utils.ErrorResponse(utils.BadRequest, "Request body too large"))
+			return
+		}
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+		ctx.Next()
+	}
+}
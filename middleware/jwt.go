@@ -2,12 +2,13 @@ package middleware
 
 import (
 	"ByteScience-WAM-Admin/internal/utils"
-	"github.com/dgrijalva/jwt-go"
+	"ByteScience-WAM-Admin/pkg/redis"
 	"github.com/gin-gonic/gin"
 )
 
 // JWTAuth 中间件
-func JWTAuth(secretKey string) gin.HandlerFunc {
+// 受限令牌（携带 scope）默认被拒绝，只有在 allowedScopes 中声明了对应 scope 的路由才会放行
+func JWTAuth(secretKey string, allowedScopes ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// 获取 Authorization header
 		authHeader := ctx.GetHeader("Authorization")
@@ -30,14 +31,7 @@ func JWTAuth(secretKey string) gin.HandlerFunc {
 		tokenString := authHeader[7:]
 
 		// 解析 token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// 检查 token 是否使用了 HS256 签名方法
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, utils.NewBusinessError(utils.InvalidTokenCode)
-			}
-			return []byte(secretKey), nil
-		})
-
+		claims, err := utils.ParseToken(secretKey, tokenString)
 		if err != nil {
 			// token 解析失败，可能是过期、无效等原因
 			if err.Error() == "Token is expired" {
@@ -51,18 +45,63 @@ func JWTAuth(secretKey string) gin.HandlerFunc {
 			return
 		}
 
-		// token 验证成功，设置用户信息
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// 设置用户信息到上下文中
-			ctx.Set("userId", claims["userId"])
-		} else {
-			// token 无效，返回错误
+		// 只接受访问令牌，刷新令牌不能直接用于访问接口
+		jti := utils.ClaimString(claims, "jti")
+		familyId := utils.ClaimString(claims, "fid")
+		subjectType := utils.ClaimString(claims, "subjectType")
+		if utils.ClaimString(claims, "typ") != utils.AccessTokenType || jti == "" || familyId == "" ||
+			(subjectType != utils.SubjectTypeAdmin && subjectType != utils.SubjectTypeUser) {
 			utils.SendResponse(ctx, 401, utils.ErrorResponse(utils.InvalidTokenCode, "Invalid token"))
 			ctx.Abort()
 			return
 		}
 
+		// 受限令牌只能访问声明了对应 scope 的路由
+		scope := utils.ClaimString(claims, "scope")
+		if scope != "" && !scopeAllowed(scope, allowedScopes) {
+			utils.SendResponse(ctx, 403, utils.ErrorResponse(utils.PasswordChangeRequiredCode, ""))
+			ctx.Abort()
+			return
+		}
+
+		// 检查 token 是否已被吊销（单独吊销或所属令牌家族已退出登录）
+		revoked, err := redis.IsTokenRevoked(ctx, jti)
+		if err != nil {
+			utils.SendResponse(ctx, 500, utils.ErrorResponse(utils.InternalError, ""))
+			ctx.Abort()
+			return
+		}
+		familyExists, err := redis.TokenFamilyExists(ctx, familyId)
+		if err != nil {
+			utils.SendResponse(ctx, 500, utils.ErrorResponse(utils.InternalError, ""))
+			ctx.Abort()
+			return
+		}
+		if revoked || !familyExists {
+			utils.SendResponse(ctx, 401, utils.ErrorResponse(utils.TokenRevokedCode, ""))
+			ctx.Abort()
+			return
+		}
+
+		// token 验证成功，设置用户信息到上下文中
+		ctx.Set("userId", claims["userId"])
+		ctx.Set("subjectType", subjectType)
+		ctx.Set("jti", jti)
+		ctx.Set("familyId", familyId)
+		ctx.Set("scope", scope)
+		ctx.Set("tokenExpireAt", utils.ClaimExpireAt(claims))
+
 		// 继续请求处理
 		ctx.Next()
 	}
 }
+
+// scopeAllowed 判断令牌的 scope 是否在路由允许的范围内
+func scopeAllowed(scope string, allowedScopes []string) bool {
+	for _, allowed := range allowedScopes {
+		if allowed == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"ByteScience-WAM-Admin/internal/service"
+	"ByteScience-WAM-Admin/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionAuth 接口权限校验中间件
+// 必须挂载在 JWTAuth 之后，根据请求方法和路由模板匹配 paths 表，校验调用方是否已被授权
+func PermissionAuth() gin.HandlerFunc {
+	permissionService := service.NewPermissionService()
+
+	return func(ctx *gin.Context) {
+		userId := ctx.GetString("userId")
+		if userId == "" {
+			utils.SendResponse(ctx, http.StatusUnauthorized, utils.ErrorResponse(utils.InvalidTokenCode, "Missing token"))
+			return
+		}
+
+		// 使用路由模板而非实际请求路径，保证与 paths 表中登记的路径一致
+		routePath := ctx.FullPath()
+		if routePath == "" {
+			utils.SendResponse(ctx, http.StatusForbidden, utils.ErrorResponse(utils.PermissionDeniedCode, ""))
+			return
+		}
+
+		subjectType := ctx.GetString("subjectType")
+		allowed, err := permissionService.CheckPermission(ctx, subjectType, userId, ctx.Request.Method, routePath)
+		if err != nil {
+			utils.SendResponse(ctx, http.StatusInternalServerError, utils.ErrorResponse(utils.InternalError, ""))
+			return
+		}
+		if !allowed {
+			code := utils.PermissionDeniedCode
+			if subjectType == utils.SubjectTypeAdmin {
+				code = utils.AdminUnauthorizedCode
+			}
+			utils.SendResponse(ctx, http.StatusForbidden, utils.ErrorResponse(code, ""))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"ByteScience-WAM-Admin/internal/utils"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestIDRegex 客户端传入的请求ID只接受字母、数字、下划线、连字符和点，长度不超过64
+var requestIDRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID 请求ID中间件
+// 优先沿用客户端或网关传入的 X-Request-ID，否则生成新的请求ID，并通过响应头返回，便于日志与审计记录关联
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(utils.RequestIDHeader)
+		if !requestIDRegex.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(utils.RequestIDKey, requestID)
+		c.Header(utils.RequestIDHeader, requestID)
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/utils"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScimAuth SCIM 客户端鉴权中间件
+// 校验 Authorization 请求头中的 Bearer 令牌是否与配置的某个 SCIM 令牌一致，
+// 通过后将客户端名称写入上下文的 userId，主体类型写入 subjectType，作为后续变更的操作人。
+func ScimAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader("Authorization")
+		token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		if header == "" || token == header {
+			ctx.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			utils.SendScimError(ctx, utils.NewScimError(http.StatusUnauthorized, "", "Missing bearer token"))
+			return
+		}
+
+		// 逐个以常量时间比较，避免通过响应时间推测令牌
+		clientName := ""
+		for name, expected := range conf.GlobalConf.System.Security.ScimTokens {
+			if expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+				clientName = name
+			}
+		}
+		if clientName == "" {
+			ctx.Header("WWW-Authenticate", `Bearer realm="scim", error="invalid_token"`)
+			utils.SendScimError(ctx, utils.NewScimError(http.StatusUnauthorized, "", "Invalid bearer token"))
+			return
+		}
+
+		ctx.Set("userId", clientName)
+		ctx.Set("subjectType", utils.SubjectTypeScim)
+		ctx.Next()
+	}
+}
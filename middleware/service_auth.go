@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"ByteScience-WAM-Admin/conf"
+	"ByteScience-WAM-Admin/internal/utils"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceTokenHeader 下游业务服务携带访问令牌的请求头
+const ServiceTokenHeader = "X-Service-Token"
+
+// ServiceAuth 下游业务服务鉴权中间件
+// 校验请求头中的服务令牌是否与配置的某个服务令牌一致，通过后将服务名称写入上下文的 serviceName
+func ServiceAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.GetHeader(ServiceTokenHeader)
+		if token == "" {
+			utils.SendResponse(ctx, http.StatusUnauthorized, utils.ErrorResponse(utils.InvalidTokenCode, "Missing service token"))
+			return
+		}
+
+		// 逐个以常量时间比较，避免通过响应时间推测令牌
+		serviceName := ""
+		for name, expected := range conf.GlobalConf.System.Security.ServiceTokens {
+			if expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+				serviceName = name
+			}
+		}
+		if serviceName == "" {
+			utils.SendResponse(ctx, http.StatusUnauthorized, utils.ErrorResponse(utils.InvalidTokenCode, "Invalid service token"))
+			return
+		}
+
+		ctx.Set("serviceName", serviceName)
+		ctx.Next()
+	}
+}
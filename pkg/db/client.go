@@ -28,13 +28,6 @@ func MysqlInit() (err error) {
 		logger.Logger.Fatalf("Failed to connect to the database: %v", err)
 	}
 
-	// 可选：自动迁移数据库结构
-	err = Client.AutoMigrate()
-	if err != nil {
-		// 如果自动迁移失败，打印错误并终止
-		logger.Logger.Fatalf("Failed to migrate database: %v", err)
-	}
-
 	logger.Logger.Info("=== Mysql initialization successful ===")
 	return
 }
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"ByteScience-WAM-Admin/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 业务使用的 MySQL 命名锁名称
+const (
+	LockBuiltinRole   = "builtin_role"   // 内置超级管理员角色的创建
+	LockRoleHierarchy = "role_hierarchy" // 角色继承关系的修改
+	LockMenuTree      = "menu_tree"      // 菜单树及菜单下接口的修改
+)
+
+// DefaultLockTimeout 业务操作等待命名锁的默认最长时间
+const DefaultLockTimeout = 10 * time.Second
+
+// WithLock 持有 MySQL 命名锁（GET_LOCK）执行 fn，用于串行化多个实例之间无法通过行锁保护的操作
+// MySQL 命名锁属于会话，因此加锁、执行和释放锁使用同一个连接，fn 中的查询和事务必须使用传入的 conn；
+// 锁名包含数据库名，不同数据库之间互不影响。
+// 参数:
+//   - client: 数据库连接池
+//   - timeout: 等待锁的最长时间
+func WithLock(ctx context.Context, client *gorm.DB, name string, timeout time.Duration, fn func(conn *gorm.DB) error) error {
+	return client.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var acquired *int
+		if err := conn.Raw("SELECT GET_LOCK(CONCAT(DATABASE(), '.', ?), ?)", name, int(timeout.Seconds())).
+			Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if acquired == nil || *acquired != 1 {
+			return fmt.Errorf("timed out after %s waiting for lock %s", timeout, name)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.', ?))", name).Error; err != nil {
+				logger.Logger.Errorf("[DB] Error releasing lock %s: %v", name, err)
+			}
+		}()
+
+		return fn(conn)
+	})
+}
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// erasureTokenKeyPrefix 个人数据擦除确认令牌的摘要，键为 前缀 + 操作人ID + ":" + 用户ID
+const erasureTokenKeyPrefix = "erasure:token:"
+
+// consumeErasureTokenScript 原子地校验并删除确认令牌：仅当摘要一致时删除，保证令牌只能使用一次
+var consumeErasureTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// SaveErasureToken 保存确认令牌的摘要，同一操作人对同一用户重新申请时覆盖之前的令牌
+func SaveErasureToken(ctx context.Context, actorId, userId, digest string, ttl time.Duration) error {
+	return Client.Set(ctx, erasureTokenKeyPrefix+actorId+":"+userId, digest, ttl).Err()
+}
+
+// ConsumeErasureToken 校验并作废确认令牌
+// 返回:
+//   - bool: 令牌是否有效，令牌不存在、已过期、已使用或摘要不一致时返回 false
+func ConsumeErasureToken(ctx context.Context, actorId, userId, digest string) (bool, error) {
+	deleted, err := consumeErasureTokenScript.Run(ctx, Client, []string{erasureTokenKeyPrefix + actorId + ":" + userId}, digest).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// PublishEvent 将事件追加到 Stream 并同时广播到 Pub/Sub 频道
+// 参数:
+//   - stream: Stream 名称，消费者通过消费组按需回放
+//   - channel: Pub/Sub 频道名称，为空时不广播
+//   - maxLen: Stream 的近似最大长度，超出后裁剪最早的事件，0 表示不裁剪
+//   - fields: Stream 条目的字段
+//   - payload: 广播到频道的消息内容
+func PublishEvent(ctx context.Context, stream, channel string, maxLen int64, fields map[string]interface{}, payload string) error {
+	pipe := Client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: maxLen > 0,
+		Values: fields,
+	})
+	if channel != "" {
+		pipe.Publish(ctx, channel, payload)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// EnsureConsumerGroup 确保 Stream 上存在指定消费组，不存在时从 Stream 起始位置创建（Stream 不存在时一并创建）
+func EnsureConsumerGroup(ctx context.Context, stream, group string) error {
+	err := Client.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// lockKeyPrefix 分布式锁的 Redis 键前缀
+const lockKeyPrefix = "lock:"
+
+// unlockScript 仅当锁仍由当前持有者持有时才释放，避免误删锁过期后被其他实例获取的锁
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendLockScript 仅当锁仍由当前持有者持有时才延长过期时间
+var extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// TryLock 尝试获取分布式锁
+// 参数:
+//   - owner: 持有者标识，释放锁时需要传入相同的值
+//   - ttl: 锁的最长持有时间，持有者异常退出时锁在到期后自动释放
+//
+// 返回:
+//   - bool: 是否获取成功，锁已被其他持有者持有时返回 false
+func TryLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	return Client.SetNX(ctx, lockKeyPrefix+name, owner, ttl).Result()
+}
+
+// Unlock 释放分布式锁
+func Unlock(ctx context.Context, name, owner string) error {
+	return unlockScript.Run(ctx, Client, []string{lockKeyPrefix + name}, owner).Err()
+}
+
+// ExtendLock 将仍由 owner 持有的锁的过期时间重置为 ttl，用于持有时间可能超过 ttl 的任务定期续期
+// 返回:
+//   - bool: 是否续期成功，锁已过期或已被其他持有者获取时返回 false
+func ExtendLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	extended, err := extendLockScript.Run(ctx, Client, []string{lockKeyPrefix + name}, owner, ttl.Milliseconds()).Int()
+	return extended == 1, err
+}
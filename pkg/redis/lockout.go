@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 登录失败锁定相关的 Redis 键前缀
+const (
+	loginFailKeyPrefix  = "login:fail:"  // 统计窗口内的失败次数
+	loginLockKeyPrefix  = "login:lock:"  // 锁定标记，TTL 即剩余锁定时长
+	loginLevelKeyPrefix = "login:level:" // 已被锁定的次数，用于计算递进的锁定时长
+)
+
+// GetLoginLockTTL 获取剩余锁定时长，未锁定时返回 0
+func GetLoginLockTTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := Client.PTTL(ctx, loginLockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// 键不存在（-2）或未设置过期时间（-1）时视为未锁定
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RecordLoginFailure 记录一次失败，返回统计窗口内的累计失败次数
+func RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := Client.TxPipeline()
+	incr := pipe.Incr(ctx, loginFailKeyPrefix+key)
+	// 仅在窗口开始时设置过期时间，避免持续失败导致窗口不断延长
+	pipe.ExpireNX(ctx, loginFailKeyPrefix+key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// LockLogin 锁定登录，锁定时长随锁定次数逐级递增，返回本次锁定时长
+func LockLogin(ctx context.Context, key string, durations []time.Duration, levelTTL time.Duration) (time.Duration, error) {
+	if len(durations) == 0 {
+		return 0, errors.New("lock durations not configured")
+	}
+
+	pipe := Client.TxPipeline()
+	level := pipe.Incr(ctx, loginLevelKeyPrefix+key)
+	pipe.Expire(ctx, loginLevelKeyPrefix+key, levelTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	index := int(level.Val()) - 1
+	if index >= len(durations) {
+		index = len(durations) - 1
+	}
+	duration := durations[index]
+
+	pipe = Client.TxPipeline()
+	pipe.Set(ctx, loginLockKeyPrefix+key, level.Val(), duration)
+	pipe.Del(ctx, loginFailKeyPrefix+key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// ClearLoginFailures 登录成功后清除失败次数，锁定级别保留至自然过期
+func ClearLoginFailures(ctx context.Context, key string) error {
+	return Client.Del(ctx, loginFailKeyPrefix+key).Err()
+}
+
+// UnlockLogin 解除锁定，并清除失败次数与锁定级别
+func UnlockLogin(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redisKeys := make([]string, 0, len(keys)*3)
+	for _, key := range keys {
+		redisKeys = append(redisKeys, loginFailKeyPrefix+key, loginLockKeyPrefix+key, loginLevelKeyPrefix+key)
+	}
+	return Client.Del(ctx, redisKeys...).Err()
+}
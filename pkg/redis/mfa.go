@@ -0,0 +1,30 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// 二次验证相关的 Redis 键前缀
+const (
+	mfaChallengeKeyPrefix = "mfa:challenge:" // 已使用的登录挑战令牌 jti
+	mfaTotpStepKeyPrefix  = "mfa:totp:"      // 已使用的 TOTP 时间步，防止验证码在有效期内被重放
+)
+
+// ConsumeMfaChallenge 标记登录挑战令牌已使用
+// 返回:
+//   - bool: 是否为首次使用，令牌已被使用过时返回 false
+func ConsumeMfaChallenge(ctx context.Context, jti string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, nil
+	}
+	return Client.SetNX(ctx, mfaChallengeKeyPrefix+jti, 1, ttl).Result()
+}
+
+// ConsumeTotpStep 标记某个 TOTP 时间步的验证码已使用
+// 返回:
+//   - bool: 是否为首次使用，同一时间步的验证码已被使用过时返回 false
+func ConsumeTotpStep(ctx context.Context, adminId string, step int64, ttl time.Duration) (bool, error) {
+	return Client.SetNX(ctx, mfaTotpStepKeyPrefix+adminId+":"+strconv.FormatInt(step, 10), 1, ttl).Result()
+}
@@ -0,0 +1,193 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 用户权限缓存相关的 Redis 键
+const (
+	permissionVersionKey     = "permission:version" // 全局权限版本号，任一用户的权限重新计算并提交后递增
+	permissionUserKeyPrefix  = "permission:user:"   // 用户的有效接口集合，set: "METHOD PATH"
+	permissionCachedSentinel = ""                   // 集合中的占位成员，用于区分"未缓存"与"没有任何权限"
+)
+
+// 管理员权限缓存相关的 Redis 键，缓存按决策缓存版本号区分，角色、接口或继承关系变更递增版本号后旧缓存不再被读取
+const (
+	adminPermissionKeyPrefix           = "permission:admin:"            // 管理员的有效接口集合，set: "METHOD PATH"
+	adminPermissionGenerationKeyPrefix = "permission:admin-generation:" // 管理员的权限缓存代数，管理员的角色变更后递增
+	AdminPermissionAll                 = "*"                            // 拥有内置角色的管理员集合中的唯一成员，表示拥有全部接口权限
+)
+
+// savePermissionsScript 仅当全局权限版本号与读取数据库前一致时才写入权限集合
+// 避免在读取数据库与写入缓存之间权限被重新计算，把旧的权限集合写回缓存。
+var savePermissionsScript = redis.NewScript(`
+local version = redis.call("GET", KEYS[1])
+if not version then
+	version = "0"
+end
+if version ~= ARGV[1] then
+	return 0
+end
+redis.call("DEL", KEYS[2])
+redis.call("SADD", KEYS[2], unpack(ARGV, 3))
+redis.call("PEXPIRE", KEYS[2], ARGV[2])
+return 1
+`)
+
+// saveAdminPermissionsScript 仅当决策缓存版本号和管理员的缓存代数与读取数据库前一致时才写入权限集合
+var saveAdminPermissionsScript = redis.NewScript(`
+local version = redis.call("GET", KEYS[1])
+if not version then
+	version = "0"
+end
+local generation = redis.call("GET", KEYS[2])
+if not generation then
+	generation = "0"
+end
+if version ~= ARGV[1] or generation ~= ARGV[2] then
+	return 0
+end
+redis.call("DEL", KEYS[3])
+redis.call("SADD", KEYS[3], unpack(ARGV, 4))
+redis.call("PEXPIRE", KEYS[3], ARGV[3])
+return 1
+`)
+
+// PermissionMember 生成权限集合中的成员
+func PermissionMember(method, path string) string {
+	return method + " " + path
+}
+
+// GetPermissionVersion 获取当前的全局权限版本号，尚未设置时返回 "0"
+// 回源查询数据库前读取，写入缓存时原样传给 SavePermissions。
+func GetPermissionVersion(ctx context.Context) (string, error) {
+	version, err := Client.Get(ctx, permissionVersionKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return version, err
+}
+
+// CheckCachedPermission 从缓存中判断用户是否拥有指定接口的权限
+// 返回:
+//   - bool: 用户的权限集合是否已缓存，未缓存时需要回源查询
+//   - bool: 是否拥有该接口的权限
+func CheckCachedPermission(ctx context.Context, userID, member string) (bool, bool, error) {
+	key := permissionUserKeyPrefix + userID
+	pipe := Client.Pipeline()
+	cached := pipe.SIsMember(ctx, key, permissionCachedSentinel)
+	allowed := pipe.SIsMember(ctx, key, member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, false, err
+	}
+	return cached.Val(), allowed.Val(), nil
+}
+
+// SavePermissions 写入用户的权限集合
+// 参数:
+//   - version: 回源查询数据库前读取的全局权限版本号，版本号已变化时放弃写入
+//
+// 返回:
+//   - bool: 是否已写入
+func SavePermissions(ctx context.Context, version, userID string, members []string, ttl time.Duration) (bool, error) {
+	args := make([]interface{}, 0, len(members)+3)
+	args = append(args, version, ttl.Milliseconds(), permissionCachedSentinel)
+	for _, member := range members {
+		args = append(args, member)
+	}
+	saved, err := savePermissionsScript.Run(ctx, Client, []string{permissionVersionKey, permissionUserKeyPrefix + userID}, args...).Int()
+	return saved == 1, err
+}
+
+// InvalidatePermissions 递增全局权限版本号并删除指定用户的权限集合
+// 必须在重新计算权限的事务提交之后调用；版本号递增使提交前已开始的回源查询结果不会写回缓存。
+func InvalidatePermissions(ctx context.Context, userIDs []string) error {
+	pipe := Client.TxPipeline()
+	pipe.Incr(ctx, permissionVersionKey)
+	if len(userIDs) > 0 {
+		keys := make([]string, 0, len(userIDs))
+		for _, userID := range userIDs {
+			keys = append(keys, permissionUserKeyPrefix+userID)
+		}
+		pipe.Del(ctx, keys...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// adminPermissionKey 生成指定决策缓存版本下管理员的权限集合键
+func adminPermissionKey(version, adminID string) string {
+	return adminPermissionKeyPrefix + version + ":" + adminID
+}
+
+// GetAdminPermissionGeneration 获取管理员的权限缓存代数，尚未设置时返回 "0"
+// 回源查询数据库前与 GetPolicyVersion 一同读取，写入缓存时原样传给 SaveAdminPermissions。
+func GetAdminPermissionGeneration(ctx context.Context, adminID string) (string, error) {
+	generation, err := Client.Get(ctx, adminPermissionGenerationKeyPrefix+adminID).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return generation, err
+}
+
+// CheckCachedAdminPermission 从缓存中判断管理员是否拥有指定接口的权限
+// 返回:
+//   - bool: 管理员的权限集合是否已缓存，未缓存时需要回源查询
+//   - bool: 是否拥有该接口的权限
+func CheckCachedAdminPermission(ctx context.Context, version, adminID, member string) (bool, bool, error) {
+	key := adminPermissionKey(version, adminID)
+	pipe := Client.Pipeline()
+	cached := pipe.SIsMember(ctx, key, permissionCachedSentinel)
+	allowed := pipe.SIsMember(ctx, key, member)
+	all := pipe.SIsMember(ctx, key, AdminPermissionAll)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, false, err
+	}
+	return cached.Val(), allowed.Val() || all.Val(), nil
+}
+
+// SaveAdminPermissions 写入管理员的权限集合
+// 参数:
+//   - version: 回源查询数据库前读取的决策缓存版本号
+//   - generation: 回源查询数据库前读取的管理员权限缓存代数，与 version 任一已变化时放弃写入
+//
+// 返回:
+//   - bool: 是否已写入
+func SaveAdminPermissions(ctx context.Context, version, generation, adminID string, members []string, ttl time.Duration) (bool, error) {
+	args := make([]interface{}, 0, len(members)+4)
+	args = append(args, version, generation, ttl.Milliseconds(), permissionCachedSentinel)
+	for _, member := range members {
+		args = append(args, member)
+	}
+	keys := []string{policyVersionKey, adminPermissionGenerationKeyPrefix + adminID, adminPermissionKey(version, adminID)}
+	saved, err := saveAdminPermissionsScript.Run(ctx, Client, keys, args...).Int()
+	return saved == 1, err
+}
+
+// InvalidateAdminPermissions 递增指定管理员的权限缓存代数并删除其在当前版本下的权限集合
+// 必须在调整管理员角色的事务提交之后调用；代数递增使提交前已开始的回源查询结果不会写回缓存。
+// 参数:
+//   - ttl: 代数的保留时间，不应短于回源查询一次的最长耗时
+func InvalidateAdminPermissions(ctx context.Context, adminIDs []string, ttl time.Duration) error {
+	if len(adminIDs) == 0 {
+		return nil
+	}
+	version, err := GetPolicyVersion(ctx)
+	if err != nil {
+		return err
+	}
+	pipe := Client.TxPipeline()
+	keys := make([]string, 0, len(adminIDs))
+	for _, adminID := range adminIDs {
+		pipe.Incr(ctx, adminPermissionGenerationKeyPrefix+adminID)
+		pipe.Expire(ctx, adminPermissionGenerationKeyPrefix+adminID, ttl)
+		keys = append(keys, adminPermissionKey(version, adminID))
+	}
+	pipe.Del(ctx, keys...)
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 权限决策缓存相关的 Redis 键
+const (
+	policyVersionKey          = "policy:version"     // 决策缓存版本号，递增后全部旧缓存失效
+	policyDecisionKeyPrefix   = "policy:decision:"   // 用户的决策缓存，hash: "METHOD PATH" -> 决策结果 JSON
+	policyGenerationKeyPrefix = "policy:generation:" // 用户的决策缓存代数，用户的授权变更后递增
+)
+
+// savePolicyDecisionsScript 仅当决策缓存版本号和用户的缓存代数与计算决策前一致时才写入决策缓存
+// 避免在查询数据库与写入缓存之间授权发生变更，把旧的决策写回缓存。
+var savePolicyDecisionsScript = redis.NewScript(`
+local version = redis.call("GET", KEYS[1])
+if not version then
+	version = "0"
+end
+local generation = redis.call("GET", KEYS[2])
+if not generation then
+	generation = "0"
+end
+if version ~= ARGV[1] or generation ~= ARGV[2] then
+	return 0
+end
+redis.call("HSET", KEYS[3], unpack(ARGV, 4))
+redis.call("PEXPIRE", KEYS[3], ARGV[3])
+return 1
+`)
+
+// policyDecisionKey 生成指定版本下用户的决策缓存键
+func policyDecisionKey(version, userID string) string {
+	return policyDecisionKeyPrefix + version + ":" + userID
+}
+
+// GetPolicyVersion 获取当前的决策缓存版本号，尚未设置时返回 "0"
+func GetPolicyVersion(ctx context.Context) (string, error) {
+	version, err := Client.Get(ctx, policyVersionKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return version, err
+}
+
+// BumpPolicyVersion 递增决策缓存版本号，使全部用户的决策缓存失效
+// 旧版本的缓存不再被读取，到期后自动清理。
+func BumpPolicyVersion(ctx context.Context) error {
+	return Client.Incr(ctx, policyVersionKey).Err()
+}
+
+// GetPolicyDecisions 批量读取用户的决策缓存
+// 返回:
+//   - map[string]string: 命中的字段及缓存值，未命中的字段不包含在结果中
+func GetPolicyDecisions(ctx context.Context, version, userID string, fields []string) (map[string]string, error) {
+	values, err := Client.HMGet(ctx, policyDecisionKey(version, userID), fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+	hits := make(map[string]string, len(fields))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			hits[fields[i]] = s
+		}
+	}
+	return hits, nil
+}
+
+// GetPolicyGeneration 获取用户的决策缓存代数，尚未设置时返回 "0"
+// 计算决策前读取，写入缓存时原样传给 SavePolicyDecisions。
+func GetPolicyGeneration(ctx context.Context, userID string) (string, error) {
+	generation, err := Client.Get(ctx, policyGenerationKeyPrefix+userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	return generation, err
+}
+
+// SavePolicyDecisions 写入用户的决策缓存，并刷新缓存的过期时间
+// 参数:
+//   - version: 计算决策前读取的决策缓存版本号
+//   - generation: 计算决策前读取的用户决策缓存代数，与 version 任一已变化时放弃写入
+//
+// 返回:
+//   - bool: 是否已写入
+func SavePolicyDecisions(ctx context.Context, version, generation, userID string, values map[string]string, ttl time.Duration) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+	args := make([]interface{}, 0, len(values)*2+3)
+	args = append(args, version, generation, ttl.Milliseconds())
+	for field, value := range values {
+		args = append(args, field, value)
+	}
+	keys := []string{policyVersionKey, policyGenerationKeyPrefix + userID, policyDecisionKey(version, userID)}
+	saved, err := savePolicyDecisionsScript.Run(ctx, Client, keys, args...).Int()
+	return saved == 1, err
+}
+
+// InvalidatePolicyDecisions 递增指定用户的决策缓存代数并删除其在当前版本下的决策缓存
+// 必须在授权变更的事务提交之后调用；代数递增使提交前已开始计算的决策不会写回缓存。
+// 参数:
+//   - ttl: 代数的保留时间，不应短于计算一次决策的最长耗时
+func InvalidatePolicyDecisions(ctx context.Context, userIDs []string, ttl time.Duration) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	version, err := GetPolicyVersion(ctx)
+	if err != nil {
+		return err
+	}
+	pipe := Client.TxPipeline()
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		pipe.Incr(ctx, policyGenerationKeyPrefix+userID)
+		pipe.Expire(ctx, policyGenerationKeyPrefix+userID, ttl)
+		keys = append(keys, policyDecisionKey(version, userID))
+	}
+	pipe.Del(ctx, keys...)
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 令牌相关的 Redis 键前缀
+const (
+	tokenFamilyKeyPrefix  = "token:family:"  // 令牌家族，hash: userId、subjectType、refreshJti
+	tokenUserKeyPrefix    = "token:user:"    // 用户持有的令牌家族集合，用于"退出所有设备"
+	tokenRevokedKeyPrefix = "token:revoked:" // 已吊销的访问令牌 jti
+)
+
+// 令牌家族 hash 字段
+const (
+	tokenFamilyFieldUserId      = "userId"
+	tokenFamilyFieldSubjectType = "subjectType"
+	tokenFamilyFieldRefreshJti  = "refreshJti"
+)
+
+// rotateRefreshScript 原子地轮换刷新令牌：仅当当前 jti 与传入的旧 jti 一致时才写入新 jti
+// 同时将家族重新登记到用户的令牌家族集合，并保证集合的有效期不短于家族，避免持续刷新的会话脱离"退出所有设备"的范围
+var rotateRefreshScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "refreshJti") == ARGV[1] then
+	redis.call("HSET", KEYS[1], "refreshJti", ARGV[2])
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	redis.call("SADD", KEYS[2], ARGV[4])
+	if redis.call("PTTL", KEYS[2]) < tonumber(ARGV[3]) then
+		redis.call("PEXPIRE", KEYS[2], ARGV[3])
+	end
+	return 1
+end
+return 0
+`)
+
+// TokenFamily 令牌家族信息
+type TokenFamily struct {
+	UserId      string // 用户ID
+	SubjectType string // 主体类型（admin、user）
+	RefreshJti  string // 当前有效的刷新令牌 jti
+}
+
+// SaveTokenFamily 保存新的令牌家族，并记录到用户的令牌家族集合中
+func SaveTokenFamily(ctx context.Context, familyId string, family TokenFamily, ttl time.Duration) error {
+	pipe := Client.TxPipeline()
+	pipe.HSet(ctx, tokenFamilyKeyPrefix+familyId,
+		tokenFamilyFieldUserId, family.UserId,
+		tokenFamilyFieldSubjectType, family.SubjectType,
+		tokenFamilyFieldRefreshJti, family.RefreshJti,
+	)
+	pipe.Expire(ctx, tokenFamilyKeyPrefix+familyId, ttl)
+	pipe.SAdd(ctx, tokenUserKeyPrefix+family.UserId, familyId)
+	pipe.Expire(ctx, tokenUserKeyPrefix+family.UserId, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetTokenFamily 获取令牌家族信息，家族不存在（已吊销或已过期）时返回 nil
+func GetTokenFamily(ctx context.Context, familyId string) (*TokenFamily, error) {
+	values, err := Client.HGetAll(ctx, tokenFamilyKeyPrefix+familyId).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return &TokenFamily{
+		UserId:      values[tokenFamilyFieldUserId],
+		SubjectType: values[tokenFamilyFieldSubjectType],
+		RefreshJti:  values[tokenFamilyFieldRefreshJti],
+	}, nil
+}
+
+// TokenFamilyExists 判断令牌家族是否仍然有效
+func TokenFamilyExists(ctx context.Context, familyId string) (bool, error) {
+	n, err := Client.Exists(ctx, tokenFamilyKeyPrefix+familyId).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RotateRefreshToken 轮换刷新令牌
+// 返回 false 表示旧的刷新令牌已被使用过（可能被盗用），调用方应吊销整个家族
+func RotateRefreshToken(ctx context.Context, userId, familyId, oldJti, newJti string, ttl time.Duration) (bool, error) {
+	res, err := rotateRefreshScript.Run(ctx, Client, []string{tokenFamilyKeyPrefix + familyId, tokenUserKeyPrefix + userId},
+		oldJti, newJti, ttl.Milliseconds(), familyId).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// RevokeTokenFamily 吊销令牌家族，家族下的刷新令牌与访问令牌随即失效
+func RevokeTokenFamily(ctx context.Context, familyId string) error {
+	family, err := GetTokenFamily(ctx, familyId)
+	if err != nil {
+		return err
+	}
+
+	pipe := Client.TxPipeline()
+	pipe.Del(ctx, tokenFamilyKeyPrefix+familyId)
+	if family != nil {
+		pipe.SRem(ctx, tokenUserKeyPrefix+family.UserId, familyId)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RevokeUserTokenFamilies 吊销用户的全部令牌家族（退出所有设备）
+func RevokeUserTokenFamilies(ctx context.Context, userId string) error {
+	familyIds, err := Client.SMembers(ctx, tokenUserKeyPrefix+userId).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	keys := make([]string, 0, len(familyIds)+1)
+	for _, familyId := range familyIds {
+		keys = append(keys, tokenFamilyKeyPrefix+familyId)
+	}
+	keys = append(keys, tokenUserKeyPrefix+userId)
+
+	return Client.Del(ctx, keys...).Err()
+}
+
+// RevokeToken 将访问令牌加入吊销列表，ttl 应为令牌剩余有效期
+func RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return Client.Set(ctx, tokenRevokedKeyPrefix+jti, 1, ttl).Err()
+}
+
+// IsTokenRevoked 判断访问令牌是否已被吊销
+func IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := Client.Exists(ctx, tokenRevokedKeyPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}